@@ -2,19 +2,35 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+	_ "time/tzdata" // 排程以 SCHEDULER_TIMEZONE 判斷日期，distroless image 不一定有時區資料
 
 	"github.com/gin-gonic/gin"
 	"github.com/taiwanstay/taiwanstay-back/internal/api"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/internal/scheduler"
 	"github.com/taiwanstay/taiwanstay-back/internal/service"
 	"github.com/taiwanstay/taiwanstay-back/pkg/config"
 	"github.com/taiwanstay/taiwanstay-back/pkg/database"
 	"github.com/taiwanstay/taiwanstay-back/pkg/email"
 	"github.com/taiwanstay/taiwanstay-back/pkg/gcp"
 	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"github.com/taiwanstay/taiwanstay-back/pkg/oauth"
+	"github.com/taiwanstay/taiwanstay-back/pkg/sms"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take after SIGTERM
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	// 1. Load Config
 	cfg, err := config.LoadConfig()
@@ -35,8 +51,11 @@ func main() {
 	defer database.Close(mongoClient)
 	logger.Info("Connected to MongoDB")
 
+	// Cancelled on SIGINT/SIGTERM to stop the scheduler and the HTTP server
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	// 4. Init GCP Clients
-	ctx := context.Background()
 	storageClient, err := gcp.NewStorageClient(ctx)
 	if err != nil {
 		logger.Warn("Failed to init GCP Storage Client (Check credentials)", "error", err)
@@ -59,6 +78,7 @@ func main() {
 	primarySender := email.NewBrevoSender(cfg)
 	secondarySender := email.NewMailerLiteSender(cfg)
 	emailSender := email.NewFallbackSender(primarySender, secondarySender)
+	smsSender := sms.NewSender(cfg)
 
 	// Repositories
 	userRepo := repository.NewUserRepository(db.Collection("users"))
@@ -67,30 +87,90 @@ func main() {
 	appRepo := repository.NewApplicationRepository(db.Collection("applications"))
 	notifRepo := repository.NewNotificationRepository(db.Collection("notifications"))
 	bookmarkRepo := repository.NewBookmarkRepository(db.Collection("bookmarks"))
+	sessionRepo := repository.NewSessionRepository(db.Collection("sessions"))
+	passwordResetRepo := repository.NewPasswordResetRepository(db.Collection("password_resets"))
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db.Collection("login_attempts"))
+	roleRepo := repository.NewRoleRepository(db.Collection("roles"))
+	phoneVerificationRepo := repository.NewPhoneVerificationRepository(db.Collection("phone_verifications"))
+	orgRepo := repository.NewOrganizationRepository(db.Collection("organizations"))
+	orgInvitationRepo := repository.NewOrganizationInvitationRepository(db.Collection("organization_invitations"))
+	hostInvitationRepo := repository.NewHostInvitationRepository(db.Collection("host_invitations"))
+	oppApprovalRepo := repository.NewOpportunityApprovalRepository(db.Collection("opportunity_approvals"))
+	lockRepo := repository.NewLockRepository(db.Collection("locks"))
 
 	// Services
-	userService := service.NewUserService(userRepo, cfg)
+	sessionService := service.NewSessionService(sessionRepo, userRepo, cfg)
+	emailVerificationService := service.NewEmailVerificationService(userRepo, emailSender, cfg)
+	loginProtectionService := service.NewLoginProtectionService(loginAttemptRepo, userRepo, emailSender, cfg)
+	mfaService := service.NewMFAService(userRepo, sessionService, loginProtectionService, cfg)
+	userService := service.NewUserService(userRepo, sessionService, emailVerificationService, mfaService, loginProtectionService)
+	passwordService := service.NewPasswordService(userRepo, passwordResetRepo, loginAttemptRepo, sessionService, emailSender, cfg)
+	phoneVerificationService := service.NewPhoneVerificationService(phoneVerificationRepo, userRepo, smsSender, cfg)
+
+	verifiers := map[domain.AuthProvider]oauth.Verifier{}
+	if len(cfg.OAuth.GoogleClientIDs) > 0 {
+		verifiers[domain.ProviderGoogle] = oauth.NewGoogleVerifier(cfg)
+	}
+	if len(cfg.OAuth.AppleClientIDs) > 0 {
+		verifiers[domain.ProviderApple] = oauth.NewAppleVerifier(cfg)
+	}
+	oauthService := service.NewOAuthService(userRepo, sessionService, mfaService, verifiers)
 
 	imageCollection := db.Collection("images")
 	imageRepo := repository.NewImageRepository(imageCollection)
 	imageService := service.NewImageService(imageRepo, storageClient, visionClient, cfg)
 
-	hostService := service.NewHostService(hostRepo)
-	oppService := service.NewOpportunityService(oppRepo)
+	hostService := service.NewHostService(hostRepo, oppRepo, cfg)
 	notifService := service.NewNotificationService(notifRepo, userRepo, emailSender)
-	appService := service.NewApplicationService(appRepo, oppRepo, hostRepo, notifService)
-	adminService := service.NewAdminService(userRepo, imageRepo, appRepo, imageService)
+	oppService := service.NewOpportunityService(oppRepo, hostRepo, oppApprovalRepo, notifService, cfg)
+	oppModerationService := service.NewOpportunityModerationService(oppRepo, oppApprovalRepo, oppService)
+	oppLifecycleService := service.NewOpportunityLifecycleService(oppRepo, hostRepo, oppService, notifService, cfg)
+	appService := service.NewApplicationService(appRepo, oppRepo, hostRepo, userRepo, orgRepo, notifService)
+	orgService := service.NewOrganizationService(orgRepo, orgInvitationRepo, hostRepo, userRepo, appRepo, notifService)
+	hostMemberService := service.NewHostMemberService(hostRepo, hostInvitationRepo, userRepo, notifService, emailSender, cfg)
+	hostVerificationService := service.NewHostVerificationService(hostRepo, imageService, notifService)
+	accountService := service.NewAccountService(userRepo, appRepo, bookmarkRepo, notifRepo, imageRepo, hostService, sessionService)
+	adminService := service.NewAdminService(userRepo, imageRepo, appRepo, imageService, sessionService, loginProtectionService, accountService)
 	bookmarkService := service.NewBookmarkService(bookmarkRepo, oppRepo)
+	roleService := service.NewRoleService(roleRepo, userRepo, sessionService)
+	if err := roleService.EnsureDefaultRoles(ctx); err != nil {
+		logger.Error("Failed to create default roles", "error", err)
+		log.Fatalf("Failed to create default roles: %v", err)
+	}
+
+	profileService := service.NewProfileService(userRepo, hostRepo, appRepo, roleService)
+
+	// Background jobs, run by a single replica (see internal/scheduler)
+	var background sync.WaitGroup
+	if cfg.Scheduler.Enabled {
+		sched := scheduler.New(lockRepo, schedulerOwner(), cfg.Scheduler.Tick, cfg.Scheduler.LeaseTTL)
+		sched.Add(scheduler.Job{Name: "opportunity-lifecycle", Interval: cfg.Scheduler.OpportunityInterval, Run: func(ctx context.Context) error {
+			return runOpportunityLifecycle(ctx, oppLifecycleService)
+		}})
+		// Delete images scheduled for removal (e.g. from deleted accounts)
+		if storageClient != nil {
+			sched.Add(scheduler.Job{Name: "purge-images", Interval: cfg.Scheduler.ImagePurgeInterval, Run: func(ctx context.Context) error {
+				return purgeScheduledImages(ctx, imageService)
+			}})
+		}
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			sched.Start(ctx)
+		}()
+	}
 
 	// Handlers
-	userHandler := api.NewUserHandler(userService)
-	imageHandler := api.NewImageHandler(imageService)
-	hostHandler := api.NewHostHandler(hostService)
-	oppHandler := api.NewOpportunityHandler(oppService, hostService)
+	userHandler := api.NewUserHandler(userService, sessionService, oauthService, emailVerificationService, passwordService, mfaService, phoneVerificationService, accountService)
+	imageHandler := api.NewImageHandler(imageService, roleService)
+	hostHandler := api.NewHostHandler(hostService, hostVerificationService, hostMemberService)
+	oppHandler := api.NewOpportunityHandler(oppService, hostService, orgService)
 	appHandler := api.NewApplicationHandler(appService)
 	notifHandler := api.NewNotificationHandler(notifService)
-	adminHandler := api.NewAdminHandler(adminService, oppService)
+	adminHandler := api.NewAdminHandler(adminService, oppService, roleService, hostService, hostVerificationService, oppModerationService)
 	bookmarkHandler := api.NewBookmarkHandler(bookmarkService)
+	profileHandler := api.NewProfileHandler(profileService)
+	orgHandler := api.NewOrganizationHandler(orgService)
 
 	// 6. Setup Server
 	if cfg.Server.Mode == "release" {
@@ -99,12 +179,61 @@ func main() {
 	router := gin.Default()
 
 	// Setup Routes
-	api.SetupRoutes(router, userHandler, imageHandler, hostHandler, oppHandler, appHandler, notifHandler, adminHandler, bookmarkHandler, cfg)
+	api.SetupRoutes(router, userHandler, imageHandler, hostHandler, oppHandler, appHandler, notifHandler, adminHandler, bookmarkHandler, profileHandler, orgHandler, sessionService, emailVerificationService, roleService, cfg)
 
 	// 7. Run Server
 	addr := ":" + cfg.Server.Port
-	logger.Info("Server listening on " + addr)
-	if err := router.Run(addr); err != nil {
-		logger.Error("Failed to run server", "error", err)
+	srv := &http.Server{Addr: addr, Handler: router}
+	go func() {
+		logger.Info("Server listening on " + addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Failed to run server", "error", err)
+			stop()
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	logger.Info("Shutting down server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Failed to shut down server gracefully", "error", err)
+	}
+	// Wait for the scheduler to release its lock before the database is closed
+	background.Wait()
+	logger.Info("Server stopped")
+}
+
+// schedulerOwner identifies this replica when competing for the scheduler lock
+func schedulerOwner() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), time.Now().UnixNano())
+}
+
+func runOpportunityLifecycle(ctx context.Context, lifecycleService service.OpportunityLifecycleService) error {
+	result, err := lifecycleService.Run(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if result.ClosedSlots > 0 || result.Notified > 0 || result.Expired > 0 || result.Filled > 0 {
+		logger.Info("Processed opportunity lifecycle", "closedSlots", result.ClosedSlots, "notified", result.Notified, "expired", result.Expired, "filled", result.Filled)
+	}
+	return nil
+}
+
+// purgeScheduledImages removes images whose deletion date has passed
+func purgeScheduledImages(ctx context.Context, imageService service.ImageService) error {
+	purged, err := imageService.PurgeScheduledImages(ctx)
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		logger.Info("Purged scheduled images", "count", purged)
 	}
+	return nil
 }
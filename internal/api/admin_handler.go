@@ -1,30 +1,42 @@
 package api
 
 import (
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
 	"github.com/taiwanstay/taiwanstay-back/internal/service"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
 )
 
 type AdminHandler struct {
-	adminService service.AdminService
-	oppService   service.OpportunityService
+	adminService     service.AdminService
+	oppService       service.OpportunityService
+	roleService      service.RoleService
+	hostService      service.HostService
+	hostVerification service.HostVerificationService
+	oppModeration    service.OpportunityModerationService
 }
 
-func NewAdminHandler(adminService service.AdminService, oppService service.OpportunityService) *AdminHandler {
+func NewAdminHandler(adminService service.AdminService, oppService service.OpportunityService, roleService service.RoleService, hostService service.HostService, hostVerification service.HostVerificationService, oppModeration service.OpportunityModerationService) *AdminHandler {
 	return &AdminHandler{
-		adminService: adminService,
-		oppService:   oppService,
+		adminService:     adminService,
+		oppService:       oppService,
+		roleService:      roleService,
+		hostService:      hostService,
+		hostVerification: hostVerification,
+		oppModeration:    oppModeration,
 	}
 }
 
 func (h *AdminHandler) GetStats(c *gin.Context) {
 	stats, err := h.adminService.GetSystemStats(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get stats"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, stats)
@@ -38,7 +50,7 @@ func (h *AdminHandler) ListPendingImages(c *gin.Context) {
 
 	images, total, err := h.adminService.ListPendingImages(c.Request.Context(), limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list pending images"})
+		c.Error(err)
 		return
 	}
 
@@ -53,14 +65,13 @@ func (h *AdminHandler) ReviewImage(c *gin.Context) {
 	var req struct {
 		Approved bool `json:"approved"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	err := h.adminService.ReviewImage(c.Request.Context(), id, req.Approved)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -73,10 +84,11 @@ func (h *AdminHandler) ListUsers(c *gin.Context) {
 	roleStr := c.Query("role")
 	limit, _ := strconv.ParseInt(limitStr, 10, 64)
 	offset, _ := strconv.ParseInt(offsetStr, 10, 64)
+	lockedOnly, _ := strconv.ParseBool(c.Query("locked"))
 
-	users, total, err := h.adminService.ListUsers(c.Request.Context(), domain.UserRole(roleStr), limit, offset)
+	users, total, err := h.adminService.ListUsers(c.Request.Context(), domain.UserRole(roleStr), lockedOnly, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list users"})
+		c.Error(err)
 		return
 	}
 
@@ -91,31 +103,111 @@ func (h *AdminHandler) UpdateUserStatus(c *gin.Context) {
 	var req struct {
 		Status domain.UserStatus `json:"status"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	err := h.adminService.UpdateUserStatus(c.Request.Context(), id, req.Status)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "user status updated"})
 }
 
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	id := c.Param("id")
+	err := h.adminService.UnlockUser(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user unlocked"})
+}
+
+func (h *AdminHandler) ExportUserData(c *gin.Context) {
+	export, err := h.adminService.ExportUserData(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	writeDataExport(c, export)
+}
+
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	err := h.adminService.DeleteUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user deleted"})
+}
+
+func (h *AdminHandler) AssignUserRole(c *gin.Context) {
+	id := c.Param("id")
+	var req struct {
+		Role domain.UserRole `json:"role" binding:"required"`
+	}
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	err := h.roleService.AssignRole(c.Request.Context(), c.GetString("userID"), id, domain.UserRole(strings.ToUpper(string(req.Role))))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "user role updated"})
+}
+
+func (h *AdminHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        roles,
+		"total":       len(roles),
+		"permissions": domain.AllPermissions,
+	})
+}
+
+func (h *AdminHandler) UpdateRole(c *gin.Context) {
+	name := domain.UserRole(strings.ToUpper(c.Param("name")))
+	var req struct {
+		Description string              `json:"description"`
+		Permissions []domain.Permission `json:"permissions"`
+	}
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(c.Request.Context(), c.GetString("userID"), name, req.Description, req.Permissions)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+}
+
 func (h *AdminHandler) UpdateOpportunity(c *gin.Context) {
 	id := c.Param("id")
 	var req domain.Opportunity
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) || !checkValid(c, validateOpportunity(&req)) {
 		return
 	}
 
 	existing, err := h.oppService.GetOpportunityByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "opportunity not found"})
+		c.Error(err)
 		return
 	}
 
@@ -124,19 +216,177 @@ func (h *AdminHandler) UpdateOpportunity(c *gin.Context) {
 
 	err = h.oppService.UpdateOpportunity(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update opportunity"})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "opportunity updated by admin"})
 }
 
+// DeleteOpportunity marks an opportunity as deleted, including admin-paused ones. Body: optional {"reason": "..."}
 func (h *AdminHandler) DeleteOpportunity(c *gin.Context) {
-	id := c.Param("id")
-	err := h.oppService.DeleteOpportunity(c.Request.Context(), id)
+	reason, ok := bindStatusReason(c)
+	if !ok {
+		return
+	}
+	err := h.oppService.DeleteOpportunity(c.Request.Context(), c.Param("id"), domain.ActorAdmin, c.GetString("userID"), reason)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete opportunity"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "opportunity deleted by admin"})
 }
+
+// ListPendingOpportunities lists opportunities waiting for review, oldest submission first.
+// With edited=true it lists live opportunities edited since their last approval instead, oldest edit first.
+// Filters: edited (true/false), hostId, type, city, flagged (true/false), flag (auto-screening code)
+func (h *AdminHandler) ListPendingOpportunities(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "20")
+	offsetStr := c.DefaultQuery("offset", "0")
+	limit, _ := strconv.ParseInt(limitStr, 10, 64)
+	offset, _ := strconv.ParseInt(offsetStr, 10, 64)
+
+	filter := repository.OpportunityReviewFilter{
+		HostID: c.Query("hostId"),
+		Type:   c.Query("type"),
+		City:   c.Query("city"),
+		Flag:   domain.ModerationFlagCode(c.Query("flag")),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if flagged, err := strconv.ParseBool(c.Query("flagged")); err == nil {
+		filter.Flagged = &flagged
+	}
+	filter.Edited, _ = strconv.ParseBool(c.Query("edited"))
+
+	opps, total, err := h.oppModeration.ListPending(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  managedOpportunities(opps),
+		"total": total,
+	})
+}
+
+// ReviewOpportunity approves or rejects a pending opportunity, or the edits made to a live one
+// (rejecting edits pauses the listing); the note becomes its StatusNote
+func (h *AdminHandler) ReviewOpportunity(c *gin.Context) {
+	var req struct {
+		Decision domain.OpportunityReviewDecision `json:"decision"`
+		Note     string                           `json:"note"`
+	}
+	if !bindJSON(c, &req) || !checkValid(c, validateOpportunityReview(req.Decision, req.Note)) {
+		return
+	}
+
+	opp, err := h.oppModeration.Review(c.Request.Context(), c.GetString("userID"), c.Param("id"), req.Decision, req.Note)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, opp.Managed())
+}
+
+// GetOpportunityChanges shows what changed since the opportunity was last approved
+func (h *AdminHandler) GetOpportunityChanges(c *gin.Context) {
+	changes, err := h.oppModeration.GetChanges(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// UpdateOpportunityStatus pauses or reinstates an opportunity; pending ones go through ReviewOpportunity
+func (h *AdminHandler) UpdateOpportunityStatus(c *gin.Context) {
+	var req struct {
+		Status domain.OpportunityStatus `json:"status"`
+		Reason string                   `json:"reason"`
+	}
+	if !bindJSON(c, &req) || !checkValid(c, validateOpportunityStatusChange(req.Status, req.Reason)) {
+		return
+	}
+
+	opp, err := h.oppModeration.ChangeStatus(c.Request.Context(), c.GetString("userID"), c.Param("id"), req.Status, req.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, opp.Managed())
+}
+
+func (h *AdminHandler) ListPendingHosts(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "20")
+	offsetStr := c.DefaultQuery("offset", "0")
+	limit, _ := strconv.ParseInt(limitStr, 10, 64)
+	offset, _ := strconv.ParseInt(offsetStr, 10, 64)
+
+	hosts, total, err := h.hostVerification.ListPending(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  hosts,
+		"total": total,
+	})
+}
+
+// GetHostDocument streams a host's verification document from the private bucket
+func (h *AdminHandler) GetHostDocument(c *gin.Context) {
+	content, err := h.hostVerification.GetDocumentContent(c.Request.Context(), c.Param("id"), c.Param("imageId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer content.Close()
+
+	c.Header("Cache-Control", "no-store")
+	if _, err := io.Copy(c.Writer, content); err != nil {
+		logger.ErrorContext(c.Request.Context(), "Failed to stream host document", "error", err)
+	}
+}
+
+func (h *AdminHandler) ReviewHost(c *gin.Context) {
+	var req struct {
+		Decision domain.HostReviewDecision `json:"decision"`
+		Note     string                    `json:"note"`
+	}
+	if !bindJSON(c, &req) || !checkValid(c, validateHostReview(req.Decision, req.Note)) {
+		return
+	}
+
+	host, err := h.hostVerification.Review(c.Request.Context(), c.GetString("userID"), c.Param("id"), req.Decision, req.Note)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, host)
+}
+
+// UpdateHostStatus suspends, reinstates or deactivates a host
+func (h *AdminHandler) UpdateHostStatus(c *gin.Context) {
+	var req struct {
+		Status domain.HostStatus `json:"status"`
+		Note   string            `json:"note"`
+	}
+	if !bindJSON(c, &req) || !checkValid(c, validateHostStatusChange(req.Status, req.Note)) {
+		return
+	}
+
+	host, err := h.hostService.ChangeStatus(c.Request.Context(), c.Param("id"), req.Status, domain.ActorAdmin, c.GetString("userID"), req.Note)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, host)
+}
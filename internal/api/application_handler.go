@@ -8,6 +8,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/service"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -22,15 +23,14 @@ func NewApplicationHandler(appService service.ApplicationService) *ApplicationHa
 
 func (h *ApplicationHandler) Create(c *gin.Context) {
 	var app domain.Application
-	if err := c.ShouldBindJSON(&app); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &app) {
 		return
 	}
 
 	// Get User ID
 	claims, exists := c.Get("userClaims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		c.Error(errcode.ErrUnauthorized)
 		return
 	}
 	mapClaims := claims.(jwt.MapClaims)
@@ -40,7 +40,7 @@ func (h *ApplicationHandler) Create(c *gin.Context) {
 
 	createdApp, err := h.appService.CreateApplication(c.Request.Context(), &app)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -67,15 +67,10 @@ func (h *ApplicationHandler) List(c *gin.Context) {
 		filter["hostId"] = objID
 	}
 
-	// Users can only see their own applications, Hosts can see applications for their opportunities
-	// This logic is complex for a simple List.
-	// For now, let's assume the client filters correctly or we add strict filtering based on user role.
-	// Ideally: if user is host, show received applications. If user is guest, show sent applications.
-	// I'll leave it open for now as per MVP speed, but in production this needs strict RBAC.
-
-	apps, total, err := h.appService.ListApplications(c.Request.Context(), filter, limit, offset)
+	// 只會列出自己送出的申請，以及可審核的接待主收到的申請
+	apps, total, err := h.appService.ListApplications(c.Request.Context(), c.GetString("userID"), filter, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list applications"})
+		c.Error(err)
 		return
 	}
 
@@ -87,9 +82,9 @@ func (h *ApplicationHandler) List(c *gin.Context) {
 
 func (h *ApplicationHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
-	app, err := h.appService.GetApplicationByID(c.Request.Context(), id)
+	app, err := h.appService.GetApplicationByID(c.Request.Context(), id, c.GetString("userID"))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "application not found"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, app)
@@ -101,8 +96,7 @@ func (h *ApplicationHandler) UpdateStatus(c *gin.Context) {
 		Status domain.ApplicationStatus `json:"status"`
 		Note   string                   `json:"note"`
 	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
@@ -112,7 +106,7 @@ func (h *ApplicationHandler) UpdateStatus(c *gin.Context) {
 
 	err := h.appService.UpdateApplicationStatus(c.Request.Context(), id, req.Status, req.Note, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -128,7 +122,7 @@ func (h *ApplicationHandler) Delete(c *gin.Context) {
 
 	err := h.appService.DeleteApplication(c.Request.Context(), id, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -22,11 +22,7 @@ func (h *BookmarkHandler) AddBookmark(c *gin.Context) {
 
 	err := h.bookmarkService.AddBookmark(c.Request.Context(), userID, opportunityID)
 	if err != nil {
-		if err == service.ErrBookmarkAlreadyExists {
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -39,7 +35,7 @@ func (h *BookmarkHandler) RemoveBookmark(c *gin.Context) {
 
 	err := h.bookmarkService.RemoveBookmark(c.Request.Context(), userID, opportunityID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -55,7 +51,7 @@ func (h *BookmarkHandler) ListBookmarks(c *gin.Context) {
 
 	bookmarks, total, err := h.bookmarkService.ListUserBookmarks(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list bookmarks"})
+		c.Error(err)
 		return
 	}
 
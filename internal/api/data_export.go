@@ -0,0 +1,55 @@
+package api
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+)
+
+// writeDataExport 將個人資料匯出寫入回應。
+// 預設為每類資料一個 JSON 檔的 ZIP 壓縮檔，?format=json 時回傳單一 JSON。
+func writeDataExport(c *gin.Context, export *domain.DataExport) {
+	if c.Query("format") == "json" {
+		c.JSON(http.StatusOK, export)
+		return
+	}
+
+	filename := fmt.Sprintf("taiwanstay-export-%s-%s.zip", export.User.ID, export.GeneratedAt.UTC().Format("20060102"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"user.json", export.User},
+		{"applications.json", export.Applications},
+		{"bookmarks.json", export.Bookmarks},
+		{"notifications.json", export.Notifications},
+		{"images.json", export.Images},
+	}
+
+	zw := zip.NewWriter(c.Writer)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			logger.Error("Failed to write data export", "userId", export.User.ID, "error", err)
+			return
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			logger.Error("Failed to write data export", "userId", export.User.ID, "error", err)
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		logger.Error("Failed to write data export", "userId", export.User.ID, "error", err)
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taiwanstay/taiwanstay-back/internal/service"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrorHandler 是一個 Gin 中介軟體，將 handler 以 c.Error 回報的錯誤轉為統一的錯誤格式：
+//
+//	{"error": {"code": "EMAIL_ALREADY_EXISTS", "message": "...", "requestId": "..."}}
+//
+// 客戶端應依 code 判斷錯誤類型，message 僅供顯示，並依請求的語系翻譯。
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		writeError(c, c.Errors.Last().Err)
+	}
+}
+
+// Recovery 攔截 panic 並以統一的錯誤格式回傳 500
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		abortWithError(c, fmt.Errorf("panic: %v", recovered))
+	})
+}
+
+// abortWithError 中斷後續的 handler 並立即回傳錯誤，供中介軟體使用
+func abortWithError(c *gin.Context, err error) {
+	c.Abort()
+	writeError(c, err)
+}
+
+// writeError 將錯誤轉為 errcode 格式並寫入響應。
+// 未預期的錯誤一律回傳 INTERNAL_ERROR，不將內部錯誤訊息暴露給客戶端。
+func writeError(c *gin.Context, err error) {
+	e := toErrcode(err)
+	requestID := c.GetString("RequestID")
+
+	if e.Status >= 500 {
+		logger.Error("Request failed", "method", c.Request.Method, "path", c.Request.URL.Path, "request_id", requestID, "error", err)
+	}
+
+	var rateLimited *service.RateLimitedError
+	var locked *service.AccountLockedError
+	switch {
+	case errors.As(err, &rateLimited):
+		setRetryAfter(c, rateLimited.RetryAfter)
+	case errors.As(err, &locked):
+		setRetryAfter(c, locked.RetryAfter)
+	}
+
+	resp := e.WithRequestID(requestID)
+	locale, ok := i18n.FromContext(c.Request.Context())
+	if !ok {
+		locale = i18n.DefaultLocale
+	}
+	if msg, ok := i18n.Lookup(locale, "error."+e.Code); ok {
+		resp.Message = msg
+	}
+	c.JSON(e.Status, gin.H{"error": resp})
+}
+
+func toErrcode(err error) *errcode.Error {
+	var e *errcode.Error
+	switch {
+	case errors.As(err, &e):
+		return e
+	case errors.Is(err, mongo.ErrNoDocuments), errors.Is(err, primitive.ErrInvalidHex):
+		return errcode.ErrNotFound
+	default:
+		return errcode.ErrInternalServer
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/taiwanstay/taiwanstay-back/internal/service"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger.InitLogger("error")
+
+	router := gin.New()
+	router.Use(Recovery())
+	router.Use(Logger())
+	router.Use(Locale())
+	router.Use(ErrorHandler())
+	router.GET("/conflict", func(c *gin.Context) {
+		c.Error(service.ErrEmailAlreadyExists)
+	})
+	router.GET("/not-found", func(c *gin.Context) {
+		c.Error(mongo.ErrNoDocuments)
+	})
+	router.GET("/internal", func(c *gin.Context) {
+		c.Error(errors.New("connection refused"))
+	})
+	router.GET("/locked", func(c *gin.Context) {
+		c.Error(&service.AccountLockedError{RetryAfter: 90 * time.Second})
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	do := func(path string, header ...string) (*httptest.ResponseRecorder, errcode.Error) {
+		req, _ := http.NewRequest("GET", path, nil)
+		if len(header) > 0 {
+			req.Header.Set("Accept-Language", header[0])
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response struct {
+			Error errcode.Error `json:"error"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response.Error
+	}
+
+	// 1. Typed service error
+	w, e := do("/conflict")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "EMAIL_ALREADY_EXISTS", e.Code)
+	assert.NotEmpty(t, e.RequestID)
+	assert.Equal(t, w.Header().Get("X-Request-ID"), e.RequestID)
+
+	// 2. Message follows Accept-Language, code does not
+	w, e = do("/conflict", "ja-JP,ja;q=0.9")
+	assert.Equal(t, "EMAIL_ALREADY_EXISTS", e.Code)
+	assert.Equal(t, "このメールアドレスはすでに登録されています", e.Message)
+	assert.Equal(t, "ja", w.Header().Get("Content-Language"))
+
+	// 3. Missing document
+	w, e = do("/not-found")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "NOT_FOUND", e.Code)
+
+	// 4. Unexpected errors are not exposed
+	w, e = do("/internal")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "INTERNAL_ERROR", e.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+
+	// 5. Lockout carries Retry-After
+	w, e = do("/locked")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "ACCOUNT_LOCKED", e.Code)
+	assert.Equal(t, "90", w.Header().Get("Retry-After"))
+
+	// 6. Panic
+	w, e = do("/panic")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "INTERNAL_ERROR", e.Code)
+}
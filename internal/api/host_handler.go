@@ -7,28 +7,35 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/service"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/validation"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type HostHandler struct {
-	hostService service.HostService
+	hostService         service.HostService
+	verificationService service.HostVerificationService
+	memberService       service.HostMemberService
 }
 
-func NewHostHandler(hostService service.HostService) *HostHandler {
-	return &HostHandler{hostService: hostService}
+func NewHostHandler(hostService service.HostService, verificationService service.HostVerificationService, memberService service.HostMemberService) *HostHandler {
+	return &HostHandler{
+		hostService:         hostService,
+		verificationService: verificationService,
+		memberService:       memberService,
+	}
 }
 
 func (h *HostHandler) Create(c *gin.Context) {
 	var host domain.Host
-	if err := c.ShouldBindJSON(&host); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &host) || !checkValid(c, validateHost(&host)) {
 		return
 	}
 
 	// Get User ID from context
 	claims, exists := c.Get("userClaims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		c.Error(errcode.ErrUnauthorized)
 		return
 	}
 	mapClaims := claims.(jwt.MapClaims)
@@ -38,18 +45,29 @@ func (h *HostHandler) Create(c *gin.Context) {
 
 	createdHost, err := h.hostService.CreateHost(c.Request.Context(), &host)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create host"})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, createdHost)
 }
 
+// GetBySlug 回傳公開的接待主頁面，不需要登入
+func (h *HostHandler) GetBySlug(c *gin.Context) {
+	page, err := h.hostService.GetPublicHost(c.Request.Context(), c.Param("slug"), contentLocale(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
 func (h *HostHandler) GetMe(c *gin.Context) {
 	// Get User ID from context
 	claims, exists := c.Get("userClaims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		c.Error(errcode.ErrUnauthorized)
 		return
 	}
 	mapClaims := claims.(jwt.MapClaims)
@@ -57,7 +75,7 @@ func (h *HostHandler) GetMe(c *gin.Context) {
 
 	host, err := h.hostService.GetHostByUserID(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "host profile not found"})
+		c.Error(err)
 		return
 	}
 
@@ -68,22 +86,21 @@ func (h *HostHandler) UpdateMe(c *gin.Context) {
 	// Get User ID from context
 	claims, exists := c.Get("userClaims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		c.Error(errcode.ErrUnauthorized)
 		return
 	}
 	mapClaims := claims.(jwt.MapClaims)
 	userID := mapClaims["sub"].(string)
 
-	// First get existing host to ensure ownership
-	existingHost, err := h.hostService.GetHostByUserID(c.Request.Context(), userID)
+	// First get existing host to ensure the user may edit it (owner or editor)
+	existingHost, err := h.hostService.GetManagedHost(c.Request.Context(), userID, domain.MemberPermissionEditListings)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "host profile not found"})
+		c.Error(err)
 		return
 	}
 
 	var updateData domain.Host
-	if err := c.ShouldBindJSON(&updateData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &updateData) || !checkValid(c, validateHost(&updateData)) {
 		return
 	}
 
@@ -93,9 +110,177 @@ func (h *HostHandler) UpdateMe(c *gin.Context) {
 
 	err = h.hostService.UpdateHost(c.Request.Context(), existingHost.ID.Hex(), &updateData)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update host"})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "host updated"})
 }
+
+// UpdateStatus 由接待主暫停 (INACTIVE) 或重新開放 (ACTIVE) 接待
+func (h *HostHandler) UpdateStatus(c *gin.Context) {
+	var req struct {
+		Status domain.HostStatus `json:"status"`
+		Note   string            `json:"note"`
+	}
+	if !bindJSON(c, &req) || !checkValid(c, validateHostStatusChange(req.Status, req.Note)) {
+		return
+	}
+
+	userID := c.GetString("userID")
+	host, err := h.hostService.GetManagedHost(c.Request.Context(), userID, domain.MemberPermissionManageTeam)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	host, err = h.hostService.ChangeStatus(c.Request.Context(), host.ID.Hex(), req.Status, domain.ActorHost, userID, req.Note)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, host)
+}
+
+// UploadDocument 上傳驗證文件 (multipart: type, document)
+func (h *HostHandler) UploadDocument(c *gin.Context) {
+	docType := domain.HostDocumentType(c.PostForm("type"))
+	file, header, fileErr := c.Request.FormFile("document")
+
+	v := validation.New()
+	v.Check(docType.IsValid(), "type", validation.RuleOneOf, "must be one of BUSINESS_REGISTRATION, FARM_LICENSE, ID_CARD")
+	v.Check(fileErr == nil, "document", validation.RuleRequired, "is required")
+	if !checkValid(c, v.Err()) {
+		return
+	}
+	defer file.Close()
+
+	doc, err := h.verificationService.UploadDocument(c.Request.Context(), c.GetString("userID"), docType, file, header)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, doc)
+}
+
+// SubmitVerification 將接待主送審
+func (h *HostHandler) SubmitVerification(c *gin.Context) {
+	host, err := h.verificationService.SubmitForReview(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, host)
+}
+
+// ListMembers 列出接待主的管理者 (建立者與共同管理者)
+func (h *HostHandler) ListMembers(c *gin.Context) {
+	members, err := h.memberService.ListMembers(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+func (h *HostHandler) UpdateMember(c *gin.Context) {
+	var req struct {
+		Role domain.HostMemberRole `json:"role"`
+	}
+	if !bindJSON(c, &req) || !checkValid(c, validateHostMember("", req.Role, false)) {
+		return
+	}
+
+	err := h.memberService.UpdateMemberRole(c.Request.Context(), c.GetString("userID"), c.Param("userId"), req.Role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member updated"})
+}
+
+// RemoveMember 移除共同管理者，userId 為自己時代表退出
+func (h *HostHandler) RemoveMember(c *gin.Context) {
+	err := h.memberService.RemoveMember(c.Request.Context(), c.GetString("userID"), c.Param("userId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member removed"})
+}
+
+// Invite 以 Email 邀請共同管理者
+func (h *HostHandler) Invite(c *gin.Context) {
+	var req struct {
+		Email string                `json:"email"`
+		Role  domain.HostMemberRole `json:"role"`
+	}
+	if !bindJSON(c, &req) || !checkValid(c, validateHostMember(req.Email, req.Role, true)) {
+		return
+	}
+
+	inv, err := h.memberService.Invite(c.Request.Context(), c.GetString("userID"), req.Email, req.Role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, inv)
+}
+
+func (h *HostHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.memberService.ListInvitations(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+func (h *HostHandler) RevokeInvitation(c *gin.Context) {
+	err := h.memberService.RevokeInvitation(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation revoked"})
+}
+
+// ListMyInvitations 列出寄給目前使用者的共同管理者邀請
+func (h *HostHandler) ListMyInvitations(c *gin.Context) {
+	invitations, err := h.memberService.ListMyInvitations(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+func (h *HostHandler) AcceptInvitation(c *gin.Context) {
+	host, err := h.memberService.AcceptInvitation(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, host)
+}
+
+func (h *HostHandler) DeclineInvitation(c *gin.Context) {
+	err := h.memberService.DeclineInvitation(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation declined"})
+}
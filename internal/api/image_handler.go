@@ -8,21 +8,24 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/service"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/validation"
 )
 
 type ImageHandler struct {
 	imageService service.ImageService
+	permissions  PermissionChecker
 }
 
-func NewImageHandler(imageService service.ImageService) *ImageHandler {
-	return &ImageHandler{imageService: imageService}
+func NewImageHandler(imageService service.ImageService, permissions PermissionChecker) *ImageHandler {
+	return &ImageHandler{imageService: imageService, permissions: permissions}
 }
 
 func (h *ImageHandler) Upload(c *gin.Context) {
 	// 1. Get User ID from context
 	claims, exists := c.Get("userClaims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		c.Error(errcode.ErrUnauthorized)
 		return
 	}
 	mapClaims := claims.(jwt.MapClaims)
@@ -31,7 +34,7 @@ func (h *ImageHandler) Upload(c *gin.Context) {
 	// 2. Get File
 	file, header, err := c.Request.FormFile("image")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "image file is required"})
+		c.Error(errcode.Validation([]errcode.FieldError{{Field: "image", Rule: validation.RuleRequired, Message: "is required"}}).WithCause(err))
 		return
 	}
 	defer file.Close()
@@ -39,7 +42,7 @@ func (h *ImageHandler) Upload(c *gin.Context) {
 	// 3. Call Service
 	image, err := h.imageService.UploadImage(c.Request.Context(), file, header, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to upload image"})
+		c.Error(err)
 		return
 	}
 
@@ -48,35 +51,74 @@ func (h *ImageHandler) Upload(c *gin.Context) {
 
 func (h *ImageHandler) GetPrivateImage(c *gin.Context) {
 	id := c.Param("id")
-	// TODO: Check permission (Owner or Admin)
+
+	image, err := h.imageService.GetImage(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	allowed, err := h.canReadImage(c, image)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !allowed {
+		// 不透露檔案是否存在
+		c.Error(service.ErrImageNotFound)
+		return
+	}
 
 	content, err := h.imageService.GetImageContent(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "image not found"})
+		c.Error(err)
 		return
 	}
 	defer content.Close()
 
+	if image.ContentType != "" {
+		c.Header("Content-Type", image.ContentType)
+	}
+
 	_, err = io.Copy(c.Writer, content)
 	if err != nil {
 		// Log error
 	}
 }
 
+// canReadImage 判斷目前使用者能否讀取圖片。
+// 私有 bucket 中的檔案只有上傳者可以讀取，或由審核者讀取：
+// 私有文件 (接待主驗證文件) 需要 hosts:verify，審核中或已拒絕的圖片需要 images:review。
+func (h *ImageHandler) canReadImage(c *gin.Context, image *domain.Image) (bool, error) {
+	if !image.InPrivateBucket() || image.UserID.Hex() == c.GetString("userID") {
+		return true, nil
+	}
+
+	permission := domain.PermissionImagesReview
+	if image.Private {
+		permission = domain.PermissionHostsVerify
+	}
+	claims, _ := c.Get("userClaims")
+	mapClaims, _ := claims.(jwt.MapClaims)
+	role, _ := mapClaims["role"].(string)
+	if role == "" {
+		return false, nil
+	}
+	return h.permissions.HasPermission(c.Request.Context(), domain.UserRole(role), permission)
+}
+
 func (h *ImageHandler) UpdateStatus(c *gin.Context) {
 	id := c.Param("id")
 	var req struct {
 		Status string `json:"status" binding:"required,oneof=PENDING APPROVED REJECTED"`
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	err := h.imageService.UpdateImageStatus(c.Request.Context(), id, domain.ImageStatus(req.Status))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update status"})
+		c.Error(err)
 		return
 	}
 
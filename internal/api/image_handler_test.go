@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/service"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// stubImageService 以記憶體中的圖片回應讀取，其餘操作不會被呼叫
+type stubImageService struct {
+	service.ImageService
+	images map[string]*domain.Image
+}
+
+func (s *stubImageService) GetImage(ctx context.Context, id string) (*domain.Image, error) {
+	image, ok := s.images[id]
+	if !ok {
+		return nil, service.ErrImageNotFound
+	}
+	return image, nil
+}
+
+func (s *stubImageService) GetImageContent(ctx context.Context, id string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("content-" + id)), nil
+}
+
+func TestGetPrivateImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	uploader := primitive.NewObjectID()
+	other := primitive.NewObjectID()
+
+	images := &stubImageService{images: map[string]*domain.Image{
+		"pending":  {UserID: uploader, Status: domain.ImageStatusPending},
+		"rejected": {UserID: uploader, Status: domain.ImageStatusRejected},
+		"document": {UserID: uploader, Status: domain.ImageStatusPending, Private: true, ContentType: "application/pdf"},
+		"approved": {UserID: uploader, Status: domain.ImageStatusApproved},
+	}}
+	checker := stubPermissionChecker{
+		domain.RoleModerator: {domain.PermissionImagesReview},
+		"VERIFIER":           {domain.PermissionHostsVerify},
+	}
+	handler := NewImageHandler(images, checker)
+
+	get := func(userID primitive.ObjectID, role domain.UserRole, imageID string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(ErrorHandler())
+		router.Use(func(c *gin.Context) {
+			c.Set("userID", userID.Hex())
+			c.Set("userClaims", jwt.MapClaims{"sub": userID.Hex(), "role": string(role)})
+		})
+		router.GET("/images/private/:id", handler.GetPrivateImage)
+
+		req, _ := http.NewRequest("GET", "/images/private/"+imageID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	cases := []struct {
+		name     string
+		userID   primitive.ObjectID
+		role     domain.UserRole
+		imageID  string
+		expected int
+	}{
+		{"Uploader Reads Pending Upload", uploader, domain.RoleUser, "pending", http.StatusOK},
+		{"Uploader Reads Own Document", uploader, domain.RoleUser, "document", http.StatusOK},
+		{"Other User Cannot Read Pending Upload", other, domain.RoleUser, "pending", http.StatusNotFound},
+		{"Other User Cannot Read Rejected Upload", other, domain.RoleUser, "rejected", http.StatusNotFound},
+		{"Other User Cannot Read Document", other, domain.RoleUser, "document", http.StatusNotFound},
+		{"Image Reviewer Reads Pending Upload", other, domain.RoleModerator, "pending", http.StatusOK},
+		{"Image Reviewer Cannot Read Document", other, domain.RoleModerator, "document", http.StatusNotFound},
+		{"Host Verifier Reads Document", other, "VERIFIER", "document", http.StatusOK},
+		{"Approved Image Is Readable", other, domain.RoleUser, "approved", http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := get(tc.userID, tc.role, tc.imageID)
+
+			assert.Equal(t, tc.expected, w.Code)
+			if tc.expected == http.StatusOK {
+				assert.Equal(t, "content-"+tc.imageID, w.Body.String())
+			}
+		})
+	}
+}
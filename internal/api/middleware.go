@@ -1,9 +1,9 @@
 package api
 
 import (
-	"net/http"
+	"context"
+	"fmt"
 	"strings"
-
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,6 +11,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
 	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
 )
 
@@ -41,18 +43,53 @@ func Logger() gin.HandlerFunc {
 	}
 }
 
-// AuthMiddleware 是一個 Gin 中介軟體，用於驗證 JWT token
-func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
+// Locale 是一個 Gin 中介軟體，依 Accept-Language 決定響應的語系並存入 request context
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.DefaultLocale
+		if header := c.GetHeader("Accept-Language"); header != "" {
+			locale = i18n.Negotiate(header)
+			c.Request = c.Request.WithContext(i18n.WithLocale(c.Request.Context(), locale))
+		}
+		c.Header("Content-Language", string(locale))
+		c.Next()
+	}
+}
+
+// contentLocale 回傳使用者內容 (工作機會、接待主介紹) 要使用的語系。
+// 以 ?locale= 指定的語系優先，其次為 Accept-Language；都沒有時回傳空字串，表示使用內容的預設語系。
+func contentLocale(c *gin.Context) string {
+	if l, ok := i18n.Parse(c.Query("locale")); ok {
+		return string(l)
+	}
+	if l, ok := i18n.FromContext(c.Request.Context()); ok {
+		return string(l)
+	}
+	return ""
+}
+
+var (
+	errInvalidToken     = errcode.Unauthorized("INVALID_TOKEN", "invalid token")
+	errPermissionDenied = errcode.Forbidden("PERMISSION_DENIED", "access denied: missing permission")
+)
+
+// SessionValidator 用於確認 access token 所屬的 session 是否仍然有效
+type SessionValidator interface {
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+// AuthMiddleware 是一個 Gin 中介軟體，用於驗證 JWT token 及其所屬的 session
+func AuthMiddleware(cfg *config.Config, sessions SessionValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+			abortWithError(c, errcode.Unauthorized("AUTH_HEADER_REQUIRED", "authorization header is required"))
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer {token}"})
+			abortWithError(c, errcode.Unauthorized("INVALID_AUTH_HEADER", "authorization header format must be Bearer {token}"))
 			return
 		}
 
@@ -67,40 +104,125 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		})
 
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			abortWithError(c, errInvalidToken)
+			return
+		}
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok || !token.Valid {
+			abortWithError(c, errInvalidToken)
+			return
+		}
+
+		userID, _ := claims["sub"].(string)
+		sessionID, _ := claims["sid"].(string)
+		if userID == "" || sessionID == "" {
+			abortWithError(c, errInvalidToken)
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			// 將 claims 存入 context，方便後續 handler 使用
-			c.Set("userClaims", claims)
-		} else {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+		// 確認 session 未被撤銷 (登出、token 重複使用、停權等)
+		active, err := sessions.IsSessionActive(c.Request.Context(), sessionID)
+		if err != nil {
+			abortWithError(c, fmt.Errorf("validate session %s: %w", sessionID, err))
+			return
+		}
+		if !active {
+			abortWithError(c, errcode.Unauthorized("SESSION_REVOKED", "session has been revoked"))
 			return
 		}
 
+		// 將 claims 存入 context，方便後續 handler 使用
+		c.Set("userClaims", claims)
+		c.Set("userID", userID)
+		c.Set("sessionID", sessionID)
+
 		c.Next()
 	}
 }
 
-// AdminAuthMiddleware 是一個 Gin 中介軟體，用於驗證使用者是否具有管理員權限
-func AdminAuthMiddleware() gin.HandlerFunc {
+// OptionalAuthMiddleware 在帶有 Authorization header 時驗證 token，沒有時以未登入身分繼續。
+// 用於同時提供給訪客與登入使用者、但內容依身分不同的路由。
+func OptionalAuthMiddleware(cfg *config.Config, sessions SessionValidator) gin.HandlerFunc {
+	authenticate := AuthMiddleware(cfg, sessions)
 	return func(c *gin.Context) {
-		claims, exists := c.Get("userClaims")
-		if !exists {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied: user claims not found"})
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
 			return
 		}
+		authenticate(c)
+	}
+}
 
-		mapClaims, ok := claims.(jwt.MapClaims)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied: invalid claims format"})
+// PermissionChecker 用於確認角色是否擁有指定權限
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, role domain.UserRole, permission domain.Permission) (bool, error)
+}
+
+// RequirePermission 是一個 Gin 中介軟體，限制只有角色擁有所有指定權限的使用者才能存取。
+// 必須放在 AuthMiddleware 之後使用。
+func RequirePermission(checker PermissionChecker, permissions ...domain.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, _ := c.Get("userClaims")
+		mapClaims, _ := claims.(jwt.MapClaims)
+		role, _ := mapClaims["role"].(string)
+		if role == "" {
+			abortWithError(c, errcode.ErrForbidden)
+			return
+		}
+
+		for _, permission := range permissions {
+			allowed, err := checker.HasPermission(c.Request.Context(), domain.UserRole(role), permission)
+			if err != nil {
+				abortWithError(c, fmt.Errorf("check permission %s for role %s: %w", permission, role, err))
+				return
+			}
+			if !allowed {
+				abortWithError(c, errPermissionDenied.WithMeta("permission", permission))
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// EmailVerificationChecker 用於確認使用者的 email 是否已驗證
+type EmailVerificationChecker interface {
+	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+}
+
+// RequireVerifiedEmail 是一個 Gin 中介軟體，限制只有 email 已驗證的使用者才能執行操作。
+// 必須放在 AuthMiddleware 之後使用。
+func RequireVerifiedEmail(checker EmailVerificationChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		verified, err := checker.IsEmailVerified(c.Request.Context(), c.GetString("userID"))
+		if err != nil {
+			abortWithError(c, fmt.Errorf("check email verification for user %s: %w", c.GetString("userID"), err))
+			return
+		}
+		if !verified {
+			abortWithError(c, errcode.Forbidden("EMAIL_NOT_VERIFIED", "email verification required"))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AdminMFAMiddleware 在設定要求時，限制後台人員必須以通過兩步驟驗證的 session 存取管理功能。
+// 必須放在 AuthMiddleware 之後使用。
+func AdminMFAMiddleware(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !cfg.Auth.RequireAdminMFA {
+			c.Next()
 			return
 		}
 
-		role, ok := mapClaims["role"].(string)
-		if !ok || domain.UserRole(role) != domain.RoleAdmin {
-			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Access denied: administrator privileges required"})
+		claims, _ := c.Get("userClaims")
+		mapClaims, _ := claims.(jwt.MapClaims)
+		if verified, _ := mapClaims["mfa"].(bool); !verified {
+			abortWithError(c, errcode.Forbidden("MFA_REQUIRED", "two-factor authentication required"))
 			return
 		}
 
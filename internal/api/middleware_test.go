@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,9 +10,17 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/pkg/config"
 )
 
+// stubSessionValidator 以固定的 session 狀態回應驗證
+type stubSessionValidator map[string]bool
+
+func (s stubSessionValidator) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return s[sessionID], nil
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	cfg := &config.Config{
@@ -19,9 +28,10 @@ func TestAuthMiddleware(t *testing.T) {
 			JWTSecret: "test-secret",
 		},
 	}
+	sessions := stubSessionValidator{"active-session": true, "revoked-session": false}
 
 	router := gin.New()
-	router.Use(AuthMiddleware(cfg))
+	router.Use(AuthMiddleware(cfg, sessions))
 	router.GET("/test", func(c *gin.Context) {
 		c.Status(http.StatusOK)
 	})
@@ -42,6 +52,7 @@ func TestAuthMiddleware(t *testing.T) {
 	// 3. Valid Token
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub": "123",
+		"sid": "active-session",
 		"exp": time.Now().Add(time.Hour).Unix(),
 	})
 	tokenString, _ := token.SignedString([]byte("test-secret"))
@@ -51,48 +62,148 @@ func TestAuthMiddleware(t *testing.T) {
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
-}
 
-func TestAdminAuthMiddleware(t *testing.T) {
-	gin.SetMode(gin.TestMode)
-	cfg := &config.Config{
-		Server: config.ServerConfig{
-			JWTSecret: "test-secret",
-		},
-	}
-
-	router := gin.New()
-	router.Use(AuthMiddleware(cfg))
-	router.Use(AdminAuthMiddleware())
-	router.GET("/admin", func(c *gin.Context) {
-		c.Status(http.StatusOK)
-	})
-
-	// 1. User Role
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub":  "123",
-		"role": "USER",
-		"exp":  time.Now().Add(time.Hour).Unix(),
+	// 4. Revoked Session
+	token = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "123",
+		"sid": "revoked-session",
+		"exp": time.Now().Add(time.Hour).Unix(),
 	})
-	tokenString, _ := token.SignedString([]byte("test-secret"))
+	tokenString, _ = token.SignedString([]byte("test-secret"))
 
-	req, _ := http.NewRequest("GET", "/admin", nil)
+	req, _ = http.NewRequest("GET", "/test", nil)
 	req.Header.Set("Authorization", "Bearer "+tokenString)
-	w := httptest.NewRecorder()
+	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-	// 2. Admin Role
+	// 5. Missing Session ID
 	token = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub":  "456",
-		"role": "ADMIN",
-		"exp":  time.Now().Add(time.Hour).Unix(),
+		"sub": "123",
+		"exp": time.Now().Add(time.Hour).Unix(),
 	})
 	tokenString, _ = token.SignedString([]byte("test-secret"))
 
-	req, _ = http.NewRequest("GET", "/admin", nil)
+	req, _ = http.NewRequest("GET", "/test", nil)
 	req.Header.Set("Authorization", "Bearer "+tokenString)
 	w = httptest.NewRecorder()
 	router.ServeHTTP(w, req)
-	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+// stubEmailVerificationChecker 以固定的驗證狀態回應
+type stubEmailVerificationChecker map[string]bool
+
+func (s stubEmailVerificationChecker) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	return s[userID], nil
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	checker := stubEmailVerificationChecker{"verified-user": true}
+
+	newRouter := func(userID string) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) { c.Set("userID", userID) })
+		router.Use(RequireVerifiedEmail(checker))
+		router.POST("/test", func(c *gin.Context) {
+			c.Status(http.StatusCreated)
+		})
+		return router
+	}
+
+	// 1. Verified
+	req, _ := http.NewRequest("POST", "/test", nil)
+	w := httptest.NewRecorder()
+	newRouter("verified-user").ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// 2. Unverified
+	req, _ = http.NewRequest("POST", "/test", nil)
+	w = httptest.NewRecorder()
+	newRouter("unverified-user").ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAdminMFAMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(required bool, claims jwt.MapClaims) *gin.Engine {
+		cfg := &config.Config{Auth: config.AuthConfig{RequireAdminMFA: required}}
+		router := gin.New()
+		router.Use(func(c *gin.Context) { c.Set("userClaims", claims) })
+		router.Use(AdminMFAMiddleware(cfg))
+		router.GET("/test", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	cases := []struct {
+		name     string
+		required bool
+		claims   jwt.MapClaims
+		expected int
+	}{
+		{"Not Required", false, jwt.MapClaims{"role": "ADMIN"}, http.StatusOK},
+		{"Required Without MFA", true, jwt.MapClaims{"role": "ADMIN", "mfa": false}, http.StatusForbidden},
+		{"Required With MFA", true, jwt.MapClaims{"role": "ADMIN", "mfa": true}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/test", nil)
+			w := httptest.NewRecorder()
+			newRouter(tc.required, tc.claims).ServeHTTP(w, req)
+			assert.Equal(t, tc.expected, w.Code)
+		})
+	}
+}
+
+// stubPermissionChecker 以固定的角色權限回應檢查
+type stubPermissionChecker map[domain.UserRole][]domain.Permission
+
+func (s stubPermissionChecker) HasPermission(ctx context.Context, role domain.UserRole, permission domain.Permission) (bool, error) {
+	for _, p := range s[role] {
+		if p == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	checker := stubPermissionChecker{
+		domain.RoleModerator: {domain.PermissionImagesReview},
+	}
+
+	newRouter := func(claims jwt.MapClaims, permissions ...domain.Permission) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) { c.Set("userClaims", claims) })
+		router.Use(RequirePermission(checker, permissions...))
+		router.GET("/test", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	cases := []struct {
+		name        string
+		claims      jwt.MapClaims
+		permissions []domain.Permission
+		expected    int
+	}{
+		{"Granted", jwt.MapClaims{"role": "MODERATOR"}, []domain.Permission{domain.PermissionImagesReview}, http.StatusOK},
+		{"Missing Permission", jwt.MapClaims{"role": "MODERATOR"}, []domain.Permission{domain.PermissionUsersSuspend}, http.StatusForbidden},
+		{"Requires All", jwt.MapClaims{"role": "MODERATOR"}, []domain.Permission{domain.PermissionImagesReview, domain.PermissionUsersSuspend}, http.StatusForbidden},
+		{"No Role", jwt.MapClaims{}, []domain.Permission{domain.PermissionImagesReview}, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/test", nil)
+			w := httptest.NewRecorder()
+			newRouter(tc.claims, tc.permissions...).ServeHTTP(w, req)
+			assert.Equal(t, tc.expected, w.Code)
+		})
+	}
 }
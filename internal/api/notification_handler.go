@@ -29,7 +29,7 @@ func (h *NotificationHandler) List(c *gin.Context) {
 
 	notifs, total, err := h.notifService.ListNotifications(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list notifications"})
+		c.Error(err)
 		return
 	}
 
@@ -47,7 +47,7 @@ func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 
 	err := h.notifService.MarkAsRead(c.Request.Context(), id, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -61,7 +61,7 @@ func (h *NotificationHandler) MarkAllAsRead(c *gin.Context) {
 
 	err := h.notifService.MarkAllAsRead(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
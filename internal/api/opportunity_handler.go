@@ -1,7 +1,7 @@
 package api
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -10,6 +10,7 @@ import (
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/repository"
 	"github.com/taiwanstay/taiwanstay-back/internal/service"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -17,53 +18,123 @@ import (
 type OpportunityHandler struct {
 	oppService  service.OpportunityService
 	hostService service.HostService
+	orgService  service.OrganizationService
 }
 
-func NewOpportunityHandler(oppService service.OpportunityService, hostService service.HostService) *OpportunityHandler {
+var (
+	errNotAHost            = errcode.Forbidden("NOT_A_HOST", "user is not a host")
+	errNotOpportunityOwner = errcode.Forbidden("NOT_OPPORTUNITY_OWNER", "you do not own this opportunity")
+	errCannotManageHost    = errcode.Forbidden("CANNOT_MANAGE_HOST", "you cannot manage listings for this host")
+)
+
+// hostRequired 將找不到 host 的錯誤轉為 403，其餘錯誤原樣回傳
+func hostRequired(err error) error {
+	if errors.Is(err, service.ErrHostNotFound) {
+		return errNotAHost.WithCause(err)
+	}
+	return err
+}
+
+func NewOpportunityHandler(oppService service.OpportunityService, hostService service.HostService, orgService service.OrganizationService) *OpportunityHandler {
 	return &OpportunityHandler{
 		oppService:  oppService,
 		hostService: hostService,
+		orgService:  orgService,
+	}
+}
+
+// canEditListings 回傳使用者是否可以編輯接待主的工作機會 (接待主的 OWNER / EDITOR 或具權限的組織成員)
+func (h *OpportunityHandler) canEditListings(c *gin.Context, userID string, hostID primitive.ObjectID) (bool, error) {
+	host, err := h.hostService.GetHostByID(c.Request.Context(), hostID.Hex())
+	if err != nil {
+		return false, err
+	}
+	return h.orgService.CanManageHost(c.Request.Context(), userID, host, domain.MemberPermissionEditListings)
+}
+
+// canViewListings 回傳使用者是否可以查看接待主未公開的工作機會 (接待主成員或具權限的組織成員)
+func (h *OpportunityHandler) canViewListings(c *gin.Context, userID string, hostID primitive.ObjectID) (bool, error) {
+	host, err := h.hostService.GetHostByID(c.Request.Context(), hostID.Hex())
+	if err != nil {
+		return false, err
 	}
+	return h.orgService.CanManageHost(c.Request.Context(), userID, host, domain.MemberPermissionViewDashboard)
 }
 
 func (h *OpportunityHandler) Create(c *gin.Context) {
 	var opp domain.Opportunity
-	if err := c.ShouldBindJSON(&opp); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &opp) || !checkValid(c, validateOpportunity(&opp)) {
 		return
 	}
 
-	// Get User ID -> Host ID
+	// Get User ID
 	claims, exists := c.Get("userClaims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		c.Error(errcode.ErrUnauthorized)
 		return
 	}
 	mapClaims := claims.(jwt.MapClaims)
 	userID := mapClaims["sub"].(string)
 
-	host, err := h.hostService.GetHostByUserID(c.Request.Context(), userID)
+	// 未指定 hostId 時建立在自己建立或共同管理的接待主下；組織成員可以指定旗下其他接待主
+	var host *domain.Host
+	var err error
+	if opp.HostID.IsZero() {
+		host, err = h.hostService.GetHostByUserID(c.Request.Context(), userID)
+		err = hostRequired(err)
+	} else {
+		host, err = h.hostService.GetHostByID(c.Request.Context(), opp.HostID.Hex())
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	ok, err := h.orgService.CanManageHost(c.Request.Context(), userID, host, domain.MemberPermissionEditListings)
 	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "user is not a host"})
+		c.Error(err)
+		return
+	}
+	if !ok {
+		c.Error(errCannotManageHost)
 		return
 	}
 	opp.HostID = host.ID
 
 	createdOpp, err := h.oppService.CreateOpportunity(c.Request.Context(), &opp)
 	if err != nil {
-		fmt.Printf("Error creating opportunity: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create opportunity: " + err.Error()})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, createdOpp)
+	c.JSON(http.StatusCreated, createdOpp.Managed())
 }
 
+// GetByID 回傳工作機會。未公開的狀態 (草稿、審核中、暫停等) 只有能管理該接待主的使用者看得到，
+// 其他人一律視為不存在；管理者會額外看到審核與狀態歷程。
 func (h *OpportunityHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 	opp, err := h.oppService.GetOpportunityByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "opportunity not found"})
+		c.Error(err)
+		return
+	}
+
+	manager := false
+	if userID := c.GetString("userID"); userID != "" {
+		manager, err = h.canViewListings(c, userID, opp.HostID)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	if !manager && !opp.Status.IsPublic() {
+		c.Error(service.ErrOpportunityNotFound)
+		return
+	}
+
+	opp.Localize(contentLocale(c))
+	if manager {
+		c.JSON(http.StatusOK, opp.Managed())
 		return
 	}
 	c.JSON(http.StatusOK, opp)
@@ -75,27 +146,20 @@ func (h *OpportunityHandler) List(c *gin.Context) {
 	limit, _ := strconv.ParseInt(limitStr, 10, 64)
 	offset, _ := strconv.ParseInt(offsetStr, 10, 64)
 
-	filter := bson.M{}
-
-	// Filter by Status (default ACTIVE if not specified?)
-	// For now, let's allow listing all public ones
-	// filter["status"] = domain.OpportunityStatusActive
+	// 公開列表只顯示上架中的工作機會，與 Search 一致
+	filter := bson.M{"status": domain.OpportunityStatusActive}
 
 	if hostID := c.Query("hostId"); hostID != "" {
 		objID, _ := primitive.ObjectIDFromHex(hostID)
 		filter["hostId"] = objID
 	}
 
-	// Exclude deleted opportunities
-	if _, ok := filter["status"]; !ok {
-		filter["status"] = bson.M{"$ne": domain.OpportunityStatusDeleted}
-	}
-
 	opps, err := h.oppService.ListOpportunities(c.Request.Context(), filter, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list opportunities"})
+		c.Error(err)
 		return
 	}
+	localizeOpportunities(c, opps)
 
 	c.JSON(http.StatusOK, opps)
 }
@@ -134,9 +198,10 @@ func (h *OpportunityHandler) Search(c *gin.Context) {
 
 	opps, total, err := h.oppService.SearchOpportunities(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to search opportunities"})
+		c.Error(err)
 		return
 	}
+	localizeOpportunities(c, opps)
 
 	c.JSON(http.StatusOK, gin.H{
 		"data":  opps,
@@ -144,39 +209,54 @@ func (h *OpportunityHandler) Search(c *gin.Context) {
 	})
 }
 
+// managedOpportunities 將列表中的工作機會轉為包含不公開欄位的版本，只用於接待主與後台的回應
+func managedOpportunities(opps []*domain.Opportunity) []*domain.ManagedOpportunity {
+	managed := make([]*domain.ManagedOpportunity, len(opps))
+	for i, opp := range opps {
+		managed[i] = opp.Managed()
+	}
+	return managed
+}
+
+// localizeOpportunities 將列表中的工作機會轉為請求的語系
+func localizeOpportunities(c *gin.Context, opps []*domain.Opportunity) {
+	locale := contentLocale(c)
+	for _, opp := range opps {
+		opp.Localize(locale)
+	}
+}
+
 func (h *OpportunityHandler) Update(c *gin.Context) {
 	id := c.Param("id")
 	var req domain.Opportunity
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) || !checkValid(c, validateOpportunity(&req)) {
 		return
 	}
 
-	// 1. Get User ID -> Host ID
+	// 1. Get User ID
 	claims, exists := c.Get("userClaims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		c.Error(errcode.ErrUnauthorized)
 		return
 	}
 	mapClaims := claims.(jwt.MapClaims)
 	userID := mapClaims["sub"].(string)
 
-	host, err := h.hostService.GetHostByUserID(c.Request.Context(), userID)
+	// 2. Get Existing Opportunity
+	existingOpp, err := h.oppService.GetOpportunityByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "user is not a host"})
+		c.Error(err)
 		return
 	}
 
-	// 2. Get Existing Opportunity
-	existingOpp, err := h.oppService.GetOpportunityByID(c.Request.Context(), id)
+	// 3. Check Ownership (host owner/editor or organization member with listings:edit)
+	ok, err := h.canEditListings(c, userID, existingOpp.HostID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "opportunity not found"})
+		c.Error(err)
 		return
 	}
-
-	// 3. Check Ownership
-	if existingOpp.HostID != host.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you do not own this opportunity"})
+	if !ok {
+		c.Error(errNotOpportunityOwner)
 		return
 	}
 
@@ -186,50 +266,123 @@ func (h *OpportunityHandler) Update(c *gin.Context) {
 
 	err = h.oppService.UpdateOpportunity(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update opportunity"})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "opportunity updated"})
 }
 
+// Delete 刪除工作機會，可附上選填的刪除原因 {"reason": "..."}
 func (h *OpportunityHandler) Delete(c *gin.Context) {
 	id := c.Param("id")
+	reason, ok := bindStatusReason(c)
+	if !ok {
+		return
+	}
 
-	// 1. Get User ID -> Host ID
+	// 1. Get User ID
 	claims, exists := c.Get("userClaims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		c.Error(errcode.ErrUnauthorized)
 		return
 	}
 	mapClaims := claims.(jwt.MapClaims)
 	userID := mapClaims["sub"].(string)
 
-	host, err := h.hostService.GetHostByUserID(c.Request.Context(), userID)
+	// 2. Get Existing Opportunity
+	existingOpp, err := h.oppService.GetOpportunityByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "user is not a host"})
+		c.Error(err)
 		return
 	}
 
-	// 2. Get Existing Opportunity
-	existingOpp, err := h.oppService.GetOpportunityByID(c.Request.Context(), id)
+	// 3. Check Ownership (host owner/editor or organization member with listings:edit)
+	ok, err = h.canEditListings(c, userID, existingOpp.HostID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "opportunity not found"})
+		c.Error(err)
 		return
 	}
-
-	// 3. Check Ownership
-	if existingOpp.HostID != host.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "you do not own this opportunity"})
+	if !ok {
+		c.Error(errNotOpportunityOwner)
 		return
 	}
 
 	// 4. Delete
-	err = h.oppService.DeleteOpportunity(c.Request.Context(), id)
+	err = h.oppService.DeleteOpportunity(c.Request.Context(), id, domain.ActorHost, userID, reason)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete opportunity"})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "opportunity deleted"})
 }
+
+// Submit 將草稿或被拒絕的工作機會送交審核
+func (h *OpportunityHandler) Submit(c *gin.Context) {
+	h.changeStatus(c, domain.OpportunityStatusPending)
+}
+
+// Withdraw 撤回審核中的工作機會，或將已過期的工作機會改回草稿重新編輯
+func (h *OpportunityHandler) Withdraw(c *gin.Context) {
+	h.changeStatus(c, domain.OpportunityStatusDraft)
+}
+
+func (h *OpportunityHandler) Pause(c *gin.Context) {
+	h.changeStatus(c, domain.OpportunityStatusPaused)
+}
+
+// Resume 重新開放暫停或已額滿的工作機會
+func (h *OpportunityHandler) Resume(c *gin.Context) {
+	h.changeStatus(c, domain.OpportunityStatusActive)
+}
+
+func (h *OpportunityHandler) MarkFilled(c *gin.Context) {
+	h.changeStatus(c, domain.OpportunityStatusFilled)
+}
+
+// changeStatus 以接待主身分變更工作機會狀態，請求內容可省略或附上 reason
+func (h *OpportunityHandler) changeStatus(c *gin.Context, to domain.OpportunityStatus) {
+	reason, ok := bindStatusReason(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetString("userID")
+	existingOpp, err := h.oppService.GetOpportunityByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	ok, err = h.canEditListings(c, userID, existingOpp.HostID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !ok {
+		c.Error(errNotOpportunityOwner)
+		return
+	}
+
+	opp, err := h.oppService.ChangeStatus(c.Request.Context(), existingOpp.ID.Hex(), to, domain.ActorHost, userID, reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, opp.Managed())
+}
+
+// bindStatusReason 讀取選填的 {"reason": "..."} 請求內容，失敗時已寫入錯誤回應
+func bindStatusReason(c *gin.Context) (string, bool) {
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request.ContentLength != 0 && !bindJSON(c, &req) {
+		return "", false
+	}
+	if !checkValid(c, validateStatusReason(req.Reason)) {
+		return "", false
+	}
+	return req.Reason, true
+}
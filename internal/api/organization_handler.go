@@ -0,0 +1,237 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/service"
+	"github.com/taiwanstay/taiwanstay-back/pkg/validation"
+)
+
+type OrganizationHandler struct {
+	orgService service.OrganizationService
+}
+
+func NewOrganizationHandler(orgService service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{orgService: orgService}
+}
+
+// Create 建立組織，建立者成為擁有者
+func (h *OrganizationHandler) Create(c *gin.Context) {
+	var org domain.Organization
+	if !bindJSON(c, &org) || !checkValid(c, validateOrganization(&org)) {
+		return
+	}
+
+	created, err := h.orgService.CreateOrganization(c.Request.Context(), c.GetString("userID"), &org)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *OrganizationHandler) GetByID(c *gin.Context) {
+	org, err := h.orgService.GetOrganization(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+func (h *OrganizationHandler) Update(c *gin.Context) {
+	var org domain.Organization
+	if !bindJSON(c, &org) || !checkValid(c, validateOrganization(&org)) {
+		return
+	}
+
+	updated, err := h.orgService.UpdateOrganization(c.Request.Context(), c.GetString("userID"), c.Param("id"), &org)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// InviteMember 以 Email 邀請已註冊的使用者加入組織，受邀者接受後才會成為成員
+func (h *OrganizationHandler) InviteMember(c *gin.Context) {
+	var req struct {
+		Email string                  `json:"email"`
+		Role  domain.OrganizationRole `json:"role"`
+	}
+	if !bindJSON(c, &req) || !checkValid(c, validateOrganizationMember(req.Email, req.Role, true)) {
+		return
+	}
+
+	inv, err := h.orgService.InviteMember(c.Request.Context(), c.GetString("userID"), c.Param("id"), req.Email, req.Role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, inv)
+}
+
+func (h *OrganizationHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.orgService.ListInvitations(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+func (h *OrganizationHandler) RevokeInvitation(c *gin.Context) {
+	err := h.orgService.RevokeInvitation(c.Request.Context(), c.GetString("userID"), c.Param("id"), c.Param("invitationId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation revoked"})
+}
+
+// ListMyInvitations 列出寄給目前使用者的組織邀請
+func (h *OrganizationHandler) ListMyInvitations(c *gin.Context) {
+	invitations, err := h.orgService.ListMyInvitations(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+func (h *OrganizationHandler) AcceptInvitation(c *gin.Context) {
+	org, err := h.orgService.AcceptInvitation(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+func (h *OrganizationHandler) DeclineInvitation(c *gin.Context) {
+	err := h.orgService.DeclineInvitation(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "invitation declined"})
+}
+
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	var req struct {
+		Role domain.OrganizationRole `json:"role"`
+	}
+	if !bindJSON(c, &req) || !checkValid(c, validateOrganizationMember("", req.Role, false)) {
+		return
+	}
+
+	err := h.orgService.UpdateMemberRole(c.Request.Context(), c.GetString("userID"), c.Param("id"), c.Param("userId"), req.Role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member updated"})
+}
+
+// RemoveMember 移除成員，userId 為自己時代表退出組織
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	err := h.orgService.RemoveMember(c.Request.Context(), c.GetString("userID"), c.Param("id"), c.Param("userId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "member removed"})
+}
+
+func (h *OrganizationHandler) ListHosts(c *gin.Context) {
+	hosts, err := h.orgService.ListHosts(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hosts)
+}
+
+// AddHost 將自己擁有的接待主加入組織
+func (h *OrganizationHandler) AddHost(c *gin.Context) {
+	var req struct {
+		HostID string `json:"hostId"`
+	}
+	if !bindJSON(c, &req) {
+		return
+	}
+	v := validation.New()
+	v.Required("hostId", req.HostID)
+	if !checkValid(c, v.Err()) {
+		return
+	}
+
+	host, err := h.orgService.AddHost(c.Request.Context(), c.GetString("userID"), c.Param("id"), req.HostID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, host)
+}
+
+func (h *OrganizationHandler) RemoveHost(c *gin.Context) {
+	err := h.orgService.RemoveHost(c.Request.Context(), c.GetString("userID"), c.Param("id"), c.Param("hostId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "host removed from organization"})
+}
+
+// GetDashboard 回傳組織旗下各接待主的申請統計
+func (h *OrganizationHandler) GetDashboard(c *gin.Context) {
+	dashboard, err := h.orgService.GetDashboard(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dashboard)
+}
+
+// ListApplications 列出組織旗下接待主收到的申請，可依 hostId 與 status 篩選
+func (h *OrganizationHandler) ListApplications(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "20")
+	offsetStr := c.DefaultQuery("offset", "0")
+	limit, _ := strconv.ParseInt(limitStr, 10, 64)
+	offset, _ := strconv.ParseInt(offsetStr, 10, 64)
+
+	filter := service.OrganizationApplicationFilter{
+		HostID: c.Query("hostId"),
+		Status: domain.ApplicationStatus(c.Query("status")),
+		Limit:  limit,
+		Offset: offset,
+	}
+	apps, total, err := h.orgService.ListApplications(c.Request.Context(), c.GetString("userID"), c.Param("id"), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  apps,
+		"total": total,
+	})
+}
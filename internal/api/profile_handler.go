@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/service"
+)
+
+// ProfileHandler 負責處理公開個人檔案相關的 HTTP 請求
+type ProfileHandler struct {
+	profileService service.ProfileService
+}
+
+func NewProfileHandler(profileService service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+// GetProfile 依查看者身分回傳使用者的個人檔案，未登入者只能看到公開欄位
+func (h *ProfileHandler) GetProfile(c *gin.Context) {
+	var viewerRole domain.UserRole
+	if claims, ok := c.Get("userClaims"); ok {
+		if mapClaims, ok := claims.(jwt.MapClaims); ok {
+			role, _ := mapClaims["role"].(string)
+			viewerRole = domain.UserRole(role)
+		}
+	}
+
+	profile, err := h.profileService.GetPublicProfile(c.Request.Context(), c.GetString("userID"), viewerRole, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
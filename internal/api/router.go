@@ -2,14 +2,34 @@ package api
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/validation"
 )
 
 // SetupRoutes 負責設定所有 API 路由
-func SetupRoutes(router *gin.Engine, userHandler *UserHandler, imageHandler *ImageHandler, hostHandler *HostHandler, oppHandler *OpportunityHandler, appHandler *ApplicationHandler, notifHandler *NotificationHandler, adminHandler *AdminHandler, bookmarkHandler *BookmarkHandler, cfg *config.Config) {
+func SetupRoutes(router *gin.Engine, userHandler *UserHandler, imageHandler *ImageHandler, hostHandler *HostHandler, oppHandler *OpportunityHandler, appHandler *ApplicationHandler, notifHandler *NotificationHandler, adminHandler *AdminHandler, bookmarkHandler *BookmarkHandler, profileHandler *ProfileHandler, orgHandler *OrganizationHandler, sessions SessionValidator, emailVerification EmailVerificationChecker, permissions PermissionChecker, cfg *config.Config) {
 	// Global Middleware
-	router.Use(gin.Recovery())
+	router.Use(Recovery())
 	router.Use(Logger())
+	router.Use(Locale())
+	router.Use(ErrorHandler())
+
+	// binding 驗證錯誤以 JSON 欄位名稱回報
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validation.UseJSONFieldNames(v)
+	}
+
+	authMiddleware := AuthMiddleware(cfg, sessions)
+	// 未驗證 email 的使用者不可申請機會或建立接待主
+	requireVerifiedEmail := RequireVerifiedEmail(emailVerification)
+	adminMFA := AdminMFAMiddleware(cfg)
+	// 後台操作依角色權限授權
+	require := func(perms ...domain.Permission) gin.HandlerFunc {
+		return RequirePermission(permissions, perms...)
+	}
 
 	// 建立 API 版本分組
 	v1 := router.Group("/api/v1")
@@ -19,40 +39,75 @@ func SetupRoutes(router *gin.Engine, userHandler *UserHandler, imageHandler *Ima
 		{
 			auth.POST("/register", userHandler.Register)
 			auth.POST("/login", userHandler.Login)
-			auth.POST("/logout", userHandler.Logout)
+			auth.POST("/refresh", userHandler.Refresh)
+			auth.POST("/logout", authMiddleware, userHandler.Logout)
+			auth.POST("/apple/notifications", userHandler.AppleNotification)
+			auth.POST("/verify-email", userHandler.VerifyEmail)
+			auth.POST("/verify-email/resend", authMiddleware, userHandler.ResendVerification)
+			auth.POST("/forgot-password", userHandler.ForgotPassword)
+			auth.POST("/reset-password", userHandler.ResetPassword)
+			auth.POST("/mfa/verify", userHandler.VerifyMFA)
 		}
 
-		// 用戶相關路由 (需要管理員權限)
+		// 用戶相關路由 (需要 users:read 權限)
 		users := v1.Group("/users")
-		users.Use(AuthMiddleware(cfg))
-		users.Use(AdminAuthMiddleware())
+		users.Use(authMiddleware)
+		users.Use(require(domain.PermissionUsersRead))
+		users.Use(adminMFA)
 		{
 			users.GET("", userHandler.GetAllUsers)
 			users.GET("/:id", userHandler.GetUserByID)
 		}
 
+		// 公開個人檔案，依查看者身分與隱私設定過濾欄位
+		profiles := v1.Group("/profiles")
+		profiles.Use(OptionalAuthMiddleware(cfg, sessions))
+		{
+			profiles.GET("/:id", profileHandler.GetProfile)
+		}
+
 		// 圖片相關路由
 		images := v1.Group("/images")
-		images.Use(AuthMiddleware(cfg))
+		images.Use(authMiddleware)
 		{
 			images.POST("/upload", imageHandler.Upload)
 			images.GET("/private/:id", imageHandler.GetPrivateImage)
 
-			// Admin only
+			// Image reviewers only
 			adminImages := images.Group("/")
-			adminImages.Use(AdminAuthMiddleware())
+			adminImages.Use(require(domain.PermissionImagesReview))
+			adminImages.Use(adminMFA)
 			{
 				adminImages.PUT("/:id/status", imageHandler.UpdateStatus)
 			}
 		}
 
-		// 接待主 (Host) 相關路由
+		// 接待主 (Host) 相關路由，公開頁面以 slug 查詢不需要登入
+		v1.GET("/hosts/:slug", hostHandler.GetBySlug)
 		hosts := v1.Group("/hosts")
-		hosts.Use(AuthMiddleware(cfg))
+		hosts.Use(authMiddleware)
 		{
-			hosts.POST("", hostHandler.Create)
+			hosts.POST("", requireVerifiedEmail, hostHandler.Create)
 			hosts.GET("/me", hostHandler.GetMe)
 			hosts.PUT("/me", hostHandler.UpdateMe)
+			hosts.PUT("/me/status", hostHandler.UpdateStatus)
+			hosts.POST("/me/documents", hostHandler.UploadDocument)
+			hosts.POST("/me/verification", hostHandler.SubmitVerification)
+			hosts.GET("/me/members", hostHandler.ListMembers)
+			hosts.PUT("/me/members/:userId", hostHandler.UpdateMember)
+			hosts.DELETE("/me/members/:userId", hostHandler.RemoveMember)
+			hosts.GET("/me/invitations", hostHandler.ListInvitations)
+			hosts.POST("/me/invitations", hostHandler.Invite)
+			hosts.DELETE("/me/invitations/:id", hostHandler.RevokeInvitation)
+		}
+
+		// 共同管理者邀請 (受邀者)
+		hostInvitations := v1.Group("/users/me/host-invitations")
+		hostInvitations.Use(authMiddleware)
+		{
+			hostInvitations.GET("", hostHandler.ListMyInvitations)
+			hostInvitations.POST("/:id/accept", hostHandler.AcceptInvitation)
+			hostInvitations.POST("/:id/decline", hostHandler.DeclineInvitation)
 		}
 
 		// 機會 (Opportunity) 相關路由
@@ -60,26 +115,59 @@ func SetupRoutes(router *gin.Engine, userHandler *UserHandler, imageHandler *Ima
 		{
 			opps.GET("", oppHandler.List)
 			opps.GET("/search", oppHandler.Search)
-			opps.GET("/:id", oppHandler.GetByID)
+			opps.GET("/:id", OptionalAuthMiddleware(cfg, sessions), oppHandler.GetByID)
 
 			// 需要認證
 			authOpps := opps.Group("")
-			authOpps.Use(AuthMiddleware(cfg))
+			authOpps.Use(authMiddleware)
 			{
 				authOpps.POST("", oppHandler.Create)
 				authOpps.PUT("/:id", oppHandler.Update)
 				authOpps.DELETE("/:id", oppHandler.Delete)
+				authOpps.POST("/:id/submit", oppHandler.Submit)
+				authOpps.POST("/:id/withdraw", oppHandler.Withdraw)
+				authOpps.POST("/:id/pause", oppHandler.Pause)
+				authOpps.POST("/:id/resume", oppHandler.Resume)
+				authOpps.POST("/:id/mark-filled", oppHandler.MarkFilled)
 				authOpps.POST("/:id/bookmark", bookmarkHandler.AddBookmark)
 				authOpps.DELETE("/:id/bookmark", bookmarkHandler.RemoveBookmark)
 			}
 
 		}
 
+		// 組織 (Organization) 相關路由，權限依成員角色在 service 內檢查
+		orgs := v1.Group("/organizations")
+		orgs.Use(authMiddleware)
+		{
+			orgs.POST("", orgHandler.Create)
+			orgs.GET("/:id", orgHandler.GetByID)
+			orgs.PUT("/:id", orgHandler.Update)
+			orgs.GET("/:id/invitations", orgHandler.ListInvitations)
+			orgs.POST("/:id/invitations", orgHandler.InviteMember)
+			orgs.DELETE("/:id/invitations/:invitationId", orgHandler.RevokeInvitation)
+			orgs.PUT("/:id/members/:userId", orgHandler.UpdateMember)
+			orgs.DELETE("/:id/members/:userId", orgHandler.RemoveMember)
+			orgs.GET("/:id/hosts", orgHandler.ListHosts)
+			orgs.POST("/:id/hosts", orgHandler.AddHost)
+			orgs.DELETE("/:id/hosts/:hostId", orgHandler.RemoveHost)
+			orgs.GET("/:id/dashboard", orgHandler.GetDashboard)
+			orgs.GET("/:id/applications", orgHandler.ListApplications)
+		}
+
+		// 組織成員邀請 (受邀者)
+		orgInvitations := v1.Group("/users/me/organization-invitations")
+		orgInvitations.Use(authMiddleware)
+		{
+			orgInvitations.GET("", orgHandler.ListMyInvitations)
+			orgInvitations.POST("/:id/accept", orgHandler.AcceptInvitation)
+			orgInvitations.POST("/:id/decline", orgHandler.DeclineInvitation)
+		}
+
 		// Applications
 		applications := v1.Group("/applications")
-		applications.Use(AuthMiddleware(cfg)) // Assuming authMiddleware refers to AuthMiddleware(cfg)
+		applications.Use(authMiddleware)
 		{
-			applications.POST("", appHandler.Create)
+			applications.POST("", requireVerifiedEmail, appHandler.Create)
 			applications.GET("", appHandler.List)
 			applications.GET("/:id", appHandler.GetByID)
 			applications.PUT("/:id", appHandler.UpdateStatus)
@@ -88,7 +176,7 @@ func SetupRoutes(router *gin.Engine, userHandler *UserHandler, imageHandler *Ima
 
 		// Notifications
 		notifications := v1.Group("/users/me/notifications")
-		notifications.Use(AuthMiddleware(cfg)) // Assuming authMiddleware refers to AuthMiddleware(cfg)
+		notifications.Use(authMiddleware)
 		{
 			notifications.GET("", notifHandler.List)
 			notifications.PUT("/:id/read", notifHandler.MarkAsRead)
@@ -97,33 +185,59 @@ func SetupRoutes(router *gin.Engine, userHandler *UserHandler, imageHandler *Ima
 
 		// Bookmarks (List)
 		bookmarks := v1.Group("/users/me/bookmarks")
-		bookmarks.Use(AuthMiddleware(cfg))
+		bookmarks.Use(authMiddleware)
 		{
 			bookmarks.GET("", bookmarkHandler.ListBookmarks)
 		}
 
 		// Admin
 		admin := v1.Group("/admin")
-		admin.Use(AuthMiddleware(cfg))   // First check if authenticated
-		admin.Use(AdminAuthMiddleware()) // Then check if admin
+		admin.Use(authMiddleware) // First check if authenticated
+		admin.Use(adminMFA)       // Then check 2FA if required; each route checks its own permission
 		{
-			admin.GET("/stats", adminHandler.GetStats)
-			admin.GET("/images/pending", adminHandler.ListPendingImages)
-			admin.PUT("/images/:id/review", adminHandler.ReviewImage)
-			admin.GET("/users", adminHandler.ListUsers)
-			admin.PUT("/users/:id/status", adminHandler.UpdateUserStatus)
-			admin.PUT("/opportunities/:id", adminHandler.UpdateOpportunity)
-			admin.DELETE("/opportunities/:id", adminHandler.DeleteOpportunity)
+			admin.GET("/stats", require(domain.PermissionStatsRead), adminHandler.GetStats)
+			admin.GET("/images/pending", require(domain.PermissionImagesReview), adminHandler.ListPendingImages)
+			admin.PUT("/images/:id/review", require(domain.PermissionImagesReview), adminHandler.ReviewImage)
+			admin.GET("/users", require(domain.PermissionUsersRead), adminHandler.ListUsers)
+			admin.PUT("/users/:id/status", require(domain.PermissionUsersSuspend), adminHandler.UpdateUserStatus)
+			admin.POST("/users/:id/unlock", require(domain.PermissionUsersSuspend), adminHandler.UnlockUser)
+			admin.GET("/users/:id/export", require(domain.PermissionUsersRead), adminHandler.ExportUserData)
+			admin.DELETE("/users/:id", require(domain.PermissionUsersDelete), adminHandler.DeleteUser)
+			admin.PUT("/users/:id/role", require(domain.PermissionRolesAssign), adminHandler.AssignUserRole)
+			admin.GET("/roles", require(domain.PermissionRolesManage), adminHandler.ListRoles)
+			admin.PUT("/roles/:name", require(domain.PermissionRolesManage), adminHandler.UpdateRole)
+			admin.GET("/hosts/pending", require(domain.PermissionHostsVerify), adminHandler.ListPendingHosts)
+			admin.GET("/hosts/:id/documents/:imageId", require(domain.PermissionHostsVerify), adminHandler.GetHostDocument)
+			admin.PUT("/hosts/:id/review", require(domain.PermissionHostsVerify), adminHandler.ReviewHost)
+			admin.PUT("/hosts/:id/status", require(domain.PermissionHostsSuspend), adminHandler.UpdateHostStatus)
+			admin.GET("/opportunities/pending", require(domain.PermissionOpportunitiesModerate), adminHandler.ListPendingOpportunities)
+			admin.PUT("/opportunities/:id/review", require(domain.PermissionOpportunitiesModerate), adminHandler.ReviewOpportunity)
+			admin.GET("/opportunities/:id/changes", require(domain.PermissionOpportunitiesModerate), adminHandler.GetOpportunityChanges)
+			admin.PUT("/opportunities/:id", require(domain.PermissionOpportunitiesModerate), adminHandler.UpdateOpportunity)
+			admin.DELETE("/opportunities/:id", require(domain.PermissionOpportunitiesModerate), adminHandler.DeleteOpportunity)
+			admin.PUT("/opportunities/:id/status", require(domain.PermissionOpportunitiesModerate), adminHandler.UpdateOpportunityStatus)
 		}
 
 		// ... 其他資源的路由設定
 
 		// 當前登入者相關路由
 		user := v1.Group("/user")
-		user.Use(AuthMiddleware(cfg))
+		user.Use(authMiddleware)
 		{
 			user.GET("/me", userHandler.GetMe)
 			user.PUT("/me", userHandler.UpdateMe)
+			user.DELETE("/me", userHandler.DeleteMe)
+			user.GET("/me/export", userHandler.ExportMe)
+			user.PUT("/me/password", userHandler.ChangePassword)
+			user.POST("/me/phone/verify/send", userHandler.SendPhoneCode)
+			user.POST("/me/phone/verify", userHandler.VerifyPhone)
+			user.POST("/me/mfa/enroll", userHandler.BeginMFAEnrollment)
+			user.POST("/me/mfa/confirm", userHandler.ConfirmMFAEnrollment)
+			user.POST("/me/mfa/recovery-codes", userHandler.RegenerateRecoveryCodes)
+			user.DELETE("/me/mfa", userHandler.DisableMFA)
+			user.GET("/me/sessions", userHandler.ListSessions)
+			user.DELETE("/me/sessions", userHandler.RevokeOtherSessions)
+			user.DELETE("/me/sessions/:id", userHandler.RevokeSession)
 		}
 	}
 }
@@ -1,13 +1,23 @@
 package api
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"log"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"regexp"
+	"sync"
 	"testing"
 	"time"
 
@@ -25,13 +35,26 @@ import (
 	"github.com/taiwanstay/taiwanstay-back/internal/repository"
 	"github.com/taiwanstay/taiwanstay-back/internal/service"
 	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
 	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"github.com/taiwanstay/taiwanstay-back/pkg/oauth"
+	"github.com/taiwanstay/taiwanstay-back/pkg/totp"
+	"github.com/taiwanstay/taiwanstay-back/pkg/validation"
 )
 
 var (
-	testCollection *mongo.Collection
-	testRouter     *gin.Engine
-	testConfig     *config.Config
+	testCollection     *mongo.Collection
+	testRouter         *gin.Engine
+	testConfig         *config.Config
+	testSessionService service.SessionService
+	testGoogleKey      *rsa.PrivateKey
+	testEmailSender    = &recordingEmailSender{}
+	testSMSSender      = &recordingSMSSender{}
+)
+
+const (
+	testGoogleClientID = "test-google-client"
+	testAppleClientID  = "tw.taiwanstay.test"
 )
 
 // TestMain 是測試的主進入點，用於設定和清理測試環境
@@ -39,12 +62,27 @@ func TestMain(m *testing.M) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
+	// 啟動 JWKS stub，讓 Google / Apple 登入測試不需連線到外部服務
+	var err error
+	testGoogleKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("failed to generate rsa key: %s", err)
+	}
+	jwksServer := newJWKSStub(&testGoogleKey.PublicKey)
+	defer jwksServer.Close()
+
 	// Setup test config
 	testConfig = &config.Config{
 		Server: config.ServerConfig{
 			JWTSecret: "test-secret",
 			Mode:      "test",
 		},
+		OAuth: config.OAuthConfig{
+			GoogleClientIDs: []string{testGoogleClientID},
+			GoogleJWKSURL:   jwksServer.URL,
+			AppleClientIDs:  []string{testAppleClientID},
+			AppleJWKSURL:    jwksServer.URL,
+		},
 	}
 
 	// Init Logger
@@ -88,20 +126,155 @@ func setupTestRouter(collection *mongo.Collection) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 
 	userRepo := repository.NewUserRepository(collection)
-	userService := service.NewUserService(userRepo, testConfig)
-	userHandler := NewUserHandler(userService)
+	sessionRepo := repository.NewSessionRepository(collection.Database().Collection("sessions"))
+	testSessionService = service.NewSessionService(sessionRepo, userRepo, testConfig)
+	emailVerificationService := service.NewEmailVerificationService(userRepo, testEmailSender, testConfig)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(collection.Database().Collection("login_attempts"))
+	loginProtection := service.NewLoginProtectionService(loginAttemptRepo, userRepo, testEmailSender, testConfig)
+	mfaService := service.NewMFAService(userRepo, testSessionService, loginProtection, testConfig)
+	userService := service.NewUserService(userRepo, testSessionService, emailVerificationService, mfaService, loginProtection)
+	passwordResetRepo := repository.NewPasswordResetRepository(collection.Database().Collection("password_resets"))
+	passwordService := service.NewPasswordService(userRepo, passwordResetRepo, loginAttemptRepo, testSessionService, testEmailSender, testConfig)
+	oauthService := service.NewOAuthService(userRepo, testSessionService, mfaService, map[domain.AuthProvider]oauth.Verifier{
+		domain.ProviderGoogle: oauth.NewGoogleVerifier(testConfig),
+		domain.ProviderApple:  oauth.NewAppleVerifier(testConfig),
+	})
+	phoneVerificationRepo := repository.NewPhoneVerificationRepository(collection.Database().Collection("phone_verifications"))
+	phoneVerification := service.NewPhoneVerificationService(phoneVerificationRepo, userRepo, testSMSSender, testConfig)
+	db := collection.Database()
+	appRepo := repository.NewApplicationRepository(db.Collection("applications"))
+	hostRepo := repository.NewHostRepository(db.Collection("hosts"))
+	hostService := service.NewHostService(hostRepo, repository.NewOpportunityRepository(db.Collection("opportunities")), testConfig)
+	accountService := service.NewAccountService(userRepo, appRepo, repository.NewBookmarkRepository(db.Collection("bookmarks")), repository.NewNotificationRepository(db.Collection("notifications")), repository.NewImageRepository(db.Collection("images")), hostService, testSessionService)
+	userHandler := NewUserHandler(userService, testSessionService, oauthService, emailVerificationService, passwordService, mfaService, phoneVerification, accountService)
+
+	roleService := service.NewRoleService(repository.NewRoleRepository(collection.Database().Collection("roles")), userRepo, testSessionService)
+	if err := roleService.EnsureDefaultRoles(context.Background()); err != nil {
+		log.Fatalf("failed to create default roles: %s", err)
+	}
+	// 只測試角色相關的管理功能
+	adminHandler := NewAdminHandler(nil, nil, roleService, nil, nil, nil)
+	profileService := service.NewProfileService(userRepo, hostRepo, appRepo, roleService)
+	profileHandler := NewProfileHandler(profileService)
 
 	router := gin.Default()
 	// Pass nil for ImageHandler, HostHandler, OppHandler, AppHandler as we are not testing them here yet
-	SetupRoutes(router, userHandler, nil, nil, nil, nil, nil, nil, nil, testConfig)
+	SetupRoutes(router, userHandler, nil, nil, nil, nil, nil, adminHandler, nil, profileHandler, nil, testSessionService, emailVerificationService, roleService, testConfig)
 	return router
 }
 
+// recordingEmailSender 記錄寄出的 email，讓測試可以取得驗證信中的連結
+type recordingEmailSender struct {
+	mu       sync.Mutex
+	lastTo   string
+	lastBody string
+}
+
+func (s *recordingEmailSender) Send(toEmail, toName, subject, htmlBody string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastTo = toEmail
+	s.lastBody = htmlBody
+	return nil
+}
+
+// lastLinkToken 等待寄給 to 的 email，並從其中 path 連結取出 token
+func (s *recordingEmailSender) lastLinkToken(t *testing.T, to, path string) string {
+	pattern := regexp.MustCompile(regexp.QuoteMeta(path) + `\?token=([^"]+)"`)
+	var match []string
+	// 部分 email 以非同步方式寄送
+	assert.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.lastTo != to {
+			return false
+		}
+		match = pattern.FindStringSubmatch(s.lastBody)
+		return len(match) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	if len(match) != 2 {
+		return ""
+	}
+	token, err := url.QueryUnescape(match[1])
+	assert.NoError(t, err)
+	return token
+}
+
+// recordingSMSSender 記錄寄出的簡訊，讓測試可以取得驗證碼
+type recordingSMSSender struct {
+	mu          sync.Mutex
+	lastTo      string
+	lastMessage string
+}
+
+func (s *recordingSMSSender) Send(ctx context.Context, to, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastTo = to
+	s.lastMessage = message
+	return nil
+}
+
+// lastCode 取出最後一封寄給 to 的簡訊中的驗證碼
+func (s *recordingSMSSender) lastCode(t *testing.T, to string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Equal(t, to, s.lastTo)
+	match := regexp.MustCompile(`\b(\d{6})\b`).FindStringSubmatch(s.lastMessage)
+	if len(match) != 2 {
+		t.Fatalf("no verification code in sms: %q", s.lastMessage)
+	}
+	return match[1]
+}
+
+// newJWKSStub 啟動一個回傳指定公鑰的 JWKS endpoint
+func newJWKSStub(key *rsa.PublicKey) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(gin.H{
+			"keys": []gin.H{{
+				"kid": "test-key",
+				"kty": "RSA",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+}
+
+// signTestJWT 以測試金鑰簽發 RS256 JWT (模擬 Google / Apple)
+func signTestJWT(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString(testGoogleKey)
+	assert.NoError(t, err)
+	return signed
+}
+
+// signGoogleIDToken 以測試金鑰簽發一個 Google ID token
+func signGoogleIDToken(t *testing.T, sub, email string) string {
+	return signTestJWT(t, jwt.MapClaims{
+		"iss":            "https://accounts.google.com",
+		"aud":            testGoogleClientID,
+		"sub":            sub,
+		"email":          email,
+		"email_verified": true,
+		"name":           "Google Traveler",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	})
+}
+
 // cleanupCollection 在每個測試前清理集合
 func cleanupCollection(ctx context.Context) {
 	if err := testCollection.Drop(ctx); err != nil {
 		log.Fatalf("failed to drop collection: %s", err)
 	}
+	// 清除登入失敗紀錄與簡訊驗證碼，避免影響其他測試
+	for _, name := range []string{"login_attempts", "phone_verifications"} {
+		if _, err := testCollection.Database().Collection(name).DeleteMany(ctx, bson.M{}); err != nil {
+			log.Fatalf("failed to clear %s: %s", name, err)
+		}
+	}
 }
 
 func TestRegister_Success(t *testing.T) {
@@ -137,6 +310,70 @@ func TestRegister_Success(t *testing.T) {
 	assert.Nil(t, response["password"])
 }
 
+func TestVerifyEmail_Flow(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	// 1. 註冊後會寄出驗證信
+	body, _ := json.Marshal(gin.H{"name": "verify", "email": "verify@example.com", "password": "password123"})
+	req, _ := http.NewRequestWithContext(ctx, "POST", "/api/v1/auth/register", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var registered domain.User
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &registered))
+	assert.Nil(t, registered.EmailVerified)
+	verificationToken := testEmailSender.lastLinkToken(t, "verify@example.com", "/verify-email")
+	accessToken := generateTestToken(t, &registered)
+
+	resend := func() *httptest.ResponseRecorder {
+		req, _ := http.NewRequestWithContext(ctx, "POST", "/api/v1/auth/verify-email/resend", nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	// 2. 剛寄出驗證信，立即重新寄送會被限制
+	w = resend()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// 3. 驗證信中的 token 不能當作 access token 使用
+	req, _ = http.NewRequestWithContext(ctx, "GET", "/api/v1/user/me", nil)
+	req.Header.Set("Authorization", "Bearer "+verificationToken)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// 4. 驗證 email
+	body, _ = json.Marshal(gin.H{"token": verificationToken})
+	req, _ = http.NewRequestWithContext(ctx, "POST", "/api/v1/auth/verify-email", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var user domain.User
+	objID, _ := primitive.ObjectIDFromHex(registered.ID)
+	assert.NoError(t, testCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&user))
+	assert.NotNil(t, user.EmailVerified)
+
+	// 5. 已驗證後不需再寄送
+	w = resend()
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	// 6. 無效的 token
+	body, _ = json.Marshal(gin.H{"token": "not-a-token"})
+	req, _ = http.NewRequestWithContext(ctx, "POST", "/api/v1/auth/verify-email", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestRegister_EmailAlreadyExists(t *testing.T) {
 	ctx := context.Background()
 	cleanupCollection(ctx)
@@ -163,10 +400,13 @@ func TestRegister_EmailAlreadyExists(t *testing.T) {
 	// 斷言
 	assert.Equal(t, http.StatusConflict, w.Code)
 
-	var response map[string]string
+	var response struct {
+		Error errcode.Error `json:"error"`
+	}
 	err = json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
-	assert.Equal(t, "email already exists", response["error"])
+	assert.Equal(t, "EMAIL_ALREADY_EXISTS", response.Error.Code)
+	assert.Equal(t, w.Header().Get("X-Request-ID"), response.Error.RequestID)
 }
 
 func TestRegister_MissingFields(t *testing.T) {
@@ -226,9 +466,115 @@ func TestLogin_Success(t *testing.T) {
 	err = json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, response["token"])
+	assert.NotEmpty(t, response["refreshToken"])
 	assert.NotNil(t, response["user"])
 }
 
+func TestLogin_Google(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	login := func(idToken string) (int, map[string]interface{}) {
+		body, _ := json.Marshal(gin.H{"loginType": "google", "token": idToken})
+		req, _ := http.NewRequestWithContext(ctx, "POST", "/api/v1/auth/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	// 1. 既有的密碼帳號以相同 email 登入時會被綁定，而不是建立新帳號
+	existing, _ := createAndLoginUser(t, ctx, "existing", "linked@example.com", "password123", domain.RoleUser)
+	code, response := login(signGoogleIDToken(t, "google-linked", "linked@example.com"))
+	assert.Equal(t, http.StatusOK, code)
+	assert.NotEmpty(t, response["token"])
+	assert.Equal(t, existing.ID, response["user"].(map[string]interface{})["id"])
+
+	// email 尚未驗證的帳號被接手後，原本的密碼不能再登入
+	body, _ := json.Marshal(gin.H{"loginType": "password", "email": "linked@example.com", "password": "password123"})
+	req, _ := http.NewRequestWithContext(ctx, "POST", "/api/v1/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// 2. 新使用者自動註冊
+	code, response = login(signGoogleIDToken(t, "google-new", "new-traveler@example.com"))
+	assert.Equal(t, http.StatusOK, code)
+	newUserID := response["user"].(map[string]interface{})["id"]
+	assert.NotEmpty(t, newUserID)
+
+	// 3. 再次登入會找到同一個帳號
+	code, response = login(signGoogleIDToken(t, "google-new", "new-traveler@example.com"))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, newUserID, response["user"].(map[string]interface{})["id"])
+
+	count, err := testCollection.CountDocuments(ctx, bson.M{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// 4. 偽造的 token 被拒絕
+	code, _ = login("not-a-jwt")
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestLogin_AppleAndConsentRevoked(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	// 1. 第一次以 Apple 登入，姓名由客戶端提供
+	nonceHash := sha256.Sum256([]byte("raw-nonce"))
+	idToken := signTestJWT(t, jwt.MapClaims{
+		"iss":              "https://appleid.apple.com",
+		"aud":              testAppleClientID,
+		"sub":              "apple-sub-001",
+		"email":            "relay001@privaterelay.appleid.com",
+		"email_verified":   "true",
+		"is_private_email": "true",
+		"nonce":            hex.EncodeToString(nonceHash[:]),
+		"exp":              time.Now().Add(time.Hour).Unix(),
+	})
+	body, _ := json.Marshal(gin.H{"loginType": "apple", "token": idToken, "nonce": "raw-nonce", "name": "Lin Mei"})
+	req, _ := http.NewRequestWithContext(ctx, "POST", "/api/v1/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var loginResp map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &loginResp))
+	assert.Equal(t, "Lin Mei", loginResp["user"].(map[string]interface{})["name"])
+	accessToken := loginResp["token"].(string)
+
+	// 2. Apple 通知使用者撤銷授權
+	payload := signTestJWT(t, jwt.MapClaims{
+		"iss":    "https://appleid.apple.com",
+		"aud":    testAppleClientID,
+		"iat":    time.Now().Unix(),
+		"events": `{"type":"consent-revoked","sub":"apple-sub-001"}`,
+	})
+	body, _ = json.Marshal(gin.H{"payload": payload})
+	req, _ = http.NewRequestWithContext(ctx, "POST", "/api/v1/auth/apple/notifications", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 3. 所有 session 被撤銷，且身分已解除綁定
+	req, _ = http.NewRequestWithContext(ctx, "GET", "/api/v1/user/me", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	count, err := testCollection.CountDocuments(ctx, bson.M{"identities.subject": "apple-sub-001"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
 func TestLogin_WrongPassword(t *testing.T) {
 	ctx := context.Background()
 	cleanupCollection(ctx)
@@ -262,6 +608,38 @@ func TestLogin_WrongPassword(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
+func TestLogin_ProgressiveDelayAfterFailures(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	_, err := testCollection.InsertOne(ctx, &domain.User{
+		Name:     "throttled",
+		Email:    "throttled@example.com",
+		Password: string(hashedPassword),
+	})
+	assert.NoError(t, err)
+
+	login := func(password string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(gin.H{"loginType": "password", "email": "throttled@example.com", "password": password})
+		req, _ := http.NewRequestWithContext(ctx, "POST", "/api/v1/auth/login", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	// 1. 前幾次失敗只回傳 401
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusUnauthorized, login("wrongpassword").Code)
+	}
+
+	// 2. 之後需等待一段時間才能再嘗試，即使密碼正確
+	w := login("password123")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
 func TestLogin_UserNotFound(t *testing.T) {
 	ctx := context.Background()
 	cleanupCollection(ctx)
@@ -284,23 +662,11 @@ func TestLogin_UserNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
 
-// generateTestToken 根據使用者資訊生成用於測試的 JWT
+// generateTestToken 為使用者建立一個 session 並回傳其 access token
 func generateTestToken(t *testing.T, user *domain.User) string {
-	jwtSecret := testConfig.Server.JWTSecret // 與 middleware 中保持一致
-	tokenDuration := time.Hour * 24
-
-	claims := jwt.MapClaims{
-		"sub":  user.ID,
-		"name": user.Name,
-		"role": user.Role,
-		"exp":  time.Now().Add(tokenDuration).Unix(),
-		"iat":  time.Now().Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(jwtSecret))
+	tokens, err := testSessionService.CreateSession(context.Background(), user, service.SessionMeta{UserAgent: "test"})
 	assert.NoError(t, err)
-	return signedToken
+	return tokens.AccessToken
 }
 
 // createAndLoginUser 是一個輔助函式，用於在資料庫中建立指定角色的使用者，並返回 userID 和 token
@@ -391,6 +757,48 @@ func TestUpdateMe_Success(t *testing.T) {
 	assert.Equal(t, "Jane Doe", updatedUser.Profile.EmergencyContact.Name)
 }
 
+func TestUpdateMe_ValidationErrors(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	_, token := createAndLoginUser(t, ctx, "invalid_update_user", "invalid-update@example.com", "password123", domain.RoleUser)
+
+	updateData := gin.H{
+		"birthDate":   time.Now().AddDate(1, 0, 0),
+		"phoneNumber": "not-a-phone",
+		"languages":   []string{"en", "English!"},
+		"location":    gin.H{"type": "Point", "coordinates": []float64{121.5, 95}},
+		"socialMedia": gin.H{"website": "javascript:alert(1)"},
+	}
+	body, _ := json.Marshal(updateData)
+
+	req, _ := http.NewRequestWithContext(ctx, "PUT", "/api/v1/user/me", bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response struct {
+		Error errcode.Error `json:"error"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "VALIDATION_FAILED", response.Error.Code)
+
+	rules := map[string]string{}
+	for _, f := range response.Error.Fields {
+		rules[f.Field] = f.Rule
+	}
+	assert.Equal(t, map[string]string{
+		"birthDate":               validation.RuleNotFuture,
+		"phoneNumber":             validation.RulePhone,
+		"languages[1]":            validation.RuleLanguage,
+		"location.coordinates[1]": validation.RuleRange,
+		"socialMedia.website":     validation.RuleURL,
+	}, rules)
+}
+
 func TestLogout_Success(t *testing.T) {
 	ctx := context.Background()
 	cleanupCollection(ctx)
@@ -411,6 +819,286 @@ func TestLogout_Success(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, "logout successful", response["message"])
+
+	// 4. 登出後同一個 access token 應立即失效
+	req, _ = http.NewRequestWithContext(ctx, "GET", "/api/v1/user/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestPasswordReset_Flow(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	user, token := createAndLoginUser(t, ctx, "reset_user", "reset@example.com", "password123", domain.RoleUser)
+
+	post := func(path string, payload gin.H) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequestWithContext(ctx, "POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	// 1. 已註冊與未註冊的 email 回應相同
+	unknown := post("/api/v1/auth/forgot-password", gin.H{"email": "nobody@example.com"})
+	known := post("/api/v1/auth/forgot-password", gin.H{"email": user.Email})
+	assert.Equal(t, http.StatusOK, known.Code)
+	assert.Equal(t, unknown.Code, known.Code)
+	assert.Equal(t, unknown.Body.String(), known.Body.String())
+
+	// 2. 以 email 中的 token 重設密碼
+	resetToken := testEmailSender.lastLinkToken(t, user.Email, "/reset-password")
+	w := post("/api/v1/auth/reset-password", gin.H{"token": resetToken, "newPassword": "newpassword456"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 3. token 只能使用一次
+	w = post("/api/v1/auth/reset-password", gin.H{"token": resetToken, "newPassword": "anotherpassword"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 4. 重設後既有的 session 全部失效
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/api/v1/user/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// 5. 新密碼可以登入，舊密碼不行
+	w = post("/api/v1/auth/login", gin.H{"loginType": "password", "email": user.Email, "password": "newpassword456"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = post("/api/v1/auth/login", gin.H{"loginType": "password", "email": user.Email, "password": "password123"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	user, currentToken := createAndLoginUser(t, ctx, "change_user", "change@example.com", "password123", domain.RoleUser)
+	otherToken := generateTestToken(t, user)
+
+	changePassword := func(current, next string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(gin.H{"currentPassword": current, "newPassword": next})
+		req, _ := http.NewRequestWithContext(ctx, "PUT", "/api/v1/user/me/password", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+currentToken)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	// 1. 目前密碼錯誤
+	w := changePassword("wrongpassword", "newpassword456")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 2. 變更成功
+	w = changePassword("password123", "newpassword456")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 3. 目前的裝置仍然有效，其他裝置被登出
+	for token, expected := range map[string]int{currentToken: http.StatusOK, otherToken: http.StatusUnauthorized} {
+		req, _ := http.NewRequestWithContext(ctx, "GET", "/api/v1/user/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w = httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		assert.Equal(t, expected, w.Code)
+	}
+}
+
+func TestMFA_EnrollAndLogin(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	user, token := createAndLoginUser(t, ctx, "mfa_host", "mfa@example.com", "password123", domain.RoleUser)
+
+	do := func(method, path, accessToken string, payload gin.H) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequestWithContext(ctx, method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if accessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+accessToken)
+		}
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	// 1. 產生密鑰並以驗證碼確認
+	w := do("POST", "/api/v1/user/me/mfa/enroll", token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var enrollment service.MFAEnrollment
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &enrollment))
+	assert.Contains(t, enrollment.ProvisioningURI, "otpauth://totp/")
+
+	code, err := totp.Code(enrollment.Secret, totp.Step(time.Now()))
+	assert.NoError(t, err)
+	w = do("POST", "/api/v1/user/me/mfa/confirm", token, gin.H{"code": code})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var confirmed struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &confirmed))
+	assert.Len(t, confirmed.RecoveryCodes, 10)
+
+	// 2. 密碼登入只取得 challenge，不會取得 token
+	w = do("POST", "/api/v1/auth/login", "", gin.H{"loginType": "password", "email": user.Email, "password": "password123"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var challenge map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &challenge))
+	assert.Equal(t, true, challenge["mfaRequired"])
+	assert.Nil(t, challenge["token"])
+	challengeToken, _ := challenge["challengeToken"].(string)
+
+	// 3. challenge token 不能當作 access token 使用
+	w = do("GET", "/api/v1/user/me", challengeToken, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// 4. 錯誤的驗證碼
+	w = do("POST", "/api/v1/auth/mfa/verify", "", gin.H{"challengeToken": challengeToken, "code": "not-a-code"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// 5. 以復原碼完成登入，復原碼只能使用一次
+	w = do("POST", "/api/v1/auth/mfa/verify", "", gin.H{"challengeToken": challengeToken, "code": confirmed.RecoveryCodes[0]})
+	assert.Equal(t, http.StatusOK, w.Code)
+	var loggedIn map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &loggedIn))
+	assert.NotEmpty(t, loggedIn["token"])
+
+	w = do("POST", "/api/v1/auth/mfa/verify", "", gin.H{"challengeToken": challengeToken, "code": confirmed.RecoveryCodes[0]})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRefresh_RotatesAndDetectsReuse(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	// 1. 建立使用者並透過 API 登入以取得 refresh token
+	_, _ = createAndLoginUser(t, ctx, "refresh_user", "refresh@example.com", "password123", domain.RoleUser)
+	loginBody, _ := json.Marshal(gin.H{"loginType": "password", "email": "refresh@example.com", "password": "password123"})
+	req, _ := http.NewRequestWithContext(ctx, "POST", "/api/v1/auth/login", bytes.NewBuffer(loginBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var login map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+	firstRefresh := login["refreshToken"].(string)
+
+	refresh := func(token string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(gin.H{"refreshToken": token})
+		req, _ := http.NewRequestWithContext(ctx, "POST", "/api/v1/auth/refresh", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	// 2. 第一次 refresh 成功並取得新的 refresh token
+	w = refresh(firstRefresh)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var rotated map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotated))
+	secondRefresh := rotated["refreshToken"].(string)
+	assert.NotEqual(t, firstRefresh, secondRefresh)
+
+	// 3. 重複使用舊 token 會被拒絕，且整個 session 被撤銷
+	w = refresh(firstRefresh)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = refresh(secondRefresh)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSessions_ListAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	// 1. 同一個使用者在三個裝置登入
+	user, phoneToken := createAndLoginUser(t, ctx, "session_user", "session@example.com", "password123", domain.RoleUser)
+	laptopToken := generateTestToken(t, user)
+	tabletToken := generateTestToken(t, user)
+
+	// 2. 列出裝置，目前的 session 應被標記
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/api/v1/user/me/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+laptopToken)
+	w := httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var listResp struct {
+		Data []struct {
+			ID      string `json:"id"`
+			Current bool   `json:"current"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResp))
+	assert.Len(t, listResp.Data, 3)
+
+	var phoneSessionID string
+	currentCount := 0
+	for _, s := range listResp.Data {
+		if s.Current {
+			currentCount++
+		}
+	}
+	assert.Equal(t, 1, currentCount)
+
+	// 找出手機的 session (以手機 token 列出時標記為 current 的那一個)
+	req, _ = http.NewRequestWithContext(ctx, "GET", "/api/v1/user/me/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+phoneToken)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &listResp))
+	for _, s := range listResp.Data {
+		if s.Current {
+			phoneSessionID = s.ID
+		}
+	}
+	assert.NotEmpty(t, phoneSessionID)
+
+	// 3. 從筆電登出遺失的手機
+	req, _ = http.NewRequestWithContext(ctx, "DELETE", "/api/v1/user/me/sessions/"+phoneSessionID, nil)
+	req.Header.Set("Authorization", "Bearer "+laptopToken)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequestWithContext(ctx, "GET", "/api/v1/user/me", nil)
+	req.Header.Set("Authorization", "Bearer "+phoneToken)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// 4. 其他使用者無法撤銷此使用者的 session
+	_, otherToken := createAndLoginUser(t, ctx, "other_user", "other@example.com", "password123", domain.RoleUser)
+	req, _ = http.NewRequestWithContext(ctx, "DELETE", "/api/v1/user/me/sessions/"+phoneSessionID, nil)
+	req.Header.Set("Authorization", "Bearer "+otherToken)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	// 5. 登出其他所有裝置，筆電本身仍然有效
+	req, _ = http.NewRequestWithContext(ctx, "DELETE", "/api/v1/user/me/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+laptopToken)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequestWithContext(ctx, "GET", "/api/v1/user/me", nil)
+	req.Header.Set("Authorization", "Bearer "+tabletToken)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req, _ = http.NewRequestWithContext(ctx, "GET", "/api/v1/user/me", nil)
+	req.Header.Set("Authorization", "Bearer "+laptopToken)
+	w = httptest.NewRecorder()
+	testRouter.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
 }
 
 func TestAdminActions_SuccessAsAdmin(t *testing.T) {
@@ -475,3 +1163,280 @@ func TestAdminActions_ForbiddenAsUser(t *testing.T) {
 	// 斷言 GetUserByID 失敗
 	assert.Equal(t, http.StatusForbidden, wGetByID.Code)
 }
+
+func TestAdminRoles_AssignModerator(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	admin, adminToken := createAndLoginUser(t, ctx, "admin", "admin@example.com", "password123", domain.RoleAdmin)
+	staff, staffToken := createAndLoginUser(t, ctx, "staff", "staff@example.com", "password123", domain.RoleUser)
+
+	do := func(method, path, token string, payload any) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			_ = json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequestWithContext(ctx, method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	// 1. 一般使用者無法指派角色
+	w := do("PUT", "/api/v1/admin/users/"+admin.ID+"/role", staffToken, gin.H{"role": "USER"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// 2. 管理員不能變更自己的角色
+	w = do("PUT", "/api/v1/admin/users/"+admin.ID+"/role", adminToken, gin.H{"role": "USER"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// 3. 指派 MODERATOR 後，舊的 token 立即失效
+	w = do("PUT", "/api/v1/admin/users/"+staff.ID+"/role", adminToken, gin.H{"role": "moderator"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = do("GET", "/api/v1/user/me", staffToken, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// 4. 重新登入後可讀取使用者，但不能停權使用者或管理角色
+	staff.Role = domain.RoleModerator
+	moderatorToken := generateTestToken(t, staff)
+
+	w = do("GET", "/api/v1/users", moderatorToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = do("PUT", "/api/v1/admin/users/"+admin.ID+"/status", moderatorToken, gin.H{"status": "SUSPENDED"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = do("GET", "/api/v1/admin/roles", moderatorToken, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// 5. 不存在的角色
+	w = do("PUT", "/api/v1/admin/users/"+staff.ID+"/role", adminToken, gin.H{"role": "WIZARD"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetProfile_HonorsPrivacySettings(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	privacy := domain.DefaultPrivacySettings()
+	privacy.PersonalInfo = domain.PrivacyRegistered
+	res, err := testCollection.InsertOne(ctx, &domain.User{
+		Name:     "traveler",
+		Email:    "traveler@example.com",
+		Password: string(hashedPassword),
+		Role:     domain.RoleUser,
+		Profile: domain.Profile{
+			Bio:              "Loves mountains",
+			PersonalInfo:     &domain.PersonalInfo{Nationality: "TW"},
+			EmergencyContact: domain.EmergencyContact{Name: "Mom", Phone: "0912345678"},
+		},
+		PrivacySettings: privacy,
+	})
+	assert.NoError(t, err)
+	travelerID := res.InsertedID.(primitive.ObjectID).Hex()
+	_, otherToken := createAndLoginUser(t, ctx, "other", "other@example.com", "password123", domain.RoleUser)
+
+	get := func(token string) map[string]interface{} {
+		req, _ := http.NewRequestWithContext(ctx, "GET", "/api/v1/profiles/"+travelerID, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+
+	// 1. 訪客只看得到公開欄位
+	anonymous := get("")
+	assert.Equal(t, "ANONYMOUS", anonymous["viewer"])
+	assert.Equal(t, "Loves mountains", anonymous["bio"])
+	assert.Nil(t, anonymous["personalInfo"])
+	assert.Nil(t, anonymous["email"])
+	assert.Nil(t, anonymous["emergencyContact"])
+
+	// 2. 登入使用者可看到 REGISTERED 欄位，但看不到緊急聯絡人
+	registered := get(otherToken)
+	assert.Equal(t, "REGISTERED", registered["viewer"])
+	assert.NotNil(t, registered["personalInfo"])
+	assert.Nil(t, registered["email"])
+	assert.Nil(t, registered["emergencyContact"])
+
+	// 3. 本人可看到全部
+	self := get(generateTestToken(t, &domain.User{ID: travelerID, Email: "traveler@example.com", Role: domain.RoleUser}))
+	assert.Equal(t, "SELF", self["viewer"])
+	assert.Equal(t, "traveler@example.com", self["email"])
+	assert.NotNil(t, self["emergencyContact"])
+}
+
+func TestPhoneVerification_SendAndVerify(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	user, token := createAndLoginUser(t, ctx, "phone_user", "phone@example.com", "password123", domain.RoleUser)
+
+	do := func(method, path string, payload gin.H) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequestWithContext(ctx, method, path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	// 1. 號碼格式錯誤
+	w := do("POST", "/api/v1/user/me/phone/verify/send", gin.H{"phoneNumber": "not-a-phone"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 2. 寄送驗證碼，台灣號碼會轉為 E.164
+	w = do("POST", "/api/v1/user/me/phone/verify/send", gin.H{"phoneNumber": "0912-345-678"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	code := testSMSSender.lastCode(t, "+886912345678")
+
+	// 3. 立即重送會被限制
+	w = do("POST", "/api/v1/user/me/phone/verify/send", gin.H{"phoneNumber": "0912345678"})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	// 4. 錯誤的驗證碼
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	w = do("POST", "/api/v1/user/me/phone/verify", gin.H{"code": wrong})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 5. 正確的驗證碼
+	w = do("POST", "/api/v1/user/me/phone/verify", gin.H{"code": code})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	objID, _ := primitive.ObjectIDFromHex(user.ID)
+	var stored domain.User
+	assert.NoError(t, testCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&stored))
+	assert.Equal(t, "+886912345678", stored.Profile.PhoneNumber)
+	assert.True(t, stored.Profile.IsPhoneVerified)
+
+	// 6. 驗證碼只能使用一次
+	w = do("POST", "/api/v1/user/me/phone/verify", gin.H{"code": code})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAccount_ExportAndDelete(t *testing.T) {
+	ctx := context.Background()
+	cleanupCollection(ctx)
+
+	user, token := createAndLoginUser(t, ctx, "gdpr_user", "gdpr@example.com", "password123", domain.RoleUser)
+	userObjID, _ := primitive.ObjectIDFromHex(user.ID)
+	db := testCollection.Database()
+
+	_, err := db.Collection("bookmarks").InsertOne(ctx, domain.Bookmark{UserID: user.ID, OpportunityID: primitive.NewObjectID().Hex(), CreatedAt: time.Now()})
+	assert.NoError(t, err)
+	appRes, err := db.Collection("applications").InsertOne(ctx, domain.Application{
+		UserID:             userObjID,
+		HostID:             primitive.NewObjectID(),
+		Status:             domain.ApplicationStatusPending,
+		ApplicationDetails: domain.ApplicationDetails{Message: "Hi, I'm Alice from Berlin", StartDate: "2026-01-01"},
+		CreatedAt:          time.Now(),
+	})
+	assert.NoError(t, err)
+
+	do := func(method, path string, payload gin.H) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload)
+		}
+		req, _ := http.NewRequestWithContext(ctx, method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		testRouter.ServeHTTP(w, req)
+		return w
+	}
+
+	// 1. 匯出 JSON
+	w := do("GET", "/api/v1/user/me/export?format=json", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var export domain.DataExport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &export))
+	assert.Equal(t, "gdpr@example.com", export.User.Email)
+	assert.Len(t, export.Bookmarks, 1)
+	assert.Len(t, export.Applications, 1)
+
+	// 2. 匯出 ZIP
+	w = do("GET", "/api/v1/user/me/export", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	assert.NoError(t, err)
+	assert.Len(t, zr.File, 5)
+
+	// 3. 密碼錯誤無法刪除
+	w = do("DELETE", "/api/v1/user/me", gin.H{"password": "wrong"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 4. 刪除帳號
+	w = do("DELETE", "/api/v1/user/me", gin.H{"password": "password123"})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var stored domain.User
+	assert.NoError(t, testCollection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&stored))
+	assert.Equal(t, domain.UserStatusDeleted, stored.Status)
+	assert.NotEqual(t, "gdpr@example.com", stored.Email)
+	assert.Empty(t, stored.Password)
+
+	count, _ := db.Collection("bookmarks").CountDocuments(ctx, bson.M{"userId": user.ID})
+	assert.Zero(t, count)
+
+	var app domain.Application
+	assert.NoError(t, db.Collection("applications").FindOne(ctx, bson.M{"_id": appRes.InsertedID}).Decode(&app))
+	assert.Equal(t, domain.ApplicationStatusCancelled, app.Status)
+	assert.Empty(t, app.ApplicationDetails.Message)
+	assert.Equal(t, "2026-01-01", app.ApplicationDetails.StartDate)
+
+	// 5. 原本的 token 已失效
+	w = do("GET", "/api/v1/user/me", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSuspendHost_PausesPublicOpportunities(t *testing.T) {
+	ctx := context.Background()
+	db := testCollection.Database()
+	hostRepo := repository.NewHostRepository(db.Collection("hosts"))
+	oppRepo := repository.NewOpportunityRepository(db.Collection("opportunities"))
+	hostService := service.NewHostService(hostRepo, oppRepo, testConfig)
+
+	host := &domain.Host{UserID: primitive.NewObjectID(), Name: "Suspended Farm", Slug: "suspended-farm-" + primitive.NewObjectID().Hex(), Status: domain.HostStatusActive, Verified: true}
+	assert.NoError(t, hostRepo.Create(ctx, host))
+
+	// 上架中與已額滿的工作機會都是公開的，草稿不受影響
+	seeded := map[domain.OpportunityStatus]*domain.Opportunity{}
+	for _, status := range []domain.OpportunityStatus{domain.OpportunityStatusActive, domain.OpportunityStatusFilled, domain.OpportunityStatusDraft} {
+		id := primitive.NewObjectID().Hex()
+		opp := &domain.Opportunity{HostID: host.ID, Title: string(status), Slug: "opp-" + id, PublicID: id, Status: status}
+		assert.NoError(t, oppRepo.Create(ctx, opp))
+		seeded[status] = opp
+	}
+
+	adminID := primitive.NewObjectID().Hex()
+	_, err := hostService.ChangeStatus(ctx, host.ID.Hex(), domain.HostStatusSuspended, domain.ActorAdmin, adminID, "fake listing")
+	assert.NoError(t, err)
+
+	for _, status := range []domain.OpportunityStatus{domain.OpportunityStatusActive, domain.OpportunityStatusFilled} {
+		opp, err := oppRepo.GetByID(ctx, seeded[status].ID.Hex())
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OpportunityStatusPaused, opp.Status, "%s listing should be paused", status)
+		assert.Equal(t, "host suspended", opp.StatusNote)
+		assert.NotEmpty(t, opp.StatusHistory)
+	}
+	draft, err := oppRepo.GetByID(ctx, seeded[domain.OpportunityStatusDraft].ID.Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OpportunityStatusDraft, draft.Status)
+}
@@ -2,26 +2,43 @@ package api
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/service"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/validation"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // UserHandler 負責處理與使用者相關的 HTTP 請求
 type UserHandler struct {
-	userService service.UserService
+	userService       service.UserService
+	sessionService    service.SessionService
+	oauthService      service.OAuthService
+	emailVerification service.EmailVerificationService
+	passwordService   service.PasswordService
+	mfaService        service.MFAService
+	phoneVerification service.PhoneVerificationService
+	accountService    service.AccountService
 }
 
 // NewUserHandler 建立一個新的 UserHandler 實例
-func NewUserHandler(userService service.UserService) *UserHandler {
+func NewUserHandler(userService service.UserService, sessionService service.SessionService, oauthService service.OAuthService, emailVerification service.EmailVerificationService, passwordService service.PasswordService, mfaService service.MFAService, phoneVerification service.PhoneVerificationService, accountService service.AccountService) *UserHandler {
 	return &UserHandler{
-		userService: userService,
+		userService:       userService,
+		sessionService:    sessionService,
+		oauthService:      oauthService,
+		emailVerification: emailVerification,
+		passwordService:   passwordService,
+		mfaService:        mfaService,
+		phoneVerification: phoneVerification,
+		accountService:    accountService,
 	}
 }
 
@@ -35,9 +52,69 @@ type RegisterRequest struct {
 // LoginRequest 定義了登入請求的資料結構，以支援多種登入方式
 type LoginRequest struct {
 	LoginType string `json:"loginType" binding:"required,oneof=password google apple"`
-	Email     string `json:"email" binding:"required,email"`
-	Password  string `json:"password"` // 密碼登入時為必需
-	Token     string `json:"token"`    // OAuth 登入時為必需
+	Email     string `json:"email" binding:"omitempty,email"` // 密碼登入時為必需
+	Password  string `json:"password"`                        // 密碼登入時為必需
+	Token     string `json:"token"`                           // OAuth 登入時為必需 (ID token)
+	Nonce     string `json:"nonce"`                           // OAuth 登入時使用的原始 nonce
+	Name      string `json:"name"`                            // Apple 第一次登入時由客戶端提供的姓名
+}
+
+// AppleNotificationRequest 是 Apple server-to-server 通知的資料結構
+type AppleNotificationRequest struct {
+	Payload string `json:"payload" binding:"required"`
+}
+
+// VerifyEmailRequest 定義了驗證 email 請求的資料結構
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ForgotPasswordRequest 定義了忘記密碼請求的資料結構
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest 定義了重設密碼請求的資料結構
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=8"`
+}
+
+// ChangePasswordRequest 定義了變更密碼請求的資料結構
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required,min=8"`
+}
+
+// MFALoginRequest 定義了兩步驟驗證登入請求的資料結構
+type MFALoginRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required"` // TOTP 驗證碼或復原碼
+}
+
+// MFACodeRequest 定義了需要兩步驟驗證碼的請求資料結構
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// SendPhoneCodeRequest 定義了寄送手機驗證碼請求的資料結構
+type SendPhoneCodeRequest struct {
+	PhoneNumber string `json:"phoneNumber" binding:"required"`
+}
+
+// VerifyPhoneRequest 定義了驗證手機號碼請求的資料結構
+type VerifyPhoneRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DeleteAccountRequest 定義了刪除帳號請求的資料結構
+type DeleteAccountRequest struct {
+	Password string `json:"password"` // 有設定密碼的帳號必須提供，沒有密碼的帳號需先重新登入
+}
+
+// RefreshRequest 定義了換發 token 請求的資料結構
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
 }
 
 // UpdateUserRequest 定義了更新使用者資訊請求的資料結構。
@@ -63,25 +140,21 @@ type UpdateUserRequest struct {
 	PhoneNumber             *string                         `json:"phoneNumber,omitempty"`
 	Address                 *string                         `json:"address,omitempty"`
 	PreferredWorkHours      *int                            `json:"preferredWorkHours,omitempty"`
+	Locale                  *string                         `json:"locale,omitempty"`
 }
 
 // Register 處理使用者註冊請求
 func (h *UserHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	// 1. 綁定並驗證請求資料
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// 2. 呼叫 Service 層執行業務邏輯
 	user, err := h.userService.RegisterUser(c.Request.Context(), req.Name, req.Email, req.Password)
 	if err != nil {
-		if errors.Is(err, service.ErrEmailAlreadyExists) {
-			c.JSON(http.StatusConflict, gin.H{"error": "email already exists"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to register user"})
+		c.Error(err)
 		return
 	}
 
@@ -93,56 +166,133 @@ func (h *UserHandler) Register(c *gin.Context) {
 func (h *UserHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	// 1. 綁定並驗證請求資料
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// 2. 根據登入類型執行不同邏輯
 	switch req.LoginType {
 	case "password":
-		// 驗證密碼是否存在
-		if req.Password == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "password is required for this login type"})
+		// 驗證 email 與密碼是否存在
+		v := validation.New()
+		v.Required("email", req.Email)
+		v.Required("password", req.Password)
+		if !checkValid(c, v.Err()) {
 			return
 		}
 		h.handlePasswordLogin(c, req)
 	case "google":
-		// TODO: 實作 Google OAuth 登入
-		c.JSON(http.StatusNotImplemented, gin.H{"error": "Google login not implemented yet"})
+		h.handleOAuthLogin(c, domain.ProviderGoogle, req)
 	case "apple":
-		// TODO: 實作 Apple OAuth 登入
-		c.JSON(http.StatusNotImplemented, gin.H{"error": "Apple login not implemented yet"})
+		h.handleOAuthLogin(c, domain.ProviderApple, req)
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid login type"})
+		c.Error(errcode.BadRequest("INVALID_LOGIN_TYPE", "invalid login type"))
 	}
 }
 
 // handlePasswordLogin 處理傳統的密碼登入
 func (h *UserHandler) handlePasswordLogin(c *gin.Context, req LoginRequest) {
-	user, token, err := h.userService.LoginUser(c.Request.Context(), req.Email, req.Password)
+	result, err := h.userService.LoginUser(c.Request.Context(), req.Email, req.Password, sessionMeta(c))
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidCredentials) {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
-			return
+		c.Error(err)
+		return
+	}
+
+	respondWithLogin(c, result)
+}
+
+// handleOAuthLogin 處理第三方登入，驗證 ID token 後登入、綁定或自動註冊
+func (h *UserHandler) handleOAuthLogin(c *gin.Context, provider domain.AuthProvider, req LoginRequest) {
+	v := validation.New()
+	v.Required("token", req.Token)
+	if !checkValid(c, v.Err()) {
+		return
+	}
+
+	result, err := h.oauthService.Login(c.Request.Context(), service.OAuthLoginRequest{
+		Provider: provider,
+		IDToken:  req.Token,
+		Nonce:    req.Nonce,
+		Name:     req.Name,
+	}, sessionMeta(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	respondWithLogin(c, result)
+}
+
+// AppleNotification 接收 Apple server-to-server 通知 (撤銷授權、刪除帳號等)
+func (h *UserHandler) AppleNotification(c *gin.Context) {
+	var req AppleNotificationRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	err := h.oauthService.HandleAppleNotification(c.Request.Context(), req.Payload)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// respondWithLogin 回傳登入結果。
+// 需要兩步驟驗證時只回傳 challenge token，客戶端需再呼叫 /auth/mfa/verify。
+func respondWithLogin(c *gin.Context, result *service.LoginResult) {
+	if result.MFARequired {
+		c.JSON(http.StatusOK, gin.H{
+			"mfaRequired":    true,
+			"challengeToken": result.ChallengeToken,
+			"expiresIn":      result.ChallengeExpiresIn,
+		})
+		return
+	}
+
+	response := gin.H{
+		"user":         result.User,
+		"token":        result.Tokens.AccessToken,
+		"refreshToken": result.Tokens.RefreshToken,
+		"expiresIn":    result.Tokens.ExpiresIn,
+	}
+	if result.MFAEnrollmentRequired {
+		response["mfaEnrollmentRequired"] = true
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// Refresh 使用 refresh token 換發新的 access token，並輪替 refresh token
+func (h *UserHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	tokens, err := h.sessionService.RefreshSession(c.Request.Context(), req.RefreshToken, sessionMeta(c))
+	if err != nil {
+		// 不讓客戶端區分 token 無效或被重複使用
+		if errors.Is(err, service.ErrRefreshTokenReused) {
+			err = service.ErrInvalidRefreshToken.WithCause(err)
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to login user"})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"user":  user,
-		"token": token,
+		"token":        tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+		"expiresIn":    tokens.ExpiresIn,
 	})
 }
 
-// Logout 處理使用者登出請求
+// Logout 處理使用者登出請求，撤銷目前 access token 所屬的 session
 func (h *UserHandler) Logout(c *gin.Context) {
 	// 呼叫 Service 層執行登出邏輯
-	err := h.userService.LogoutUser(c.Request.Context())
+	err := h.userService.LogoutUser(c.Request.Context(), c.GetString("sessionID"))
 	if err != nil {
-		// 理論上目前版本的 LogoutUser 不會回傳錯誤，但保留以備未來擴充
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to logout user"})
+		c.Error(err)
 		return
 	}
 
@@ -150,11 +300,287 @@ func (h *UserHandler) Logout(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "logout successful"})
 }
 
+// SessionResponse 是回傳給使用者的裝置 session 資訊
+type SessionResponse struct {
+	*domain.Session
+	Current bool `json:"current"` // 是否為發出此請求的 session
+}
+
+// ListSessions 列出當前使用者所有已登入的裝置
+func (h *UserHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.sessionService.ListUserSessions(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	currentID := c.GetString("sessionID")
+	response := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, SessionResponse{
+			Session: session,
+			Current: session.ID.Hex() == currentID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+// RevokeSession 登出當前使用者的某個裝置
+func (h *UserHandler) RevokeSession(c *gin.Context) {
+	err := h.sessionService.RevokeUserSession(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "session revoked"})
+}
+
+// RevokeOtherSessions 登出當前裝置以外的所有裝置
+func (h *UserHandler) RevokeOtherSessions(c *gin.Context) {
+	count, err := h.sessionService.RevokeAllUserSessions(c.Request.Context(), c.GetString("userID"), c.GetString("sessionID"), service.SessionRevokedByUser)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "other sessions revoked", "revoked": count})
+}
+
+// VerifyEmail 以驗證信中的 token 完成 email 驗證
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.emailVerification.VerifyEmail(c.Request.Context(), req.Token)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "email verified", "emailVerified": user.EmailVerified})
+}
+
+// ResendVerification 重新寄送驗證信給當前使用者
+func (h *UserHandler) ResendVerification(c *gin.Context) {
+	err := h.emailVerification.SendVerification(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "verification email sent"})
+}
+
+// SendPhoneCode 寄送簡訊驗證碼到當前使用者提供的手機號碼
+func (h *UserHandler) SendPhoneCode(c *gin.Context) {
+	var req SendPhoneCodeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	err := h.phoneVerification.SendCode(c.Request.Context(), c.GetString("userID"), req.PhoneNumber)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "verification code sent"})
+}
+
+// VerifyPhone 以簡訊驗證碼完成手機號碼驗證
+func (h *UserHandler) VerifyPhone(c *gin.Context) {
+	var req VerifyPhoneRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	phone, err := h.phoneVerification.VerifyCode(c.Request.Context(), c.GetString("userID"), req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "phone number verified", "phoneNumber": phone})
+}
+
+// ExportMe 匯出當前使用者的個人資料，預設為 ZIP，?format=json 時直接回傳 JSON
+func (h *UserHandler) ExportMe(c *gin.Context) {
+	export, err := h.accountService.ExportData(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	writeDataExport(c, export)
+}
+
+// DeleteMe 刪除當前使用者的帳號，個人資料會被匿名化且無法復原
+func (h *UserHandler) DeleteMe(c *gin.Context) {
+	var req DeleteAccountRequest
+	// 沒有密碼的帳號 (僅使用第三方登入) 可以不帶 body
+	if c.Request.ContentLength > 0 {
+		if !bindJSON(c, &req) {
+			return
+		}
+	}
+
+	err := h.accountService.DeleteAccount(c.Request.Context(), c.GetString("userID"), c.GetString("sessionID"), req.Password)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "account deleted"})
+}
+
+// ForgotPassword 寄送重設密碼信。
+// 無論 email 是否已註冊都回傳相同的響應，避免洩漏帳號是否存在。
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.passwordService.ForgotPassword(c.Request.Context(), req.Email, c.ClientIP()); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "if the email is registered, a password reset link has been sent"})
+}
+
+// ResetPassword 以重設信中的 token 設定新密碼
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	err := h.passwordService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password has been reset"})
+}
+
+// ChangePassword 變更當前使用者的密碼，並登出其他裝置
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	var req ChangePasswordRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	err := h.passwordService.ChangePassword(c.Request.Context(), c.GetString("userID"), c.GetString("sessionID"), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "password changed"})
+}
+
+// VerifyMFA 以 challenge token 與驗證碼完成兩步驟驗證登入
+func (h *UserHandler) VerifyMFA(c *gin.Context) {
+	var req MFALoginRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.mfaService.CompleteLogin(c.Request.Context(), req.ChallengeToken, req.Code, sessionMeta(c))
+	if err != nil {
+		// 登入流程中驗證碼錯誤視為驗證失敗
+		if errors.Is(err, service.ErrInvalidMFACode) {
+			err = service.ErrInvalidMFACode.WithStatus(http.StatusUnauthorized)
+		}
+		c.Error(err)
+		return
+	}
+
+	respondWithLogin(c, result)
+}
+
+// BeginMFAEnrollment 產生 TOTP 密鑰與 provisioning URI
+func (h *UserHandler) BeginMFAEnrollment(c *gin.Context) {
+	enrollment, err := h.mfaService.BeginEnrollment(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmMFAEnrollment 以驗證碼確認設定並啟用兩步驟驗證，回傳一次性的復原碼
+func (h *UserHandler) ConfirmMFAEnrollment(c *gin.Context) {
+	var req MFACodeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmEnrollment(c.Request.Context(), c.GetString("userID"), req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recoveryCodes": codes})
+}
+
+// RegenerateRecoveryCodes 產生新的一組復原碼
+func (h *UserHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req MFACodeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(c.Request.Context(), c.GetString("userID"), req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recoveryCodes": codes})
+}
+
+// DisableMFA 停用兩步驟驗證
+func (h *UserHandler) DisableMFA(c *gin.Context) {
+	var req MFACodeRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.mfaService.Disable(c.Request.Context(), c.GetString("userID"), req.Code); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+}
+
+// sessionMeta 從請求中擷取裝置資訊，用於記錄 session
+func sessionMeta(c *gin.Context) service.SessionMeta {
+	return service.SessionMeta{
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+}
+
+// setRetryAfter 設定 Retry-After header (秒，無條件進位)
+func setRetryAfter(c *gin.Context, d time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+}
+
 // GetAllUsers 處理取得所有使用者的請求 (僅限管理員)
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
 	users, err := h.userService.GetAllUsers(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get users"})
+		c.Error(err)
 		return
 	}
 
@@ -167,11 +593,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 
 	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user"})
+		c.Error(err)
 		return
 	}
 
@@ -187,8 +609,7 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 
 	// 2. 綁定請求資料
 	var req UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &req) || !checkValid(c, validateUpdateUser(&req)) {
 		return
 	}
 
@@ -198,6 +619,9 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 	if req.Name != nil {
 		payload["name"] = *req.Name
 	}
+	if req.Locale != nil {
+		payload["locale"] = *req.Locale
+	}
 	// 注意：對於 Profile 內嵌結構，我們使用點表示法來更新特定欄位
 	if req.Avatar != nil {
 		payload["profile.avatar"] = *req.Avatar
@@ -263,12 +687,7 @@ func (h *UserHandler) UpdateMe(c *gin.Context) {
 	// 4. 呼叫 Service 層執行更新
 	updatedUser, err := h.userService.UpdateUser(c.Request.Context(), userID, payload)
 	if err != nil {
-		// 這邊可以根據 service 回傳的 error 類型做更細緻的處理
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update user"})
+		c.Error(err)
 		return
 	}
 
@@ -281,33 +700,33 @@ func (h *UserHandler) GetMe(c *gin.Context) {
 	// 從 context 中取得 AuthMiddleware 注入的 userClaims
 	claims, exists := c.Get("userClaims")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user claims not found"})
+		c.Error(errcode.ErrUnauthorized)
 		return
 	}
 
 	mapClaims, ok := claims.(jwt.MapClaims)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid claims format"})
+		c.Error(errcode.ErrUnauthorized)
 		return
 	}
 
 	// 從 claims 中取得使用者 ID (sub)
 	userID, ok := mapClaims["sub"].(string)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id in token"})
+		c.Error(errcode.ErrUnauthorized)
 		return
 	}
 
 	// 複用 GetUserByID 服務
 	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "user not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get user information"})
+		c.Error(err)
 		return
 	}
 
+	// 附上個人檔案完整度，供前端顯示引導清單
+	completeness := user.Profile.Completeness()
+	user.ProfileCompleteness = &completeness
+
 	c.JSON(http.StatusOK, user)
 }
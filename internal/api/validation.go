@@ -0,0 +1,307 @@
+package api
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/validation"
+)
+
+// 個人檔案文字欄位的長度上限
+const (
+	maxNameLength  = 100
+	maxTitleLength = 200
+	maxBioLength   = 2000
+	maxNoteLength  = 1000
+)
+
+// bindJSON 綁定請求內容，失敗時回傳 400 與逐欄錯誤
+func bindJSON(c *gin.Context, obj any) bool {
+	if err := c.ShouldBindJSON(obj); err != nil {
+		c.Error(validation.FromBindError(err).WithCause(err))
+		return false
+	}
+	return true
+}
+
+// validateUpdateUser 驗證 UpdateMe 的請求內容，只檢查有提供的欄位
+func validateUpdateUser(req *UpdateUserRequest) error {
+	v := validation.New()
+
+	if req.Name != nil {
+		v.Required("name", *req.Name)
+		v.MaxLength("name", *req.Name, maxNameLength)
+	}
+	if req.Bio != nil {
+		v.MaxLength("bio", *req.Bio, maxBioLength)
+	}
+	v.LanguageCodes("languages", req.Languages)
+	if req.Location != nil {
+		v.Check(req.Location.Type == "Point", "location.type", validation.RuleOneOf, "must be \"Point\"")
+		v.Coordinates("location.coordinates", req.Location.Coordinates)
+	}
+	if req.SocialMedia != nil {
+		validateSocialMedia(v, "socialMedia", req.SocialMedia)
+	}
+	if req.PersonalInfo != nil && req.PersonalInfo.Birthdate != nil {
+		v.NotFuture("personalInfo.birthdate", *req.PersonalInfo.Birthdate)
+	}
+	if p := req.WorkExchangePreferences; p != nil {
+		if p.AvailableFrom != nil && p.AvailableTo != nil {
+			v.Check(!p.AvailableTo.Before(*p.AvailableFrom), "workExchangePreferences.availableTo", validation.RuleOrder, "must not be before availableFrom")
+		}
+		v.Check(p.MinDuration >= 0, "workExchangePreferences.minDuration", validation.RuleRange, "must not be negative")
+		if p.MaxDuration > 0 {
+			v.Check(p.MaxDuration >= p.MinDuration, "workExchangePreferences.maxDuration", validation.RuleOrder, "must not be less than minDuration")
+		}
+	}
+	if req.BirthDate != nil {
+		v.NotFuture("birthDate", *req.BirthDate)
+	}
+	if ec := req.EmergencyContact; ec != nil {
+		v.Required("emergencyContact.name", ec.Name)
+		v.Required("emergencyContact.phone", ec.Phone)
+		v.Phone("emergencyContact.phone", ec.Phone)
+		v.Email("emergencyContact.email", ec.Email)
+	}
+	for i, exp := range req.WorkExperience {
+		v.Required(validation.Field(validation.Index("workExperience", i), "title"), exp.Title)
+	}
+	if req.PhoneNumber != nil {
+		v.Phone("phoneNumber", *req.PhoneNumber)
+	}
+	if req.PreferredWorkHours != nil {
+		v.Range("preferredWorkHours", *req.PreferredWorkHours, 0, 24)
+	}
+	if req.Locale != nil {
+		v.Required("locale", *req.Locale)
+		v.Locale("locale", *req.Locale)
+	}
+
+	return v.Err()
+}
+
+func validateSocialMedia(v *validation.Validator, field string, sm *domain.SocialMedia) {
+	v.URL(validation.Field(field, "instagram"), sm.Instagram)
+	v.URL(validation.Field(field, "facebook"), sm.Facebook)
+	v.URL(validation.Field(field, "threads"), sm.Threads)
+	v.URL(validation.Field(field, "linkedin"), sm.Linkedin)
+	v.URL(validation.Field(field, "twitter"), sm.Twitter)
+	v.URL(validation.Field(field, "youtube"), sm.Youtube)
+	v.URL(validation.Field(field, "tiktok"), sm.Tiktok)
+	v.URL(validation.Field(field, "website"), sm.Website)
+	for i, other := range sm.Other {
+		item := validation.Index(validation.Field(field, "other"), i)
+		v.Required(validation.Field(item, "name"), other.Name)
+		v.Required(validation.Field(item, "url"), other.URL)
+		v.URL(validation.Field(item, "url"), other.URL)
+	}
+}
+
+// validateHost 驗證建立或更新接待主的請求內容
+func validateHost(host *domain.Host) error {
+	v := validation.New()
+
+	v.Required("name", host.Name)
+	v.MaxLength("name", host.Name, maxNameLength)
+	v.Locale("defaultLocale", host.DefaultLocale)
+	for locale := range host.Translations {
+		v.Locale(validation.Field("translations", locale), locale)
+	}
+	v.Email("email", host.Email)
+	v.Phone("mobile", host.Mobile)
+
+	v.Email("contactInfo.contactEmail", host.ContactInfo.ContactEmail)
+	v.Phone("contactInfo.phone", host.ContactInfo.Phone)
+	v.Phone("contactInfo.contactMobile", host.ContactInfo.ContactMobile)
+	v.URL("contactInfo.website", host.ContactInfo.Website)
+	if sm := host.ContactInfo.SocialMedia; sm != nil {
+		for i, other := range sm.Other {
+			v.URL(validation.Field(validation.Index("contactInfo.socialMedia.other", i), "url"), other.URL)
+		}
+	}
+
+	if host.Location.Coordinates != nil {
+		v.Coordinates("location.coordinates.coordinates", host.Location.Coordinates.Coordinates)
+	}
+	if host.VideoIntroduction != nil {
+		v.URL("videoIntroduction.url", host.VideoIntroduction.URL)
+	}
+	if host.AdditionalMedia != nil {
+		v.URL("additionalMedia.virtualTour", host.AdditionalMedia.VirtualTour)
+	}
+
+	d := host.Details
+	if d.FoundingYear != 0 {
+		v.Range("details.foundingYear", d.FoundingYear, 1800, time.Now().Year())
+	}
+	v.LanguageCodes("details.languages", d.Languages)
+	v.Range("details.workDaysPerWeek", d.WorkDaysPerWeek, 0, 7)
+	v.Range("details.workHoursPerWeek", d.WorkHoursPerWeek, 0, 168)
+	if d.MaxStayDuration > 0 {
+		v.Check(d.MaxStayDuration >= d.MinStayDuration, "details.maxStayDuration", validation.RuleOrder, "must not be less than minStayDuration")
+	}
+
+	return v.Err()
+}
+
+// validateOpportunity 驗證建立或更新機會的請求內容
+func validateOpportunity(opp *domain.Opportunity) error {
+	v := validation.New()
+
+	v.Required("title", opp.Title)
+	v.MaxLength("title", opp.Title, maxTitleLength)
+	v.Locale("defaultLocale", opp.DefaultLocale)
+	for locale, t := range opp.Translations {
+		field := validation.Field("translations", locale)
+		v.Locale(field, locale)
+		v.MaxLength(validation.Field(field, "title"), t.Title, maxTitleLength)
+	}
+
+	v.LanguageCodes("workDetails.languages", opp.WorkDetails.Languages)
+	for i, month := range opp.WorkDetails.AvailableMonths {
+		v.Range(validation.Index("workDetails.availableMonths", i), month, 1, 12)
+	}
+
+	r := opp.Requirements
+	v.Range("requirements.minAge", r.MinAge, 0, 120)
+	v.Range("requirements.maxAge", r.MaxAge, 0, 120)
+	if r.MaxAge > 0 {
+		v.Check(r.MaxAge >= r.MinAge, "requirements.maxAge", validation.RuleOrder, "must not be less than minAge")
+	}
+	for i, f := range r.RequiredProfileFields {
+		v.Check(f.IsValid(), validation.Index("requirements.requiredProfileFields", i), validation.RuleOneOf, "unknown profile field")
+	}
+
+	v.URL("media.videoUrl", opp.Media.VideoURL)
+	v.URL("media.virtualTour", opp.Media.VirtualTour)
+
+	if opp.Location.Coordinates != nil {
+		v.Coordinates("location.coordinates.coordinates", opp.Location.Coordinates.Coordinates)
+	}
+
+	for i, slot := range opp.TimeSlots {
+		field := validation.Index("timeSlots", i)
+		start, okStart := v.Date(validation.Field(field, "startDate"), slot.StartDate)
+		end, okEnd := v.Date(validation.Field(field, "endDate"), slot.EndDate)
+		if okStart && okEnd {
+			v.Check(!end.Before(start), validation.Field(field, "endDate"), validation.RuleOrder, "must not be before startDate")
+		}
+		v.Range(validation.Field(field, "workDaysPerWeek"), slot.WorkDaysPerWeek, 0, 7)
+		v.Range(validation.Field(field, "workHoursPerDay"), slot.WorkHoursPerDay, 0, 24)
+	}
+
+	return v.Err()
+}
+
+// checkValid 在 err 不為 nil 時回報錯誤並回傳 false
+func checkValid(c *gin.Context, err error) bool {
+	if err != nil {
+		c.Error(err)
+		return false
+	}
+	return true
+}
+
+// validateHostReview 驗證接待主審核請求，拒絕或退回修改時必須說明原因
+func validateHostReview(decision domain.HostReviewDecision, note string) error {
+	v := validation.New()
+
+	switch decision {
+	case domain.HostReviewApprove:
+	case domain.HostReviewReject, domain.HostReviewRequestChanges:
+		v.Required("note", note)
+	default:
+		v.Add("decision", validation.RuleOneOf, "must be one of APPROVE, REJECT, REQUEST_CHANGES")
+	}
+	v.MaxLength("note", note, maxNoteLength)
+
+	return v.Err()
+}
+
+// validateHostStatusChange 驗證接待主狀態變更請求，可否變更由狀態機判斷
+func validateHostStatusChange(status domain.HostStatus, note string) error {
+	v := validation.New()
+
+	v.Required("status", string(status))
+	v.MaxLength("note", note, maxNoteLength)
+
+	return v.Err()
+}
+
+// validateStatusReason 驗證狀態變更附帶的原因 (選填)
+func validateStatusReason(reason string) error {
+	v := validation.New()
+	v.MaxLength("reason", reason, maxNoteLength)
+	return v.Err()
+}
+
+// validateOpportunityStatusChange 驗證管理員變更工作機會狀態的請求，拒絕與暫停時必須說明原因
+func validateOpportunityStatusChange(status domain.OpportunityStatus, reason string) error {
+	v := validation.New()
+
+	v.Required("status", string(status))
+	if status == domain.OpportunityStatusRejected || status == domain.OpportunityStatusAdminPaused {
+		v.Required("reason", reason)
+	}
+	v.MaxLength("reason", reason, maxNoteLength)
+
+	return v.Err()
+}
+
+// validateOpportunityReview 驗證工作機會審核結果，拒絕時必須說明原因
+func validateOpportunityReview(decision domain.OpportunityReviewDecision, note string) error {
+	v := validation.New()
+
+	switch decision {
+	case domain.OpportunityReviewApprove:
+	case domain.OpportunityReviewReject:
+		v.Required("note", note)
+	default:
+		v.Add("decision", validation.RuleOneOf, "must be one of APPROVE, REJECT")
+	}
+	v.MaxLength("note", note, maxNoteLength)
+
+	return v.Err()
+}
+
+// validateOrganization 驗證組織資料
+func validateOrganization(org *domain.Organization) error {
+	v := validation.New()
+
+	v.Required("name", org.Name)
+	v.MaxLength("name", org.Name, maxNameLength)
+	v.Check(org.Type.IsValid(), "type", validation.RuleOneOf, "must be one of NGO, HOSTEL_CHAIN, COMPANY, OTHER")
+	v.MaxLength("description", org.Description, maxBioLength)
+	v.URL("website", org.Website)
+	v.Email("contactEmail", org.ContactEmail)
+
+	return v.Err()
+}
+
+// validateOrganizationMember 驗證新增成員或變更角色的請求，變更角色時 requireEmail 為 false
+func validateOrganizationMember(email string, role domain.OrganizationRole, requireEmail bool) error {
+	v := validation.New()
+
+	if requireEmail {
+		v.Required("email", email)
+		v.Email("email", email)
+	}
+	v.Check(role.IsValid(), "role", validation.RuleOneOf, "must be one of OWNER, MANAGER, REVIEWER")
+
+	return v.Err()
+}
+
+// validateHostMember 驗證邀請共同管理者或變更角色的請求，變更角色時 requireEmail 為 false
+func validateHostMember(email string, role domain.HostMemberRole, requireEmail bool) error {
+	v := validation.New()
+
+	if requireEmail {
+		v.Required("email", email)
+		v.Email("email", email)
+	}
+	v.Check(role.IsValid(), "role", validation.RuleOneOf, "must be one of OWNER, EDITOR, REVIEWER")
+
+	return v.Err()
+}
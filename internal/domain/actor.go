@@ -0,0 +1,10 @@
+package domain
+
+// Actor 是執行狀態變更的一方，狀態機依此決定可以進行哪些變更
+type Actor string
+
+const (
+	ActorHost   Actor = "HOST"   // 接待主本人
+	ActorAdmin  Actor = "ADMIN"  // 後台管理員
+	ActorSystem Actor = "SYSTEM" // 系統自動處理
+)
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// DataExport 是使用者個人資料匯出的內容，包含使用者文件與其關聯資料
+type DataExport struct {
+	GeneratedAt   time.Time       `json:"generatedAt"`
+	User          *User           `json:"user"`
+	Applications  []*Application  `json:"applications"`
+	Bookmarks     []*Bookmark     `json:"bookmarks"`
+	Notifications []*Notification `json:"notifications"`
+	Images        []*Image        `json:"images"`
+}
@@ -17,6 +17,41 @@ const (
 	HostStatusEditing   HostStatus = "EDITING"
 )
 
+// hostTransitions 定義接待主狀態的變更：目前狀態 -> 目標狀態 -> 可執行的一方。
+// PENDING 的變更即為後台驗證審核；停權 (SUSPENDED) 只能由管理員解除；
+// 建立者刪除帳號時由系統停用接待主。
+var hostTransitions = map[HostStatus]map[HostStatus][]Actor{
+	HostStatusPending: {
+		HostStatusActive:   {ActorAdmin},
+		HostStatusRejected: {ActorAdmin},
+		HostStatusEditing:  {ActorAdmin},
+	},
+	HostStatusEditing:  {HostStatusPending: {ActorHost}},
+	HostStatusRejected: {HostStatusPending: {ActorHost}},
+	HostStatusActive: {
+		HostStatusInactive:  {ActorHost, ActorAdmin, ActorSystem},
+		HostStatusSuspended: {ActorAdmin},
+	},
+	HostStatusInactive: {
+		HostStatusActive:    {ActorHost, ActorAdmin},
+		HostStatusSuspended: {ActorAdmin},
+	},
+	HostStatusSuspended: {
+		HostStatusActive:   {ActorAdmin},
+		HostStatusInactive: {ActorAdmin},
+	},
+}
+
+// CanTransitionTo 回傳 actor 是否可以將狀態從 s 變更為 to
+func (s HostStatus) CanTransitionTo(to HostStatus, actor Actor) bool {
+	for _, a := range hostTransitions[s][to] {
+		if a == actor {
+			return true
+		}
+	}
+	return false
+}
+
 type HostType string
 
 const (
@@ -39,6 +74,44 @@ const (
 	HostTypeCommunityCenter HostType = "COMMUNITY_CENTER"
 )
 
+// HostDocumentType 是接待主驗證文件的種類
+type HostDocumentType string
+
+const (
+	HostDocumentBusinessRegistration HostDocumentType = "BUSINESS_REGISTRATION" // 營業登記
+	HostDocumentFarmLicense          HostDocumentType = "FARM_LICENSE"          // 農業相關登記證明
+	HostDocumentIDCard               HostDocumentType = "ID_CARD"               // 負責人身分證件
+)
+
+// HostDocumentTypes 列出所有可上傳的驗證文件種類
+var HostDocumentTypes = []HostDocumentType{HostDocumentBusinessRegistration, HostDocumentFarmLicense, HostDocumentIDCard}
+
+// IsValid 回傳是否為已定義的文件種類
+func (t HostDocumentType) IsValid() bool {
+	for _, known := range HostDocumentTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// HostVerificationDocument 是接待主上傳的驗證文件，檔案只存放於私有 bucket
+type HostVerificationDocument struct {
+	Type       HostDocumentType   `bson:"type" json:"type"`
+	ImageID    primitive.ObjectID `bson:"imageId" json:"imageId"`
+	UploadedAt time.Time          `bson:"uploadedAt" json:"uploadedAt"`
+}
+
+// HostReviewDecision 是管理員對驗證申請的審核結果
+type HostReviewDecision string
+
+const (
+	HostReviewApprove        HostReviewDecision = "APPROVE"         // 通過，接待主成為 ACTIVE 並標記為已驗證
+	HostReviewReject         HostReviewDecision = "REJECT"          // 拒絕
+	HostReviewRequestChanges HostReviewDecision = "REQUEST_CHANGES" // 退回修改 (EDITING)，修改後可重新送審
+)
+
 type HostStatusHistory struct {
 	Status     HostStatus         `bson:"status" json:"status"`
 	StatusNote string             `bson:"statusNote,omitempty" json:"statusNote,omitempty"`
@@ -182,4 +255,17 @@ type Host struct {
 	OrganizationID    *primitive.ObjectID `bson:"organizationId,omitempty" json:"organizationId,omitempty"`
 	CreatedAt         time.Time           `bson:"createdAt" json:"createdAt"`
 	UpdatedAt         time.Time           `bson:"updatedAt" json:"updatedAt"`
+
+	// 多語系內容：Description 為 DefaultLocale 的版本，其他語系放在 Translations
+	DefaultLocale    string                     `bson:"defaultLocale,omitempty" json:"defaultLocale,omitempty"`
+	Translations     map[string]HostTranslation `bson:"translations,omitempty" json:"translations,omitempty"`
+	Locale           string                     `bson:"-" json:"locale,omitempty"`           // 回應內容實際使用的語系，由 Localize 設定
+	AvailableLocales []string                   `bson:"-" json:"availableLocales,omitempty"` // 由 Localize 設定
+
+	// 驗證文件與最近一次送審時間，送審後狀態為 PENDING 並出現在後台審核佇列
+	VerificationDocuments   []HostVerificationDocument `bson:"verificationDocuments,omitempty" json:"verificationDocuments,omitempty"`
+	VerificationSubmittedAt *time.Time                 `bson:"verificationSubmittedAt,omitempty" json:"verificationSubmittedAt,omitempty"`
+
+	// 共同管理者 (co-host)，UserID 為建立者且一律視為 OWNER，不會出現在 Members
+	Members []HostMember `bson:"members,omitempty" json:"members,omitempty"`
 }
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HostMemberRole 是接待主共同管理者的角色
+type HostMemberRole string
+
+const (
+	HostMemberRoleOwner    HostMemberRole = "OWNER"
+	HostMemberRoleEditor   HostMemberRole = "EDITOR"
+	HostMemberRoleReviewer HostMemberRole = "REVIEWER"
+)
+
+// hostMemberRolePermissions 定義各共同管理者角色擁有的權限
+var hostMemberRolePermissions = map[HostMemberRole][]MemberPermission{
+	HostMemberRoleOwner: {
+		MemberPermissionManageTeam,
+		MemberPermissionEditListings,
+		MemberPermissionReviewApplications,
+		MemberPermissionViewDashboard,
+	},
+	HostMemberRoleEditor: {
+		MemberPermissionEditListings,
+		MemberPermissionViewDashboard,
+	},
+	HostMemberRoleReviewer: {
+		MemberPermissionReviewApplications,
+		MemberPermissionViewDashboard,
+	},
+}
+
+// IsValid 回傳是否為已定義的共同管理者角色
+func (r HostMemberRole) IsValid() bool {
+	_, ok := hostMemberRolePermissions[r]
+	return ok
+}
+
+// HasPermission 回傳角色是否擁有指定權限
+func (r HostMemberRole) HasPermission(permission MemberPermission) bool {
+	for _, p := range hostMemberRolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HostMember 是接待主的共同管理者，使用者同時只能管理一個接待主
+type HostMember struct {
+	UserID  primitive.ObjectID `bson:"userId" json:"userId"`
+	Role    HostMemberRole     `bson:"role" json:"role"`
+	AddedBy primitive.ObjectID `bson:"addedBy,omitempty" json:"addedBy,omitempty"`
+	AddedAt time.Time          `bson:"addedAt" json:"addedAt"`
+}
+
+// MemberRole 回傳使用者在接待主的角色；建立者 (UserID) 為 OWNER，不是成員時 ok 為 false
+func (h *Host) MemberRole(userID string) (role HostMemberRole, ok bool) {
+	if h.UserID.Hex() == userID {
+		return HostMemberRoleOwner, true
+	}
+	for _, m := range h.Members {
+		if m.UserID.Hex() == userID {
+			return m.Role, true
+		}
+	}
+	return "", false
+}
+
+// HasPermission 回傳使用者在接待主的角色是否擁有指定權限
+func (h *Host) HasPermission(userID string, permission MemberPermission) bool {
+	role, ok := h.MemberRole(userID)
+	return ok && role.HasPermission(permission)
+}
+
+// HostInvitationStatus 是共同管理者邀請的狀態
+type HostInvitationStatus string
+
+const (
+	HostInvitationPending  HostInvitationStatus = "PENDING"
+	HostInvitationAccepted HostInvitationStatus = "ACCEPTED"
+	HostInvitationDeclined HostInvitationStatus = "DECLINED"
+	HostInvitationRevoked  HostInvitationStatus = "REVOKED"
+)
+
+// HostInvitation 是以 Email 邀請使用者成為接待主共同管理者的邀請。
+// 受邀者以相同 Email 登入後可以接受或拒絕，尚未註冊的受邀者註冊後即可看到邀請。
+type HostInvitation struct {
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	HostID      primitive.ObjectID   `bson:"hostId" json:"hostId"`
+	HostName    string               `bson:"hostName" json:"hostName"`
+	Email       string               `bson:"email" json:"email"`
+	Role        HostMemberRole       `bson:"role" json:"role"`
+	Status      HostInvitationStatus `bson:"status" json:"status"`
+	InvitedBy   primitive.ObjectID   `bson:"invitedBy" json:"invitedBy"`
+	CreatedAt   time.Time            `bson:"createdAt" json:"createdAt"`
+	ExpiresAt   time.Time            `bson:"expiresAt" json:"expiresAt"`
+	RespondedAt *time.Time           `bson:"respondedAt,omitempty" json:"respondedAt,omitempty"`
+}
+
+// IsOpen 回傳邀請是否仍可接受或拒絕
+func (i *HostInvitation) IsOpen(now time.Time) bool {
+	return i.Status == HostInvitationPending && now.Before(i.ExpiresAt)
+}
@@ -32,4 +32,15 @@ type Image struct {
 	VisionData VisionAIRawData    `bson:"visionData" json:"visionData"`
 	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt  time.Time          `bson:"updatedAt" json:"updatedAt"`
+	// 排定刪除的時間，到期後由背景工作刪除 GCS 檔案與資料
+	DeleteAfter *time.Time `bson:"deleteAfter,omitempty" json:"deleteAfter,omitempty"`
+	// 私有圖片 (例如接待主驗證文件) 只存放於私有 bucket，不經過內容審核也不會公開
+	Private bool `bson:"private,omitempty" json:"private,omitempty"`
+	// 依檔案內容判斷的類型，目前只記錄私有檔案 (image/jpeg、image/png、application/pdf)
+	ContentType string `bson:"contentType,omitempty" json:"contentType,omitempty"`
+}
+
+// InPrivateBucket 回傳檔案是否存放於私有 bucket：私有文件與尚未核准 (審核中、已拒絕) 的圖片都不公開
+func (i *Image) InPrivateBucket() bool {
+	return i.Private || i.Status != ImageStatusApproved
 }
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LoginAttempt 記錄某個帳號或 IP 在目前時間窗內的登入失敗次數。
+// Key 的格式為 "email:<email>"、"ip:<ip>"、"mfa:<challenge id>" (該 MFA challenge 的驗證次數)，
+// 或 "reset-ip:<ip>" (該 IP 請求重設密碼的次數)。
+type LoginAttempt struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Key           string             `bson:"key" json:"key"`
+	Failures      int                `bson:"failures" json:"failures"`
+	WindowStart   time.Time          `bson:"windowStart" json:"windowStart"`
+	LastFailureAt time.Time          `bson:"lastFailureAt" json:"lastFailureAt"`
+	LockedUntil   *time.Time         `bson:"lockedUntil,omitempty" json:"lockedUntil,omitempty"`
+	ExpiresAt     time.Time          `bson:"expiresAt" json:"expiresAt"` // TTL，過期後由 MongoDB 自動清除
+}
+
+// IsLocked 回傳是否仍在鎖定期間內
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
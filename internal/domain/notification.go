@@ -12,6 +12,30 @@ type NotificationType string
 const (
 	NotificationTypeApplicationCreated       NotificationType = "APPLICATION_CREATED"
 	NotificationTypeApplicationStatusChanged NotificationType = "APPLICATION_STATUS_CHANGED"
+
+	// 接待主驗證審核結果
+	NotificationTypeHostVerificationApproved         NotificationType = "HOST_VERIFICATION_APPROVED"
+	NotificationTypeHostVerificationRejected         NotificationType = "HOST_VERIFICATION_REJECTED"
+	NotificationTypeHostVerificationChangesRequested NotificationType = "HOST_VERIFICATION_CHANGES_REQUESTED"
+
+	// 管理員變更工作機會狀態
+	NotificationTypeOpportunityPublished     NotificationType = "OPPORTUNITY_PUBLISHED"
+	NotificationTypeOpportunityRejected      NotificationType = "OPPORTUNITY_REJECTED"
+	NotificationTypeOpportunityAdminPaused   NotificationType = "OPPORTUNITY_ADMIN_PAUSED"
+	NotificationTypeOpportunityStatusChanged NotificationType = "OPPORTUNITY_STATUS_CHANGED"
+
+	// 排程自動處理工作機會
+	NotificationTypeOpportunityExpiring NotificationType = "OPPORTUNITY_EXPIRING"
+	NotificationTypeOpportunityExpired  NotificationType = "OPPORTUNITY_EXPIRED"
+	NotificationTypeOpportunityFilled   NotificationType = "OPPORTUNITY_FILLED"
+
+	// 接待主共同管理者邀請
+	NotificationTypeHostInvitation         NotificationType = "HOST_INVITATION"
+	NotificationTypeHostInvitationAccepted NotificationType = "HOST_INVITATION_ACCEPTED"
+
+	// 組織成員邀請
+	NotificationTypeOrganizationInvitation         NotificationType = "ORGANIZATION_INVITATION"
+	NotificationTypeOrganizationInvitationAccepted NotificationType = "ORGANIZATION_INVITATION_ACCEPTED"
 )
 
 // Notification 代表一則系統通知
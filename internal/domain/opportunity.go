@@ -20,6 +20,82 @@ const (
 	OpportunityStatusDeleted     OpportunityStatus = "DELETED"
 )
 
+// opportunityTransitions 定義工作機會狀態的變更：目前狀態 -> 目標狀態 -> 可執行的一方。
+// 上架 (PENDING -> ACTIVE) 需經管理員審核；管理員暫停 (ADMIN_PAUSED) 只能由管理員解除；
+// 過期由系統排程處理；上架後修改的內容出現新的自動篩檢結果時由系統送回審核。
+// 接待主可刪除 (DELETED) 管理員暫停以外的工作機會，管理員可刪除任何工作機會。
+var opportunityTransitions = map[OpportunityStatus]map[OpportunityStatus][]Actor{
+	OpportunityStatusDraft: {
+		OpportunityStatusPending: {ActorHost},
+		OpportunityStatusDeleted: {ActorHost, ActorAdmin},
+	},
+	OpportunityStatusPending: {
+		OpportunityStatusActive:   {ActorAdmin},
+		OpportunityStatusRejected: {ActorAdmin},
+		OpportunityStatusDraft:    {ActorHost},
+		OpportunityStatusDeleted:  {ActorHost, ActorAdmin},
+	},
+	OpportunityStatusRejected: {
+		OpportunityStatusPending: {ActorHost},
+		OpportunityStatusDeleted: {ActorHost, ActorAdmin},
+	},
+	OpportunityStatusActive: {
+		OpportunityStatusPending:     {ActorSystem},
+		OpportunityStatusPaused:      {ActorHost},
+		OpportunityStatusFilled:      {ActorHost, ActorSystem},
+		OpportunityStatusExpired:     {ActorSystem},
+		OpportunityStatusAdminPaused: {ActorAdmin},
+		OpportunityStatusDeleted:     {ActorHost, ActorAdmin},
+	},
+	OpportunityStatusPaused: {
+		OpportunityStatusPending:     {ActorSystem},
+		OpportunityStatusActive:      {ActorHost},
+		OpportunityStatusExpired:     {ActorSystem},
+		OpportunityStatusAdminPaused: {ActorAdmin},
+		OpportunityStatusDeleted:     {ActorHost, ActorAdmin},
+	},
+	OpportunityStatusFilled: {
+		OpportunityStatusPending:     {ActorSystem},
+		OpportunityStatusActive:      {ActorHost},
+		OpportunityStatusExpired:     {ActorSystem},
+		OpportunityStatusAdminPaused: {ActorAdmin},
+		OpportunityStatusDeleted:     {ActorHost, ActorAdmin},
+	},
+	OpportunityStatusAdminPaused: {
+		OpportunityStatusActive:  {ActorAdmin},
+		OpportunityStatusPaused:  {ActorAdmin},
+		OpportunityStatusDeleted: {ActorAdmin},
+	},
+	OpportunityStatusExpired: {
+		OpportunityStatusDraft:   {ActorHost},
+		OpportunityStatusDeleted: {ActorHost, ActorAdmin},
+	},
+}
+
+// CanTransitionTo 回傳 actor 是否可以將狀態從 s 變更為 to
+func (s OpportunityStatus) CanTransitionTo(to OpportunityStatus, actor Actor) bool {
+	for _, a := range opportunityTransitions[s][to] {
+		if a == actor {
+			return true
+		}
+	}
+	return false
+}
+
+// IsLive 回傳工作機會是否已通過審核且接待主不需再次送審即可公開 (上架中、暫停或額滿)
+func (s OpportunityStatus) IsLive() bool {
+	switch s {
+	case OpportunityStatusActive, OpportunityStatusPaused, OpportunityStatusFilled:
+		return true
+	}
+	return false
+}
+
+// IsPublic 回傳此狀態的工作機會是否對所有人公開 (上架中或已額滿)
+func (s OpportunityStatus) IsPublic() bool {
+	return s == OpportunityStatusActive || s == OpportunityStatusFilled
+}
+
 type OpportunityType string
 
 const (
@@ -105,6 +181,8 @@ type Requirements struct {
 	SpecificNationalities []string `bson:"specificNationalities,omitempty" json:"specificNationalities,omitempty"`
 	SpecificSkills        []string `bson:"specificSkills,omitempty" json:"specificSkills,omitempty"`
 	OtherRequirements     []string `bson:"otherRequirements,omitempty" json:"otherRequirements,omitempty"`
+	// 申請前必須填寫的個人檔案欄位
+	RequiredProfileFields []ProfileField `bson:"requiredProfileFields,omitempty" json:"requiredProfileFields,omitempty"`
 }
 
 type OpportunityMedia struct {
@@ -183,9 +261,14 @@ type Opportunity struct {
 	Description        string                     `bson:"description" json:"description"`
 	ShortDescription   string                     `bson:"shortDescription" json:"shortDescription"`
 	Status             OpportunityStatus          `bson:"status" json:"status"`
-	StatusNote         string                     `bson:"statusNote,omitempty" json:"statusNote,omitempty"`
+	StatusNote         string                     `bson:"statusNote,omitempty" json:"-"`
 	Type               OpportunityType            `bson:"type" json:"type"`
-	StatusHistory      []OpportunityStatusHistory `bson:"statusHistory,omitempty" json:"statusHistory,omitempty"`
+	StatusHistory      []OpportunityStatusHistory `bson:"statusHistory,omitempty" json:"-"`
+	SubmittedAt        *time.Time                 `bson:"submittedAt,omitempty" json:"-"`    // 最近一次送審時間
+	LastApprovedAt     *time.Time                 `bson:"lastApprovedAt,omitempty" json:"-"` // 最近一次通過審核時間
+	LastEditedAt       *time.Time                 `bson:"lastEditedAt,omitempty" json:"-"`   // 通過審核後最近一次修改內容的時間，再次審核後清除
+	ModerationFlags    []ModerationFlag           `bson:"moderationFlags,omitempty" json:"-"`
+	ExpiryNotifiedAt   *time.Time                 `bson:"expiryNotifiedAt,omitempty" json:"-"` // 已通知接待主即將到期的時間
 	WorkDetails        WorkDetails                `bson:"workDetails" json:"workDetails"`
 	Benefits           Benefits                   `bson:"benefits" json:"benefits"`
 	Requirements       Requirements               `bson:"requirements" json:"requirements"`
@@ -199,4 +282,75 @@ type Opportunity struct {
 	HasTimeSlots       bool                       `bson:"hasTimeSlots" json:"hasTimeSlots"`
 	CreatedAt          time.Time                  `bson:"createdAt" json:"createdAt"`
 	UpdatedAt          time.Time                  `bson:"updatedAt" json:"updatedAt"`
+
+	// 多語系內容：Title、Description、ShortDescription 為 DefaultLocale 的版本，其他語系放在 Translations
+	DefaultLocale    string                            `bson:"defaultLocale,omitempty" json:"defaultLocale,omitempty"`
+	Translations     map[string]OpportunityTranslation `bson:"translations,omitempty" json:"translations,omitempty"`
+	Locale           string                            `bson:"-" json:"locale,omitempty"`           // 回應內容實際使用的語系，由 Localize 設定
+	AvailableLocales []string                          `bson:"-" json:"availableLocales,omitempty"` // 由 Localize 設定
+}
+
+// ManagedOpportunity 是接待主與後台看到的工作機會，額外包含審核與狀態歷程等不公開的欄位
+type ManagedOpportunity struct {
+	*Opportunity
+	StatusNote       string                     `json:"statusNote,omitempty"`
+	StatusHistory    []OpportunityStatusHistory `json:"statusHistory,omitempty"`
+	SubmittedAt      *time.Time                 `json:"submittedAt,omitempty"`
+	LastApprovedAt   *time.Time                 `json:"lastApprovedAt,omitempty"`
+	LastEditedAt     *time.Time                 `json:"lastEditedAt,omitempty"`
+	ModerationFlags  []ModerationFlag           `json:"moderationFlags,omitempty"`
+	ExpiryNotifiedAt *time.Time                 `json:"expiryNotifiedAt,omitempty"`
+}
+
+// Managed 回傳包含不公開欄位的版本，只用於接待主與後台的回應
+func (o *Opportunity) Managed() *ManagedOpportunity {
+	return &ManagedOpportunity{
+		Opportunity:      o,
+		StatusNote:       o.StatusNote,
+		StatusHistory:    o.StatusHistory,
+		SubmittedAt:      o.SubmittedAt,
+		LastApprovedAt:   o.LastApprovedAt,
+		LastEditedAt:     o.LastEditedAt,
+		ModerationFlags:  o.ModerationFlags,
+		ExpiryNotifiedAt: o.ExpiryNotifiedAt,
+	}
+}
+
+// TimeSlotDateLayout 是 TimeSlot.StartDate / EndDate 的日期格式
+const TimeSlotDateLayout = "2006-01-02"
+
+// ExpiresAt 回傳工作機會的到期時間：申請截止時間，或最後一個時段結束日隔天的 0 點 (loc)，取較早者。
+// 兩者皆未設定時回傳 false。
+func (o *Opportunity) ExpiresAt(loc *time.Location) (time.Time, bool) {
+	expiresAt := o.ApplicationProcess.Deadline
+
+	lastEnd := ""
+	for _, slot := range o.TimeSlots {
+		if slot.EndDate > lastEnd {
+			lastEnd = slot.EndDate
+		}
+	}
+	if end, err := time.ParseInLocation(TimeSlotDateLayout, lastEnd, loc); err == nil {
+		end = end.AddDate(0, 0, 1)
+		if expiresAt.IsZero() || end.Before(expiresAt) {
+			expiresAt = end
+		}
+	}
+	return expiresAt, !expiresAt.IsZero()
+}
+
+// SlotsFull 回傳在 today (YYYY-MM-DD) 尚未結束的時段是否都已額滿；沒有這樣的時段時回傳 false
+func (o *Opportunity) SlotsFull(today string) bool {
+	open := 0
+	for _, slot := range o.TimeSlots {
+		if slot.Status == TimeSlotStatusClosed || slot.EndDate < today {
+			continue
+		}
+		open++
+		full := slot.Status == TimeSlotStatusFilled || (slot.DefaultCapacity > 0 && slot.ConfirmedCount >= slot.DefaultCapacity)
+		if !full {
+			return false
+		}
+	}
+	return open > 0
 }
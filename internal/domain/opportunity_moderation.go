@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ModerationFlagCode 是自動篩檢標記的原因
+type ModerationFlagCode string
+
+const (
+	ModerationFlagBannedWord        ModerationFlagCode = "BANNED_WORD"
+	ModerationFlagMissingField      ModerationFlagCode = "MISSING_FIELD"
+	ModerationFlagSuspiciousStipend ModerationFlagCode = "SUSPICIOUS_STIPEND"
+)
+
+// ModerationFlag 是自動篩檢的結果，只提供管理員審核參考，不會阻擋送審
+type ModerationFlag struct {
+	Code   ModerationFlagCode `bson:"code" json:"code"`
+	Field  string             `bson:"field" json:"field"`
+	Detail string             `bson:"detail,omitempty" json:"detail,omitempty"` // 命中的詞或可疑的金額
+}
+
+// OpportunityReviewDecision 是管理員對送審工作機會的審核結果
+type OpportunityReviewDecision string
+
+const (
+	OpportunityReviewApprove OpportunityReviewDecision = "APPROVE" // 通過，工作機會上架 (ACTIVE)
+	OpportunityReviewReject  OpportunityReviewDecision = "REJECT"  // 拒絕 (REJECTED)，修改後可重新送審
+)
+
+// OpportunityApproval 保存工作機會最近一次通過審核時的內容，用來比對之後的修改
+type OpportunityApproval struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	OpportunityID primitive.ObjectID `bson:"opportunityId" json:"opportunityId"`
+	Snapshot      Opportunity        `bson:"snapshot" json:"snapshot"`
+	ApprovedBy    primitive.ObjectID `bson:"approvedBy" json:"approvedBy"`
+	ApprovedAt    time.Time          `bson:"approvedAt" json:"approvedAt"`
+}
+
+// OpportunityFieldChange 是單一欄位的變更，Field 為 bson 路徑 (e.g. "benefits.stipend.amount")
+type OpportunityFieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// OpportunityChanges 是工作機會自最近一次通過審核後的修改，從未通過審核時 ApprovedAt 為 nil
+type OpportunityChanges struct {
+	OpportunityID primitive.ObjectID       `json:"opportunityId"`
+	ApprovedBy    primitive.ObjectID       `json:"approvedBy,omitempty"`
+	ApprovedAt    *time.Time               `json:"approvedAt,omitempty"`
+	Changes       []OpportunityFieldChange `json:"changes"`
+}
@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OrganizationType 是經營多個接待據點的組織類型
+type OrganizationType string
+
+const (
+	OrganizationTypeNGO         OrganizationType = "NGO"
+	OrganizationTypeHostelChain OrganizationType = "HOSTEL_CHAIN"
+	OrganizationTypeCompany     OrganizationType = "COMPANY"
+	OrganizationTypeOther       OrganizationType = "OTHER"
+)
+
+// IsValid 回傳是否為已定義的組織類型
+func (t OrganizationType) IsValid() bool {
+	switch t {
+	case OrganizationTypeNGO, OrganizationTypeHostelChain, OrganizationTypeCompany, OrganizationTypeOther:
+		return true
+	}
+	return false
+}
+
+// MemberPermission 是團隊成員對組織與其接待主可執行的操作
+type MemberPermission string
+
+const (
+	MemberPermissionManageTeam         MemberPermission = "team:manage"         // 編輯組織資料、成員與旗下接待主
+	MemberPermissionEditListings       MemberPermission = "listings:edit"       // 編輯接待主的工作機會
+	MemberPermissionReviewApplications MemberPermission = "applications:review" // 審核申請
+	MemberPermissionViewDashboard      MemberPermission = "dashboard:view"      // 查看申請與統計
+)
+
+// OrganizationRole 是組織成員的角色
+type OrganizationRole string
+
+const (
+	OrganizationRoleOwner    OrganizationRole = "OWNER"
+	OrganizationRoleManager  OrganizationRole = "MANAGER"
+	OrganizationRoleReviewer OrganizationRole = "REVIEWER"
+)
+
+// organizationRolePermissions 定義各組織角色擁有的權限
+var organizationRolePermissions = map[OrganizationRole][]MemberPermission{
+	OrganizationRoleOwner: {
+		MemberPermissionManageTeam,
+		MemberPermissionEditListings,
+		MemberPermissionReviewApplications,
+		MemberPermissionViewDashboard,
+	},
+	OrganizationRoleManager: {
+		MemberPermissionEditListings,
+		MemberPermissionReviewApplications,
+		MemberPermissionViewDashboard,
+	},
+	OrganizationRoleReviewer: {
+		MemberPermissionReviewApplications,
+		MemberPermissionViewDashboard,
+	},
+}
+
+// IsValid 回傳是否為已定義的組織角色
+func (r OrganizationRole) IsValid() bool {
+	_, ok := organizationRolePermissions[r]
+	return ok
+}
+
+// HasPermission 回傳角色是否擁有指定權限
+func (r OrganizationRole) HasPermission(permission MemberPermission) bool {
+	for _, p := range organizationRolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// OrganizationMember 是組織的成員，使用者同時只能屬於一個組織 (User.OrganizationID)
+type OrganizationMember struct {
+	UserID  primitive.ObjectID `bson:"userId" json:"userId"`
+	Role    OrganizationRole   `bson:"role" json:"role"`
+	AddedBy primitive.ObjectID `bson:"addedBy,omitempty" json:"addedBy,omitempty"`
+	AddedAt time.Time          `bson:"addedAt" json:"addedAt"`
+}
+
+// Organization 代表經營多個接待主 (Host.OrganizationID) 的組織，例如 NGO 或連鎖青旅
+type Organization struct {
+	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name         string               `bson:"name" json:"name"`
+	Type         OrganizationType     `bson:"type" json:"type"`
+	Description  string               `bson:"description,omitempty" json:"description,omitempty"`
+	Website      string               `bson:"website,omitempty" json:"website,omitempty"`
+	ContactEmail string               `bson:"contactEmail,omitempty" json:"contactEmail,omitempty"`
+	Members      []OrganizationMember `bson:"members" json:"members"`
+	CreatedAt    time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Member 回傳使用者在組織中的成員資料，不是成員時回傳 nil
+func (o *Organization) Member(userID string) *OrganizationMember {
+	for i := range o.Members {
+		if o.Members[i].UserID.Hex() == userID {
+			return &o.Members[i]
+		}
+	}
+	return nil
+}
+
+// OwnerCount 回傳組織的擁有者人數
+func (o *Organization) OwnerCount() int {
+	n := 0
+	for _, m := range o.Members {
+		if m.Role == OrganizationRoleOwner {
+			n++
+		}
+	}
+	return n
+}
+
+// OrganizationInvitationStatus 是組織成員邀請的狀態
+type OrganizationInvitationStatus string
+
+const (
+	OrganizationInvitationPending  OrganizationInvitationStatus = "PENDING"
+	OrganizationInvitationAccepted OrganizationInvitationStatus = "ACCEPTED"
+	OrganizationInvitationDeclined OrganizationInvitationStatus = "DECLINED"
+	OrganizationInvitationRevoked  OrganizationInvitationStatus = "REVOKED"
+)
+
+// OrganizationInvitation 是邀請已註冊使用者加入組織的邀請，受邀者接受後才會成為成員
+type OrganizationInvitation struct {
+	ID               primitive.ObjectID           `bson:"_id,omitempty" json:"id"`
+	OrganizationID   primitive.ObjectID           `bson:"organizationId" json:"organizationId"`
+	OrganizationName string                       `bson:"organizationName" json:"organizationName"`
+	Email            string                       `bson:"email" json:"email"`
+	Role             OrganizationRole             `bson:"role" json:"role"`
+	Status           OrganizationInvitationStatus `bson:"status" json:"status"`
+	InvitedBy        primitive.ObjectID           `bson:"invitedBy" json:"invitedBy"`
+	CreatedAt        time.Time                    `bson:"createdAt" json:"createdAt"`
+	ExpiresAt        time.Time                    `bson:"expiresAt" json:"expiresAt"`
+	RespondedAt      *time.Time                   `bson:"respondedAt,omitempty" json:"respondedAt,omitempty"`
+}
+
+// IsOpen 回傳邀請是否仍可接受或拒絕
+func (i *OrganizationInvitation) IsOpen(now time.Time) bool {
+	return i.Status == OrganizationInvitationPending && now.Before(i.ExpiresAt)
+}
+
+// OrganizationDashboard 彙整組織旗下所有接待主的申請狀況
+type OrganizationDashboard struct {
+	OrganizationID string                      `json:"organizationId"`
+	Hosts          []OrganizationHostSummary   `json:"hosts"`
+	Applications   map[ApplicationStatus]int64 `json:"applications"` // 全部接待主合計
+}
+
+// OrganizationHostSummary 是組織儀表板上單一接待主的摘要
+type OrganizationHostSummary struct {
+	ID           string                      `json:"id"`
+	Name         string                      `json:"name"`
+	Slug         string                      `json:"slug"`
+	Status       HostStatus                  `json:"status"`
+	Applications map[ApplicationStatus]int64 `json:"applications"`
+}
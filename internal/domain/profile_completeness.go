@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ProfileField 是個人檔案完整度計算的欄位，也用於機會要求申請者必填的欄位
+type ProfileField string
+
+const (
+	ProfileFieldAvatar            ProfileField = "avatar"
+	ProfileFieldBio               ProfileField = "bio"
+	ProfileFieldBirthDate         ProfileField = "birthDate"
+	ProfileFieldPhoneNumber       ProfileField = "phoneNumber"
+	ProfileFieldEmergencyContact  ProfileField = "emergencyContact"
+	ProfileFieldLanguages         ProfileField = "languages"
+	ProfileFieldSkills            ProfileField = "skills"
+	ProfileFieldNationality       ProfileField = "nationality"
+	ProfileFieldPhysicalCondition ProfileField = "physicalCondition"
+	ProfileFieldWorkExperience    ProfileField = "workExperience"
+)
+
+// profileFieldWeights 定義各欄位在完整度分數中的權重，總和為 100，順序即為引導清單的顯示順序
+var profileFieldWeights = []struct {
+	Field  ProfileField
+	Weight int
+}{
+	{ProfileFieldAvatar, 10},
+	{ProfileFieldBio, 10},
+	{ProfileFieldBirthDate, 10},
+	{ProfileFieldPhoneNumber, 10},
+	{ProfileFieldEmergencyContact, 15},
+	{ProfileFieldLanguages, 10},
+	{ProfileFieldSkills, 10},
+	{ProfileFieldNationality, 10},
+	{ProfileFieldPhysicalCondition, 5},
+	{ProfileFieldWorkExperience, 10},
+}
+
+// IsValid 回傳是否為已定義的個人檔案欄位
+func (f ProfileField) IsValid() bool {
+	for _, w := range profileFieldWeights {
+		if w.Field == f {
+			return true
+		}
+	}
+	return false
+}
+
+// ProfileCompleteness 是個人檔案的完整度，Missing 依引導清單順序排列
+type ProfileCompleteness struct {
+	Score     int            `json:"score"` // 0-100
+	Completed []ProfileField `json:"completed"`
+	Missing   []ProfileField `json:"missing"`
+}
+
+// Completeness 計算個人檔案的完整度。
+// 註冊時填入的預設值 (例如 "N/A"、註冊當下的生日) 視為未填寫。
+func (p Profile) Completeness() ProfileCompleteness {
+	c := ProfileCompleteness{Completed: []ProfileField{}, Missing: []ProfileField{}}
+	for _, w := range profileFieldWeights {
+		if p.HasField(w.Field) {
+			c.Score += w.Weight
+			c.Completed = append(c.Completed, w.Field)
+		} else {
+			c.Missing = append(c.Missing, w.Field)
+		}
+	}
+	return c
+}
+
+// MissingFields 回傳 fields 中尚未填寫的欄位
+func (p Profile) MissingFields(fields []ProfileField) []ProfileField {
+	var missing []ProfileField
+	for _, f := range fields {
+		if !p.HasField(f) {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
+
+// HasField 回傳欄位是否已填寫有效的值
+func (p Profile) HasField(field ProfileField) bool {
+	switch field {
+	case ProfileFieldAvatar:
+		return !isPlaceholder(p.Avatar)
+	case ProfileFieldBio:
+		return !isPlaceholder(p.Bio)
+	case ProfileFieldBirthDate:
+		// 舊資料以註冊當下的時間作為生日的預設值
+		return !p.BirthDate.IsZero() && p.BirthDate.Before(time.Now().AddDate(-1, 0, 0))
+	case ProfileFieldPhoneNumber:
+		return !isPlaceholder(p.PhoneNumber)
+	case ProfileFieldEmergencyContact:
+		return !isPlaceholder(p.EmergencyContact.Name) && !isPlaceholder(p.EmergencyContact.Phone)
+	case ProfileFieldLanguages:
+		return hasValue(p.Languages)
+	case ProfileFieldSkills:
+		return hasValue(p.Skills)
+	case ProfileFieldNationality:
+		return p.PersonalInfo != nil && !isPlaceholder(p.PersonalInfo.Nationality)
+	case ProfileFieldPhysicalCondition:
+		return !isPlaceholder(p.PhysicalCondition)
+	case ProfileFieldWorkExperience:
+		for _, exp := range p.WorkExperience {
+			if !isPlaceholder(exp.Title) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// isPlaceholder 回傳字串是否為空白或預設的佔位值
+func isPlaceholder(s string) bool {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "", "N/A", "NA", "-":
+		return true
+	}
+	return false
+}
+
+func hasValue(values []string) bool {
+	for _, v := range values {
+		if !isPlaceholder(v) {
+			return true
+		}
+	}
+	return false
+}
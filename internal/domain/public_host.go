@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// PublicHost 是公開接待主頁面 (依 slug 查詢) 的內容，不包含聯絡方式、驗證文件與狀態歷程
+type PublicHost struct {
+	ID                string             `json:"id"`
+	Slug              string             `json:"slug"`
+	Name              string             `json:"name"`
+	Description       string             `json:"description"`
+	Type              HostType           `json:"type"`
+	Category          string             `json:"category,omitempty"`
+	Verified          bool               `json:"verified"`
+	Location          PublicHostLocation `json:"location"`
+	Photos            []HostPhoto        `json:"photos,omitempty"`
+	PhotoDescriptions []string           `json:"photoDescriptions,omitempty"`
+	VideoIntroduction *VideoIntroduction `json:"videoIntroduction,omitempty"`
+	AdditionalMedia   *AdditionalMedia   `json:"additionalMedia,omitempty"`
+	Amenities         Amenities          `json:"amenities"`
+	Details           HostDetails        `json:"details"`
+	Features          *HostFeatures      `json:"features,omitempty"`
+	Ratings           HostRatings        `json:"ratings"`
+	Website           string             `json:"website,omitempty"`
+	SocialMedia       *HostSocialMedia   `json:"socialMedia,omitempty"`
+	MemberSince       time.Time          `json:"memberSince"`
+	Locale            string             `json:"locale,omitempty"`
+	AvailableLocales  []string           `json:"availableLocales,omitempty"`
+
+	Opportunities []PublicHostOpportunity `json:"opportunities"`
+}
+
+// PublicHostLocation 是公開的接待主位置。
+// ShowExactLocation 為 false 時不顯示地址，座標為偏移後的大約位置 (Approximate 為 true)。
+type PublicHostLocation struct {
+	Address     string   `json:"address,omitempty"`
+	City        string   `json:"city"`
+	District    string   `json:"district,omitempty"`
+	Country     string   `json:"country"`
+	Coordinates *GeoJSON `json:"coordinates,omitempty"`
+	Approximate bool     `json:"approximate"`
+}
+
+// PublicHostOpportunity 是接待主頁面上列出的上架中工作機會摘要
+type PublicHostOpportunity struct {
+	ID               string          `json:"id"`
+	Slug             string          `json:"slug"`
+	PublicID         string          `json:"publicId"`
+	Title            string          `json:"title"`
+	ShortDescription string          `json:"shortDescription"`
+	Type             OpportunityType `json:"type"`
+	CoverImage       *HostPhoto      `json:"coverImage,omitempty"`
+	City             string          `json:"city"`
+	Ratings          HostRatings     `json:"ratings"`
+	Locale           string          `json:"locale,omitempty"`
+}
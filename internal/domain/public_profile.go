@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// ProfileViewer 表示查看個人檔案的人與檔案主人的關係，決定可見的欄位
+type ProfileViewer string
+
+const (
+	ProfileViewerAnonymous  ProfileViewer = "ANONYMOUS"  // 未登入
+	ProfileViewerRegistered ProfileViewer = "REGISTERED" // 已登入的其他使用者
+	ProfileViewerHost       ProfileViewer = "HOST"       // 使用者申請過的接待主
+	ProfileViewerSelf       ProfileViewer = "SELF"
+	ProfileViewerAdmin      ProfileViewer = "ADMIN"
+)
+
+// PublicProfile 是依隱私設定過濾後、提供給其他人查看的個人檔案
+type PublicProfile struct {
+	ID              string        `json:"id"`
+	Name            string        `json:"name"`
+	Image           string        `json:"image,omitempty"`
+	Viewer          ProfileViewer `json:"viewer"`
+	MemberSince     time.Time     `json:"memberSince"`
+	IsEmailVerified bool          `json:"isEmailVerified"`
+	IsPhoneVerified bool          `json:"isPhoneVerified"`
+
+	// 依 PrivacySettings 決定是否顯示
+	Email                   string                   `json:"email,omitempty"`
+	PhoneNumber             string                   `json:"phoneNumber,omitempty"`
+	Bio                     string                   `json:"bio,omitempty"`
+	Skills                  []string                 `json:"skills,omitempty"`
+	Languages               []string                 `json:"languages,omitempty"`
+	SocialMedia             *SocialMedia             `json:"socialMedia,omitempty"`
+	PersonalInfo            *PersonalInfo            `json:"personalInfo,omitempty"`
+	WorkExchangePreferences *WorkExchangePreferences `json:"workExchangePreferences,omitempty"`
+
+	// 一律公開
+	Avatar            string           `json:"avatar,omitempty"`
+	WorkExperience    []WorkExperience `json:"workExperience,omitempty"`
+	CulturalInterests []string         `json:"culturalInterests,omitempty"`
+	LearningGoals     []string         `json:"learningGoals,omitempty"`
+
+	// 只有本人、管理員與使用者申請過的接待主可見
+	BirthDate          *time.Time        `json:"birthDate,omitempty"`
+	EmergencyContact   *EmergencyContact `json:"emergencyContact,omitempty"`
+	PhysicalCondition  string            `json:"physicalCondition,omitempty"`
+	AccommodationNeeds string            `json:"accommodationNeeds,omitempty"`
+	PreferredWorkHours int               `json:"preferredWorkHours,omitempty"`
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Permission 代表一項可授權的操作，格式為 "<resource>:<action>"
+type Permission string
+
+const (
+	PermissionStatsRead             Permission = "stats:read"
+	PermissionImagesReview          Permission = "images:review"
+	PermissionUsersRead             Permission = "users:read"
+	PermissionUsersSuspend          Permission = "users:suspend" // 停權、解除鎖定等帳號狀態操作
+	PermissionUsersDelete           Permission = "users:delete"  // 刪除 (匿名化) 帳號
+	PermissionOpportunitiesModerate Permission = "opportunities:moderate"
+	PermissionHostsVerify           Permission = "hosts:verify"
+	PermissionHostsSuspend          Permission = "hosts:suspend" // 停權、恢復接待主
+	PermissionRolesManage           Permission = "roles:manage"  // 編輯角色的權限組合
+	PermissionRolesAssign           Permission = "roles:assign"  // 指派使用者角色
+)
+
+// AllPermissions 列出系統中所有的權限
+var AllPermissions = []Permission{
+	PermissionStatsRead,
+	PermissionImagesReview,
+	PermissionUsersRead,
+	PermissionUsersSuspend,
+	PermissionUsersDelete,
+	PermissionOpportunitiesModerate,
+	PermissionHostsVerify,
+	PermissionHostsSuspend,
+	PermissionRolesManage,
+	PermissionRolesAssign,
+}
+
+// IsValid 回傳是否為已定義的權限
+func (p Permission) IsValid() bool {
+	for _, known := range AllPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdminOnly 回傳是否為帳號與角色管理權限，這些權限不可授予一般使用者與接待主角色
+func (p Permission) IsAdminOnly() bool {
+	return strings.HasPrefix(string(p), "users:") || strings.HasPrefix(string(p), "roles:")
+}
+
+// Role 定義角色與其擁有的權限，存放於 roles collection。
+// Name 對應 User.Role；System 角色為內建角色，不可刪除。
+type Role struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        UserRole           `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Permissions []Permission       `bson:"permissions" json:"permissions"`
+	System      bool               `bson:"system" json:"system"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// HasPermission 回傳角色是否擁有指定權限
+func (r *Role) HasPermission(permission Permission) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session 代表一次登入所建立的伺服器端 session (一個裝置一個 session)。
+// Refresh token 每次使用後都會輪替，舊的 hash 會保留在 PreviousTokenHashes
+// 以便偵測重複使用 (reuse detection)。
+type Session struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID              primitive.ObjectID `bson:"userId" json:"userId"`
+	RefreshTokenHash    string             `bson:"refreshTokenHash" json:"-"`
+	PreviousTokenHashes []string           `bson:"previousTokenHashes,omitempty" json:"-"`
+	UserAgent           string             `bson:"userAgent,omitempty" json:"userAgent,omitempty"`
+	IP                  string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	CreatedAt           time.Time          `bson:"createdAt" json:"createdAt"`
+	LastUsedAt          time.Time          `bson:"lastUsedAt" json:"lastUsedAt"`
+	ExpiresAt           time.Time          `bson:"expiresAt" json:"expiresAt"`
+	RevokedAt           *time.Time         `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+	RevokedReason       string             `bson:"revokedReason,omitempty" json:"revokedReason,omitempty"`
+	MFAVerified         bool               `bson:"mfaVerified,omitempty" json:"mfaVerified"` // 登入時是否通過兩步驟驗證
+}
+
+// IsActive 回傳 session 是否尚未被撤銷且未過期
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
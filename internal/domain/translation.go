@@ -0,0 +1,71 @@
+package domain
+
+import "sort"
+
+// OpportunityTranslation 是工作機會文字內容在某個語系的版本，未填寫的欄位沿用預設內容
+type OpportunityTranslation struct {
+	Title            string `bson:"title,omitempty" json:"title,omitempty"`
+	Description      string `bson:"description,omitempty" json:"description,omitempty"`
+	ShortDescription string `bson:"shortDescription,omitempty" json:"shortDescription,omitempty"`
+}
+
+// HostTranslation 是接待主介紹在某個語系的版本
+type HostTranslation struct {
+	Description string `bson:"description,omitempty" json:"description,omitempty"`
+}
+
+// translatableLocales 回傳內容可提供的所有語系，預設語系排在最前面
+func translatableLocales[T any](defaultLocale string, translations map[string]T) []string {
+	others := make([]string, 0, len(translations))
+	for l := range translations {
+		if l != defaultLocale {
+			others = append(others, l)
+		}
+	}
+	sort.Strings(others)
+	if defaultLocale == "" {
+		return others
+	}
+	return append([]string{defaultLocale}, others...)
+}
+
+// Localize 將 Title 等文字欄位替換為指定語系的翻譯，並記錄實際使用的語系。
+// 沒有該語系的翻譯 (或 locale 為空) 時保留預設內容。
+func (o *Opportunity) Localize(locale string) {
+	o.Locale = o.DefaultLocale
+	o.AvailableLocales = translatableLocales(o.DefaultLocale, o.Translations)
+	if locale == "" || locale == o.DefaultLocale {
+		return
+	}
+	t, ok := o.Translations[locale]
+	if !ok {
+		return
+	}
+	o.Locale = locale
+	if t.Title != "" {
+		o.Title = t.Title
+	}
+	if t.Description != "" {
+		o.Description = t.Description
+	}
+	if t.ShortDescription != "" {
+		o.ShortDescription = t.ShortDescription
+	}
+}
+
+// Localize 將 Description 替換為指定語系的翻譯，規則與 Opportunity.Localize 相同
+func (h *Host) Localize(locale string) {
+	h.Locale = h.DefaultLocale
+	h.AvailableLocales = translatableLocales(h.DefaultLocale, h.Translations)
+	if locale == "" || locale == h.DefaultLocale {
+		return
+	}
+	t, ok := h.Translations[locale]
+	if !ok {
+		return
+	}
+	h.Locale = locale
+	if t.Description != "" {
+		h.Description = t.Description
+	}
+}
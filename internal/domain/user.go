@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"regexp"
 	"time"
 )
 
@@ -9,38 +10,93 @@ type UserRole string
 type PrivacyLevel string
 
 const (
-	RoleUser  UserRole = "USER"
-	RoleHost  UserRole = "HOST"
-	RoleAdmin UserRole = "ADMIN"
+	RoleUser      UserRole = "USER"
+	RoleHost      UserRole = "HOST"
+	RoleAdmin     UserRole = "ADMIN"
+	RoleModerator UserRole = "MODERATOR"
 
 	PrivacyPublic     PrivacyLevel = "PUBLIC"
 	PrivacyRegistered PrivacyLevel = "REGISTERED"
 	PrivacyPrivate    PrivacyLevel = "PRIVATE"
 )
 
+// roleNamePattern 限制角色名稱為大寫英數與底線，例如 CONTENT_REVIEWER
+var roleNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]{0,31}$`)
+
+// IsValid 回傳角色名稱格式是否正確，不代表角色已存在
+func (r UserRole) IsValid() bool {
+	return roleNamePattern.MatchString(string(r))
+}
+
+// IsStaff 回傳是否為後台人員角色 (一般使用者與接待主以外的角色)
+func (r UserRole) IsStaff() bool {
+	return r != "" && r != RoleUser && r != RoleHost
+}
+
 type UserStatus string
+type AuthProvider string
 
 const (
 	UserStatusActive    UserStatus = "ACTIVE"
 	UserStatusSuspended UserStatus = "SUSPENDED"
+	UserStatusDeleted   UserStatus = "DELETED" // 帳號已刪除，個人資料已匿名化
+)
+
+const (
+	ProviderGoogle AuthProvider = "google"
+	ProviderApple  AuthProvider = "apple"
 )
 
 // User 定義了與前端 User.ts 對應的完整使用者模型
 type User struct {
-	ID              string          `json:"id" bson:"_id,omitempty"`
-	Name            string          `json:"name" bson:"name"`
-	Email           string          `json:"email" bson:"email"`
-	Image           string          `json:"image,omitempty" bson:"image,omitempty"`
-	EmailVerified   *time.Time      `json:"emailVerified,omitempty" bson:"emailVerified,omitempty"`
-	Password        string          `json:"-" bson:"password,omitempty"`
-	Role            UserRole        `json:"role" bson:"role"`
-	Status          UserStatus      `json:"status" bson:"status"`
-	Profile         Profile         `json:"profile" bson:"profile"`
-	HostID          string          `json:"hostId,omitempty" bson:"hostId,omitempty"`
-	OrganizationID  string          `json:"organizationId,omitempty" bson:"organizationId,omitempty"`
-	PrivacySettings PrivacySettings `json:"privacySettings" bson:"privacySettings"`
-	CreatedAt       time.Time       `json:"createdAt" bson:"createdAt"`
-	UpdatedAt       time.Time       `json:"updatedAt" bson:"updatedAt"`
+	ID              string             `json:"id" bson:"_id,omitempty"`
+	Name            string             `json:"name" bson:"name"`
+	Email           string             `json:"email" bson:"email"`
+	Image           string             `json:"image,omitempty" bson:"image,omitempty"`
+	EmailVerified   *time.Time         `json:"emailVerified,omitempty" bson:"emailVerified,omitempty"`
+	Password        string             `json:"-" bson:"password,omitempty"`
+	Role            UserRole           `json:"role" bson:"role"`
+	Status          UserStatus         `json:"status" bson:"status"`
+	Locale          string             `json:"locale,omitempty" bson:"locale,omitempty"` // 偏好語系，用於通知、email 與簡訊
+	Profile         Profile            `json:"profile" bson:"profile"`
+	HostID          string             `json:"hostId,omitempty" bson:"hostId,omitempty"`
+	OrganizationID  string             `json:"organizationId,omitempty" bson:"organizationId,omitempty"`
+	PrivacySettings PrivacySettings    `json:"privacySettings" bson:"privacySettings"`
+	Identities      []ProviderIdentity `json:"identities,omitempty" bson:"identities,omitempty"`
+	MFA             MFASettings        `json:"mfa" bson:"mfa,omitempty"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+
+	// 最近一次寄送驗證信的時間，用於限制重新寄送的頻率
+	EmailVerificationSentAt *time.Time `json:"-" bson:"emailVerificationSentAt,omitempty"`
+	// 最近一次寄送重設密碼信的時間，用於限制寄送頻率
+	PasswordResetSentAt *time.Time `json:"-" bson:"passwordResetSentAt,omitempty"`
+	// 登入失敗次數過多時的暫時鎖定期限，供管理後台顯示
+	LockedUntil *time.Time `json:"lockedUntil,omitempty" bson:"lockedUntil,omitempty"`
+	// 帳號刪除 (匿名化) 的時間
+	DeletedAt *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+
+	// 個人檔案完整度，由 Profile 計算而來，不儲存於資料庫
+	ProfileCompleteness *ProfileCompleteness `json:"profileCompleteness,omitempty" bson:"-"`
+}
+
+// MFASettings 是使用者的兩步驟驗證 (TOTP) 設定
+type MFASettings struct {
+	Enabled            bool       `json:"enabled" bson:"enabled"`
+	EnabledAt          *time.Time `json:"enabledAt,omitempty" bson:"enabledAt,omitempty"`
+	Secret             string     `json:"-" bson:"secret,omitempty"`
+	PendingSecret      string     `json:"-" bson:"pendingSecret,omitempty"` // 已產生但尚未確認的密鑰
+	RecoveryCodeHashes []string   `json:"-" bson:"recoveryCodeHashes,omitempty"`
+	LastUsedStep       int64      `json:"-" bson:"lastUsedStep,omitempty"` // 最後一次使用的 TOTP 時間區間，防止重放
+}
+
+// ProviderIdentity 記錄使用者綁定的第三方登入身分 (Google、Apple 等)
+type ProviderIdentity struct {
+	Provider     AuthProvider `json:"provider" bson:"provider"`
+	Subject      string       `json:"-" bson:"subject"` // 第三方平台的使用者 ID (sub)
+	Email        string       `json:"email,omitempty" bson:"email,omitempty"`
+	PrivateRelay bool         `json:"privateRelay,omitempty" bson:"privateRelay,omitempty"` // Email 為 Apple「隱藏我的電子郵件」的轉寄地址
+	LinkedAt     time.Time    `json:"linkedAt" bson:"linkedAt"`
 }
 
 // Profile 對應前端的 profile 物件
@@ -140,3 +196,37 @@ type PrivacySettings struct {
 	Languages               PrivacyLevel `json:"languages" bson:"languages"`
 	Bio                     PrivacyLevel `json:"bio" bson:"bio"`
 }
+
+// DefaultPrivacySettings 回傳新使用者的預設隱私設定：
+// 聯絡方式僅本人可見，個人資訊與換宿偏好限登入使用者，其餘公開
+func DefaultPrivacySettings() PrivacySettings {
+	return PrivacySettings{
+		Email:                   PrivacyPrivate,
+		Phone:                   PrivacyPrivate,
+		PersonalInfo:            PrivacyRegistered,
+		SocialMedia:             PrivacyPublic,
+		WorkExchangePreferences: PrivacyRegistered,
+		Skills:                  PrivacyPublic,
+		Languages:               PrivacyPublic,
+		Bio:                     PrivacyPublic,
+	}
+}
+
+// WithDefaults 以預設值補齊未設定的欄位 (舊資料可能缺少部分設定)
+func (p PrivacySettings) WithDefaults() PrivacySettings {
+	d := DefaultPrivacySettings()
+	fill := func(level *PrivacyLevel, fallback PrivacyLevel) {
+		if *level == "" {
+			*level = fallback
+		}
+	}
+	fill(&p.Email, d.Email)
+	fill(&p.Phone, d.Phone)
+	fill(&p.PersonalInfo, d.PersonalInfo)
+	fill(&p.SocialMedia, d.SocialMedia)
+	fill(&p.WorkExchangePreferences, d.WorkExchangePreferences)
+	fill(&p.Skills, d.Skills)
+	fill(&p.Languages, d.Languages)
+	fill(&p.Bio, d.Bio)
+	return p
+}
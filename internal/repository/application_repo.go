@@ -18,6 +18,8 @@ type ApplicationRepository interface {
 	Update(ctx context.Context, id string, app *domain.Application) error
 	Delete(ctx context.Context, id string) error
 	CountByDate(ctx context.Context, date time.Time) (int64, error)
+	AnonymizeByUserID(ctx context.Context, userID string) (int64, error)
+	CountByHostAndStatus(ctx context.Context, hostIDs []primitive.ObjectID) (map[primitive.ObjectID]map[domain.ApplicationStatus]int64, error)
 }
 
 type mongoApplicationRepository struct {
@@ -122,3 +124,58 @@ func (r *mongoApplicationRepository) CountByDate(ctx context.Context, date time.
 	}
 	return r.collection.CountDocuments(ctx, filter)
 }
+
+// AnonymizeByUserID 清除使用者申請中的自由填寫內容，保留日期與狀態供接待主查閱紀錄
+func (r *mongoApplicationRepository) AnonymizeByUserID(ctx context.Context, userID string) (int64, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, err
+	}
+	update := bson.M{"$set": bson.M{
+		"applicationDetails.message":            "",
+		"applicationDetails.relevantExperience": "",
+		"applicationDetails.languages":          []string{},
+		"updatedAt":                             time.Now(),
+	}}
+	res, err := r.collection.UpdateMany(ctx, bson.M{"userId": userObjID}, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// CountByHostAndStatus 依接待主與狀態統計申請數量
+func (r *mongoApplicationRepository) CountByHostAndStatus(ctx context.Context, hostIDs []primitive.ObjectID) (map[primitive.ObjectID]map[domain.ApplicationStatus]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"hostId": bson.M{"$in": hostIDs}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"hostId": "$hostId", "status": "$status"},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID struct {
+			HostID primitive.ObjectID       `bson:"hostId"`
+			Status domain.ApplicationStatus `bson:"status"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[primitive.ObjectID]map[domain.ApplicationStatus]int64)
+	for _, row := range rows {
+		if counts[row.ID.HostID] == nil {
+			counts[row.ID.HostID] = make(map[domain.ApplicationStatus]int64)
+		}
+		counts[row.ID.HostID][row.ID.Status] = row.Count
+	}
+	return counts, nil
+}
@@ -15,6 +15,7 @@ type BookmarkRepository interface {
 	Delete(ctx context.Context, userID, opportunityID string) error
 	ListByUserID(ctx context.Context, userID string, limit, offset int64) ([]*domain.Bookmark, int64, error)
 	Exists(ctx context.Context, userID, opportunityID string) (bool, error)
+	DeleteByUserID(ctx context.Context, userID string) (int64, error)
 }
 
 type mongoBookmarkRepository struct {
@@ -89,3 +90,12 @@ func (r *mongoBookmarkRepository) Exists(ctx context.Context, userID, opportunit
 	}
 	return count > 0, nil
 }
+
+// DeleteByUserID 刪除使用者的所有收藏
+func (r *mongoBookmarkRepository) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	res, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
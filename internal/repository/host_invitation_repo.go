@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type HostInvitationRepository interface {
+	Create(ctx context.Context, inv *domain.HostInvitation) error
+	GetByID(ctx context.Context, id string) (*domain.HostInvitation, error)
+	ListByHostID(ctx context.Context, hostID primitive.ObjectID, status domain.HostInvitationStatus) ([]*domain.HostInvitation, error)
+	ListOpenByEmail(ctx context.Context, email string, now time.Time) ([]*domain.HostInvitation, error)
+	HasOpen(ctx context.Context, hostID primitive.ObjectID, email string, now time.Time) (bool, error)
+	Respond(ctx context.Context, id string, status domain.HostInvitationStatus, at time.Time) error
+}
+
+type mongoHostInvitationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewHostInvitationRepository(collection *mongo.Collection) HostInvitationRepository {
+	// Create Indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "email", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "hostId", Value: 1}, {Key: "status", Value: 1}}},
+	})
+
+	return &mongoHostInvitationRepository{collection: collection}
+}
+
+func (r *mongoHostInvitationRepository) Create(ctx context.Context, inv *domain.HostInvitation) error {
+	inv.CreatedAt = time.Now()
+	res, err := r.collection.InsertOne(ctx, inv)
+	if err != nil {
+		return err
+	}
+	inv.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *mongoHostInvitationRepository) GetByID(ctx context.Context, id string) (*domain.HostInvitation, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var inv domain.HostInvitation
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&inv)
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// ListByHostID 列出接待主發出的邀請，status 為空時列出全部
+func (r *mongoHostInvitationRepository) ListByHostID(ctx context.Context, hostID primitive.ObjectID, status domain.HostInvitationStatus) ([]*domain.HostInvitation, error) {
+	filter := bson.M{"hostId": hostID}
+	if status != "" {
+		filter["status"] = status
+	}
+	return r.find(ctx, filter)
+}
+
+// ListOpenByEmail 列出寄給 email 且尚未回覆、未過期的邀請
+func (r *mongoHostInvitationRepository) ListOpenByEmail(ctx context.Context, email string, now time.Time) ([]*domain.HostInvitation, error) {
+	return r.find(ctx, bson.M{
+		"email":     email,
+		"status":    domain.HostInvitationPending,
+		"expiresAt": bson.M{"$gt": now},
+	})
+}
+
+// HasOpen 回傳接待主是否已有寄給 email 且尚未回覆、未過期的邀請
+func (r *mongoHostInvitationRepository) HasOpen(ctx context.Context, hostID primitive.ObjectID, email string, now time.Time) (bool, error) {
+	n, err := r.collection.CountDocuments(ctx, bson.M{
+		"hostId":    hostID,
+		"email":     email,
+		"status":    domain.HostInvitationPending,
+		"expiresAt": bson.M{"$gt": now},
+	})
+	return n > 0, err
+}
+
+// Respond 將尚未回覆的邀請改為 status，邀請已回覆時回傳 mongo.ErrNoDocuments
+func (r *mongoHostInvitationRepository) Respond(ctx context.Context, id string, status domain.HostInvitationStatus, at time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": objID, "status": domain.HostInvitationPending}
+	update := bson.M{"$set": bson.M{"status": status, "respondedAt": at}}
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *mongoHostInvitationRepository) find(ctx context.Context, filter bson.M) ([]*domain.HostInvitation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	invitations := []*domain.HostInvitation{}
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
@@ -15,7 +15,16 @@ type HostRepository interface {
 	Create(ctx context.Context, host *domain.Host) error
 	GetByID(ctx context.Context, id string) (*domain.Host, error)
 	GetByUserID(ctx context.Context, userID string) (*domain.Host, error)
+	GetBySlug(ctx context.Context, slug string) (*domain.Host, error)
 	Update(ctx context.Context, id string, host *domain.Host) error
+	AddVerificationDocument(ctx context.Context, id string, doc domain.HostVerificationDocument) error
+	UpdateStatus(ctx context.Context, id string, from domain.HostStatus, entry domain.HostStatusHistory, fields bson.M) error
+	ListPendingVerification(ctx context.Context, limit, offset int64) ([]*domain.Host, int64, error)
+	ListByOrganizationID(ctx context.Context, orgID primitive.ObjectID) ([]*domain.Host, error)
+	SetOrganization(ctx context.Context, id string, orgID *primitive.ObjectID) error
+	AddMember(ctx context.Context, id string, member domain.HostMember) error
+	UpdateMemberRole(ctx context.Context, id, userID string, role domain.HostMemberRole) error
+	RemoveMember(ctx context.Context, id, userID string) error
 }
 
 type mongoHostRepository struct {
@@ -32,10 +41,8 @@ func NewHostRepository(collection *mongo.Collection) HostRepository {
 		Keys: bson.D{{Key: "location.coordinates", Value: "2dsphere"}},
 	})
 
-	// Text index for name and description
-	collection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}},
-	})
+	// Text index for name and description, covering every translation
+	ensureTextIndex(ctx, collection, "host_text_i18n", translatedTextKeys([]string{"name", "description"}, "description"))
 
 	// Unique index for slug
 	collection.Indexes().CreateOne(ctx, mongo.IndexModel{
@@ -43,6 +50,21 @@ func NewHostRepository(collection *mongo.Collection) HostRepository {
 		Options: options.Index().SetUnique(true),
 	})
 
+	// Index for the verification review queue
+	collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "verificationSubmittedAt", Value: 1}},
+	})
+
+	// Index for listing an organization's hosts
+	collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "organizationId", Value: 1}},
+	})
+
+	// Index for looking up co-hosts
+	collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "members.userId", Value: 1}},
+	})
+
 	return &mongoHostRepository{collection: collection}
 }
 
@@ -70,13 +92,27 @@ func (r *mongoHostRepository) GetByID(ctx context.Context, id string) (*domain.H
 	return &host, nil
 }
 
+// GetByUserID 回傳使用者建立或共同管理的接待主
 func (r *mongoHostRepository) GetByUserID(ctx context.Context, userID string) (*domain.Host, error) {
 	objID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return nil, err
 	}
+	filter := bson.M{"$or": bson.A{
+		bson.M{"userId": objID},
+		bson.M{"members.userId": objID},
+	}}
 	var host domain.Host
-	err = r.collection.FindOne(ctx, bson.M{"userId": objID}).Decode(&host)
+	err = r.collection.FindOne(ctx, filter).Decode(&host)
+	if err != nil {
+		return nil, err
+	}
+	return &host, nil
+}
+
+func (r *mongoHostRepository) GetBySlug(ctx context.Context, slug string) (*domain.Host, error) {
+	var host domain.Host
+	err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&host)
 	if err != nil {
 		return nil, err
 	}
@@ -96,3 +132,174 @@ func (r *mongoHostRepository) Update(ctx context.Context, id string, host *domai
 	_, err = r.collection.ReplaceOne(ctx, bson.M{"_id": objID}, host)
 	return err
 }
+
+// AddVerificationDocument 新增一份驗證文件
+func (r *mongoHostRepository) AddVerificationDocument(ctx context.Context, id string, doc domain.HostVerificationDocument) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	update := bson.M{
+		"$push": bson.M{"verificationDocuments": doc},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateStatus 在目前狀態仍為 from 時改為 entry.Status 並附加到 statusHistory，fields 為同時更新的其他欄位。
+// 狀態已被變更時回傳 mongo.ErrNoDocuments。
+func (r *mongoHostRepository) UpdateStatus(ctx context.Context, id string, from domain.HostStatus, entry domain.HostStatusHistory, fields bson.M) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	set := bson.M{
+		"status":     entry.Status,
+		"statusNote": entry.StatusNote,
+		"updatedAt":  time.Now(),
+	}
+	for k, v := range fields {
+		set[k] = v
+	}
+	update := bson.M{
+		"$set":  set,
+		"$push": bson.M{"statusHistory": entry},
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID, "status": from}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ListPendingVerification 列出已送審、等待驗證的接待主，先送審的排在前面
+func (r *mongoHostRepository) ListPendingVerification(ctx context.Context, limit, offset int64) ([]*domain.Host, int64, error) {
+	filter := bson.M{
+		"status":                  domain.HostStatusPending,
+		"verificationSubmittedAt": bson.M{"$exists": true},
+	}
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetLimit(limit).SetSkip(offset).SetSort(bson.D{{Key: "verificationSubmittedAt", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var hosts []*domain.Host
+	if err := cursor.All(ctx, &hosts); err != nil {
+		return nil, 0, err
+	}
+	return hosts, total, nil
+}
+
+// ListByOrganizationID 列出組織旗下的接待主
+func (r *mongoHostRepository) ListByOrganizationID(ctx context.Context, orgID primitive.ObjectID) ([]*domain.Host, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"organizationId": orgID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var hosts []*domain.Host
+	if err := cursor.All(ctx, &hosts); err != nil {
+		return nil, err
+	}
+	return hosts, nil
+}
+
+// SetOrganization 設定接待主所屬的組織，orgID 為 nil 時移出組織
+func (r *mongoHostRepository) SetOrganization(ctx context.Context, id string, orgID *primitive.ObjectID) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{"organizationId": orgID, "updatedAt": time.Now()}}
+	if orgID == nil {
+		update = bson.M{
+			"$unset": bson.M{"organizationId": ""},
+			"$set":   bson.M{"updatedAt": time.Now()},
+		}
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// AddMember 新增共同管理者，使用者已是成員時不會重複加入
+func (r *mongoHostRepository) AddMember(ctx context.Context, id string, member domain.HostMember) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": objID, "members.userId": bson.M{"$ne": member.UserID}}
+	update := bson.M{
+		"$push": bson.M{"members": member},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	return r.updateOne(ctx, filter, update)
+}
+
+// UpdateMemberRole 變更共同管理者的角色
+func (r *mongoHostRepository) UpdateMemberRole(ctx context.Context, id, userID string, role domain.HostMemberRole) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": objID, "members.userId": userObjID}
+	update := bson.M{"$set": bson.M{"members.$.role": role, "updatedAt": time.Now()}}
+	return r.updateOne(ctx, filter, update)
+}
+
+// RemoveMember 移除共同管理者
+func (r *mongoHostRepository) RemoveMember(ctx context.Context, id, userID string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": objID, "members.userId": userObjID}
+	update := bson.M{
+		"$pull": bson.M{"members": bson.M{"userId": userObjID}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	return r.updateOne(ctx, filter, update)
+}
+
+func (r *mongoHostRepository) updateOne(ctx context.Context, filter, update bson.M) error {
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
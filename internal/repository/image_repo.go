@@ -17,6 +17,10 @@ type ImageRepository interface {
 	UpdateStatus(ctx context.Context, id string, status domain.ImageStatus) error
 	CountByStatus(ctx context.Context, status domain.ImageStatus) (int64, error)
 	ListByStatus(ctx context.Context, status domain.ImageStatus, limit, offset int64) ([]*domain.Image, int64, error)
+	ListByUserID(ctx context.Context, userID string) ([]*domain.Image, error)
+	ScheduleDeletionByUserID(ctx context.Context, userID string, deleteAfter time.Time) (int64, error)
+	ListDueForDeletion(ctx context.Context, now time.Time, limit int64) ([]*domain.Image, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
 }
 
 type mongoImageRepository struct {
@@ -32,6 +36,10 @@ func NewImageRepository(collection *mongo.Collection) ImageRepository {
 	collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
 		{Keys: bson.D{{Key: "userId", Value: 1}}},
 		{Keys: bson.D{{Key: "status", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "deleteAfter", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
 	})
 
 	return &mongoImageRepository{collection: collection}
@@ -78,11 +86,12 @@ func (r *mongoImageRepository) UpdateStatus(ctx context.Context, id string, stat
 }
 
 func (r *mongoImageRepository) CountByStatus(ctx context.Context, status domain.ImageStatus) (int64, error) {
-	return r.collection.CountDocuments(ctx, bson.M{"status": status})
+	return r.collection.CountDocuments(ctx, bson.M{"status": status, "private": bson.M{"$ne": true}})
 }
 
 func (r *mongoImageRepository) ListByStatus(ctx context.Context, status domain.ImageStatus, limit, offset int64) ([]*domain.Image, int64, error) {
-	filter := bson.M{"status": status}
+	// 已排定刪除的圖片與私有文件不需要審核
+	filter := bson.M{"status": status, "private": bson.M{"$ne": true}, "deleteAfter": bson.M{"$exists": false}}
 	total, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
@@ -102,3 +111,61 @@ func (r *mongoImageRepository) ListByStatus(ctx context.Context, status domain.I
 
 	return images, total, nil
 }
+
+// ListByUserID 列出使用者上傳的所有圖片
+func (r *mongoImageRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.Image, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userObjID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var images []*domain.Image
+	if err := cursor.All(ctx, &images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// ScheduleDeletionByUserID 將使用者尚未排定刪除的圖片標記為在 deleteAfter 後刪除
+func (r *mongoImageRepository) ScheduleDeletionByUserID(ctx context.Context, userID string, deleteAfter time.Time) (int64, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	filter := bson.M{"userId": userObjID, "deleteAfter": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"deleteAfter": deleteAfter, "updatedAt": time.Now()}}
+	res, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// ListDueForDeletion 列出排定刪除時間已到的圖片
+func (r *mongoImageRepository) ListDueForDeletion(ctx context.Context, now time.Time, limit int64) ([]*domain.Image, error) {
+	opts := options.Find().SetLimit(limit).SetSort(bson.D{{Key: "deleteAfter", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"deleteAfter": bson.M{"$lte": now}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var images []*domain.Image
+	if err := cursor.All(ctx, &images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (r *mongoImageRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
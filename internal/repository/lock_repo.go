@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LockRepository 是以 Mongo 文件實作的租約鎖，多個副本共用同一個 collection
+type LockRepository interface {
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name, owner string) error
+}
+
+type mongoLockRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLockRepository(collection *mongo.Collection) LockRepository {
+	return &mongoLockRepository{collection: collection}
+}
+
+// Acquire 取得或續約名為 name 的鎖，租約期間為 ttl。鎖由其他 owner 持有且尚未過期時回傳 false
+func (r *mongoLockRepository) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": []bson.M{
+			{"owner": owner},
+			{"expiresAt": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"owner": owner, "expiresAt": now.Add(ttl)}}
+
+	// 鎖由其他人持有時 filter 不符，upsert 會因 _id 重複而失敗
+	_, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Release 釋放自己持有的鎖，讓其他副本不必等租約過期
+func (r *mongoLockRepository) Release(ctx context.Context, name, owner string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": name, "owner": owner})
+	return err
+}
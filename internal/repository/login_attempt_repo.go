@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, key string) (*domain.LoginAttempt, error)
+	RecordFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempt, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Delete(ctx context.Context, key string) error
+}
+
+type mongoLoginAttemptRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLoginAttemptRepository(collection *mongo.Collection) LoginAttemptRepository {
+	// Create Indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		// TTL index: 時間窗與鎖定都結束後由 MongoDB 自動清除
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+
+	return &mongoLoginAttemptRepository{collection: collection}
+}
+
+func (r *mongoLoginAttemptRepository) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	var attempt domain.LoginAttempt
+	if err := r.collection.FindOne(ctx, bson.M{"key": key}).Decode(&attempt); err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// RecordFailure 將失敗次數加一並回傳最新的紀錄。
+// 時間窗已結束的紀錄會先重新計算；計數以原子操作完成，可在多個實例間共用。
+func (r *mongoLoginAttemptRepository) RecordFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempt, error) {
+	now := time.Now()
+
+	// 1. 重設已過期的時間窗 (仍在鎖定中的紀錄保留)
+	_, err := r.collection.UpdateOne(ctx, bson.M{
+		"key":         key,
+		"windowStart": bson.M{"$lte": now.Add(-window)},
+		"lockedUntil": bson.M{"$not": bson.M{"$gt": now}},
+	}, bson.M{
+		"$set":   bson.M{"failures": 0, "windowStart": now},
+		"$unset": bson.M{"lockedUntil": ""},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 累加失敗次數
+	update := bson.M{
+		"$inc":         bson.M{"failures": 1},
+		"$set":         bson.M{"lastFailureAt": now},
+		"$setOnInsert": bson.M{"windowStart": now},
+		"$max":         bson.M{"expiresAt": now.Add(window)},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var attempt domain.LoginAttempt
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"key": key}, update, opts).Decode(&attempt); err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// Lock 鎖定至 until，紀錄會保留到鎖定結束
+func (r *mongoLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"key": key}, bson.M{
+		"$set": bson.M{"lockedUntil": until},
+		"$max": bson.M{"expiresAt": until},
+	})
+	return err
+}
+
+func (r *mongoLoginAttemptRepository) Delete(ctx context.Context, key string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"key": key})
+	return err
+}
@@ -16,6 +16,7 @@ type NotificationRepository interface {
 	ListByUserID(ctx context.Context, userID string, limit, offset int64) ([]*domain.Notification, int64, error)
 	MarkAsRead(ctx context.Context, id string, userID string) error
 	MarkAllAsRead(ctx context.Context, userID string) error
+	DeleteByUserID(ctx context.Context, userID string) (int64, error)
 }
 
 type mongoNotificationRepository struct {
@@ -99,3 +100,16 @@ func (r *mongoNotificationRepository) MarkAllAsRead(ctx context.Context, userID
 	_, err := r.collection.UpdateMany(ctx, filter, update)
 	return err
 }
+
+// DeleteByUserID 刪除使用者的所有通知
+func (r *mongoNotificationRepository) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, err
+	}
+	res, err := r.collection.DeleteMany(ctx, bson.M{"userId": userObjID})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OpportunityApprovalRepository 保存每個工作機會最近一次通過審核時的內容
+type OpportunityApprovalRepository interface {
+	Save(ctx context.Context, approval *domain.OpportunityApproval) error
+	GetByOpportunityID(ctx context.Context, oppID primitive.ObjectID) (*domain.OpportunityApproval, error)
+}
+
+type mongoOpportunityApprovalRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOpportunityApprovalRepository(collection *mongo.Collection) OpportunityApprovalRepository {
+	// Create Indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "opportunityId", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+
+	return &mongoOpportunityApprovalRepository{collection: collection}
+}
+
+// Save 以新的審核內容取代同一工作機會先前的紀錄
+func (r *mongoOpportunityApprovalRepository) Save(ctx context.Context, approval *domain.OpportunityApproval) error {
+	update := bson.M{
+		"$set": bson.M{
+			"snapshot":   approval.Snapshot,
+			"approvedBy": approval.ApprovedBy,
+			"approvedAt": approval.ApprovedAt,
+		},
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"opportunityId": approval.OpportunityID}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *mongoOpportunityApprovalRepository) GetByOpportunityID(ctx context.Context, oppID primitive.ObjectID) (*domain.OpportunityApproval, error) {
+	var approval domain.OpportunityApproval
+	err := r.collection.FindOne(ctx, bson.M{"opportunityId": oppID}).Decode(&approval)
+	if err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
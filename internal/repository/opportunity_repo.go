@@ -15,9 +15,16 @@ type OpportunityRepository interface {
 	Create(ctx context.Context, opp *domain.Opportunity) error
 	GetByID(ctx context.Context, id string) (*domain.Opportunity, error)
 	List(ctx context.Context, filter bson.M, limit, offset int64) ([]*domain.Opportunity, error)
-	Update(ctx context.Context, id string, opp *domain.Opportunity) error
-	Delete(ctx context.Context, id string) error
+	Update(ctx context.Context, id string, from domain.OpportunityStatus, opp *domain.Opportunity) error
 	Search(ctx context.Context, filter OpportunityFilter) ([]*domain.Opportunity, int64, error)
+	UpdateStatus(ctx context.Context, id string, from domain.OpportunityStatus, entry domain.OpportunityStatusHistory, fields bson.M) error
+	UpdateStatusByHostID(ctx context.Context, hostID primitive.ObjectID, from []domain.OpportunityStatus, entry domain.OpportunityStatusHistory) (int64, error)
+	ListPendingReview(ctx context.Context, filter OpportunityReviewFilter) ([]*domain.Opportunity, int64, error)
+	ListExpiring(ctx context.Context, cutoff time.Time, cutoffDate string) ([]*domain.Opportunity, error)
+	CloseEndedTimeSlots(ctx context.Context, today string) (int64, error)
+	ListFullyBooked(ctx context.Context, today string) ([]*domain.Opportunity, error)
+	MarkExpiryNotified(ctx context.Context, id string, at time.Time) error
+	MarkEditsReviewed(ctx context.Context, id string, editedAt, reviewedAt time.Time) error
 }
 
 type OpportunityFilter struct {
@@ -34,6 +41,19 @@ type OpportunityFilter struct {
 	Offset    int64
 }
 
+// OpportunityReviewFilter 篩選審核佇列，Flagged 為 nil 時不依自動篩檢結果篩選。
+// Edited 為 true 時改為列出通過審核後仍公開、但內容已被修改的工作機會。
+type OpportunityReviewFilter struct {
+	Edited  bool
+	HostID  string
+	Type    string
+	City    string
+	Flagged *bool
+	Flag    domain.ModerationFlagCode
+	Limit   int64
+	Offset  int64
+}
+
 type mongoOpportunityRepository struct {
 	collection *mongo.Collection
 }
@@ -48,16 +68,11 @@ func NewOpportunityRepository(collection *mongo.Collection) OpportunityRepositor
 		Keys: bson.D{{Key: "location.coordinates", Value: "2dsphere"}},
 	})
 
-	// Text index for search
-	collection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bson.D{
-			{Key: "title", Value: "text"},
-			{Key: "description", Value: "text"},
-			{Key: "shortDescription", Value: "text"},
-			{Key: "location.city", Value: "text"},
-			{Key: "location.country", Value: "text"},
-		},
-	})
+	// Text index for search, covering every translation
+	ensureTextIndex(ctx, collection, "opportunity_text_i18n", translatedTextKeys(
+		[]string{"title", "description", "shortDescription", "location.city", "location.country"},
+		"title", "description", "shortDescription",
+	))
 
 	// Unique index for slug and publicId
 	collection.Indexes().CreateOne(ctx, mongo.IndexModel{
@@ -76,6 +91,8 @@ func NewOpportunityRepository(collection *mongo.Collection) OpportunityRepositor
 		{Keys: bson.D{{Key: "type", Value: 1}}},
 		{Keys: bson.D{{Key: "location.city", Value: 1}}},
 		{Keys: bson.D{{Key: "location.country", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "submittedAt", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "applicationProcess.deadline", Value: 1}}},
 	})
 
 	return &mongoOpportunityRepository{collection: collection}
@@ -120,29 +137,66 @@ func (r *mongoOpportunityRepository) List(ctx context.Context, filter bson.M, li
 	return opps, nil
 }
 
-func (r *mongoOpportunityRepository) Update(ctx context.Context, id string, opp *domain.Opportunity) error {
+// Update 在目前狀態仍為 from 時以 opp 取代整份文件，
+// 避免覆蓋讀取後由其他請求或排程寫入的狀態與狀態歷程。狀態已被變更時回傳 mongo.ErrNoDocuments。
+func (r *mongoOpportunityRepository) Update(ctx context.Context, id string, from domain.OpportunityStatus, opp *domain.Opportunity) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 	opp.UpdatedAt = time.Now()
-	_, err = r.collection.ReplaceOne(ctx, bson.M{"_id": objID}, opp)
-	return err
+	res, err := r.collection.ReplaceOne(ctx, bson.M{"_id": objID, "status": from}, opp)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 
-func (r *mongoOpportunityRepository) Delete(ctx context.Context, id string) error {
+// UpdateStatus 在目前狀態仍為 from 時改為 entry.Status 並附加狀態歷程，entry.Reason 同時寫入 statusNote，
+// fields 為同時更新的其他欄位。狀態已被變更時回傳 mongo.ErrNoDocuments。
+func (r *mongoOpportunityRepository) UpdateStatus(ctx context.Context, id string, from domain.OpportunityStatus, entry domain.OpportunityStatusHistory, fields bson.M) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
+	filter := bson.M{"_id": objID, "status": from}
+	set := bson.M{
+		"status":     entry.Status,
+		"statusNote": entry.Reason,
+		"updatedAt":  time.Now(),
+	}
+	for k, v := range fields {
+		set[k] = v
+	}
 	update := bson.M{
-		"$set": bson.M{
-			"status":    domain.OpportunityStatusDeleted,
-			"updatedAt": time.Now(),
-		},
+		"$set":  set,
+		"$push": bson.M{"statusHistory": entry},
 	}
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
-	return err
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// UpdateStatusByHostID 將接待主狀態為 from 的工作機會改為 entry.Status，並附加狀態歷程
+func (r *mongoOpportunityRepository) UpdateStatusByHostID(ctx context.Context, hostID primitive.ObjectID, from []domain.OpportunityStatus, entry domain.OpportunityStatusHistory) (int64, error) {
+	filter := bson.M{"hostId": hostID, "status": bson.M{"$in": from}}
+	update := bson.M{
+		"$set":  bson.M{"status": entry.Status, "statusNote": entry.Reason, "updatedAt": time.Now()},
+		"$push": bson.M{"statusHistory": entry},
+	}
+	res, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
 }
 
 func (r *mongoOpportunityRepository) Search(ctx context.Context, filter OpportunityFilter) ([]*domain.Opportunity, int64, error) {
@@ -224,3 +278,151 @@ func (r *mongoOpportunityRepository) Search(ctx context.Context, filter Opportun
 
 	return opps, total, nil
 }
+
+// ListPendingReview 列出等待審核的工作機會，先送審 (或先修改) 的排在前面
+func (r *mongoOpportunityRepository) ListPendingReview(ctx context.Context, filter OpportunityReviewFilter) ([]*domain.Opportunity, int64, error) {
+	query := bson.M{"status": domain.OpportunityStatusPending}
+	sortKey := "submittedAt"
+	if filter.Edited {
+		query = bson.M{"status": bson.M{"$in": liveOpportunityStatuses}, "lastEditedAt": bson.M{"$ne": nil}}
+		sortKey = "lastEditedAt"
+	}
+	if filter.HostID != "" {
+		hostID, err := primitive.ObjectIDFromHex(filter.HostID)
+		if err != nil {
+			return nil, 0, err
+		}
+		query["hostId"] = hostID
+	}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.City != "" {
+		query["location.city"] = filter.City
+	}
+	if filter.Flagged != nil {
+		query["moderationFlags.0"] = bson.M{"$exists": *filter.Flagged}
+	}
+	if filter.Flag != "" {
+		query["moderationFlags.code"] = filter.Flag
+	}
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetLimit(filter.Limit).SetSkip(filter.Offset).SetSort(bson.D{{Key: sortKey, Value: 1}})
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var opps []*domain.Opportunity
+	if err := cursor.All(ctx, &opps); err != nil {
+		return nil, 0, err
+	}
+	return opps, total, nil
+}
+
+// liveOpportunityStatuses 是排程會自動過期的狀態，管理員暫停中的工作機會不受影響
+var liveOpportunityStatuses = []domain.OpportunityStatus{
+	domain.OpportunityStatusActive,
+	domain.OpportunityStatusPaused,
+	domain.OpportunityStatusFilled,
+}
+
+// ListExpiring 列出在 cutoff 前到期的工作機會：申請截止時間不晚於 cutoff，
+// 或所有時段都在 cutoffDate (cutoff 當天) 之前結束
+func (r *mongoOpportunityRepository) ListExpiring(ctx context.Context, cutoff time.Time, cutoffDate string) ([]*domain.Opportunity, error) {
+	filter := bson.M{
+		"status": bson.M{"$in": liveOpportunityStatuses},
+		"$or": []bson.M{
+			{"applicationProcess.deadline": bson.M{"$lte": cutoff}},
+			{
+				"timeSlots.0": bson.M{"$exists": true},
+				"timeSlots":   bson.M{"$not": bson.M{"$elemMatch": bson.M{"endDate": bson.M{"$gte": cutoffDate}}}},
+			},
+		},
+	}
+	return r.List(ctx, filter, 0, 0)
+}
+
+// CloseEndedTimeSlots 將 today 之前已結束、尚未關閉的時段改為 CLOSED，回傳更新的工作機會數
+func (r *mongoOpportunityRepository) CloseEndedTimeSlots(ctx context.Context, today string) (int64, error) {
+	ended := bson.M{"endDate": bson.M{"$lt": today}, "status": bson.M{"$ne": domain.TimeSlotStatusClosed}}
+	filter := bson.M{
+		"status":    bson.M{"$ne": domain.OpportunityStatusDeleted},
+		"timeSlots": bson.M{"$elemMatch": ended},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"timeSlots.$[slot].status": domain.TimeSlotStatusClosed,
+			"updatedAt":                time.Now(),
+		},
+	}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		bson.M{"slot.endDate": bson.M{"$lt": today}, "slot.status": bson.M{"$ne": domain.TimeSlotStatusClosed}},
+	}})
+	res, err := r.collection.UpdateMany(ctx, filter, update, opts)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// ListFullyBooked 列出 ACTIVE 且在 today 尚未結束的時段都已額滿的工作機會，條件與 Opportunity.SlotsFull 相同：
+// 至少有一個未關閉、未結束的時段，且其中沒有狀態不是 FILLED、confirmedCount 也未達 defaultCapacity 的時段
+func (r *mongoOpportunityRepository) ListFullyBooked(ctx context.Context, today string) ([]*domain.Opportunity, error) {
+	upcoming := []interface{}{
+		bson.M{"$ne": bson.A{"$$slot.status", domain.TimeSlotStatusClosed}},
+		bson.M{"$gte": bson.A{"$$slot.endDate", today}},
+	}
+	openSlots := bson.M{"$filter": bson.M{
+		"input": "$timeSlots",
+		"as":    "slot",
+		"cond": bson.M{"$and": append(upcoming,
+			bson.M{"$ne": bson.A{"$$slot.status", domain.TimeSlotStatusFilled}},
+			bson.M{"$or": bson.A{
+				bson.M{"$lte": bson.A{"$$slot.defaultCapacity", 0}},
+				bson.M{"$lt": bson.A{"$$slot.confirmedCount", "$$slot.defaultCapacity"}},
+			}},
+		)},
+	}}
+	filter := bson.M{
+		"status":    domain.OpportunityStatusActive,
+		"timeSlots": bson.M{"$elemMatch": bson.M{"endDate": bson.M{"$gte": today}, "status": bson.M{"$ne": domain.TimeSlotStatusClosed}}},
+		"$expr":     bson.M{"$eq": bson.A{bson.M{"$size": openSlots}, 0}},
+	}
+	return r.List(ctx, filter, 0, 0)
+}
+
+func (r *mongoOpportunityRepository) MarkExpiryNotified(ctx context.Context, id string, at time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": bson.M{"expiryNotifiedAt": at}})
+	return err
+}
+
+// MarkEditsReviewed 在管理員審核過上架後的修改時更新通過審核時間並清除修改紀錄。
+// 審核期間接待主又修改了內容 (lastEditedAt 已不是 editedAt) 時回傳 mongo.ErrNoDocuments。
+func (r *mongoOpportunityRepository) MarkEditsReviewed(ctx context.Context, id string, editedAt, reviewedAt time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	res, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "lastEditedAt": editedAt},
+		bson.M{"$set": bson.M{"lastApprovedAt": reviewedAt, "lastEditedAt": nil, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
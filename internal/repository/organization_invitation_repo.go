@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type OrganizationInvitationRepository interface {
+	Create(ctx context.Context, inv *domain.OrganizationInvitation) error
+	GetByID(ctx context.Context, id string) (*domain.OrganizationInvitation, error)
+	ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID, status domain.OrganizationInvitationStatus) ([]*domain.OrganizationInvitation, error)
+	ListOpenByEmail(ctx context.Context, email string, now time.Time) ([]*domain.OrganizationInvitation, error)
+	HasOpen(ctx context.Context, organizationID primitive.ObjectID, email string, now time.Time) (bool, error)
+	Respond(ctx context.Context, id string, status domain.OrganizationInvitationStatus, at time.Time) error
+}
+
+type mongoOrganizationInvitationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOrganizationInvitationRepository(collection *mongo.Collection) OrganizationInvitationRepository {
+	// Create Indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "email", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "organizationId", Value: 1}, {Key: "status", Value: 1}}},
+	})
+
+	return &mongoOrganizationInvitationRepository{collection: collection}
+}
+
+func (r *mongoOrganizationInvitationRepository) Create(ctx context.Context, inv *domain.OrganizationInvitation) error {
+	inv.CreatedAt = time.Now()
+	res, err := r.collection.InsertOne(ctx, inv)
+	if err != nil {
+		return err
+	}
+	inv.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *mongoOrganizationInvitationRepository) GetByID(ctx context.Context, id string) (*domain.OrganizationInvitation, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var inv domain.OrganizationInvitation
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&inv)
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+// ListByOrganizationID 列出組織發出的邀請，status 為空時列出全部
+func (r *mongoOrganizationInvitationRepository) ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID, status domain.OrganizationInvitationStatus) ([]*domain.OrganizationInvitation, error) {
+	filter := bson.M{"organizationId": organizationID}
+	if status != "" {
+		filter["status"] = status
+	}
+	return r.find(ctx, filter)
+}
+
+// ListOpenByEmail 列出寄給 email 且尚未回覆、未過期的邀請
+func (r *mongoOrganizationInvitationRepository) ListOpenByEmail(ctx context.Context, email string, now time.Time) ([]*domain.OrganizationInvitation, error) {
+	return r.find(ctx, bson.M{
+		"email":     email,
+		"status":    domain.OrganizationInvitationPending,
+		"expiresAt": bson.M{"$gt": now},
+	})
+}
+
+// HasOpen 回傳組織是否已有寄給 email 且尚未回覆、未過期的邀請
+func (r *mongoOrganizationInvitationRepository) HasOpen(ctx context.Context, organizationID primitive.ObjectID, email string, now time.Time) (bool, error) {
+	n, err := r.collection.CountDocuments(ctx, bson.M{
+		"organizationId": organizationID,
+		"email":          email,
+		"status":         domain.OrganizationInvitationPending,
+		"expiresAt":      bson.M{"$gt": now},
+	})
+	return n > 0, err
+}
+
+// Respond 將尚未回覆的邀請改為 status，邀請已回覆時回傳 mongo.ErrNoDocuments
+func (r *mongoOrganizationInvitationRepository) Respond(ctx context.Context, id string, status domain.OrganizationInvitationStatus, at time.Time) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": objID, "status": domain.OrganizationInvitationPending}
+	update := bson.M{"$set": bson.M{"status": status, "respondedAt": at}}
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *mongoOrganizationInvitationRepository) find(ctx context.Context, filter bson.M) ([]*domain.OrganizationInvitation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	invitations := []*domain.OrganizationInvitation{}
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
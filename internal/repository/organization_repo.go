@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *domain.Organization) error
+	GetByID(ctx context.Context, id string) (*domain.Organization, error)
+	Update(ctx context.Context, id string, fields bson.M) error
+	AddMember(ctx context.Context, id string, member domain.OrganizationMember) error
+	UpdateMemberRole(ctx context.Context, id, userID string, role domain.OrganizationRole) error
+	RemoveMember(ctx context.Context, id, userID string) error
+}
+
+type mongoOrganizationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOrganizationRepository(collection *mongo.Collection) OrganizationRepository {
+	// Create Indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Index for looking up a user's organization
+	collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "members.userId", Value: 1}},
+	})
+
+	return &mongoOrganizationRepository{collection: collection}
+}
+
+func (r *mongoOrganizationRepository) Create(ctx context.Context, org *domain.Organization) error {
+	org.CreatedAt = time.Now()
+	org.UpdatedAt = time.Now()
+	res, err := r.collection.InsertOne(ctx, org)
+	if err != nil {
+		return err
+	}
+	org.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *mongoOrganizationRepository) GetByID(ctx context.Context, id string) (*domain.Organization, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var org domain.Organization
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&org)
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+// Update 更新組織資料欄位，成員請使用 AddMember / UpdateMemberRole / RemoveMember
+func (r *mongoOrganizationRepository) Update(ctx context.Context, id string, fields bson.M) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	fields["updatedAt"] = time.Now()
+	return r.updateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": fields})
+}
+
+// AddMember 新增成員，使用者已是成員時不會重複加入
+func (r *mongoOrganizationRepository) AddMember(ctx context.Context, id string, member domain.OrganizationMember) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": objID, "members.userId": bson.M{"$ne": member.UserID}}
+	update := bson.M{
+		"$push": bson.M{"members": member},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	return r.updateOne(ctx, filter, update)
+}
+
+// UpdateMemberRole 變更成員的角色
+func (r *mongoOrganizationRepository) UpdateMemberRole(ctx context.Context, id, userID string, role domain.OrganizationRole) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": objID, "members.userId": userObjID}
+	update := bson.M{"$set": bson.M{"members.$.role": role, "updatedAt": time.Now()}}
+	return r.updateOne(ctx, filter, update)
+}
+
+// RemoveMember 移除成員
+func (r *mongoOrganizationRepository) RemoveMember(ctx context.Context, id, userID string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": objID, "members.userId": userObjID}
+	update := bson.M{
+		"$pull": bson.M{"members": bson.M{"userId": userObjID}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+	return r.updateOne(ctx, filter, update)
+}
+
+func (r *mongoOrganizationRepository) updateOne(ctx context.Context, filter, update bson.M) error {
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
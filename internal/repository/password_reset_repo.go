@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, reset *domain.PasswordReset) error
+	Consume(ctx context.Context, tokenHash string) (*domain.PasswordReset, error)
+	InvalidateByUserID(ctx context.Context, userID string) error
+}
+
+type mongoPasswordResetRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPasswordResetRepository(collection *mongo.Collection) PasswordResetRepository {
+	// Create Indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "userId", Value: 1}}},
+		// TTL index: 過期的重設請求由 MongoDB 自動清除
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+
+	return &mongoPasswordResetRepository{collection: collection}
+}
+
+func (r *mongoPasswordResetRepository) Create(ctx context.Context, reset *domain.PasswordReset) error {
+	reset.CreatedAt = time.Now()
+	res, err := r.collection.InsertOne(ctx, reset)
+	if err != nil {
+		return err
+	}
+	reset.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// Consume 將未使用且未過期的 token 標記為已使用並回傳。
+// 以單一原子操作完成，確保同一個 token 不會被使用兩次；找不到時回傳 mongo.ErrNoDocuments。
+func (r *mongoPasswordResetRepository) Consume(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+	now := time.Now()
+	filter := bson.M{
+		"tokenHash": tokenHash,
+		"usedAt":    bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{"usedAt": now}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var reset domain.PasswordReset
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&reset); err != nil {
+		return nil, err
+	}
+	return &reset, nil
+}
+
+// InvalidateByUserID 使該使用者所有尚未使用的重設 token 失效
+func (r *mongoPasswordResetRepository) InvalidateByUserID(ctx context.Context, userID string) error {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"userId": objID,
+		"usedAt": bson.M{"$exists": false},
+	}
+	_, err = r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"usedAt": time.Now()}})
+	return err
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PhoneVerificationRepository interface {
+	Create(ctx context.Context, verification *domain.PhoneVerification) error
+	ListSince(ctx context.Context, userID string, since time.Time) ([]*domain.PhoneVerification, error)
+	ListSinceByPhone(ctx context.Context, phone string, since time.Time) ([]*domain.PhoneVerification, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	IncrementAttempts(ctx context.Context, id primitive.ObjectID) (*domain.PhoneVerification, error)
+	MarkUsed(ctx context.Context, id primitive.ObjectID) (bool, error)
+}
+
+type mongoPhoneVerificationRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPhoneVerificationRepository(collection *mongo.Collection) PhoneVerificationRepository {
+	// Create Indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "phone", Value: 1}, {Key: "createdAt", Value: 1}}},
+		// TTL index: 保留 24 小時供每日寄送上限計算
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32((24 * time.Hour).Seconds())),
+		},
+	})
+
+	return &mongoPhoneVerificationRepository{collection: collection}
+}
+
+func (r *mongoPhoneVerificationRepository) Create(ctx context.Context, verification *domain.PhoneVerification) error {
+	verification.CreatedAt = time.Now()
+
+	res, err := r.collection.InsertOne(ctx, verification)
+	if err != nil {
+		return err
+	}
+	verification.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// ListSince 依寄送時間由舊到新列出使用者在 since 之後的驗證碼
+func (r *mongoPhoneVerificationRepository) ListSince(ctx context.Context, userID string, since time.Time) ([]*domain.PhoneVerification, error) {
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	return r.listSince(ctx, bson.M{"userId": objID}, since)
+}
+
+// ListSinceByPhone 依寄送時間由舊到新列出在 since 之後寄到該號碼的驗證碼 (不分帳號)
+func (r *mongoPhoneVerificationRepository) ListSinceByPhone(ctx context.Context, phone string, since time.Time) ([]*domain.PhoneVerification, error) {
+	return r.listSince(ctx, bson.M{"phone": phone}, since)
+}
+
+// listSince 以寄送時間與 _id 排序，同一時間寫入的資料在每次查詢中順序一致
+func (r *mongoPhoneVerificationRepository) listSince(ctx context.Context, filter bson.M, since time.Time) ([]*domain.PhoneVerification, error) {
+	filter["createdAt"] = bson.M{"$gt": since}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var verifications []*domain.PhoneVerification
+	if err := cursor.All(ctx, &verifications); err != nil {
+		return nil, err
+	}
+	return verifications, nil
+}
+
+// Delete 刪除驗證碼，用於撤回超過寄送上限的資料
+func (r *mongoPhoneVerificationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// IncrementAttempts 將嘗試次數加一並回傳更新後的資料，嘗試次數在比對驗證碼前先累加，避免並行請求繞過上限
+func (r *mongoPhoneVerificationRepository) IncrementAttempts(ctx context.Context, id primitive.ObjectID) (*domain.PhoneVerification, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var verification domain.PhoneVerification
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"attempts": 1}}, opts).Decode(&verification)
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// MarkUsed 將驗證碼標記為已使用，回傳 false 表示已被使用過
+func (r *mongoPhoneVerificationRepository) MarkUsed(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	filter := bson.M{"_id": id, "usedAt": bson.M{"$exists": false}}
+	res, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"usedAt": time.Now()}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
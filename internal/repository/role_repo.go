@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RoleRepository interface {
+	GetByName(ctx context.Context, name domain.UserRole) (*domain.Role, error)
+	List(ctx context.Context) ([]*domain.Role, error)
+	Upsert(ctx context.Context, role *domain.Role) error
+	CreateIfNotExists(ctx context.Context, role *domain.Role) error
+}
+
+type mongoRoleRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRoleRepository(collection *mongo.Collection) RoleRepository {
+	// Create Indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+
+	return &mongoRoleRepository{collection: collection}
+}
+
+func (r *mongoRoleRepository) GetByName(ctx context.Context, name domain.UserRole) (*domain.Role, error) {
+	var role domain.Role
+	if err := r.collection.FindOne(ctx, bson.M{"name": name}).Decode(&role); err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *mongoRoleRepository) List(ctx context.Context) ([]*domain.Role, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var roles []*domain.Role
+	if err := cursor.All(ctx, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// Upsert 建立角色或更新既有角色的說明與權限
+func (r *mongoRoleRepository) Upsert(ctx context.Context, role *domain.Role) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"description": role.Description,
+			"permissions": role.Permissions,
+			"updatedAt":   now,
+		},
+		"$setOnInsert": bson.M{
+			"system":    role.System,
+			"createdAt": now,
+		},
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"name": role.Name}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// CreateIfNotExists 只在角色不存在時建立，不覆寫管理員調整過的權限
+func (r *mongoRoleRepository) CreateIfNotExists(ctx context.Context, role *domain.Role) error {
+	now := time.Now()
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"description": role.Description,
+			"permissions": role.Permissions,
+			"system":      role.System,
+			"createdAt":   now,
+			"updatedAt":   now,
+		},
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"name": role.Name}, update, options.Update().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxPreviousTokenHashes 限制每個 session 保留的舊 refresh token hash 數量
+const maxPreviousTokenHashes = 20
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *domain.Session) error
+	GetByID(ctx context.Context, id string) (*domain.Session, error)
+	GetByRefreshTokenHash(ctx context.Context, hash string) (*domain.Session, error)
+	GetByPreviousTokenHash(ctx context.Context, hash string) (*domain.Session, error)
+	Rotate(ctx context.Context, session *domain.Session, oldHash string) error
+	Revoke(ctx context.Context, id string, reason string) error
+	ListActiveByUserID(ctx context.Context, userID string) ([]*domain.Session, error)
+	RevokeAllByUserID(ctx context.Context, userID string, exceptID string, reason string) (int64, error)
+}
+
+type mongoSessionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSessionRepository(collection *mongo.Collection) SessionRepository {
+	// Create Indexes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "refreshTokenHash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "previousTokenHashes", Value: 1}}},
+		{Keys: bson.D{{Key: "userId", Value: 1}}},
+		// TTL index: 過期的 session 由 MongoDB 自動清除
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+
+	return &mongoSessionRepository{collection: collection}
+}
+
+func (r *mongoSessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	now := time.Now()
+	session.CreatedAt = now
+	session.LastUsedAt = now
+	res, err := r.collection.InsertOne(ctx, session)
+	if err != nil {
+		return err
+	}
+	session.ID = res.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *mongoSessionRepository) GetByID(ctx context.Context, id string) (*domain.Session, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.findOne(ctx, bson.M{"_id": objID})
+}
+
+func (r *mongoSessionRepository) GetByRefreshTokenHash(ctx context.Context, hash string) (*domain.Session, error) {
+	return r.findOne(ctx, bson.M{"refreshTokenHash": hash})
+}
+
+func (r *mongoSessionRepository) GetByPreviousTokenHash(ctx context.Context, hash string) (*domain.Session, error) {
+	return r.findOne(ctx, bson.M{"previousTokenHashes": hash})
+}
+
+// Rotate 以 session 中新的 RefreshTokenHash 取代 oldHash。
+// 僅在目前的 hash 仍為 oldHash 且 session 未被撤銷時才會成功，避免同一個 token 被並行使用兩次。
+func (r *mongoSessionRepository) Rotate(ctx context.Context, session *domain.Session, oldHash string) error {
+	session.LastUsedAt = time.Now()
+
+	filter := bson.M{
+		"_id":              session.ID,
+		"refreshTokenHash": oldHash,
+		"revokedAt":        bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"refreshTokenHash": session.RefreshTokenHash,
+			"userAgent":        session.UserAgent,
+			"ip":               session.IP,
+			"lastUsedAt":       session.LastUsedAt,
+			"expiresAt":        session.ExpiresAt,
+		},
+		"$push": bson.M{
+			"previousTokenHashes": bson.M{
+				"$each":  []string{oldHash},
+				"$slice": -maxPreviousTokenHashes,
+			},
+		},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *mongoSessionRepository) Revoke(ctx context.Context, id string, reason string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": objID, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revokedAt": time.Now(), "revokedReason": reason}}
+	_, err = r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// ListActiveByUserID 回傳使用者所有未撤銷且未過期的 session，最近使用的排在前面
+func (r *mongoSessionRepository) ListActiveByUserID(ctx context.Context, userID string) ([]*domain.Session, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"userId":    userObjID,
+		"revokedAt": bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": time.Now()},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "lastUsedAt", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []*domain.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeAllByUserID 撤銷使用者所有仍有效的 session。exceptID 不為空時保留該 session。
+func (r *mongoSessionRepository) RevokeAllByUserID(ctx context.Context, userID string, exceptID string, reason string) (int64, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	filter := bson.M{"userId": userObjID, "revokedAt": bson.M{"$exists": false}}
+	if exceptID != "" {
+		exceptObjID, err := primitive.ObjectIDFromHex(exceptID)
+		if err != nil {
+			return 0, err
+		}
+		filter["_id"] = bson.M{"$ne": exceptObjID}
+	}
+	update := bson.M{"$set": bson.M{"revokedAt": time.Now(), "revokedReason": reason}}
+
+	res, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+func (r *mongoSessionRepository) findOne(ctx context.Context, filter bson.M) (*domain.Session, error) {
+	var session domain.Session
+	if err := r.collection.FindOne(ctx, filter).Decode(&session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// translatedTextKeys 回傳 fields 及其在每個語系翻譯 (translations.<locale>.<field>) 的全文索引欄位
+func translatedTextKeys(fields []string, translated ...string) bson.D {
+	keys := bson.D{}
+	for _, f := range fields {
+		keys = append(keys, bson.E{Key: f, Value: "text"})
+	}
+	for _, l := range i18n.Supported {
+		for _, f := range translated {
+			keys = append(keys, bson.E{Key: "translations." + string(l) + "." + f, Value: "text"})
+		}
+	}
+	return keys
+}
+
+// ensureTextIndex 建立指定名稱的全文索引。
+// 每個 collection 只能有一個全文索引，因此會先移除名稱不同的舊全文索引；
+// 索引欄位變更時應同時變更名稱。
+func ensureTextIndex(ctx context.Context, collection *mongo.Collection, name string, keys bson.D) error {
+	specs, err := collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return err
+	}
+	for _, spec := range specs {
+		if spec.Name == name {
+			return nil
+		}
+		if _, err := spec.KeysDocument.LookupErr("_fts"); err == nil {
+			if _, err := collection.Indexes().DropOne(ctx, spec.Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetName(name),
+	})
+	return err
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
@@ -22,6 +23,13 @@ type UserRepository interface {
 	Count(ctx context.Context) (int64, error)
 	List(ctx context.Context, filter bson.M, limit, offset int64) ([]*domain.User, int64, error)
 	UpdateStatus(ctx context.Context, id string, status domain.UserStatus) error
+	GetByIdentity(ctx context.Context, provider domain.AuthProvider, subject string) (*domain.User, error)
+	AddIdentity(ctx context.Context, id string, identity domain.ProviderIdentity) error
+	RemoveIdentity(ctx context.Context, id string, provider domain.AuthProvider, subject string) error
+	MarkVerificationEmailSent(ctx context.Context, id string, sentBefore time.Time) (bool, error)
+	MarkPasswordResetSent(ctx context.Context, id string, sentBefore time.Time) (bool, error)
+	UseMFAStep(ctx context.Context, id string, step int64) (bool, error)
+	ConsumeRecoveryCode(ctx context.Context, id string, codeHash string) (bool, error)
 }
 
 // mongoUserRepository 是 UserRepository 的 MongoDB 實作
@@ -35,10 +43,19 @@ func NewUserRepository(collection *mongo.Collection) UserRepository {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Unique index for email
-	collection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys:    bson.D{{Key: "email", Value: 1}},
-		Options: options.Index().SetUnique(true),
+	collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		// Unique index for email
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		// 一個第三方身分只能綁定一個使用者
+		{
+			Keys: bson.D{{Key: "identities.provider", Value: 1}, {Key: "identities.subject", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{
+				"identities.subject": bson.M{"$exists": true},
+			}),
+		},
 	})
 
 	return &mongoUserRepository{collection: collection}
@@ -78,7 +95,7 @@ func (r *mongoUserRepository) Update(ctx context.Context, id string, payload bso
 	// 將 ID 字串轉換為 ObjectID
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid user id format")
+		return fmt.Errorf("invalid user id format: %w", err)
 	}
 
 	// 確保更新時間戳被設定
@@ -151,7 +168,7 @@ func (r *mongoUserRepository) GetByID(ctx context.Context, id string) (*domain.U
 	var user domain.User
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid user id format")
+		return nil, fmt.Errorf("invalid user id format: %w", err)
 	}
 
 	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&user)
@@ -168,7 +185,7 @@ func (r *mongoUserRepository) GetByID(ctx context.Context, id string) (*domain.U
 func (r *mongoUserRepository) UpdateStatus(ctx context.Context, id string, status domain.UserStatus) error {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid user id format")
+		return fmt.Errorf("invalid user id format: %w", err)
 	}
 
 	filter := bson.M{"_id": objID}
@@ -185,3 +202,157 @@ func (r *mongoUserRepository) UpdateStatus(ctx context.Context, id string, statu
 
 	return nil
 }
+
+// GetByIdentity 透過第三方登入身分尋找使用者
+func (r *mongoUserRepository) GetByIdentity(ctx context.Context, provider domain.AuthProvider, subject string) (*domain.User, error) {
+	var user domain.User
+	filter := bson.M{"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}}}
+	err := r.collection.FindOne(ctx, filter).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, mongo.ErrNoDocuments
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// AddIdentity 將第三方登入身分綁定到使用者
+func (r *mongoUserRepository) AddIdentity(ctx context.Context, id string, identity domain.ProviderIdentity) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid user id format: %w", err)
+	}
+
+	filter := bson.M{"_id": objID}
+	update := bson.M{
+		"$push": bson.M{"identities": identity},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// RemoveIdentity 解除使用者與第三方登入身分的綁定
+func (r *mongoUserRepository) RemoveIdentity(ctx context.Context, id string, provider domain.AuthProvider, subject string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid user id format: %w", err)
+	}
+
+	filter := bson.M{"_id": objID}
+	update := bson.M{
+		"$pull": bson.M{"identities": bson.M{"provider": provider, "subject": subject}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// MarkVerificationEmailSent 記錄驗證信寄送時間。
+// 只有在上次寄送時間早於 sentBefore (或從未寄送) 時才會更新，回傳 false 表示仍在限制期間內。
+func (r *mongoUserRepository) MarkVerificationEmailSent(ctx context.Context, id string, sentBefore time.Time) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid user id format: %w", err)
+	}
+
+	filter := bson.M{
+		"_id": objID,
+		"$or": bson.A{
+			bson.M{"emailVerificationSentAt": bson.M{"$exists": false}},
+			bson.M{"emailVerificationSentAt": bson.M{"$lte": sentBefore}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"emailVerificationSentAt": time.Now()}}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+// MarkPasswordResetSent 記錄重設密碼信寄送時間。
+// 只有在上次寄送時間早於 sentBefore (或從未寄送) 時才會更新，回傳 false 表示仍在限制期間內。
+func (r *mongoUserRepository) MarkPasswordResetSent(ctx context.Context, id string, sentBefore time.Time) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid user id format: %w", err)
+	}
+
+	filter := bson.M{
+		"_id":                 objID,
+		"passwordResetSentAt": bson.M{"$not": bson.M{"$gt": sentBefore}},
+	}
+	update := bson.M{"$set": bson.M{"passwordResetSentAt": time.Now()}}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+// UseMFAStep 記錄已使用的 TOTP 時間區間。
+// 只有在 step 大於上次使用的區間時才會更新，回傳 false 表示驗證碼已被使用過。
+func (r *mongoUserRepository) UseMFAStep(ctx context.Context, id string, step int64) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid user id format: %w", err)
+	}
+
+	filter := bson.M{
+		"_id":              objID,
+		"mfa.lastUsedStep": bson.M{"$not": bson.M{"$gte": step}},
+	}
+	update := bson.M{"$set": bson.M{"mfa.lastUsedStep": step}}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.MatchedCount > 0, nil
+}
+
+// ConsumeRecoveryCode 移除一組復原碼，回傳 false 表示復原碼不存在或已被使用
+func (r *mongoUserRepository) ConsumeRecoveryCode(ctx context.Context, id string, codeHash string) (bool, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid user id format: %w", err)
+	}
+
+	filter := bson.M{
+		"_id":                    objID,
+		"mfa.recoveryCodeHashes": codeHash,
+	}
+	update := bson.M{"$pull": bson.M{"mfa.recoveryCodeHashes": codeHash}}
+
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.ModifiedCount > 0, nil
+}
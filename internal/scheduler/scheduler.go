@@ -0,0 +1,132 @@
+// Package scheduler 在伺服器程序內執行週期性的背景工作。
+// 多個副本同時運作時，以 Mongo 租約鎖選出一個 leader，只有 leader 會執行工作；
+// leader 停止續約後，其他副本會在租約過期後接手，因此工作必須可以重複執行。
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+)
+
+// leaderLock 是 leader 選舉使用的鎖名稱
+const leaderLock = "scheduler:leader"
+
+// Locker 是跨副本的租約鎖 (見 repository.LockRepository)
+type Locker interface {
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name, owner string) error
+}
+
+// Job 是每隔 Interval 執行一次的背景工作
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type scheduledJob struct {
+	Job
+	next time.Time
+}
+
+type Scheduler struct {
+	locker   Locker
+	owner    string
+	tick     time.Duration
+	leaseTTL time.Duration
+	jobs     []*scheduledJob
+	leader   bool
+	now      func() time.Time
+}
+
+// New 建立排程器，owner 需在各副本間唯一；tick 必須小於 leaseTTL，leader 才能在租約過期前續約
+func New(locker Locker, owner string, tick, leaseTTL time.Duration) *Scheduler {
+	return &Scheduler{
+		locker:   locker,
+		owner:    owner,
+		tick:     tick,
+		leaseTTL: leaseTTL,
+		now:      time.Now,
+	}
+}
+
+// Add 註冊工作，需在 Start 之前呼叫；成為 leader 後會立即執行一次
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, &scheduledJob{Job: job})
+}
+
+// Start 持續執行直到 ctx 結束，結束時釋放 leader 鎖
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	for {
+		s.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.release()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue 續約 leader 後執行到期的工作；不是 leader 時什麼都不做
+func (s *Scheduler) runDue(ctx context.Context) {
+	if !s.renew(ctx) {
+		return
+	}
+
+	for _, job := range s.jobs {
+		now := s.now()
+		if now.Before(job.next) {
+			continue
+		}
+		// 每個工作執行前再續約一次，避免前一個工作執行太久讓租約過期
+		if !s.renew(ctx) {
+			return
+		}
+		job.next = now.Add(job.Interval)
+
+		start := s.now()
+		if err := job.Run(ctx); err != nil {
+			logger.ErrorContext(ctx, "Scheduled job failed", "job", job.Name, "error", err)
+			continue
+		}
+		logger.Debug("Scheduled job finished", "job", job.Name, "duration", s.now().Sub(start))
+	}
+}
+
+// renew 取得或續約 leader 鎖，回傳自己是否為 leader
+func (s *Scheduler) renew(ctx context.Context) bool {
+	leader, err := s.locker.Acquire(ctx, leaderLock, s.owner, s.leaseTTL)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to acquire scheduler lock", "error", err)
+		leader = false
+	}
+	if leader != s.leader {
+		logger.InfoContext(ctx, "Scheduler leadership changed", "owner", s.owner, "leader", leader)
+		s.leader = leader
+		if !leader {
+			// 重新成為 leader 時立即執行所有工作
+			for _, job := range s.jobs {
+				job.next = time.Time{}
+			}
+		}
+	}
+	return leader
+}
+
+func (s *Scheduler) release() {
+	if !s.leader {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.locker.Release(ctx, leaderLock, s.owner); err != nil {
+		logger.Error("Failed to release scheduler lock", "error", err)
+	}
+}
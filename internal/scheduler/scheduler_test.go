@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+)
+
+// fakeLocker 模擬多個副本共用的租約鎖
+type fakeLocker struct {
+	owner     string
+	expiresAt time.Time
+	now       func() time.Time
+	err       error
+}
+
+func (l *fakeLocker) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	if l.err != nil {
+		return false, l.err
+	}
+	now := l.now()
+	if l.owner != "" && l.owner != owner && now.Before(l.expiresAt) {
+		return false, nil
+	}
+	l.owner, l.expiresAt = owner, now.Add(ttl)
+	return true, nil
+}
+
+func (l *fakeLocker) Release(ctx context.Context, name, owner string) error {
+	if l.owner == owner {
+		l.owner = ""
+	}
+	return nil
+}
+
+func TestScheduler_OnlyLeaderRunsJobs(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	now := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	locker := &fakeLocker{now: clock}
+
+	runs := map[string]int{}
+	newScheduler := func(owner string) *Scheduler {
+		s := New(locker, owner, time.Minute, 3*time.Minute)
+		s.now = clock
+		s.Add(Job{Name: "hourly", Interval: time.Hour, Run: func(ctx context.Context) error {
+			runs[owner]++
+			return nil
+		}})
+		return s
+	}
+	a, b := newScheduler("a"), newScheduler("b")
+
+	a.runDue(ctx)
+	b.runDue(ctx)
+	assert.Equal(t, map[string]int{"a": 1}, runs)
+
+	// 未到下一次執行時間
+	now = now.Add(30 * time.Minute)
+	a.runDue(ctx)
+	b.runDue(ctx)
+	assert.Equal(t, map[string]int{"a": 1}, runs)
+
+	now = now.Add(30 * time.Minute)
+	a.runDue(ctx)
+	assert.Equal(t, map[string]int{"a": 2}, runs)
+
+	// a 停止續約，租約過期後由 b 接手並立即執行
+	now = now.Add(5 * time.Minute)
+	b.runDue(ctx)
+	assert.Equal(t, map[string]int{"a": 2, "b": 1}, runs)
+	a.runDue(ctx)
+	assert.Equal(t, map[string]int{"a": 2, "b": 1}, runs)
+	assert.False(t, a.leader)
+}
+
+func TestScheduler_FailedJobDoesNotBlockOthers(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	s := New(&fakeLocker{now: time.Now}, "a", time.Minute, 3*time.Minute)
+
+	ran := false
+	s.Add(Job{Name: "failing", Interval: time.Hour, Run: func(ctx context.Context) error { return errors.New("boom") }})
+	s.Add(Job{Name: "ok", Interval: time.Hour, Run: func(ctx context.Context) error {
+		ran = true
+		return nil
+	}})
+
+	s.runDue(ctx)
+
+	assert.True(t, ran)
+}
+
+func TestScheduler_LockErrorSkipsRun(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	s := New(&fakeLocker{now: time.Now, err: errors.New("mongo down")}, "a", time.Minute, 3*time.Minute)
+
+	ran := false
+	s.Add(Job{Name: "job", Interval: time.Hour, Run: func(ctx context.Context) error {
+		ran = true
+		return nil
+	}})
+
+	s.runDue(ctx)
+
+	assert.False(t, ran)
+}
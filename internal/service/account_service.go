@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrAccountDeleted      = errcode.Conflict("ACCOUNT_DELETED", "account already deleted")
+	ErrRecentLoginRequired = errcode.Forbidden("RECENT_LOGIN_REQUIRED", "please sign in again to confirm this action")
+)
+
+const (
+	// deletedUserName 是匿名化後使用者的顯示名稱
+	deletedUserName = "Deleted user"
+	// accountDeletedNote 記錄在因帳號刪除而取消的申請上
+	accountDeletedNote = "Cancelled because the applicant deleted their account"
+	// ownerDeletedNote 記錄在因建立者刪除帳號而停用的接待主上
+	ownerDeletedNote = "Deactivated because the owner deleted their account"
+	// recentLoginWindow 是沒有密碼的帳號刪除前，目前 session 必須在多久內登入
+	recentLoginWindow = 10 * time.Minute
+)
+
+// AccountService 處理個人資料匯出與帳號刪除 (匿名化)
+type AccountService interface {
+	ExportData(ctx context.Context, userID string) (*domain.DataExport, error)
+	DeleteAccount(ctx context.Context, userID, sessionID, password string) error
+	EraseUser(ctx context.Context, userID string) error
+}
+
+type accountService struct {
+	userRepo       repository.UserRepository
+	appRepo        repository.ApplicationRepository
+	bookmarkRepo   repository.BookmarkRepository
+	notifRepo      repository.NotificationRepository
+	imageRepo      repository.ImageRepository
+	hostService    HostService
+	sessionService SessionService
+}
+
+func NewAccountService(userRepo repository.UserRepository, appRepo repository.ApplicationRepository, bookmarkRepo repository.BookmarkRepository, notifRepo repository.NotificationRepository, imageRepo repository.ImageRepository, hostService HostService, sessionService SessionService) AccountService {
+	return &accountService{
+		userRepo:       userRepo,
+		appRepo:        appRepo,
+		bookmarkRepo:   bookmarkRepo,
+		notifRepo:      notifRepo,
+		imageRepo:      imageRepo,
+		hostService:    hostService,
+		sessionService: sessionService,
+	}
+}
+
+// ExportData 匯出使用者的個人資料與關聯資料。
+// 密碼、兩步驟驗證密鑰等憑證不會出現在 JSON 中。
+func (s *accountService) ExportData(ctx context.Context, userID string) (*domain.DataExport, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+	user.Password = ""
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// limit 為 0 表示不限制筆數
+	apps, _, err := s.appRepo.List(ctx, bson.M{"userId": userObjID}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	bookmarks, _, err := s.bookmarkRepo.ListByUserID(ctx, userID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	notifications, _, err := s.notifRepo.ListByUserID(ctx, userID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	images, err := s.imageRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.DataExport{
+		GeneratedAt:   time.Now(),
+		User:          user,
+		Applications:  apps,
+		Bookmarks:     bookmarks,
+		Notifications: notifications,
+		Images:        images,
+	}, nil
+}
+
+// DeleteAccount 由使用者本人刪除帳號。
+// 有設定密碼的帳號需要再次輸入密碼確認；僅使用第三方登入的帳號則需要目前的 session
+// 是在 recentLoginWindow 內重新登入 (含兩步驟驗證) 建立的，否則回傳 ErrRecentLoginRequired。
+func (s *accountService) DeleteAccount(ctx context.Context, userID, sessionID, password string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return notFound(err, ErrUserNotFound)
+	}
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+			return ErrIncorrectPassword
+		}
+	} else if err := s.checkRecentLogin(ctx, userID, sessionID); err != nil {
+		return err
+	}
+	return s.erase(ctx, user)
+}
+
+// checkRecentLogin 確認目前的 session 屬於該使用者且是最近登入建立的
+func (s *accountService) checkRecentLogin(ctx context.Context, userID, sessionID string) error {
+	session, err := s.sessionService.GetUserSession(ctx, userID, sessionID)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return ErrRecentLoginRequired
+		}
+		return err
+	}
+	if time.Since(session.CreatedAt) > recentLoginWindow {
+		return ErrRecentLoginRequired
+	}
+	return nil
+}
+
+// EraseUser 由管理員刪除帳號，不需要使用者的密碼
+func (s *accountService) EraseUser(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return notFound(err, ErrUserNotFound)
+	}
+	return s.erase(ctx, user)
+}
+
+// erase 匿名化使用者並清除關聯資料：
+//   - 停用使用者建立的接待主並暫停其公開的工作機會
+//   - 登出所有裝置
+//   - 刪除收藏與通知
+//   - 申請保留給接待主查閱，但清除自由填寫內容，未完成的申請改為取消
+//   - 上傳的圖片排定由背景工作刪除
+//
+// 使用者文件最後才匿名化，中途失敗時可以重試完成剩餘步驟。
+func (s *accountService) erase(ctx context.Context, user *domain.User) error {
+	if user.Status == domain.UserStatusDeleted {
+		return ErrAccountDeleted
+	}
+
+	if err := s.deactivateOwnedHost(ctx, user.ID); err != nil {
+		return err
+	}
+	if _, err := s.sessionService.RevokeAllUserSessions(ctx, user.ID, "", SessionRevokedAccountDeleted); err != nil {
+		return err
+	}
+	if _, err := s.bookmarkRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+	if _, err := s.notifRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := s.cancelOpenApplications(ctx, user.ID); err != nil {
+		return err
+	}
+	if _, err := s.appRepo.AnonymizeByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+	scheduled, err := s.imageRepo.ScheduleDeletionByUserID(ctx, user.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = s.userRepo.Update(ctx, user.ID, bson.M{
+		"name":                    deletedUserName,
+		"email":                   fmt.Sprintf("deleted-%s@deleted.taiwanstay.invalid", user.ID),
+		"image":                   "",
+		"emailVerified":           nil,
+		"password":                "",
+		"status":                  domain.UserStatusDeleted,
+		"profile":                 domain.Profile{},
+		"privacySettings":         domain.DefaultPrivacySettings(),
+		"identities":              []domain.ProviderIdentity{},
+		"mfa":                     domain.MFASettings{},
+		"emailVerificationSentAt": nil,
+		"passwordResetSentAt":     nil,
+		"lockedUntil":             nil,
+		"deletedAt":               now,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Account deleted", "userId", user.ID, "imagesScheduled", scheduled)
+	return nil
+}
+
+// deactivateOwnedHost 由系統停用使用者建立且上架中的接待主。
+// 共同管理的接待主不受影響；未上架或已停權的接待主本來就沒有公開的工作機會。
+func (s *accountService) deactivateOwnedHost(ctx context.Context, userID string) error {
+	host, err := s.hostService.GetHostByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrHostNotFound) {
+			return nil
+		}
+		return err
+	}
+	if host.UserID.Hex() != userID || host.Status != domain.HostStatusActive {
+		return nil
+	}
+
+	if _, err := s.hostService.ChangeStatus(ctx, host.ID.Hex(), domain.HostStatusInactive, domain.ActorSystem, "", ownerDeletedNote); err != nil {
+		return err
+	}
+	logger.Info("Deactivated host of deleted account", "userId", userID, "hostId", host.ID)
+	return nil
+}
+
+// cancelOpenApplications 取消使用者尚未完成的申請
+func (s *accountService) cancelOpenApplications(ctx context.Context, userID string) error {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	apps, _, err := s.appRepo.List(ctx, bson.M{
+		"userId": userObjID,
+		"status": bson.M{"$in": []domain.ApplicationStatus{domain.ApplicationStatusDraft, domain.ApplicationStatusPending}},
+	}, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, app := range apps {
+		app.Status = domain.ApplicationStatusCancelled
+		app.StatusNote = accountDeletedNote
+		if err := s.appRepo.Update(ctx, app.ID.Hex(), app); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type accountTestDeps struct {
+	users     *mockUserRepository
+	apps      *MockApplicationRepository
+	bookmarks *MockBookmarkRepository
+	notifs    *MockNotificationRepository
+	images    *MockImageRepository
+	hosts     *MockHostRepository
+	opps      *MockOpportunityRepository
+	sessions  *MockSessionService
+}
+
+func newTestAccountService() (AccountService, *accountTestDeps) {
+	deps := &accountTestDeps{
+		users:     new(mockUserRepository),
+		apps:      new(MockApplicationRepository),
+		bookmarks: new(MockBookmarkRepository),
+		notifs:    new(MockNotificationRepository),
+		images:    new(MockImageRepository),
+		hosts:     new(MockHostRepository),
+		opps:      new(MockOpportunityRepository),
+		sessions:  new(MockSessionService),
+	}
+	hostService := NewHostService(deps.hosts, deps.opps, testHostConfig)
+	svc := NewAccountService(deps.users, deps.apps, deps.bookmarks, deps.notifs, deps.images, hostService, deps.sessions)
+	return svc, deps
+}
+
+func TestExportData(t *testing.T) {
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	svc, deps := newTestAccountService()
+
+	deps.users.On("GetByID", ctx, userID.Hex()).Return(&domain.User{ID: userID.Hex(), Email: "me@example.com", Password: "hashed"}, nil)
+	deps.apps.On("List", ctx, bson.M{"userId": userID}, int64(0), int64(0)).Return([]*domain.Application{{UserID: userID}}, int64(1), nil)
+	deps.bookmarks.On("ListByUserID", ctx, userID.Hex(), int64(0), int64(0)).Return([]*domain.Bookmark{{UserID: userID.Hex()}}, int64(1), nil)
+	deps.notifs.On("ListByUserID", ctx, userID.Hex(), int64(0), int64(0)).Return([]*domain.Notification{}, int64(0), nil)
+	deps.images.On("ListByUserID", ctx, userID.Hex()).Return([]*domain.Image{{UserID: userID}}, nil)
+
+	export, err := svc.ExportData(ctx, userID.Hex())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "me@example.com", export.User.Email)
+	assert.Empty(t, export.User.Password)
+	assert.Len(t, export.Applications, 1)
+	assert.Len(t, export.Bookmarks, 1)
+	assert.Len(t, export.Images, 1)
+}
+
+func TestDeleteAccount(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+
+	newUser := func() *domain.User {
+		return &domain.User{ID: userID.Hex(), Name: "Traveler", Email: "me@example.com", Password: string(hashed), Status: domain.UserStatusActive}
+	}
+
+	// expectErase 設定刪除帳號時清除關聯資料的呼叫
+	expectErase := func(deps *accountTestDeps) {
+		deps.sessions.On("RevokeAllUserSessions", ctx, userID.Hex(), "", SessionRevokedAccountDeleted).Return(int64(1), nil)
+		deps.bookmarks.On("DeleteByUserID", ctx, userID.Hex()).Return(int64(0), nil)
+		deps.notifs.On("DeleteByUserID", ctx, userID.Hex()).Return(int64(0), nil)
+		deps.apps.On("List", ctx, mock.Anything, int64(0), int64(0)).Return([]*domain.Application{}, int64(0), nil)
+		deps.apps.On("AnonymizeByUserID", ctx, userID.Hex()).Return(int64(0), nil)
+		deps.images.On("ScheduleDeletionByUserID", ctx, userID.Hex(), mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+		deps.users.On("Update", ctx, userID.Hex(), mock.Anything).Return(nil)
+	}
+
+	t.Run("Anonymizes User And Cascades", func(t *testing.T) {
+		svc, deps := newTestAccountService()
+		pending := &domain.Application{ID: primitive.NewObjectID(), UserID: userID, Status: domain.ApplicationStatusPending}
+
+		deps.users.On("GetByID", ctx, userID.Hex()).Return(newUser(), nil)
+		deps.hosts.On("GetByUserID", ctx, userID.Hex()).Return(nil, mongo.ErrNoDocuments)
+		deps.sessions.On("RevokeAllUserSessions", ctx, userID.Hex(), "", SessionRevokedAccountDeleted).Return(int64(2), nil)
+		deps.bookmarks.On("DeleteByUserID", ctx, userID.Hex()).Return(int64(3), nil)
+		deps.notifs.On("DeleteByUserID", ctx, userID.Hex()).Return(int64(4), nil)
+		deps.apps.On("List", ctx, mock.Anything, int64(0), int64(0)).Return([]*domain.Application{pending}, int64(1), nil)
+		deps.apps.On("Update", ctx, pending.ID.Hex(), mock.MatchedBy(func(app *domain.Application) bool {
+			return app.Status == domain.ApplicationStatusCancelled
+		})).Return(nil)
+		deps.apps.On("AnonymizeByUserID", ctx, userID.Hex()).Return(int64(1), nil)
+		deps.images.On("ScheduleDeletionByUserID", ctx, userID.Hex(), mock.AnythingOfType("time.Time")).Return(int64(2), nil)
+		deps.users.On("Update", ctx, userID.Hex(), mock.MatchedBy(func(payload bson.M) bool {
+			return payload["status"] == domain.UserStatusDeleted &&
+				payload["email"] == "deleted-"+userID.Hex()+"@deleted.taiwanstay.invalid" &&
+				payload["password"] == ""
+		})).Return(nil)
+
+		err := svc.DeleteAccount(ctx, userID.Hex(), "", "password123")
+
+		assert.NoError(t, err)
+		deps.sessions.AssertExpectations(t)
+		deps.bookmarks.AssertExpectations(t)
+		deps.notifs.AssertExpectations(t)
+		deps.apps.AssertExpectations(t)
+		deps.images.AssertExpectations(t)
+		deps.users.AssertExpectations(t)
+	})
+
+	t.Run("Wrong Password", func(t *testing.T) {
+		svc, deps := newTestAccountService()
+		deps.users.On("GetByID", ctx, userID.Hex()).Return(newUser(), nil)
+
+		err := svc.DeleteAccount(ctx, userID.Hex(), "", "wrong-password")
+
+		assert.ErrorIs(t, err, ErrIncorrectPassword)
+		deps.sessions.AssertNotCalled(t, "RevokeAllUserSessions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Deactivates Owned Host", func(t *testing.T) {
+		svc, deps := newTestAccountService()
+		host := &domain.Host{ID: primitive.NewObjectID(), UserID: userID, Status: domain.HostStatusActive, Verified: true}
+
+		deps.users.On("GetByID", ctx, userID.Hex()).Return(newUser(), nil)
+		deps.hosts.On("GetByUserID", ctx, userID.Hex()).Return(host, nil)
+		deps.hosts.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+		deps.hosts.On("UpdateStatus", ctx, host.ID.Hex(), host.Status, mock.MatchedBy(func(e domain.HostStatusHistory) bool {
+			return e.Status == domain.HostStatusInactive && e.StatusNote == ownerDeletedNote
+		}), bson.M{}).Return(nil)
+		deps.opps.On("UpdateStatusByHostID", ctx, host.ID, []domain.OpportunityStatus{domain.OpportunityStatusActive, domain.OpportunityStatusFilled}, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+			return e.Status == domain.OpportunityStatusPaused
+		})).Return(int64(3), nil)
+		expectErase(deps)
+
+		err := svc.DeleteAccount(ctx, userID.Hex(), "", "password123")
+
+		assert.NoError(t, err)
+		deps.hosts.AssertExpectations(t)
+		deps.opps.AssertExpectations(t)
+	})
+
+	t.Run("Leaves Co-Hosted Host Untouched", func(t *testing.T) {
+		svc, deps := newTestAccountService()
+		host := &domain.Host{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Status: domain.HostStatusActive}
+
+		deps.users.On("GetByID", ctx, userID.Hex()).Return(newUser(), nil)
+		deps.hosts.On("GetByUserID", ctx, userID.Hex()).Return(host, nil)
+		expectErase(deps)
+
+		err := svc.DeleteAccount(ctx, userID.Hex(), "", "password123")
+
+		assert.NoError(t, err)
+		deps.hosts.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Password-less Account Requires Recent Login", func(t *testing.T) {
+		svc, deps := newTestAccountService()
+		oauthUser := newUser()
+		oauthUser.Password = ""
+		sessionID := primitive.NewObjectID().Hex()
+
+		deps.users.On("GetByID", ctx, userID.Hex()).Return(oauthUser, nil)
+		deps.sessions.On("GetUserSession", ctx, userID.Hex(), sessionID).Return(&domain.Session{UserID: userID, CreatedAt: time.Now().Add(-time.Hour)}, nil)
+
+		err := svc.DeleteAccount(ctx, userID.Hex(), sessionID, "")
+
+		assert.ErrorIs(t, err, ErrRecentLoginRequired)
+		deps.sessions.AssertNotCalled(t, "RevokeAllUserSessions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Password-less Account Rejects Unknown Session", func(t *testing.T) {
+		svc, deps := newTestAccountService()
+		oauthUser := newUser()
+		oauthUser.Password = ""
+
+		deps.users.On("GetByID", ctx, userID.Hex()).Return(oauthUser, nil)
+		deps.sessions.On("GetUserSession", ctx, userID.Hex(), "").Return(nil, ErrSessionNotFound)
+
+		err := svc.DeleteAccount(ctx, userID.Hex(), "", "")
+
+		assert.ErrorIs(t, err, ErrRecentLoginRequired)
+	})
+
+	t.Run("Password-less Account After Fresh Login", func(t *testing.T) {
+		svc, deps := newTestAccountService()
+		oauthUser := newUser()
+		oauthUser.Password = ""
+		sessionID := primitive.NewObjectID().Hex()
+
+		deps.users.On("GetByID", ctx, userID.Hex()).Return(oauthUser, nil)
+		deps.sessions.On("GetUserSession", ctx, userID.Hex(), sessionID).Return(&domain.Session{UserID: userID, CreatedAt: time.Now().Add(-time.Minute)}, nil)
+		deps.hosts.On("GetByUserID", ctx, userID.Hex()).Return(nil, mongo.ErrNoDocuments)
+		expectErase(deps)
+
+		err := svc.DeleteAccount(ctx, userID.Hex(), sessionID, "")
+
+		assert.NoError(t, err)
+		deps.users.AssertExpectations(t)
+	})
+
+	t.Run("Already Deleted", func(t *testing.T) {
+		svc, deps := newTestAccountService()
+		deleted := newUser()
+		deleted.Status = domain.UserStatusDeleted
+		deps.users.On("GetByID", ctx, userID.Hex()).Return(deleted, nil)
+
+		err := svc.EraseUser(ctx, userID.Hex())
+		assert.ErrorIs(t, err, ErrAccountDeleted)
+	})
+}
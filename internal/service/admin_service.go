@@ -6,30 +6,42 @@ import (
 
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+var ErrInvalidUserStatus = errcode.BadRequest("INVALID_USER_STATUS", "status must be ACTIVE or SUSPENDED")
+
 type AdminService interface {
 	GetSystemStats(ctx context.Context) (map[string]int64, error)
 	ListPendingImages(ctx context.Context, limit, offset int64) ([]*domain.Image, int64, error)
 	ReviewImage(ctx context.Context, imageID string, approved bool) error
-	ListUsers(ctx context.Context, role domain.UserRole, limit, offset int64) ([]*domain.User, int64, error)
+	ListUsers(ctx context.Context, role domain.UserRole, lockedOnly bool, limit, offset int64) ([]*domain.User, int64, error)
 	UpdateUserStatus(ctx context.Context, userID string, status domain.UserStatus) error
+	UnlockUser(ctx context.Context, userID string) error
+	ExportUserData(ctx context.Context, userID string) (*domain.DataExport, error)
+	DeleteUser(ctx context.Context, userID string) error
 }
 
 type adminService struct {
-	userRepo     repository.UserRepository
-	imageRepo    repository.ImageRepository
-	appRepo      repository.ApplicationRepository
-	imageService ImageService
+	userRepo        repository.UserRepository
+	imageRepo       repository.ImageRepository
+	appRepo         repository.ApplicationRepository
+	imageService    ImageService
+	sessionService  SessionService
+	loginProtection LoginProtectionService
+	accountService  AccountService
 }
 
-func NewAdminService(userRepo repository.UserRepository, imageRepo repository.ImageRepository, appRepo repository.ApplicationRepository, imageService ImageService) AdminService {
+func NewAdminService(userRepo repository.UserRepository, imageRepo repository.ImageRepository, appRepo repository.ApplicationRepository, imageService ImageService, sessionService SessionService, loginProtection LoginProtectionService, accountService AccountService) AdminService {
 	return &adminService{
-		userRepo:     userRepo,
-		imageRepo:    imageRepo,
-		appRepo:      appRepo,
-		imageService: imageService,
+		userRepo:        userRepo,
+		imageRepo:       imageRepo,
+		appRepo:         appRepo,
+		imageService:    imageService,
+		sessionService:  sessionService,
+		loginProtection: loginProtection,
+		accountService:  accountService,
 	}
 }
 
@@ -72,14 +84,46 @@ func (s *adminService) ReviewImage(ctx context.Context, imageID string, approved
 	return s.imageService.UpdateImageStatus(ctx, imageID, status)
 }
 
-func (s *adminService) ListUsers(ctx context.Context, role domain.UserRole, limit, offset int64) ([]*domain.User, int64, error) {
+func (s *adminService) ListUsers(ctx context.Context, role domain.UserRole, lockedOnly bool, limit, offset int64) ([]*domain.User, int64, error) {
 	filter := bson.M{}
 	if role != "" {
 		filter["role"] = role
 	}
+	if lockedOnly {
+		filter["lockedUntil"] = bson.M{"$gt": time.Now()}
+	}
 	return s.userRepo.List(ctx, filter, limit, offset)
 }
 
 func (s *adminService) UpdateUserStatus(ctx context.Context, userID string, status domain.UserStatus) error {
-	return s.userRepo.UpdateStatus(ctx, userID, status)
+	// Deletion must go through DeleteUser so related data is anonymized too
+	if status != domain.UserStatusActive && status != domain.UserStatusSuspended {
+		return ErrInvalidUserStatus
+	}
+	if err := s.userRepo.UpdateStatus(ctx, userID, status); err != nil {
+		return notFound(err, ErrUserNotFound)
+	}
+
+	// Force logout on every device when suspended
+	if status == domain.UserStatusSuspended {
+		if _, err := s.sessionService.RevokeAllUserSessions(ctx, userID, "", SessionRevokedByAdmin); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnlockUser clears a login lockout and the failed-attempt counter
+func (s *adminService) UnlockUser(ctx context.Context, userID string) error {
+	return notFound(s.loginProtection.Unlock(ctx, userID), ErrUserNotFound)
+}
+
+// ExportUserData returns the same personal data bundle the user can download themselves
+func (s *adminService) ExportUserData(ctx context.Context, userID string) (*domain.DataExport, error) {
+	return s.accountService.ExportData(ctx, userID)
+}
+
+// DeleteUser anonymizes the account and its related data
+func (s *adminService) DeleteUser(ctx context.Context, userID string) error {
+	return s.accountService.EraseUser(ctx, userID)
 }
@@ -5,11 +5,13 @@ import (
 	"io"
 	"mime/multipart"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // MockImageRepository
@@ -45,6 +47,26 @@ func (m *MockImageRepository) ListByStatus(ctx context.Context, status domain.Im
 	return args.Get(0).([]*domain.Image), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockImageRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.Image, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*domain.Image), args.Error(1)
+}
+
+func (m *MockImageRepository) ScheduleDeletionByUserID(ctx context.Context, userID string, deleteAfter time.Time) (int64, error) {
+	args := m.Called(ctx, userID, deleteAfter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockImageRepository) ListDueForDeletion(ctx context.Context, now time.Time, limit int64) ([]*domain.Image, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]*domain.Image), args.Error(1)
+}
+
+func (m *MockImageRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) UpdateStatus(ctx context.Context, id string, status domain.UserStatus) error {
 	args := m.Called(ctx, id, status)
 	return args.Error(0)
@@ -60,6 +82,14 @@ func (m *MockImageService) UploadImage(ctx context.Context, file multipart.File,
 	return nil, nil
 }
 
+func (m *MockImageService) UploadPrivateImage(ctx context.Context, file multipart.File, header *multipart.FileHeader, userID string) (*domain.Image, error) {
+	args := m.Called(ctx, file, header, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Image), args.Error(1)
+}
+
 func (m *MockImageService) GetImage(ctx context.Context, id string) (*domain.Image, error) {
 	return nil, nil
 }
@@ -73,6 +103,11 @@ func (m *MockImageService) GetImageContent(ctx context.Context, id string) (io.R
 	return nil, nil
 }
 
+func (m *MockImageService) PurgeScheduledImages(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
 func TestGetSystemStats(t *testing.T) {
 	mockUserRepo := new(MockUserRepository) // Reusing from notification_service_test.go if in same package, but we are in same package 'service' so it should be available?
 	// Wait, MockUserRepository is defined in notification_service_test.go which is in package service_test or service?
@@ -81,7 +116,7 @@ func TestGetSystemStats(t *testing.T) {
 	mockAppRepo := new(MockApplicationRepository)
 	mockImageService := new(MockImageService)
 
-	adminService := NewAdminService(mockUserRepo, mockImageRepo, mockAppRepo, mockImageService)
+	adminService := NewAdminService(mockUserRepo, mockImageRepo, mockAppRepo, mockImageService, new(MockSessionService), new(MockLoginProtectionService), nil)
 
 	ctx := context.Background()
 
@@ -103,7 +138,7 @@ func TestReviewImage(t *testing.T) {
 	mockAppRepo := new(MockApplicationRepository)
 	mockImageService := new(MockImageService)
 
-	adminService := NewAdminService(mockUserRepo, mockImageRepo, mockAppRepo, mockImageService)
+	adminService := NewAdminService(mockUserRepo, mockImageRepo, mockAppRepo, mockImageService, new(MockSessionService), new(MockLoginProtectionService), nil)
 
 	ctx := context.Background()
 	imageID := "img123"
@@ -125,21 +160,21 @@ func TestListUsers(t *testing.T) {
 	mockAppRepo := new(MockApplicationRepository)
 	mockImageService := new(MockImageService)
 
-	adminService := NewAdminService(mockUserRepo, mockImageRepo, mockAppRepo, mockImageService)
+	adminService := NewAdminService(mockUserRepo, mockImageRepo, mockAppRepo, mockImageService, new(MockSessionService), new(MockLoginProtectionService), nil)
 
 	ctx := context.Background()
 	expectedUsers := []*domain.User{{Name: "Test"}}
 
 	// Filter by Role
 	mockUserRepo.On("List", ctx, bson.M{"role": domain.RoleHost}, int64(10), int64(0)).Return(expectedUsers, int64(1), nil)
-	users, total, err := adminService.ListUsers(ctx, domain.RoleHost, 10, 0)
+	users, total, err := adminService.ListUsers(ctx, domain.RoleHost, false, 10, 0)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(1), total)
 	assert.Equal(t, expectedUsers, users)
 
 	// No Filter
 	mockUserRepo.On("List", ctx, bson.M{}, int64(10), int64(0)).Return(expectedUsers, int64(1), nil)
-	users, total, err = adminService.ListUsers(ctx, "", 10, 0)
+	users, total, err = adminService.ListUsers(ctx, "", false, 10, 0)
 	assert.NoError(t, err)
 }
 
@@ -148,16 +183,58 @@ func TestUpdateUserStatus(t *testing.T) {
 	mockImageRepo := new(MockImageRepository)
 	mockAppRepo := new(MockApplicationRepository)
 	mockImageService := new(MockImageService)
+	mockSessionService := new(MockSessionService)
 
-	adminService := NewAdminService(mockUserRepo, mockImageRepo, mockAppRepo, mockImageService)
+	adminService := NewAdminService(mockUserRepo, mockImageRepo, mockAppRepo, mockImageService, mockSessionService, new(MockLoginProtectionService), nil)
 
 	ctx := context.Background()
 	userID := "user123"
-	status := domain.UserStatusSuspended
 
-	mockUserRepo.On("UpdateStatus", ctx, userID, status).Return(nil)
+	// Suspending revokes all sessions
+	mockUserRepo.On("UpdateStatus", ctx, userID, domain.UserStatusSuspended).Return(nil)
+	mockSessionService.On("RevokeAllUserSessions", ctx, userID, "", SessionRevokedByAdmin).Return(int64(2), nil)
 
-	err := adminService.UpdateUserStatus(ctx, userID, status)
+	err := adminService.UpdateUserStatus(ctx, userID, domain.UserStatusSuspended)
 	assert.NoError(t, err)
 	mockUserRepo.AssertExpectations(t)
+	mockSessionService.AssertExpectations(t)
+
+	// Reactivating leaves sessions alone
+	mockUserRepo.On("UpdateStatus", ctx, userID, domain.UserStatusActive).Return(nil)
+
+	err = adminService.UpdateUserStatus(ctx, userID, domain.UserStatusActive)
+	assert.NoError(t, err)
+	mockSessionService.AssertNumberOfCalls(t, "RevokeAllUserSessions", 1)
+
+	// Deletion must go through DeleteUser
+	err = adminService.UpdateUserStatus(ctx, userID, domain.UserStatusDeleted)
+	assert.ErrorIs(t, err, ErrInvalidUserStatus)
+}
+
+func TestListLockedUsers(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	adminService := NewAdminService(mockUserRepo, new(MockImageRepository), new(MockApplicationRepository), new(MockImageService), new(MockSessionService), new(MockLoginProtectionService), nil)
+
+	ctx := context.Background()
+	lockedFilter := mock.MatchedBy(func(filter bson.M) bool {
+		cond, ok := filter["lockedUntil"].(bson.M)
+		return ok && cond["$gt"] != nil
+	})
+	mockUserRepo.On("List", ctx, lockedFilter, int64(10), int64(0)).Return([]*domain.User{}, int64(0), nil)
+
+	_, _, err := adminService.ListUsers(ctx, "", true, 10, 0)
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUnlockUser(t *testing.T) {
+	mockLoginProtection := new(MockLoginProtectionService)
+	adminService := NewAdminService(new(MockUserRepository), new(MockImageRepository), new(MockApplicationRepository), new(MockImageService), new(MockSessionService), mockLoginProtection, nil)
+
+	ctx := context.Background()
+	mockLoginProtection.On("Unlock", ctx, "user123").Return(nil)
+
+	err := adminService.UnlockUser(ctx, "user123")
+	assert.NoError(t, err)
+	mockLoginProtection.AssertExpectations(t)
 }
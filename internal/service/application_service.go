@@ -3,16 +3,43 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+var (
+	ErrProfileIncomplete       = errcode.Unprocessable("PROFILE_INCOMPLETE", "profile is missing fields required by this opportunity")
+	ErrDatesUnavailable        = errcode.Unprocessable("DATES_UNAVAILABLE", "selected dates are not available in any open time slot")
+	ErrOpportunityNotOpen      = errcode.Conflict("OPPORTUNITY_NOT_OPEN", "this opportunity is not accepting applications")
+	ErrApplicationNotDeletable = errcode.Conflict("APPLICATION_NOT_DELETABLE", "cannot delete application that is not draft or pending")
+	ErrNotApplicationOwner     = errcode.Forbidden("NOT_APPLICATION_OWNER", "unauthorized to delete this application")
+	ErrNotApplicationReviewer  = errcode.Forbidden("NOT_APPLICATION_REVIEWER", "you cannot review applications for this host")
+)
+
+// ProfileIncompleteError 表示申請者的個人檔案缺少機會要求的欄位
+type ProfileIncompleteError struct {
+	Missing []domain.ProfileField
+}
+
+func (e *ProfileIncompleteError) Error() string {
+	return fmt.Sprintf("profile is missing required fields: %v", e.Missing)
+}
+
+// Unwrap 回傳附上缺少欄位的 ErrProfileIncomplete，讓 API 回應帶有 missing 清單
+func (e *ProfileIncompleteError) Unwrap() error {
+	return ErrProfileIncomplete.WithMeta("missing", e.Missing)
+}
+
 type ApplicationService interface {
 	CreateApplication(ctx context.Context, app *domain.Application) (*domain.Application, error)
-	GetApplicationByID(ctx context.Context, id string) (*domain.Application, error)
-	ListApplications(ctx context.Context, filter bson.M, limit, offset int64) ([]*domain.Application, int64, error)
+	GetApplicationByID(ctx context.Context, id, userID string) (*domain.Application, error)
+	ListApplications(ctx context.Context, userID string, filter bson.M, limit, offset int64) ([]*domain.Application, int64, error)
 	UpdateApplication(ctx context.Context, id string, app *domain.Application) error
 	UpdateApplicationStatus(ctx context.Context, id string, status domain.ApplicationStatus, note string, userID string) error
 	DeleteApplication(ctx context.Context, id string, userID string) error
@@ -22,14 +49,18 @@ type applicationService struct {
 	repo         repository.ApplicationRepository
 	oppRepo      repository.OpportunityRepository
 	hostRepo     repository.HostRepository
+	userRepo     repository.UserRepository
+	orgRepo      repository.OrganizationRepository
 	notifService NotificationService
 }
 
-func NewApplicationService(repo repository.ApplicationRepository, oppRepo repository.OpportunityRepository, hostRepo repository.HostRepository, notifService NotificationService) ApplicationService {
+func NewApplicationService(repo repository.ApplicationRepository, oppRepo repository.OpportunityRepository, hostRepo repository.HostRepository, userRepo repository.UserRepository, orgRepo repository.OrganizationRepository, notifService NotificationService) ApplicationService {
 	return &applicationService{
 		repo:         repo,
 		oppRepo:      oppRepo,
 		hostRepo:     hostRepo,
+		userRepo:     userRepo,
+		orgRepo:      orgRepo,
 		notifService: notifService,
 	}
 }
@@ -38,7 +69,11 @@ func (s *applicationService) CreateApplication(ctx context.Context, app *domain.
 	// 1. Check Opportunity existence
 	opp, err := s.oppRepo.GetByID(ctx, app.OpportunityID.Hex())
 	if err != nil {
-		return nil, errors.New("opportunity not found")
+		return nil, notFound(err, ErrOpportunityNotFound)
+	}
+	// 只有上架中的工作機會接受申請 (草稿、審核中、暫停、額滿、過期或已刪除都不行)
+	if opp.Status != domain.OpportunityStatusActive {
+		return nil, ErrOpportunityNotOpen.WithMeta("status", string(opp.Status))
 	}
 
 	// 2. Validate TimeSlot (if applicable)
@@ -56,20 +91,31 @@ func (s *applicationService) CreateApplication(ctx context.Context, app *domain.
 			}
 		}
 		if !valid {
-			return nil, errors.New("selected dates are not available in any open time slot")
+			return nil, ErrDatesUnavailable
+		}
+	}
+
+	// 3. Check the applicant's profile has the fields this opportunity requires
+	if len(opp.Requirements.RequiredProfileFields) > 0 {
+		applicant, err := s.userRepo.GetByID(ctx, app.UserID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		if missing := applicant.Profile.MissingFields(opp.Requirements.RequiredProfileFields); len(missing) > 0 {
+			return nil, &ProfileIncompleteError{Missing: missing}
 		}
 	}
 
-	// 3. Set HostID from Opportunity
+	// 4. Set HostID from Opportunity
 	app.HostID = opp.HostID
 	app.Status = domain.ApplicationStatusPending // Default to Pending
 
-	// 4. Save
+	// 5. Save
 	if err := s.repo.Create(ctx, app); err != nil {
 		return nil, err
 	}
 
-	// 5. Notify Host
+	// 6. Notify Host
 	go func() {
 		// Use background context to prevent cancellation if request finishes
 		bgCtx := context.Background()
@@ -85,9 +131,7 @@ func (s *applicationService) CreateApplication(ctx context.Context, app *domain.
 			bgCtx,
 			host.UserID.Hex(),
 			domain.NotificationTypeApplicationCreated,
-			"New Application Received",
-			"You have a new application for "+opp.Title,
-			map[string]string{"applicationId": app.ID.Hex()},
+			map[string]string{"applicationId": app.ID.Hex(), "opportunityTitle": opp.Title},
 		)
 		if err != nil {
 			// Log error
@@ -97,12 +141,99 @@ func (s *applicationService) CreateApplication(ctx context.Context, app *domain.
 	return app, nil
 }
 
-func (s *applicationService) GetApplicationByID(ctx context.Context, id string) (*domain.Application, error) {
-	return s.repo.GetByID(ctx, id)
+// GetApplicationByID 回傳申請，只有申請者本人與可審核該接待主申請的成員能讀取，
+// 其他人一律視為不存在，避免透過回應差異得知申請是否存在
+func (s *applicationService) GetApplicationByID(ctx context.Context, id, userID string) (*domain.Application, error) {
+	app, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrApplicationNotFound)
+	}
+	if app.UserID.Hex() == userID {
+		return app, nil
+	}
+
+	host, err := s.hostRepo.GetByID(ctx, app.HostID.Hex())
+	if err != nil {
+		return nil, notFound(err, ErrApplicationNotFound)
+	}
+	ok, err := canManageHost(ctx, s.orgRepo, userID, host, domain.MemberPermissionReviewApplications)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrApplicationNotFound
+	}
+	return app, nil
+}
+
+// ListApplications 依 filter 列出申請，結果限於使用者自己送出的申請，
+// 以及使用者可審核的接待主 (含所屬組織旗下的接待主) 收到的申請
+func (s *applicationService) ListApplications(ctx context.Context, userID string, filter bson.M, limit, offset int64) ([]*domain.Application, int64, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, 0, errcode.ErrUnauthorized
+	}
+	hostIDs, err := s.reviewableHostIDs(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := bson.M{}
+	for k, v := range filter {
+		query[k] = v
+	}
+	visible := bson.A{bson.M{"userId": userObjID}}
+	if len(hostIDs) > 0 {
+		visible = append(visible, bson.M{"hostId": bson.M{"$in": hostIDs}})
+	}
+	query["$or"] = visible
+	return s.repo.List(ctx, query, limit, offset)
 }
 
-func (s *applicationService) ListApplications(ctx context.Context, filter bson.M, limit, offset int64) ([]*domain.Application, int64, error) {
-	return s.repo.List(ctx, filter, limit, offset)
+// reviewableHostIDs 回傳使用者可審核申請的接待主：
+// 自己建立或共同管理的接待主，以及所屬組織旗下的接待主 (需組織角色具審核權限)
+func (s *applicationService) reviewableHostIDs(ctx context.Context, userID string) ([]primitive.ObjectID, error) {
+	var ids []primitive.ObjectID
+
+	host, err := s.hostRepo.GetByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	if host != nil && host.HasPermission(userID, domain.MemberPermissionReviewApplications) {
+		ids = append(ids, host.ID)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ids, nil
+		}
+		return nil, err
+	}
+	if user.OrganizationID == "" {
+		return ids, nil
+	}
+	org, err := s.orgRepo.GetByID(ctx, user.OrganizationID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ids, nil
+		}
+		return nil, err
+	}
+	member := org.Member(userID)
+	if member == nil || !member.Role.HasPermission(domain.MemberPermissionReviewApplications) {
+		return ids, nil
+	}
+	orgHosts, err := s.hostRepo.ListByOrganizationID(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range orgHosts {
+		if host == nil || h.ID != host.ID {
+			ids = append(ids, h.ID)
+		}
+	}
+	return ids, nil
 }
 
 func (s *applicationService) UpdateApplication(ctx context.Context, id string, app *domain.Application) error {
@@ -111,12 +242,23 @@ func (s *applicationService) UpdateApplication(ctx context.Context, id string, a
 
 func (s *applicationService) UpdateApplicationStatus(ctx context.Context, id string, status domain.ApplicationStatus, note string, userID string) error {
 	app, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return notFound(err, ErrApplicationNotFound)
+	}
+
+	// 接待主的 OWNER / REVIEWER 或具審核權限的組織成員才能變更申請狀態
+	host, err := s.hostRepo.GetByID(ctx, app.HostID.Hex())
+	if err != nil {
+		return notFound(err, ErrHostNotFound)
+	}
+	ok, err := canManageHost(ctx, s.orgRepo, userID, host, domain.MemberPermissionReviewApplications)
 	if err != nil {
 		return err
 	}
+	if !ok {
+		return ErrNotApplicationReviewer
+	}
 
-	// Verify ownership (Host) - This logic might be better in Handler or Middleware, but service check is safe
-	// Here we just update the status
 	app.Status = status
 	app.StatusNote = note
 
@@ -129,17 +271,17 @@ func (s *applicationService) UpdateApplicationStatus(ctx context.Context, id str
 func (s *applicationService) DeleteApplication(ctx context.Context, id string, userID string) error {
 	app, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return notFound(err, ErrApplicationNotFound)
 	}
 
 	// Only allow deleting if status is DRAFT or PENDING
 	if app.Status != domain.ApplicationStatusDraft && app.Status != domain.ApplicationStatusPending {
-		return errors.New("cannot delete application that is not draft or pending")
+		return ErrApplicationNotDeletable
 	}
 
 	// Verify ownership
 	if app.UserID.Hex() != userID {
-		return errors.New("unauthorized to delete this application")
+		return ErrNotApplicationOwner
 	}
 
 	return s.repo.Delete(ctx, id)
@@ -52,6 +52,16 @@ func (m *MockApplicationRepository) CountByDate(ctx context.Context, date time.T
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockApplicationRepository) AnonymizeByUserID(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockApplicationRepository) CountByHostAndStatus(ctx context.Context, hostIDs []primitive.ObjectID) (map[primitive.ObjectID]map[domain.ApplicationStatus]int64, error) {
+	args := m.Called(ctx, hostIDs)
+	return args.Get(0).(map[primitive.ObjectID]map[domain.ApplicationStatus]int64), args.Error(1)
+}
+
 type MockOpportunityRepository struct {
 	mock.Mock
 }
@@ -74,16 +84,60 @@ func (m *MockOpportunityRepository) List(ctx context.Context, filter bson.M, lim
 	return args.Get(0).([]*domain.Opportunity), args.Error(1)
 }
 
-func (m *MockOpportunityRepository) Update(ctx context.Context, id string, opp *domain.Opportunity) error {
-	args := m.Called(ctx, id, opp)
+func (m *MockOpportunityRepository) Update(ctx context.Context, id string, from domain.OpportunityStatus, opp *domain.Opportunity) error {
+	args := m.Called(ctx, id, from, opp)
 	return args.Error(0)
 }
 
-func (m *MockOpportunityRepository) Delete(ctx context.Context, id string) error {
-	args := m.Called(ctx, id)
+func (m *MockOpportunityRepository) UpdateStatus(ctx context.Context, id string, from domain.OpportunityStatus, entry domain.OpportunityStatusHistory, fields bson.M) error {
+	args := m.Called(ctx, id, from, entry, fields)
+	return args.Error(0)
+}
+
+func (m *MockOpportunityRepository) ListExpiring(ctx context.Context, cutoff time.Time, cutoffDate string) ([]*domain.Opportunity, error) {
+	args := m.Called(ctx, cutoff, cutoffDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Opportunity), args.Error(1)
+}
+
+func (m *MockOpportunityRepository) ListFullyBooked(ctx context.Context, today string) ([]*domain.Opportunity, error) {
+	args := m.Called(ctx, today)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Opportunity), args.Error(1)
+}
+
+func (m *MockOpportunityRepository) CloseEndedTimeSlots(ctx context.Context, today string) (int64, error) {
+	args := m.Called(ctx, today)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOpportunityRepository) MarkExpiryNotified(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockOpportunityRepository) MarkEditsReviewed(ctx context.Context, id string, editedAt, reviewedAt time.Time) error {
+	args := m.Called(ctx, id, editedAt, reviewedAt)
 	return args.Error(0)
 }
 
+func (m *MockOpportunityRepository) ListPendingReview(ctx context.Context, filter repository.OpportunityReviewFilter) ([]*domain.Opportunity, int64, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*domain.Opportunity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockOpportunityRepository) UpdateStatusByHostID(ctx context.Context, hostID primitive.ObjectID, from []domain.OpportunityStatus, entry domain.OpportunityStatusHistory) (int64, error) {
+	args := m.Called(ctx, hostID, from, entry)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockOpportunityRepository) Search(ctx context.Context, filter repository.OpportunityFilter) ([]*domain.Opportunity, int64, error) {
 	args := m.Called(ctx, filter)
 	return args.Get(0).([]*domain.Opportunity), args.Get(1).(int64), args.Error(2)
@@ -93,8 +147,8 @@ type MockNotificationService struct {
 	mock.Mock
 }
 
-func (m *MockNotificationService) SendNotification(ctx context.Context, userID string, notifType domain.NotificationType, title, message string, data map[string]string) error {
-	args := m.Called(ctx, userID, notifType, title, message, data)
+func (m *MockNotificationService) SendNotification(ctx context.Context, userID string, notifType domain.NotificationType, data map[string]string) error {
+	args := m.Called(ctx, userID, notifType, data)
 	return args.Error(0)
 }
 
@@ -119,7 +173,7 @@ func TestCreateApplication_Success(t *testing.T) {
 	mockOppRepo := new(MockOpportunityRepository)
 	mockHostRepo := new(MockHostRepository)
 	mockNotifService := new(MockNotificationService)
-	service := NewApplicationService(mockAppRepo, mockOppRepo, mockHostRepo, mockNotifService)
+	service := NewApplicationService(mockAppRepo, mockOppRepo, mockHostRepo, new(MockUserRepository), nil, mockNotifService)
 
 	ctx := context.Background()
 	oppID := primitive.NewObjectID()
@@ -130,6 +184,7 @@ func TestCreateApplication_Success(t *testing.T) {
 	opp := &domain.Opportunity{
 		ID:           oppID,
 		HostID:       hostID,
+		Status:       domain.OpportunityStatusActive,
 		HasTimeSlots: true,
 		TimeSlots: []domain.TimeSlot{
 			{
@@ -158,7 +213,7 @@ func TestCreateApplication_Success(t *testing.T) {
 	mockAppRepo.On("Create", ctx, app).Return(nil)
 	// Expect GetByID to be called with background context
 	mockHostRepo.On("GetByID", mock.Anything, hostID.Hex()).Return(host, nil)
-	mockNotifService.On("SendNotification", mock.Anything, userID.Hex(), mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockNotifService.On("SendNotification", mock.Anything, userID.Hex(), mock.Anything, mock.Anything).Return(nil)
 
 	createdApp, err := service.CreateApplication(ctx, app)
 
@@ -181,7 +236,7 @@ func TestCreateApplication_InvalidDates(t *testing.T) {
 	mockOppRepo := new(MockOpportunityRepository)
 	mockHostRepo := new(MockHostRepository)
 	mockNotifService := new(MockNotificationService)
-	service := NewApplicationService(mockAppRepo, mockOppRepo, mockHostRepo, mockNotifService)
+	service := NewApplicationService(mockAppRepo, mockOppRepo, mockHostRepo, new(MockUserRepository), nil, mockNotifService)
 
 	ctx := context.Background()
 	oppID := primitive.NewObjectID()
@@ -189,6 +244,7 @@ func TestCreateApplication_InvalidDates(t *testing.T) {
 	// Opportunity with time slot NOT covering application dates
 	opp := &domain.Opportunity{
 		ID:           oppID,
+		Status:       domain.OpportunityStatusActive,
 		HasTimeSlots: true,
 		TimeSlots: []domain.TimeSlot{
 			{
@@ -216,3 +272,222 @@ func TestCreateApplication_InvalidDates(t *testing.T) {
 	assert.Contains(t, err.Error(), "selected dates are not available")
 	mockOppRepo.AssertExpectations(t)
 }
+
+func TestCreateApplication_ProfileIncomplete(t *testing.T) {
+	mockAppRepo := new(MockApplicationRepository)
+	mockOppRepo := new(MockOpportunityRepository)
+	mockUserRepo := new(MockUserRepository)
+	service := NewApplicationService(mockAppRepo, mockOppRepo, new(MockHostRepository), mockUserRepo, nil, new(MockNotificationService))
+
+	ctx := context.Background()
+	oppID := primitive.NewObjectID()
+	userID := primitive.NewObjectID()
+
+	opp := &domain.Opportunity{
+		ID:     oppID,
+		Status: domain.OpportunityStatusActive,
+		Requirements: domain.Requirements{
+			RequiredProfileFields: []domain.ProfileField{domain.ProfileFieldEmergencyContact, domain.ProfileFieldSkills},
+		},
+	}
+	// 註冊時的佔位值視為未填寫
+	user := &domain.User{
+		ID: userID.Hex(),
+		Profile: domain.Profile{
+			EmergencyContact: domain.EmergencyContact{Name: "N/A", Relationship: "N/A", Phone: "N/A"},
+			Skills:           []string{"cooking"},
+		},
+	}
+
+	app := &domain.Application{
+		UserID:        userID,
+		OpportunityID: oppID,
+		ApplicationDetails: domain.ApplicationDetails{
+			StartDate: "2023-01-05",
+			EndDate:   "2023-01-10",
+		},
+	}
+
+	mockOppRepo.On("GetByID", ctx, oppID.Hex()).Return(opp, nil)
+	mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(user, nil)
+
+	createdApp, err := service.CreateApplication(ctx, app)
+
+	assert.Nil(t, createdApp)
+	assert.ErrorIs(t, err, ErrProfileIncomplete)
+	var incomplete *ProfileIncompleteError
+	assert.ErrorAs(t, err, &incomplete)
+	assert.Equal(t, []domain.ProfileField{domain.ProfileFieldEmergencyContact}, incomplete.Missing)
+	mockAppRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateApplication_OpportunityNotOpen(t *testing.T) {
+	ctx := context.Background()
+	statuses := []domain.OpportunityStatus{
+		domain.OpportunityStatusDraft,
+		domain.OpportunityStatusPending,
+		domain.OpportunityStatusPaused,
+		domain.OpportunityStatusAdminPaused,
+		domain.OpportunityStatusExpired,
+		domain.OpportunityStatusFilled,
+		domain.OpportunityStatusDeleted,
+	}
+	for _, status := range statuses {
+		t.Run(string(status), func(t *testing.T) {
+			mockAppRepo := new(MockApplicationRepository)
+			mockOppRepo := new(MockOpportunityRepository)
+			service := NewApplicationService(mockAppRepo, mockOppRepo, new(MockHostRepository), new(MockUserRepository), nil, new(MockNotificationService))
+
+			opp := &domain.Opportunity{ID: primitive.NewObjectID(), HostID: primitive.NewObjectID(), Status: status}
+			mockOppRepo.On("GetByID", ctx, opp.ID.Hex()).Return(opp, nil)
+
+			_, err := service.CreateApplication(ctx, &domain.Application{OpportunityID: opp.ID, UserID: primitive.NewObjectID()})
+
+			assert.ErrorIs(t, err, ErrOpportunityNotOpen)
+			mockAppRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdateApplicationStatus_RequiresReviewer(t *testing.T) {
+	ctx := context.Background()
+	ownerID := primitive.NewObjectID()
+	editorID := primitive.NewObjectID()
+	reviewerID := primitive.NewObjectID()
+	host := &domain.Host{ID: primitive.NewObjectID(), UserID: ownerID, Members: []domain.HostMember{
+		{UserID: editorID, Role: domain.HostMemberRoleEditor},
+		{UserID: reviewerID, Role: domain.HostMemberRoleReviewer},
+	}}
+
+	tests := []struct {
+		name    string
+		userID  primitive.ObjectID
+		wantErr error
+	}{
+		{"Owner", ownerID, nil},
+		{"Co-host Reviewer", reviewerID, nil},
+		{"Co-host Editor", editorID, ErrNotApplicationReviewer},
+		{"Outsider", primitive.NewObjectID(), ErrNotApplicationReviewer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAppRepo := new(MockApplicationRepository)
+			mockHostRepo := new(MockHostRepository)
+			service := NewApplicationService(mockAppRepo, nil, mockHostRepo, nil, nil, nil)
+			app := &domain.Application{ID: primitive.NewObjectID(), HostID: host.ID, Status: domain.ApplicationStatusPending}
+
+			mockAppRepo.On("GetByID", ctx, app.ID.Hex()).Return(app, nil)
+			mockHostRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+			mockAppRepo.On("Update", ctx, app.ID.Hex(), app).Return(nil)
+
+			err := service.UpdateApplicationStatus(ctx, app.ID.Hex(), domain.ApplicationStatusAccepted, "", tt.userID.Hex())
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockAppRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, domain.ApplicationStatusAccepted, app.Status)
+		})
+	}
+}
+
+func TestListApplications_Visibility(t *testing.T) {
+	ctx := context.Background()
+	orgID := primitive.NewObjectID()
+	ownHost := &domain.Host{ID: primitive.NewObjectID()}
+	orgHost := &domain.Host{ID: primitive.NewObjectID(), OrganizationID: &orgID}
+
+	t.Run("Own Applications And Reviewable Hosts", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		ownHost.UserID = userID
+		mockAppRepo := new(MockApplicationRepository)
+		mockHostRepo := new(MockHostRepository)
+		mockUserRepo := new(MockUserRepository)
+		mockOrgRepo := new(MockOrganizationRepository)
+		service := NewApplicationService(mockAppRepo, nil, mockHostRepo, mockUserRepo, mockOrgRepo, nil)
+
+		mockHostRepo.On("GetByUserID", ctx, userID.Hex()).Return(ownHost, nil)
+		mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(&domain.User{ID: userID.Hex(), OrganizationID: orgID.Hex()}, nil)
+		mockOrgRepo.On("GetByID", ctx, orgID.Hex()).Return(&domain.Organization{ID: orgID, Members: []domain.OrganizationMember{
+			{UserID: userID, Role: domain.OrganizationRoleReviewer},
+		}}, nil)
+		mockHostRepo.On("ListByOrganizationID", ctx, orgID).Return([]*domain.Host{orgHost}, nil)
+		mockAppRepo.On("List", ctx, bson.M{
+			"status": "PENDING",
+			"$or": bson.A{
+				bson.M{"userId": userID},
+				bson.M{"hostId": bson.M{"$in": []primitive.ObjectID{ownHost.ID, orgHost.ID}}},
+			},
+		}, int64(10), int64(0)).Return([]*domain.Application{}, int64(0), nil)
+
+		_, _, err := service.ListApplications(ctx, userID.Hex(), bson.M{"status": "PENDING"}, 10, 0)
+		assert.NoError(t, err)
+		mockAppRepo.AssertExpectations(t)
+	})
+
+	t.Run("Editor Sees Only Own Applications", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		host := &domain.Host{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Members: []domain.HostMember{
+			{UserID: userID, Role: domain.HostMemberRoleEditor},
+		}}
+		mockAppRepo := new(MockApplicationRepository)
+		mockHostRepo := new(MockHostRepository)
+		mockUserRepo := new(MockUserRepository)
+		service := NewApplicationService(mockAppRepo, nil, mockHostRepo, mockUserRepo, nil, nil)
+
+		mockHostRepo.On("GetByUserID", ctx, userID.Hex()).Return(host, nil)
+		mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(&domain.User{ID: userID.Hex()}, nil)
+		// hostId 篩選只是縮小範圍，無法擴大可見的申請
+		mockAppRepo.On("List", ctx, bson.M{
+			"hostId": host.ID,
+			"$or":    bson.A{bson.M{"userId": userID}},
+		}, int64(10), int64(0)).Return([]*domain.Application{}, int64(0), nil)
+
+		_, _, err := service.ListApplications(ctx, userID.Hex(), bson.M{"hostId": host.ID}, 10, 0)
+		assert.NoError(t, err)
+		mockAppRepo.AssertExpectations(t)
+	})
+}
+
+func TestGetApplicationByID_Access(t *testing.T) {
+	ctx := context.Background()
+	applicantID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	editorID := primitive.NewObjectID()
+	host := &domain.Host{ID: primitive.NewObjectID(), UserID: ownerID, Members: []domain.HostMember{
+		{UserID: editorID, Role: domain.HostMemberRoleEditor},
+	}}
+	app := &domain.Application{ID: primitive.NewObjectID(), UserID: applicantID, HostID: host.ID}
+
+	tests := []struct {
+		name    string
+		userID  primitive.ObjectID
+		wantErr error
+	}{
+		{"Applicant", applicantID, nil},
+		{"Host Owner", ownerID, nil},
+		{"Co-host Editor", editorID, ErrApplicationNotFound},
+		{"Outsider", primitive.NewObjectID(), ErrApplicationNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAppRepo := new(MockApplicationRepository)
+			mockHostRepo := new(MockHostRepository)
+			service := NewApplicationService(mockAppRepo, nil, mockHostRepo, nil, nil, nil)
+
+			mockAppRepo.On("GetByID", ctx, app.ID.Hex()).Return(app, nil)
+			mockHostRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+
+			got, err := service.GetApplicationByID(ctx, app.ID.Hex(), tt.userID.Hex())
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, app, got)
+		})
+	}
+}
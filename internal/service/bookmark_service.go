@@ -2,15 +2,15 @@ package service
 
 import (
 	"context"
-	"errors"
 
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
 )
 
 var (
-	ErrBookmarkAlreadyExists = errors.New("bookmark already exists")
-	ErrBookmarkNotFound      = errors.New("bookmark not found")
+	ErrBookmarkAlreadyExists = errcode.Conflict("BOOKMARK_ALREADY_EXISTS", "bookmark already exists")
+	ErrBookmarkNotFound      = errcode.NotFound("BOOKMARK_NOT_FOUND", "bookmark not found")
 )
 
 type BookmarkService interface {
@@ -35,7 +35,7 @@ func (s *bookmarkService) AddBookmark(ctx context.Context, userID, opportunityID
 	// Check if opportunity exists
 	_, err := s.oppRepo.GetByID(ctx, opportunityID)
 	if err != nil {
-		return err // Could be ErrOpportunityNotFound if that's what repo returns
+		return notFound(err, ErrOpportunityNotFound)
 	}
 
 	// Check if already bookmarked
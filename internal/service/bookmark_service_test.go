@@ -35,6 +35,11 @@ func (m *MockBookmarkRepository) Exists(ctx context.Context, userID, opportunity
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockBookmarkRepository) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestAddBookmark(t *testing.T) {
 	mockBookmarkRepo := new(MockBookmarkRepository)
 	mockOppRepo := new(MockOpportunityRepository)
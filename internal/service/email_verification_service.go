@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/email"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrInvalidVerificationToken = errcode.BadRequest("INVALID_VERIFICATION_TOKEN", "invalid or expired verification token")
+	ErrEmailAlreadyVerified     = errcode.Conflict("EMAIL_ALREADY_VERIFIED", "email already verified")
+	ErrRateLimited              = errcode.TooManyRequests("RATE_LIMITED", "too many requests, please try again later")
+)
+
+// RateLimitedError 表示請求過於頻繁，RetryAfter 為可再次嘗試前需等待的時間
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("too many requests, retry after %s", e.RetryAfter)
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// emailVerificationTokenType 用於區分驗證信 token 與 access token
+const emailVerificationTokenType = "email_verification"
+
+const (
+	defaultEmailVerificationTTL = 24 * time.Hour
+	defaultEmailResendInterval  = time.Minute
+)
+
+// EmailVerificationService 處理 email 驗證信的寄送與驗證
+type EmailVerificationService interface {
+	SendVerification(ctx context.Context, userID string) error
+	VerifyEmail(ctx context.Context, token string) (*domain.User, error)
+	IsEmailVerified(ctx context.Context, userID string) (bool, error)
+}
+
+type emailVerificationService struct {
+	userRepo       repository.UserRepository
+	emailSender    email.EmailSender
+	jwtSecret      string
+	frontendURL    string
+	tokenTTL       time.Duration
+	resendInterval time.Duration
+}
+
+func NewEmailVerificationService(userRepo repository.UserRepository, emailSender email.EmailSender, cfg *config.Config) EmailVerificationService {
+	tokenTTL := cfg.Auth.EmailVerificationTTL
+	if tokenTTL <= 0 {
+		tokenTTL = defaultEmailVerificationTTL
+	}
+	resendInterval := cfg.Auth.EmailResendInterval
+	if resendInterval <= 0 {
+		resendInterval = defaultEmailResendInterval
+	}
+
+	return &emailVerificationService{
+		userRepo:       userRepo,
+		emailSender:    emailSender,
+		jwtSecret:      cfg.Server.JWTSecret,
+		frontendURL:    strings.TrimRight(cfg.Server.FrontendURL, "/"),
+		tokenTTL:       tokenTTL,
+		resendInterval: resendInterval,
+	}
+}
+
+// SendVerification 寄送驗證信。
+// 同一使用者在 resendInterval 內只能寄送一次，超過頻率時回傳 *RateLimitedError。
+func (s *emailVerificationService) SendVerification(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerified != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	now := time.Now()
+	allowed, err := s.userRepo.MarkVerificationEmailSent(ctx, user.ID, now.Add(-s.resendInterval))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		retryAfter := s.resendInterval
+		if user.EmailVerificationSentAt != nil {
+			retryAfter = user.EmailVerificationSentAt.Add(s.resendInterval).Sub(now)
+		}
+		if retryAfter < time.Second {
+			retryAfter = time.Second
+		}
+		return &RateLimitedError{RetryAfter: retryAfter}
+	}
+
+	token, err := s.signToken(user, now)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.frontendURL, url.QueryEscape(token))
+	locale := messageLocale(ctx, user)
+	body := i18n.T(locale, "email.verify.body",
+		"name", html.EscapeString(user.Name),
+		"link", html.EscapeString(link),
+		"ttl", s.tokenTTL,
+	)
+
+	return s.emailSender.Send(user.Email, user.Name, i18n.T(locale, "email.verify.subject"), body)
+}
+
+// VerifyEmail 驗證 token 並將使用者的 email 標記為已驗證。
+// token 中的 email 必須與使用者目前的 email 相同，避免變更 email 後舊連結仍然有效。
+// 已驗證的使用者再次驗證時直接回傳成功。
+func (s *emailVerificationService) VerifyEmail(ctx context.Context, token string) (*domain.User, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(s.jwtSecret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	typ, _ := claims["typ"].(string)
+	userID, _ := claims["sub"].(string)
+	tokenEmail, _ := claims["email"].(string)
+	if typ != emailVerificationTokenType || userID == "" || tokenEmail == "" {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, tokenEmail) {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	if user.EmailVerified == nil {
+		now := time.Now()
+		if err := s.userRepo.Update(ctx, user.ID, bson.M{"emailVerified": now}); err != nil {
+			return nil, err
+		}
+		user.EmailVerified = &now
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+// IsEmailVerified 回傳使用者的 email 是否已驗證
+func (s *emailVerificationService) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user.EmailVerified != nil, nil
+}
+
+func (s *emailVerificationService) signToken(user *domain.User, now time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"typ":   emailVerificationTokenType,
+		"sub":   user.ID,
+		"email": user.Email,
+		"iat":   now.Unix(),
+		"exp":   now.Add(s.tokenTTL).Unix(),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.jwtSecret))
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+)
+
+// MockEmailVerificationService 是一個用於測試的 EmailVerificationService mock
+type MockEmailVerificationService struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationService) SendVerification(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) VerifyEmail(ctx context.Context, token string) (*domain.User, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockEmailVerificationService) IsEmailVerified(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+var verificationLinkPattern = regexp.MustCompile(`/verify-email\?token=([^"]+)"`)
+
+func newTestEmailVerificationService(userRepo *mockUserRepository, sender *MockEmailSender) EmailVerificationService {
+	return NewEmailVerificationService(userRepo, sender, &config.Config{
+		Server: config.ServerConfig{JWTSecret: "test-secret", FrontendURL: "https://taiwanstay.test/"},
+		Auth:   config.AuthConfig{EmailVerificationTTL: time.Hour, EmailResendInterval: time.Minute},
+	})
+}
+
+func signVerificationToken(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+	return token
+}
+
+func TestSendVerification(t *testing.T) {
+	ctx := context.Background()
+	newUser := func() *domain.User {
+		return &domain.User{ID: "user-1", Name: "Traveler", Email: "traveler@example.com"}
+	}
+
+	t.Run("Sends Link And Token Verifies Email", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockSender := new(MockEmailSender)
+		svc := newTestEmailVerificationService(mockRepo, mockSender)
+
+		var body string
+		user := newUser()
+		mockRepo.On("GetByID", ctx, "user-1").Return(user, nil)
+		mockRepo.On("MarkVerificationEmailSent", ctx, "user-1", mock.AnythingOfType("time.Time")).Return(true, nil)
+		mockSender.On("Send", user.Email, user.Name, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			body = args.String(3)
+		}).Return(nil)
+
+		err := svc.SendVerification(ctx, "user-1")
+		require.NoError(t, err)
+
+		match := verificationLinkPattern.FindStringSubmatch(body)
+		require.Len(t, match, 2)
+		assert.Contains(t, body, "https://taiwanstay.test/verify-email")
+		token, err := url.QueryUnescape(match[1])
+		require.NoError(t, err)
+
+		mockRepo.On("Update", ctx, "user-1", mock.Anything).Return(nil)
+		verified, err := svc.VerifyEmail(ctx, token)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, verified.EmailVerified)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Throttled", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockSender := new(MockEmailSender)
+		svc := newTestEmailVerificationService(mockRepo, mockSender)
+
+		sentAt := time.Now().Add(-20 * time.Second)
+		throttledUser := newUser()
+		throttledUser.EmailVerificationSentAt = &sentAt
+		mockRepo.On("GetByID", ctx, "user-1").Return(throttledUser, nil)
+		mockRepo.On("MarkVerificationEmailSent", ctx, "user-1", mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		err := svc.SendVerification(ctx, "user-1")
+
+		var rateLimited *RateLimitedError
+		require.ErrorAs(t, err, &rateLimited)
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.InDelta(t, 40, rateLimited.RetryAfter.Seconds(), 2)
+		mockSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Already Verified", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		svc := newTestEmailVerificationService(mockRepo, new(MockEmailSender))
+
+		now := time.Now()
+		verifiedUser := newUser()
+		verifiedUser.EmailVerified = &now
+		mockRepo.On("GetByID", ctx, "user-1").Return(verifiedUser, nil)
+
+		err := svc.SendVerification(ctx, "user-1")
+
+		assert.ErrorIs(t, err, ErrEmailAlreadyVerified)
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"typ":   "email_verification",
+			"sub":   "user-1",
+			"email": "traveler@example.com",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	t.Run("Already Verified Is Idempotent", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		svc := newTestEmailVerificationService(mockRepo, new(MockEmailSender))
+
+		verifiedAt := time.Now().Add(-time.Hour)
+		mockRepo.On("GetByID", ctx, "user-1").Return(&domain.User{ID: "user-1", Email: "traveler@example.com", EmailVerified: &verifiedAt}, nil)
+
+		user, err := svc.VerifyEmail(ctx, signVerificationToken(t, validClaims()))
+
+		assert.NoError(t, err)
+		assert.Equal(t, verifiedAt, *user.EmailVerified)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Email Changed Since Token Was Issued", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		svc := newTestEmailVerificationService(mockRepo, new(MockEmailSender))
+
+		mockRepo.On("GetByID", ctx, "user-1").Return(&domain.User{ID: "user-1", Email: "new@example.com"}, nil)
+
+		_, err := svc.VerifyEmail(ctx, signVerificationToken(t, validClaims()))
+
+		assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+	})
+
+	invalid := map[string]func(jwt.MapClaims){
+		"Expired":      func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"Access Token": func(c jwt.MapClaims) { delete(c, "typ"); c["sid"] = "session-1" },
+		"Missing Sub":  func(c jwt.MapClaims) { delete(c, "sub") },
+	}
+	for name, mutate := range invalid {
+		t.Run(name, func(t *testing.T) {
+			svc := newTestEmailVerificationService(new(mockUserRepository), new(MockEmailSender))
+			claims := validClaims()
+			mutate(claims)
+
+			_, err := svc.VerifyEmail(ctx, signVerificationToken(t, claims))
+
+			assert.ErrorIs(t, err, ErrInvalidVerificationToken)
+		})
+	}
+}
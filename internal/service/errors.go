@@ -0,0 +1,35 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// 主要資源的 not-found 錯誤，保留原始的 mongo.ErrNoDocuments 供 errors.Is 判斷
+var (
+	ErrUserNotFound         = errcode.NotFound("USER_NOT_FOUND", "user not found")
+	ErrHostNotFound         = errcode.NotFound("HOST_NOT_FOUND", "host not found")
+	ErrOpportunityNotFound  = errcode.NotFound("OPPORTUNITY_NOT_FOUND", "opportunity not found")
+	ErrApplicationNotFound  = errcode.NotFound("APPLICATION_NOT_FOUND", "application not found")
+	ErrNotificationNotFound = errcode.NotFound("NOTIFICATION_NOT_FOUND", "notification not found")
+	ErrImageNotFound        = errcode.NotFound("IMAGE_NOT_FOUND", "image not found")
+)
+
+// notFound 將查無資料或無效的 ID 轉為指定的 not-found 錯誤，其他錯誤原樣回傳
+func notFound(err error, nf *errcode.Error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex) {
+		return nf.WithCause(err)
+	}
+	return err
+}
+
+// ErrInvalidStatusTransition 表示狀態機不允許的狀態變更，meta 帶有 from 與 to
+var ErrInvalidStatusTransition = errcode.Conflict("INVALID_STATUS_TRANSITION", "status change is not allowed")
+
+// invalidTransition 回傳帶有目前與目標狀態的 ErrInvalidStatusTransition
+func invalidTransition[S ~string](from, to S) error {
+	return ErrInvalidStatusTransition.WithMeta("from", string(from)).WithMeta("to", string(to))
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// canManageHost 回傳使用者是否可以對接待主執行指定操作：
+// 先依使用者在接待主的角色 (建立者或共同管理者) 判斷，再依所屬組織的成員角色判斷。
+func canManageHost(ctx context.Context, orgRepo repository.OrganizationRepository, userID string, host *domain.Host, permission domain.MemberPermission) (bool, error) {
+	if host.HasPermission(userID, permission) {
+		return true, nil
+	}
+	if host.OrganizationID == nil {
+		return false, nil
+	}
+
+	org, err := orgRepo.GetByID(ctx, host.OrganizationID.Hex())
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+		return false, err
+	}
+	member := org.Member(userID)
+	return member != nil && member.Role.HasPermission(permission), nil
+}
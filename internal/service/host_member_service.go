@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/email"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// hostInvitationTTL 是共同管理者邀請的有效期限
+const hostInvitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrHostInvitationNotFound = errcode.NotFound("HOST_INVITATION_NOT_FOUND", "invitation not found")
+	ErrHostInvitationClosed   = errcode.Conflict("HOST_INVITATION_CLOSED", "invitation has already been answered or has expired")
+	ErrHostInvitationPending  = errcode.Conflict("HOST_INVITATION_ALREADY_PENDING", "an invitation has already been sent to this email")
+	ErrHostMemberNotFound     = errcode.NotFound("HOST_MEMBER_NOT_FOUND", "user is not a member of this host")
+	ErrHostCreatorRoleFixed   = errcode.Conflict("HOST_CREATOR_ROLE_FIXED", "the host creator is always an owner and cannot be removed")
+)
+
+// HostMemberService 管理接待主的共同管理者 (co-host)：
+// OWNER 以 Email 邀請使用者，受邀者以相同 Email 登入後接受或拒絕。
+// 使用者同時只能建立或共同管理一個接待主。
+type HostMemberService interface {
+	ListMembers(ctx context.Context, userID string) ([]domain.HostMember, error)
+	UpdateMemberRole(ctx context.Context, userID, memberID string, role domain.HostMemberRole) error
+	RemoveMember(ctx context.Context, userID, memberID string) error
+	Invite(ctx context.Context, userID, email string, role domain.HostMemberRole) (*domain.HostInvitation, error)
+	ListInvitations(ctx context.Context, userID string) ([]*domain.HostInvitation, error)
+	RevokeInvitation(ctx context.Context, userID, invitationID string) error
+	ListMyInvitations(ctx context.Context, userID string) ([]*domain.HostInvitation, error)
+	AcceptInvitation(ctx context.Context, userID, invitationID string) (*domain.Host, error)
+	DeclineInvitation(ctx context.Context, userID, invitationID string) error
+}
+
+type hostMemberService struct {
+	hostRepo       repository.HostRepository
+	invitationRepo repository.HostInvitationRepository
+	userRepo       repository.UserRepository
+	notifService   NotificationService
+	emailSender    email.EmailSender
+	frontendURL    string
+}
+
+func NewHostMemberService(hostRepo repository.HostRepository, invitationRepo repository.HostInvitationRepository, userRepo repository.UserRepository, notifService NotificationService, emailSender email.EmailSender, cfg *config.Config) HostMemberService {
+	return &hostMemberService{
+		hostRepo:       hostRepo,
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		notifService:   notifService,
+		emailSender:    emailSender,
+		frontendURL:    strings.TrimRight(cfg.Server.FrontendURL, "/"),
+	}
+}
+
+// managedHost 回傳使用者建立或共同管理的接待主，並確認其角色擁有指定權限
+func managedHost(ctx context.Context, repo repository.HostRepository, userID string, permission domain.MemberPermission) (*domain.Host, error) {
+	host, err := repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+	if !host.HasPermission(userID, permission) {
+		return nil, ErrHostPermissionDenied.WithMeta("permission", string(permission))
+	}
+	return host, nil
+}
+
+// ListMembers 列出接待主的所有管理者，建立者排在最前面
+func (s *hostMemberService) ListMembers(ctx context.Context, userID string) ([]domain.HostMember, error) {
+	host, err := managedHost(ctx, s.hostRepo, userID, domain.MemberPermissionViewDashboard)
+	if err != nil {
+		return nil, err
+	}
+	members := []domain.HostMember{{UserID: host.UserID, Role: domain.HostMemberRoleOwner, AddedAt: host.CreatedAt}}
+	return append(members, host.Members...), nil
+}
+
+func (s *hostMemberService) UpdateMemberRole(ctx context.Context, userID, memberID string, role domain.HostMemberRole) error {
+	host, err := managedHost(ctx, s.hostRepo, userID, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return err
+	}
+	if host.UserID.Hex() == memberID {
+		return ErrHostCreatorRoleFixed
+	}
+	return notFound(s.hostRepo.UpdateMemberRole(ctx, host.ID.Hex(), memberID, role), ErrHostMemberNotFound)
+}
+
+// RemoveMember 移除共同管理者；成員也可以自行退出
+func (s *hostMemberService) RemoveMember(ctx context.Context, userID, memberID string) error {
+	permission := domain.MemberPermissionManageTeam
+	if memberID == userID {
+		permission = domain.MemberPermissionViewDashboard
+	}
+	host, err := managedHost(ctx, s.hostRepo, userID, permission)
+	if err != nil {
+		return err
+	}
+	if host.UserID.Hex() == memberID {
+		return ErrHostCreatorRoleFixed
+	}
+	return notFound(s.hostRepo.RemoveMember(ctx, host.ID.Hex(), memberID), ErrHostMemberNotFound)
+}
+
+// Invite 以 Email 邀請使用者成為共同管理者。
+// 已註冊的受邀者會收到通知；尚未註冊的受邀者會收到邀請信，以該 Email 註冊後即可在邀請列表看到邀請。
+func (s *hostMemberService) Invite(ctx context.Context, userID, email string, role domain.HostMemberRole) (*domain.HostInvitation, error) {
+	host, err := managedHost(ctx, s.hostRepo, userID, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	email = normalizeEmail(email)
+	invitee, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	if invitee != nil {
+		if err := s.checkCanJoin(ctx, invitee.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	open, err := s.invitationRepo.HasOpen(ctx, host.ID, email, now)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, ErrHostInvitationPending
+	}
+
+	invitedBy, _ := primitive.ObjectIDFromHex(userID)
+	inv := &domain.HostInvitation{
+		HostID:    host.ID,
+		HostName:  host.Name,
+		Email:     email,
+		Role:      role,
+		Status:    domain.HostInvitationPending,
+		InvitedBy: invitedBy,
+		ExpiresAt: now.Add(hostInvitationTTL),
+	}
+	if err := s.invitationRepo.Create(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	if invitee != nil {
+		s.notify(ctx, invitee.ID, domain.NotificationTypeHostInvitation, inv)
+	} else {
+		s.sendInvitationEmail(ctx, inv)
+	}
+	return inv, nil
+}
+
+// ListInvitations 列出接待主尚未回覆的邀請
+func (s *hostMemberService) ListInvitations(ctx context.Context, userID string) ([]*domain.HostInvitation, error) {
+	host, err := managedHost(ctx, s.hostRepo, userID, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return nil, err
+	}
+	return s.invitationRepo.ListByHostID(ctx, host.ID, domain.HostInvitationPending)
+}
+
+func (s *hostMemberService) RevokeInvitation(ctx context.Context, userID, invitationID string) error {
+	host, err := managedHost(ctx, s.hostRepo, userID, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return err
+	}
+	inv, err := s.invitationRepo.GetByID(ctx, invitationID)
+	if err != nil {
+		return notFound(err, ErrHostInvitationNotFound)
+	}
+	if inv.HostID != host.ID {
+		return ErrHostInvitationNotFound
+	}
+	return s.respond(ctx, inv, domain.HostInvitationRevoked)
+}
+
+// ListMyInvitations 列出寄給使用者 Email 的有效邀請
+func (s *hostMemberService) ListMyInvitations(ctx context.Context, userID string) ([]*domain.HostInvitation, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+	return s.invitationRepo.ListOpenByEmail(ctx, normalizeEmail(user.Email), time.Now())
+}
+
+// AcceptInvitation 接受邀請並加入接待主，已建立或共同管理其他接待主的使用者不能接受
+func (s *hostMemberService) AcceptInvitation(ctx context.Context, userID, invitationID string) (*domain.Host, error) {
+	inv, err := s.invitationFor(ctx, userID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCanJoin(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	// 先關閉邀請，避免同一份邀請被重複接受
+	if err := s.respond(ctx, inv, domain.HostInvitationAccepted); err != nil {
+		return nil, err
+	}
+	member := domain.HostMember{
+		UserID:  userObjID,
+		Role:    inv.Role,
+		AddedBy: inv.InvitedBy,
+		AddedAt: time.Now(),
+	}
+	if err := s.hostRepo.AddMember(ctx, inv.HostID.Hex(), member); err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+
+	host, err := s.hostRepo.GetByID(ctx, inv.HostID.Hex())
+	if err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+	s.notify(ctx, inv.InvitedBy.Hex(), domain.NotificationTypeHostInvitationAccepted, inv)
+	return host, nil
+}
+
+func (s *hostMemberService) DeclineInvitation(ctx context.Context, userID, invitationID string) error {
+	inv, err := s.invitationFor(ctx, userID, invitationID)
+	if err != nil {
+		return err
+	}
+	return s.respond(ctx, inv, domain.HostInvitationDeclined)
+}
+
+// invitationFor 讀取寄給使用者 Email 且仍可回覆的邀請
+func (s *hostMemberService) invitationFor(ctx context.Context, userID, invitationID string) (*domain.HostInvitation, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+	inv, err := s.invitationRepo.GetByID(ctx, invitationID)
+	if err != nil {
+		return nil, notFound(err, ErrHostInvitationNotFound)
+	}
+	// 寄給其他人的邀請視為不存在
+	if inv.Email != normalizeEmail(user.Email) {
+		return nil, ErrHostInvitationNotFound
+	}
+	if !inv.IsOpen(time.Now()) {
+		return nil, ErrHostInvitationClosed
+	}
+	return inv, nil
+}
+
+// respond 回覆邀請，邀請已被回覆時回傳 ErrHostInvitationClosed
+func (s *hostMemberService) respond(ctx context.Context, inv *domain.HostInvitation, status domain.HostInvitationStatus) error {
+	now := time.Now()
+	if err := s.invitationRepo.Respond(ctx, inv.ID.Hex(), status, now); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrHostInvitationClosed.WithCause(err)
+		}
+		return err
+	}
+	inv.Status = status
+	inv.RespondedAt = &now
+	return nil
+}
+
+// checkCanJoin 確認使用者尚未建立或共同管理任何接待主
+func (s *hostMemberService) checkCanJoin(ctx context.Context, userID string) error {
+	_, err := s.hostRepo.GetByUserID(ctx, userID)
+	if err == nil {
+		return ErrAlreadyManagesHost
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	return err
+}
+
+// notify 發送邀請相關通知，通知失敗不影響邀請結果
+func (s *hostMemberService) notify(ctx context.Context, userID string, notifType domain.NotificationType, inv *domain.HostInvitation) {
+	err := s.notifService.SendNotification(ctx, userID, notifType, map[string]string{
+		"invitationId": inv.ID.Hex(),
+		"hostId":       inv.HostID.Hex(),
+		"hostName":     inv.HostName,
+		"email":        inv.Email,
+		"role":         string(inv.Role),
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to send host invitation notification", "invitationId", inv.ID.Hex(), "error", err)
+	}
+}
+
+// sendInvitationEmail 寄送邀請信給尚未註冊的受邀者，寄送失敗不影響邀請結果
+func (s *hostMemberService) sendInvitationEmail(ctx context.Context, inv *domain.HostInvitation) {
+	link := fmt.Sprintf("%s/register?email=%s", s.frontendURL, url.QueryEscape(inv.Email))
+	locale := requestLocale(ctx)
+	subject := i18n.T(locale, "email.hostInvitation.subject", "hostName", inv.HostName)
+	body := i18n.T(locale, "email.hostInvitation.body",
+		"hostName", html.EscapeString(inv.HostName),
+		"link", html.EscapeString(link),
+		"ttl", hostInvitationTTL,
+	)
+	if err := s.emailSender.Send(inv.Email, "", subject, body); err != nil {
+		logger.ErrorContext(ctx, "Failed to send host invitation email", "invitationId", inv.ID.Hex(), "error", err)
+	}
+}
+
+// normalizeEmail 將 Email 轉為比對邀請用的格式
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
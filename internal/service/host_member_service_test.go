@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MockHostInvitationRepository struct {
+	mock.Mock
+}
+
+func (m *MockHostInvitationRepository) Create(ctx context.Context, inv *domain.HostInvitation) error {
+	args := m.Called(ctx, inv)
+	return args.Error(0)
+}
+
+func (m *MockHostInvitationRepository) GetByID(ctx context.Context, id string) (*domain.HostInvitation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.HostInvitation), args.Error(1)
+}
+
+func (m *MockHostInvitationRepository) ListByHostID(ctx context.Context, hostID primitive.ObjectID, status domain.HostInvitationStatus) ([]*domain.HostInvitation, error) {
+	args := m.Called(ctx, hostID, status)
+	return args.Get(0).([]*domain.HostInvitation), args.Error(1)
+}
+
+func (m *MockHostInvitationRepository) ListOpenByEmail(ctx context.Context, email string, now time.Time) ([]*domain.HostInvitation, error) {
+	args := m.Called(ctx, email, now)
+	return args.Get(0).([]*domain.HostInvitation), args.Error(1)
+}
+
+func (m *MockHostInvitationRepository) HasOpen(ctx context.Context, hostID primitive.ObjectID, email string, now time.Time) (bool, error) {
+	args := m.Called(ctx, hostID, email, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockHostInvitationRepository) Respond(ctx context.Context, id string, status domain.HostInvitationStatus, at time.Time) error {
+	args := m.Called(ctx, id, status, at)
+	return args.Error(0)
+}
+
+func TestHostMemberRolePermissions(t *testing.T) {
+	ownerID := primitive.NewObjectID()
+	editorID := primitive.NewObjectID()
+	reviewerID := primitive.NewObjectID()
+	host := &domain.Host{UserID: ownerID, Members: []domain.HostMember{
+		{UserID: editorID, Role: domain.HostMemberRoleEditor},
+		{UserID: reviewerID, Role: domain.HostMemberRoleReviewer},
+	}}
+
+	assert.True(t, host.HasPermission(ownerID.Hex(), domain.MemberPermissionManageTeam))
+	assert.True(t, host.HasPermission(editorID.Hex(), domain.MemberPermissionEditListings))
+	assert.False(t, host.HasPermission(editorID.Hex(), domain.MemberPermissionReviewApplications))
+	assert.True(t, host.HasPermission(reviewerID.Hex(), domain.MemberPermissionReviewApplications))
+	assert.False(t, host.HasPermission(reviewerID.Hex(), domain.MemberPermissionEditListings))
+	assert.False(t, host.HasPermission(primitive.NewObjectID().Hex(), domain.MemberPermissionViewDashboard))
+}
+
+func TestInviteHostMember(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	ownerID := primitive.NewObjectID()
+	editorID := primitive.NewObjectID()
+	host := &domain.Host{ID: primitive.NewObjectID(), UserID: ownerID, Name: "Family Farm", Members: []domain.HostMember{{UserID: editorID, Role: domain.HostMemberRoleEditor}}}
+
+	t.Run("Success", func(t *testing.T) {
+		mockHostRepo := new(MockHostRepository)
+		mockInvRepo := new(MockHostInvitationRepository)
+		mockUserRepo := new(MockUserRepository)
+		mockNotif := new(MockNotificationService)
+		service := NewHostMemberService(mockHostRepo, mockInvRepo, mockUserRepo, mockNotif, nil, testHostConfig)
+		inviteeID := primitive.NewObjectID()
+
+		mockHostRepo.On("GetByUserID", ctx, ownerID.Hex()).Return(host, nil)
+		mockUserRepo.On("GetByEmail", ctx, "sister@example.com").Return(&domain.User{ID: inviteeID.Hex()}, nil)
+		mockHostRepo.On("GetByUserID", ctx, inviteeID.Hex()).Return(nil, mongo.ErrNoDocuments)
+		mockInvRepo.On("HasOpen", ctx, host.ID, "sister@example.com", mock.Anything).Return(false, nil)
+		mockInvRepo.On("Create", ctx, mock.MatchedBy(func(inv *domain.HostInvitation) bool {
+			return inv.HostID == host.ID && inv.Role == domain.HostMemberRoleReviewer && inv.Status == domain.HostInvitationPending && inv.InvitedBy == ownerID
+		})).Return(nil)
+		mockNotif.On("SendNotification", ctx, inviteeID.Hex(), domain.NotificationTypeHostInvitation, mock.Anything).Return(nil)
+
+		inv, err := service.Invite(ctx, ownerID.Hex(), " Sister@Example.com ", domain.HostMemberRoleReviewer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "sister@example.com", inv.Email)
+		assert.True(t, inv.ExpiresAt.After(time.Now()))
+		mockInvRepo.AssertExpectations(t)
+		mockNotif.AssertExpectations(t)
+	})
+
+	t.Run("Unregistered Invitee Gets Email", func(t *testing.T) {
+		mockHostRepo := new(MockHostRepository)
+		mockInvRepo := new(MockHostInvitationRepository)
+		mockUserRepo := new(MockUserRepository)
+		mockSender := new(MockEmailSender)
+		service := NewHostMemberService(mockHostRepo, mockInvRepo, mockUserRepo, nil, mockSender, testHostConfig)
+
+		mockHostRepo.On("GetByUserID", ctx, ownerID.Hex()).Return(host, nil)
+		mockUserRepo.On("GetByEmail", ctx, "new@example.com").Return(nil, mongo.ErrNoDocuments)
+		mockInvRepo.On("HasOpen", ctx, host.ID, "new@example.com", mock.Anything).Return(false, nil)
+		mockInvRepo.On("Create", ctx, mock.Anything).Return(nil)
+		var body string
+		mockSender.On("Send", "new@example.com", "", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			body = args.String(3)
+		}).Return(nil)
+
+		_, err := service.Invite(ctx, ownerID.Hex(), "new@example.com", domain.HostMemberRoleEditor)
+
+		assert.NoError(t, err)
+		assert.Contains(t, body, "https://taiwanstay.test/register?email=new%40example.com")
+		assert.Contains(t, body, "Family Farm")
+		mockSender.AssertExpectations(t)
+	})
+
+	t.Run("Editor Cannot Invite", func(t *testing.T) {
+		mockHostRepo := new(MockHostRepository)
+		service := NewHostMemberService(mockHostRepo, nil, nil, nil, nil, testHostConfig)
+
+		mockHostRepo.On("GetByUserID", ctx, editorID.Hex()).Return(host, nil)
+
+		_, err := service.Invite(ctx, editorID.Hex(), "sister@example.com", domain.HostMemberRoleReviewer)
+
+		assert.ErrorIs(t, err, ErrHostPermissionDenied)
+	})
+
+	t.Run("Already Pending", func(t *testing.T) {
+		mockHostRepo := new(MockHostRepository)
+		mockInvRepo := new(MockHostInvitationRepository)
+		mockUserRepo := new(MockUserRepository)
+		service := NewHostMemberService(mockHostRepo, mockInvRepo, mockUserRepo, nil, nil, testHostConfig)
+
+		mockHostRepo.On("GetByUserID", ctx, ownerID.Hex()).Return(host, nil)
+		mockUserRepo.On("GetByEmail", ctx, "new@example.com").Return(nil, mongo.ErrNoDocuments)
+		mockInvRepo.On("HasOpen", ctx, host.ID, "new@example.com", mock.Anything).Return(true, nil)
+
+		_, err := service.Invite(ctx, ownerID.Hex(), "new@example.com", domain.HostMemberRoleEditor)
+
+		assert.ErrorIs(t, err, ErrHostInvitationPending)
+		mockInvRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAcceptHostInvitation(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	user := &domain.User{ID: userID.Hex(), Email: "Brother@example.com"}
+	newInvitation := func() *domain.HostInvitation {
+		return &domain.HostInvitation{
+			ID:        primitive.NewObjectID(),
+			HostID:    primitive.NewObjectID(),
+			Email:     "brother@example.com",
+			Role:      domain.HostMemberRoleEditor,
+			Status:    domain.HostInvitationPending,
+			InvitedBy: primitive.NewObjectID(),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockHostRepo := new(MockHostRepository)
+		mockInvRepo := new(MockHostInvitationRepository)
+		mockUserRepo := new(MockUserRepository)
+		mockNotif := new(MockNotificationService)
+		service := NewHostMemberService(mockHostRepo, mockInvRepo, mockUserRepo, mockNotif, nil, testHostConfig)
+		inv := newInvitation()
+		host := &domain.Host{ID: inv.HostID, UserID: inv.InvitedBy}
+
+		mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(user, nil)
+		mockInvRepo.On("GetByID", ctx, inv.ID.Hex()).Return(inv, nil)
+		mockHostRepo.On("GetByUserID", ctx, userID.Hex()).Return(nil, mongo.ErrNoDocuments)
+		mockInvRepo.On("Respond", ctx, inv.ID.Hex(), domain.HostInvitationAccepted, mock.Anything).Return(nil)
+		mockHostRepo.On("AddMember", ctx, inv.HostID.Hex(), mock.MatchedBy(func(m domain.HostMember) bool {
+			return m.UserID == userID && m.Role == domain.HostMemberRoleEditor && m.AddedBy == inv.InvitedBy
+		})).Return(nil)
+		mockHostRepo.On("GetByID", ctx, inv.HostID.Hex()).Return(host, nil)
+		mockNotif.On("SendNotification", ctx, inv.InvitedBy.Hex(), domain.NotificationTypeHostInvitationAccepted, mock.Anything).Return(nil)
+
+		result, err := service.AcceptInvitation(ctx, userID.Hex(), inv.ID.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, host.ID, result.ID)
+		assert.Equal(t, domain.HostInvitationAccepted, inv.Status)
+		mockHostRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invitation For Someone Else", func(t *testing.T) {
+		mockInvRepo := new(MockHostInvitationRepository)
+		mockUserRepo := new(MockUserRepository)
+		service := NewHostMemberService(nil, mockInvRepo, mockUserRepo, nil, nil, testHostConfig)
+		inv := newInvitation()
+		inv.Email = "someone@example.com"
+
+		mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(user, nil)
+		mockInvRepo.On("GetByID", ctx, inv.ID.Hex()).Return(inv, nil)
+
+		_, err := service.AcceptInvitation(ctx, userID.Hex(), inv.ID.Hex())
+
+		assert.ErrorIs(t, err, ErrHostInvitationNotFound)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		mockInvRepo := new(MockHostInvitationRepository)
+		mockUserRepo := new(MockUserRepository)
+		service := NewHostMemberService(nil, mockInvRepo, mockUserRepo, nil, nil, testHostConfig)
+		inv := newInvitation()
+		inv.ExpiresAt = time.Now().Add(-time.Minute)
+
+		mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(user, nil)
+		mockInvRepo.On("GetByID", ctx, inv.ID.Hex()).Return(inv, nil)
+
+		_, err := service.AcceptInvitation(ctx, userID.Hex(), inv.ID.Hex())
+
+		assert.ErrorIs(t, err, ErrHostInvitationClosed)
+	})
+
+	t.Run("Already Manages Host", func(t *testing.T) {
+		mockHostRepo := new(MockHostRepository)
+		mockInvRepo := new(MockHostInvitationRepository)
+		mockUserRepo := new(MockUserRepository)
+		service := NewHostMemberService(mockHostRepo, mockInvRepo, mockUserRepo, nil, nil, testHostConfig)
+		inv := newInvitation()
+
+		mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(user, nil)
+		mockInvRepo.On("GetByID", ctx, inv.ID.Hex()).Return(inv, nil)
+		mockHostRepo.On("GetByUserID", ctx, userID.Hex()).Return(&domain.Host{UserID: userID}, nil)
+
+		_, err := service.AcceptInvitation(ctx, userID.Hex(), inv.ID.Hex())
+
+		assert.ErrorIs(t, err, ErrAlreadyManagesHost)
+		mockInvRepo.AssertNotCalled(t, "Respond", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRemoveHostMember_CreatorIsFixed(t *testing.T) {
+	ctx := context.Background()
+	ownerID := primitive.NewObjectID()
+	coOwnerID := primitive.NewObjectID()
+	host := &domain.Host{ID: primitive.NewObjectID(), UserID: ownerID, Members: []domain.HostMember{{UserID: coOwnerID, Role: domain.HostMemberRoleOwner}}}
+
+	mockHostRepo := new(MockHostRepository)
+	service := NewHostMemberService(mockHostRepo, nil, nil, nil, nil, testHostConfig)
+	mockHostRepo.On("GetByUserID", ctx, coOwnerID.Hex()).Return(host, nil)
+
+	err := service.RemoveMember(ctx, coOwnerID.Hex(), ownerID.Hex())
+	assert.ErrorIs(t, err, ErrHostCreatorRoleFixed)
+
+	err = service.UpdateMemberRole(ctx, coOwnerID.Hex(), ownerID.Hex(), domain.HostMemberRoleEditor)
+	assert.ErrorIs(t, err, ErrHostCreatorRoleFixed)
+}
@@ -2,38 +2,75 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrAlreadyManagesHost   = errcode.Conflict("ALREADY_MANAGES_HOST", "user already owns or co-hosts a host")
+	ErrHostPermissionDenied = errcode.Forbidden("HOST_PERMISSION_DENIED", "your role on this host does not allow this action")
 )
 
 type HostService interface {
 	CreateHost(ctx context.Context, host *domain.Host) (*domain.Host, error)
 	GetHostByUserID(ctx context.Context, userID string) (*domain.Host, error)
+	GetManagedHost(ctx context.Context, userID string, permission domain.MemberPermission) (*domain.Host, error)
 	GetHostByID(ctx context.Context, id string) (*domain.Host, error)
 	UpdateHost(ctx context.Context, id string, host *domain.Host) error
+	ChangeStatus(ctx context.Context, id string, to domain.HostStatus, actor domain.Actor, actorID, note string) (*domain.Host, error)
+	GetPublicHost(ctx context.Context, slug, locale string) (*domain.PublicHost, error)
 }
 
 type hostService struct {
-	repo repository.HostRepository
+	repo    repository.HostRepository
+	oppRepo repository.OpportunityRepository
+	// locationKey 用於產生公開座標的偏移量，外部無法由公開的接待主 ID 回推
+	locationKey []byte
 }
 
-func NewHostService(repo repository.HostRepository) HostService {
-	return &hostService{repo: repo}
+func NewHostService(repo repository.HostRepository, oppRepo repository.OpportunityRepository, cfg *config.Config) HostService {
+	return &hostService{repo: repo, oppRepo: oppRepo, locationKey: hostLocationKey(cfg.Server)}
 }
 
 func (s *hostService) CreateHost(ctx context.Context, host *domain.Host) (*domain.Host, error) {
+	// 使用者同時只能建立或共同管理一個接待主
+	if _, err := s.repo.GetByUserID(ctx, host.UserID.Hex()); err == nil {
+		return nil, ErrAlreadyManagesHost
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
 	// Generate Slug
 	if host.Slug == "" {
 		host.Slug = generateSlug(host.Name)
 	}
 
-	// Set default status if not provided
-	if host.Status == "" {
-		host.Status = domain.HostStatusPending
+	// 新的接待主一律從 PENDING 開始，狀態與驗證資料只能透過送審與 ChangeStatus 變更
+	host.Status = domain.HostStatusPending
+	host.StatusNote = ""
+	host.StatusHistory = nil
+	host.Verified = false
+	host.VerifiedAt = nil
+	host.VerificationDocuments = nil
+	host.VerificationSubmittedAt = nil
+	host.OrganizationID = nil
+	host.Members = nil
+
+	// 未指定內容語系時視為與請求相同
+	if host.DefaultLocale == "" {
+		host.DefaultLocale = string(requestLocale(ctx))
 	}
 
 	err := s.repo.Create(ctx, host)
@@ -43,18 +80,130 @@ func (s *hostService) CreateHost(ctx context.Context, host *domain.Host) (*domai
 	return host, nil
 }
 
+// GetHostByUserID 回傳使用者建立或共同管理的接待主
 func (s *hostService) GetHostByUserID(ctx context.Context, userID string) (*domain.Host, error) {
-	return s.repo.GetByUserID(ctx, userID)
+	host, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+	return host, nil
+}
+
+// GetManagedHost 回傳使用者建立或共同管理的接待主，角色沒有指定權限時回傳 ErrHostPermissionDenied
+func (s *hostService) GetManagedHost(ctx context.Context, userID string, permission domain.MemberPermission) (*domain.Host, error) {
+	return managedHost(ctx, s.repo, userID, permission)
 }
 
 func (s *hostService) GetHostByID(ctx context.Context, id string) (*domain.Host, error) {
-	return s.repo.GetByID(ctx, id)
+	host, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+	return host, nil
 }
 
+// UpdateHost 更新接待主資料。
+// 狀態與驗證資料只能透過 ChangeStatus 與驗證流程變更，所屬組織與共同管理者
+// 只能透過 OrganizationService 與 HostMemberService 變更，會沿用目前的值。
 func (s *hostService) UpdateHost(ctx context.Context, id string, host *domain.Host) error {
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return notFound(err, ErrHostNotFound)
+	}
+
+	host.Status = existing.Status
+	host.StatusNote = existing.StatusNote
+	host.StatusHistory = existing.StatusHistory
+	host.Verified = existing.Verified
+	host.VerifiedAt = existing.VerifiedAt
+	host.VerificationDocuments = existing.VerificationDocuments
+	host.VerificationSubmittedAt = existing.VerificationSubmittedAt
+	host.OrganizationID = existing.OrganizationID
+	host.Members = existing.Members
+
 	return s.repo.Update(ctx, id, host)
 }
 
+// ChangeStatus 依狀態機變更接待主狀態。
+// 停權或停用 (接待主自行停用，或建立者刪除帳號時由系統停用) 時會暫停接待主所有公開 (上架中或已額滿) 的工作機會，
+// 恢復後由接待主自行重新上架。
+func (s *hostService) ChangeStatus(ctx context.Context, id string, to domain.HostStatus, actor domain.Actor, actorID, note string) (*domain.Host, error) {
+	host, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+	if err := changeHostStatus(ctx, s.repo, host, to, actor, actorID, note, nil); err != nil {
+		return nil, err
+	}
+
+	var reason string
+	switch to {
+	case domain.HostStatusSuspended:
+		reason = "host suspended"
+	case domain.HostStatusInactive:
+		reason = "host deactivated"
+	default:
+		return host, nil
+	}
+
+	actorObjID, _ := primitive.ObjectIDFromHex(actorID)
+	entry := domain.OpportunityStatusHistory{
+		Status:    domain.OpportunityStatusPaused,
+		Reason:    reason,
+		ChangedBy: actorObjID,
+		ChangedAt: time.Now(),
+	}
+	public := []domain.OpportunityStatus{domain.OpportunityStatusActive, domain.OpportunityStatusFilled}
+	paused, err := s.oppRepo.UpdateStatusByHostID(ctx, host.ID, public, entry)
+	if err != nil {
+		return nil, err
+	}
+	logger.InfoContext(ctx, "Paused opportunities of host", "hostId", id, "status", to, "count", paused)
+
+	return host, nil
+}
+
+// changeHostStatus 檢查狀態機後變更狀態並寫入 StatusHistory，成功時同步更新 host。
+// 管理員啟用尚未驗證的接待主視同通過驗證；fields 為同時更新的其他欄位。
+func changeHostStatus(ctx context.Context, repo repository.HostRepository, host *domain.Host, to domain.HostStatus, actor domain.Actor, actorID, note string, fields bson.M) error {
+	if !host.Status.CanTransitionTo(to, actor) {
+		return invalidTransition(host.Status, to)
+	}
+
+	actorObjID, _ := primitive.ObjectIDFromHex(actorID)
+	now := time.Now()
+	entry := domain.HostStatusHistory{
+		Status:     to,
+		StatusNote: note,
+		UpdatedBy:  actorObjID,
+		UpdatedAt:  now,
+	}
+	if fields == nil {
+		fields = bson.M{}
+	}
+	verify := to == domain.HostStatusActive && !host.Verified
+	if verify {
+		fields["verified"] = true
+		fields["verifiedAt"] = now
+	}
+	if err := repo.UpdateStatus(ctx, host.ID.Hex(), host.Status, entry, fields); err != nil {
+		// 讀取後狀態已被其他請求變更
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return invalidTransition(host.Status, to)
+		}
+		return err
+	}
+
+	host.Status = to
+	host.StatusNote = note
+	host.StatusHistory = append(host.StatusHistory, entry)
+	if verify {
+		host.Verified = true
+		host.VerifiedAt = &now
+	}
+	return nil
+}
+
 // Helper to generate simple slug
 func generateSlug(name string) string {
 	slug := strings.ToLower(name)
@@ -7,9 +7,16 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+var testHostConfig = &config.Config{Server: config.ServerConfig{JWTSecret: "test-secret", FrontendURL: "https://taiwanstay.test/"}}
+
 // MockHostRepository is a mock implementation of HostRepository
 type MockHostRepository struct {
 	mock.Mock
@@ -36,14 +43,62 @@ func (m *MockHostRepository) GetByUserID(ctx context.Context, userID string) (*d
 	return args.Get(0).(*domain.Host), args.Error(1)
 }
 
+func (m *MockHostRepository) GetBySlug(ctx context.Context, slug string) (*domain.Host, error) {
+	args := m.Called(ctx, slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Host), args.Error(1)
+}
+
 func (m *MockHostRepository) Update(ctx context.Context, id string, host *domain.Host) error {
 	args := m.Called(ctx, id, host)
 	return args.Error(0)
 }
 
+func (m *MockHostRepository) AddVerificationDocument(ctx context.Context, id string, doc domain.HostVerificationDocument) error {
+	args := m.Called(ctx, id, doc)
+	return args.Error(0)
+}
+
+func (m *MockHostRepository) UpdateStatus(ctx context.Context, id string, from domain.HostStatus, entry domain.HostStatusHistory, fields bson.M) error {
+	args := m.Called(ctx, id, from, entry, fields)
+	return args.Error(0)
+}
+
+func (m *MockHostRepository) ListPendingVerification(ctx context.Context, limit, offset int64) ([]*domain.Host, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]*domain.Host), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockHostRepository) ListByOrganizationID(ctx context.Context, orgID primitive.ObjectID) ([]*domain.Host, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).([]*domain.Host), args.Error(1)
+}
+
+func (m *MockHostRepository) SetOrganization(ctx context.Context, id string, orgID *primitive.ObjectID) error {
+	args := m.Called(ctx, id, orgID)
+	return args.Error(0)
+}
+
+func (m *MockHostRepository) AddMember(ctx context.Context, id string, member domain.HostMember) error {
+	args := m.Called(ctx, id, member)
+	return args.Error(0)
+}
+
+func (m *MockHostRepository) UpdateMemberRole(ctx context.Context, id, userID string, role domain.HostMemberRole) error {
+	args := m.Called(ctx, id, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockHostRepository) RemoveMember(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
 func TestCreateHost(t *testing.T) {
 	mockRepo := new(MockHostRepository)
-	service := NewHostService(mockRepo)
+	service := NewHostService(mockRepo, nil, testHostConfig)
 
 	ctx := context.Background()
 	userID := primitive.NewObjectID()
@@ -53,6 +108,7 @@ func TestCreateHost(t *testing.T) {
 	}
 
 	// Expect Create to be called
+	mockRepo.On("GetByUserID", ctx, userID.Hex()).Return(nil, mongo.ErrNoDocuments)
 	mockRepo.On("Create", ctx, host).Return(nil)
 
 	createdHost, err := service.CreateHost(ctx, host)
@@ -67,9 +123,25 @@ func TestCreateHost(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestCreateHost_AlreadyManagesHost(t *testing.T) {
+	mockRepo := new(MockHostRepository)
+	service := NewHostService(mockRepo, nil, testHostConfig)
+
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	// 已是其他接待主的共同管理者
+	existing := &domain.Host{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Members: []domain.HostMember{{UserID: userID, Role: domain.HostMemberRoleEditor}}}
+	mockRepo.On("GetByUserID", ctx, userID.Hex()).Return(existing, nil)
+
+	_, err := service.CreateHost(ctx, &domain.Host{UserID: userID, Name: "Second Farm"})
+
+	assert.ErrorIs(t, err, ErrAlreadyManagesHost)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestGetHostByUserID(t *testing.T) {
 	mockRepo := new(MockHostRepository)
-	service := NewHostService(mockRepo)
+	service := NewHostService(mockRepo, nil, testHostConfig)
 
 	ctx := context.Background()
 	userID := primitive.NewObjectID().Hex()
@@ -85,3 +157,182 @@ func TestGetHostByUserID(t *testing.T) {
 	assert.Equal(t, expectedHost, host)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestCreateHost_DefaultLocale(t *testing.T) {
+	mockRepo := new(MockHostRepository)
+	service := NewHostService(mockRepo, nil, testHostConfig)
+
+	ctx := i18n.WithLocale(context.Background(), i18n.LocaleZhTW)
+	mockRepo.On("GetByUserID", ctx, mock.Anything).Return(nil, mongo.ErrNoDocuments)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Host")).Return(nil)
+
+	// 未指定預設語系時使用請求語系
+	host, err := service.CreateHost(ctx, &domain.Host{Name: "山中民宿"})
+	assert.NoError(t, err)
+	assert.Equal(t, "zh-TW", host.DefaultLocale)
+
+	// 已指定時保留
+	host, err = service.CreateHost(ctx, &domain.Host{Name: "Mountain Inn", DefaultLocale: "en"})
+	assert.NoError(t, err)
+	assert.Equal(t, "en", host.DefaultLocale)
+}
+
+func TestLocalize(t *testing.T) {
+	opp := domain.Opportunity{
+		Title:            "有機農場幫手",
+		Description:      "協助農務",
+		ShortDescription: "農場換宿",
+		DefaultLocale:    "zh-TW",
+		Translations: map[string]domain.OpportunityTranslation{
+			"ja": {Title: "有機農場のお手伝い"},
+			"en": {Title: "Organic farm helper", Description: "Help on the farm"},
+		},
+	}
+
+	// 部分翻譯：未翻譯的欄位沿用預設內容
+	en := opp
+	en.Localize("en")
+	assert.Equal(t, "en", en.Locale)
+	assert.Equal(t, "Organic farm helper", en.Title)
+	assert.Equal(t, "Help on the farm", en.Description)
+	assert.Equal(t, "農場換宿", en.ShortDescription)
+	assert.Equal(t, []string{"zh-TW", "en", "ja"}, en.AvailableLocales)
+
+	// 沒有翻譯的語系回到預設語系
+	host := domain.Host{
+		Description:   "山中民宿",
+		DefaultLocale: "zh-TW",
+		Translations:  map[string]domain.HostTranslation{"en": {Description: "Mountain inn"}},
+	}
+	host.Localize("ja")
+	assert.Equal(t, "zh-TW", host.Locale)
+	assert.Equal(t, "山中民宿", host.Description)
+
+	host.Localize("en")
+	assert.Equal(t, "en", host.Locale)
+	assert.Equal(t, "Mountain inn", host.Description)
+}
+
+func TestHostStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from  domain.HostStatus
+		to    domain.HostStatus
+		actor domain.Actor
+		want  bool
+	}{
+		{domain.HostStatusPending, domain.HostStatusActive, domain.ActorAdmin, true},
+		{domain.HostStatusPending, domain.HostStatusActive, domain.ActorHost, false}, // 不可自行上線
+		{domain.HostStatusActive, domain.HostStatusInactive, domain.ActorHost, true},
+		{domain.HostStatusActive, domain.HostStatusInactive, domain.ActorSystem, true},
+		{domain.HostStatusInactive, domain.HostStatusActive, domain.ActorSystem, false},
+		{domain.HostStatusInactive, domain.HostStatusActive, domain.ActorHost, true},
+		{domain.HostStatusActive, domain.HostStatusSuspended, domain.ActorHost, false},
+		{domain.HostStatusActive, domain.HostStatusSuspended, domain.ActorAdmin, true},
+		{domain.HostStatusSuspended, domain.HostStatusActive, domain.ActorHost, false},
+		{domain.HostStatusSuspended, domain.HostStatusActive, domain.ActorAdmin, true},
+		{domain.HostStatusRejected, domain.HostStatusPending, domain.ActorHost, true},
+		{domain.HostStatusRejected, domain.HostStatusActive, domain.ActorAdmin, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.from.CanTransitionTo(tt.to, tt.actor), "%s -> %s by %s", tt.from, tt.to, tt.actor)
+	}
+}
+
+func TestChangeHostStatus(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	adminID := primitive.NewObjectID()
+
+	t.Run("Suspend Pauses Opportunities", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		mockOppRepo := new(MockOpportunityRepository)
+		service := NewHostService(mockRepo, mockOppRepo, testHostConfig)
+		host := &domain.Host{ID: primitive.NewObjectID(), Status: domain.HostStatusActive, Verified: true}
+
+		mockRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+		mockRepo.On("UpdateStatus", ctx, host.ID.Hex(), host.Status, mock.MatchedBy(func(e domain.HostStatusHistory) bool {
+			return e.Status == domain.HostStatusSuspended && e.UpdatedBy == adminID && e.StatusNote == "fake listing"
+		}), bson.M{}).Return(nil)
+		mockOppRepo.On("UpdateStatusByHostID", ctx, host.ID, []domain.OpportunityStatus{domain.OpportunityStatusActive, domain.OpportunityStatusFilled}, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+			return e.Status == domain.OpportunityStatusPaused && e.ChangedBy == adminID && e.Reason != ""
+		})).Return(int64(2), nil)
+
+		result, err := service.ChangeStatus(ctx, host.ID.Hex(), domain.HostStatusSuspended, domain.ActorAdmin, adminID.Hex(), "fake listing")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.HostStatusSuspended, result.Status)
+		assert.Len(t, result.StatusHistory, 1)
+		mockRepo.AssertExpectations(t)
+		mockOppRepo.AssertExpectations(t)
+	})
+
+	t.Run("Host Deactivation Pauses Opportunities", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		mockOppRepo := new(MockOpportunityRepository)
+		service := NewHostService(mockRepo, mockOppRepo, testHostConfig)
+		ownerID := primitive.NewObjectID()
+		host := &domain.Host{ID: primitive.NewObjectID(), UserID: ownerID, Status: domain.HostStatusActive, Verified: true}
+
+		mockRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+		mockRepo.On("UpdateStatus", ctx, host.ID.Hex(), domain.HostStatusActive, mock.MatchedBy(func(e domain.HostStatusHistory) bool {
+			return e.Status == domain.HostStatusInactive && e.UpdatedBy == ownerID
+		}), bson.M{}).Return(nil)
+		mockOppRepo.On("UpdateStatusByHostID", ctx, host.ID, []domain.OpportunityStatus{domain.OpportunityStatusActive, domain.OpportunityStatusFilled}, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+			return e.Status == domain.OpportunityStatusPaused && e.ChangedBy == ownerID && e.Reason == "host deactivated"
+		})).Return(int64(1), nil)
+
+		result, err := service.ChangeStatus(ctx, host.ID.Hex(), domain.HostStatusInactive, domain.ActorHost, ownerID.Hex(), "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.HostStatusInactive, result.Status)
+		mockOppRepo.AssertExpectations(t)
+	})
+
+	t.Run("Concurrent Change", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		mockOppRepo := new(MockOpportunityRepository)
+		service := NewHostService(mockRepo, mockOppRepo, testHostConfig)
+		host := &domain.Host{ID: primitive.NewObjectID(), Status: domain.HostStatusActive, Verified: true}
+
+		mockRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+		// 讀取後已被其他請求變更狀態，條件更新不成立
+		mockRepo.On("UpdateStatus", ctx, host.ID.Hex(), domain.HostStatusActive, mock.AnythingOfType("domain.HostStatusHistory"), bson.M{}).Return(mongo.ErrNoDocuments)
+
+		_, err := service.ChangeStatus(ctx, host.ID.Hex(), domain.HostStatusSuspended, domain.ActorAdmin, adminID.Hex(), "fake listing")
+
+		assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+		mockOppRepo.AssertNotCalled(t, "UpdateStatusByHostID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Host Cannot Lift Suspension", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		service := NewHostService(mockRepo, new(MockOpportunityRepository), testHostConfig)
+		host := &domain.Host{ID: primitive.NewObjectID(), Status: domain.HostStatusSuspended}
+
+		mockRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+
+		_, err := service.ChangeStatus(ctx, host.ID.Hex(), domain.HostStatusActive, domain.ActorHost, host.UserID.Hex(), "")
+
+		assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+		mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateHost_KeepsStatus(t *testing.T) {
+	mockRepo := new(MockHostRepository)
+	service := NewHostService(mockRepo, nil, testHostConfig)
+
+	ctx := context.Background()
+	id := primitive.NewObjectID()
+	existing := &domain.Host{ID: id, Status: domain.HostStatusPending, Verified: false}
+	mockRepo.On("GetByID", ctx, id.Hex()).Return(existing, nil)
+	mockRepo.On("Update", ctx, id.Hex(), mock.MatchedBy(func(h *domain.Host) bool {
+		return h.Status == domain.HostStatusPending && !h.Verified && h.Name == "Renamed"
+	})).Return(nil)
+
+	// 接待主不能透過一般更新讓自己上線或標記為已驗證
+	err := service.UpdateHost(ctx, id.Hex(), &domain.Host{Name: "Renamed", Status: domain.HostStatusActive, Verified: true})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrVerificationDocumentsRequired = errcode.Unprocessable("VERIFICATION_DOCUMENTS_REQUIRED", "upload at least one verification document before submitting")
+	ErrVerificationAlreadySubmitted  = errcode.Conflict("VERIFICATION_ALREADY_SUBMITTED", "host is already waiting for review")
+	ErrVerificationNotAllowed        = errcode.Conflict("VERIFICATION_NOT_ALLOWED", "host cannot be submitted for verification in its current status")
+	ErrHostNotPendingReview          = errcode.Conflict("HOST_NOT_PENDING_REVIEW", "host is not waiting for review")
+	ErrInvalidReviewDecision         = errcode.BadRequest("INVALID_REVIEW_DECISION", "decision must be APPROVE, REJECT or REQUEST_CHANGES")
+)
+
+// HostVerificationService 處理接待主的驗證流程：
+// 接待主上傳驗證文件並送審 (PENDING)，管理員審核後通過 (ACTIVE)、拒絕 (REJECTED) 或退回修改 (EDITING)。
+type HostVerificationService interface {
+	UploadDocument(ctx context.Context, userID string, docType domain.HostDocumentType, file multipart.File, header *multipart.FileHeader) (*domain.HostVerificationDocument, error)
+	SubmitForReview(ctx context.Context, userID string) (*domain.Host, error)
+	ListPending(ctx context.Context, limit, offset int64) ([]*domain.Host, int64, error)
+	GetDocumentContent(ctx context.Context, hostID, imageID string) (io.ReadCloser, error)
+	Review(ctx context.Context, reviewerID, hostID string, decision domain.HostReviewDecision, note string) (*domain.Host, error)
+}
+
+type hostVerificationService struct {
+	hostRepo     repository.HostRepository
+	imageService ImageService
+	notifService NotificationService
+}
+
+func NewHostVerificationService(hostRepo repository.HostRepository, imageService ImageService, notifService NotificationService) HostVerificationService {
+	return &hostVerificationService{
+		hostRepo:     hostRepo,
+		imageService: imageService,
+		notifService: notifService,
+	}
+}
+
+// UploadDocument 將驗證文件上傳到私有 bucket 並加入使用者的接待主資料，只有 OWNER 可以上傳
+func (s *hostVerificationService) UploadDocument(ctx context.Context, userID string, docType domain.HostDocumentType, file multipart.File, header *multipart.FileHeader) (*domain.HostVerificationDocument, error) {
+	host, err := managedHost(ctx, s.hostRepo, userID, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	image, err := s.imageService.UploadPrivateImage(ctx, file, header, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := domain.HostVerificationDocument{
+		Type:       docType,
+		ImageID:    image.ID,
+		UploadedAt: time.Now(),
+	}
+	if err := s.hostRepo.AddVerificationDocument(ctx, host.ID.Hex(), doc); err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+	return &doc, nil
+}
+
+// SubmitForReview 將接待主送審。
+// 新建立 (尚未送審)、被退回修改或被拒絕的接待主可以送審，且至少需要一份驗證文件；只有 OWNER 可以送審。
+func (s *hostVerificationService) SubmitForReview(ctx context.Context, userID string) (*domain.Host, error) {
+	host, err := managedHost(ctx, s.hostRepo, userID, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	if host.Status == domain.HostStatusPending {
+		if host.VerificationSubmittedAt != nil {
+			return nil, ErrVerificationAlreadySubmitted
+		}
+	} else if !host.Status.CanTransitionTo(domain.HostStatusPending, domain.ActorHost) {
+		return nil, ErrVerificationNotAllowed
+	}
+	if len(host.VerificationDocuments) == 0 {
+		return nil, ErrVerificationDocumentsRequired
+	}
+
+	now := time.Now()
+	entry := domain.HostStatusHistory{
+		Status:    domain.HostStatusPending,
+		UpdatedBy: host.UserID,
+		UpdatedAt: now,
+	}
+	if err := s.hostRepo.UpdateStatus(ctx, host.ID.Hex(), host.Status, entry, bson.M{"verificationSubmittedAt": now}); err != nil {
+		// 讀取後狀態已被其他請求變更
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrVerificationNotAllowed
+		}
+		return nil, err
+	}
+
+	host.Status = entry.Status
+	host.StatusNote = ""
+	host.StatusHistory = append(host.StatusHistory, entry)
+	host.VerificationSubmittedAt = &now
+	return host, nil
+}
+
+// ListPending 列出等待審核的接待主
+func (s *hostVerificationService) ListPending(ctx context.Context, limit, offset int64) ([]*domain.Host, int64, error) {
+	return s.hostRepo.ListPendingVerification(ctx, limit, offset)
+}
+
+// GetDocumentContent 讀取接待主的驗證文件，imageID 必須屬於該接待主
+func (s *hostVerificationService) GetDocumentContent(ctx context.Context, hostID, imageID string) (io.ReadCloser, error) {
+	host, err := s.hostRepo.GetByID(ctx, hostID)
+	if err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+	for _, doc := range host.VerificationDocuments {
+		if doc.ImageID.Hex() == imageID {
+			return s.imageService.GetImageContent(ctx, imageID)
+		}
+	}
+	return nil, ErrImageNotFound.WithCause(mongo.ErrNoDocuments)
+}
+
+// Review 記錄管理員的審核結果並通知接待主。
+// 通過時標記為已驗證 (見 changeHostStatus)；拒絕或退回修改時 note 會顯示給接待主。
+func (s *hostVerificationService) Review(ctx context.Context, reviewerID, hostID string, decision domain.HostReviewDecision, note string) (*domain.Host, error) {
+	var status domain.HostStatus
+	var notifType domain.NotificationType
+	switch decision {
+	case domain.HostReviewApprove:
+		status, notifType = domain.HostStatusActive, domain.NotificationTypeHostVerificationApproved
+	case domain.HostReviewReject:
+		status, notifType = domain.HostStatusRejected, domain.NotificationTypeHostVerificationRejected
+	case domain.HostReviewRequestChanges:
+		status, notifType = domain.HostStatusEditing, domain.NotificationTypeHostVerificationChangesRequested
+	default:
+		return nil, ErrInvalidReviewDecision
+	}
+
+	host, err := s.hostRepo.GetByID(ctx, hostID)
+	if err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+	if host.Status != domain.HostStatusPending || host.VerificationSubmittedAt == nil {
+		return nil, ErrHostNotPendingReview
+	}
+
+	if err := changeHostStatus(ctx, s.hostRepo, host, status, domain.ActorAdmin, reviewerID, note, nil); err != nil {
+		return nil, err
+	}
+
+	// 通知失敗不影響審核結果
+	err = s.notifService.SendNotification(ctx, host.UserID.Hex(), notifType, map[string]string{
+		"hostId":   host.ID.Hex(),
+		"hostName": host.Name,
+		"note":     note,
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to notify host of verification decision", "hostId", host.ID.Hex(), "error", err)
+	}
+
+	return host, nil
+}
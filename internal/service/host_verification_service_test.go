@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSubmitForReview(t *testing.T) {
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	doc := domain.HostVerificationDocument{Type: domain.HostDocumentFarmLicense, ImageID: primitive.NewObjectID()}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		service := NewHostVerificationService(mockRepo, nil, nil)
+		host := &domain.Host{ID: primitive.NewObjectID(), UserID: userID, Status: domain.HostStatusPending, VerificationDocuments: []domain.HostVerificationDocument{doc}}
+
+		mockRepo.On("GetByUserID", ctx, userID.Hex()).Return(host, nil)
+		mockRepo.On("UpdateStatus", ctx, host.ID.Hex(), host.Status, mock.MatchedBy(func(e domain.HostStatusHistory) bool {
+			return e.Status == domain.HostStatusPending && e.UpdatedBy == userID
+		}), mock.AnythingOfType("primitive.M")).Return(nil)
+
+		result, err := service.SubmitForReview(ctx, userID.Hex())
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result.VerificationSubmittedAt)
+		assert.Len(t, result.StatusHistory, 1)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("No Documents", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		service := NewHostVerificationService(mockRepo, nil, nil)
+		host := &domain.Host{ID: primitive.NewObjectID(), UserID: userID, Status: domain.HostStatusEditing}
+
+		mockRepo.On("GetByUserID", ctx, userID.Hex()).Return(host, nil)
+
+		_, err := service.SubmitForReview(ctx, userID.Hex())
+
+		assert.ErrorIs(t, err, ErrVerificationDocumentsRequired)
+		mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Already Submitted", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		service := NewHostVerificationService(mockRepo, nil, nil)
+		submittedAt := time.Now()
+		host := &domain.Host{ID: primitive.NewObjectID(), UserID: userID, Status: domain.HostStatusPending, VerificationSubmittedAt: &submittedAt, VerificationDocuments: []domain.HostVerificationDocument{doc}}
+
+		mockRepo.On("GetByUserID", ctx, userID.Hex()).Return(host, nil)
+
+		_, err := service.SubmitForReview(ctx, userID.Hex())
+
+		assert.ErrorIs(t, err, ErrVerificationAlreadySubmitted)
+	})
+
+	t.Run("Active Host", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		service := NewHostVerificationService(mockRepo, nil, nil)
+		host := &domain.Host{ID: primitive.NewObjectID(), UserID: userID, Status: domain.HostStatusActive, VerificationDocuments: []domain.HostVerificationDocument{doc}}
+
+		mockRepo.On("GetByUserID", ctx, userID.Hex()).Return(host, nil)
+
+		_, err := service.SubmitForReview(ctx, userID.Hex())
+
+		assert.ErrorIs(t, err, ErrVerificationNotAllowed)
+	})
+}
+
+func TestReviewHost(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	reviewerID := primitive.NewObjectID()
+	submittedAt := time.Now().Add(-time.Hour)
+	pendingHost := func() *domain.Host {
+		return &domain.Host{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Name: "Green Farm", Status: domain.HostStatusPending, VerificationSubmittedAt: &submittedAt}
+	}
+
+	t.Run("Approve", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		mockNotif := new(MockNotificationService)
+		service := NewHostVerificationService(mockRepo, nil, mockNotif)
+		host := pendingHost()
+
+		mockRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+		mockRepo.On("UpdateStatus", ctx, host.ID.Hex(), host.Status, mock.MatchedBy(func(e domain.HostStatusHistory) bool {
+			return e.Status == domain.HostStatusActive && e.UpdatedBy == reviewerID
+		}), mock.MatchedBy(func(fields bson.M) bool {
+			return fields["verified"] == true && fields["verifiedAt"] != nil
+		})).Return(nil)
+		mockNotif.On("SendNotification", ctx, host.UserID.Hex(), domain.NotificationTypeHostVerificationApproved, mock.Anything).Return(nil)
+
+		result, err := service.Review(ctx, reviewerID.Hex(), host.ID.Hex(), domain.HostReviewApprove, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.HostStatusActive, result.Status)
+		assert.True(t, result.Verified)
+		assert.NotNil(t, result.VerifiedAt)
+		mockRepo.AssertExpectations(t)
+		mockNotif.AssertExpectations(t)
+	})
+
+	t.Run("Request Changes", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		mockNotif := new(MockNotificationService)
+		service := NewHostVerificationService(mockRepo, nil, mockNotif)
+		host := pendingHost()
+		note := "ID card photo is blurry"
+
+		mockRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+		mockRepo.On("UpdateStatus", ctx, host.ID.Hex(), host.Status, mock.MatchedBy(func(e domain.HostStatusHistory) bool {
+			return e.Status == domain.HostStatusEditing && e.StatusNote == note
+		}), bson.M{}).Return(nil)
+		mockNotif.On("SendNotification", ctx, host.UserID.Hex(), domain.NotificationTypeHostVerificationChangesRequested, mock.MatchedBy(func(data map[string]string) bool {
+			return data["note"] == note && data["hostName"] == "Green Farm"
+		})).Return(nil)
+
+		result, err := service.Review(ctx, reviewerID.Hex(), host.ID.Hex(), domain.HostReviewRequestChanges, note)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.HostStatusEditing, result.Status)
+		assert.False(t, result.Verified)
+		mockNotif.AssertExpectations(t)
+	})
+
+	t.Run("Not Pending", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		service := NewHostVerificationService(mockRepo, nil, nil)
+		host := pendingHost()
+		host.Status = domain.HostStatusActive
+
+		mockRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+
+		_, err := service.Review(ctx, reviewerID.Hex(), host.ID.Hex(), domain.HostReviewReject, "duplicate listing")
+
+		assert.ErrorIs(t, err, ErrHostNotPendingReview)
+	})
+}
+
+func TestGetHostDocumentContent_NotOwnedByHost(t *testing.T) {
+	mockRepo := new(MockHostRepository)
+	service := NewHostVerificationService(mockRepo, new(MockImageService), nil)
+
+	ctx := context.Background()
+	host := &domain.Host{ID: primitive.NewObjectID(), VerificationDocuments: []domain.HostVerificationDocument{{ImageID: primitive.NewObjectID()}}}
+	mockRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+
+	_, err := service.GetDocumentContent(ctx, host.ID.Hex(), primitive.NewObjectID().Hex())
+
+	assert.ErrorIs(t, err, ErrImageNotFound)
+}
@@ -1,10 +1,14 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/http"
+	"time"
 
 	"cloud.google.com/go/storage"
 	vision "cloud.google.com/go/vision/v2/apiv1"
@@ -13,17 +17,41 @@ import (
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/repository"
 	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
 	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type ImageService interface {
 	UploadImage(ctx context.Context, file multipart.File, header *multipart.FileHeader, userID string) (*domain.Image, error)
+	UploadPrivateImage(ctx context.Context, file multipart.File, header *multipart.FileHeader, userID string) (*domain.Image, error)
 	GetImage(ctx context.Context, id string) (*domain.Image, error)
 	UpdateImageStatus(ctx context.Context, id string, status domain.ImageStatus) error
 	GetImageContent(ctx context.Context, id string) (io.ReadCloser, error)
+	PurgeScheduledImages(ctx context.Context) (int, error)
 }
 
+var (
+	// ErrPrivateImage 私有圖片不可審核為公開
+	ErrPrivateImage        = errcode.BadRequest("PRIVATE_IMAGE", "private images cannot be published")
+	ErrUnsupportedFileType = errcode.Unprocessable("UNSUPPORTED_FILE_TYPE", "only JPEG, PNG and PDF files are allowed")
+	ErrFileTooLarge        = errcode.Unprocessable("FILE_TOO_LARGE", "file is too large")
+)
+
+// maxPrivateUploadSize 是私有檔案 (驗證文件) 的大小上限
+const maxPrivateUploadSize = 10 << 20
+
+// privateUploadTypes 是私有檔案允許的類型 (依內容判斷) 與對應的副檔名
+var privateUploadTypes = map[string]string{
+	"image/jpeg":      "jpg",
+	"image/png":       "png",
+	"application/pdf": "pdf",
+}
+
+// imagePurgeBatchSize 是每次清除排定刪除圖片的最大數量
+const imagePurgeBatchSize = 100
+
 type imageService struct {
 	repo          repository.ImageRepository
 	storageClient *storage.Client
@@ -50,12 +78,8 @@ func (s *imageService) UploadImage(ctx context.Context, file multipart.File, hea
 	filename := fmt.Sprintf("%s/%s.%s", userID, uuid.New().String(), ext)
 
 	// 2. Upload to Private Bucket initially
-	wc := s.storageClient.Bucket(s.privateBucket).Object(filename).NewWriter(ctx)
-	if _, err := io.Copy(wc, file); err != nil {
-		return nil, fmt.Errorf("failed to upload to GCS: %w", err)
-	}
-	if err := wc.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close GCS writer: %w", err)
+	if err := s.writeObject(ctx, s.privateBucket, filename, "", file); err != nil {
+		return nil, err
 	}
 
 	// 3. Analyze with Vision API (from Private Bucket)
@@ -136,6 +160,50 @@ func (s *imageService) UploadImage(ctx context.Context, file multipart.File, hea
 	return image, nil
 }
 
+// UploadPrivateImage 上傳只供擁有者與管理員查看的圖片 (例如驗證文件)。
+// 檔案留在私有 bucket，不送 Vision API 也不進入圖片審核佇列。
+// 只接受 JPEG、PNG 與 PDF，類型依檔案內容判斷而非上傳者提供的 Content-Type。
+func (s *imageService) UploadPrivateImage(ctx context.Context, file multipart.File, header *multipart.FileHeader, userID string) (*domain.Image, error) {
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id %q: %w", userID, err)
+	}
+	if header.Size > maxPrivateUploadSize {
+		return nil, ErrFileTooLarge.WithMeta("maxBytes", maxPrivateUploadSize)
+	}
+
+	// http.DetectContentType 最多參考前 512 bytes
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	ext, ok := privateUploadTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedFileType.WithMeta("contentType", contentType)
+	}
+
+	filename := fmt.Sprintf("%s/private/%s.%s", userID, uuid.New().String(), ext)
+	body := io.MultiReader(bytes.NewReader(head), file)
+	if err := s.writeObject(ctx, s.privateBucket, filename, contentType, body); err != nil {
+		return nil, err
+	}
+
+	image := &domain.Image{
+		UserID:      userObjID,
+		GCSPath:     filename,
+		ContentType: contentType,
+		Status:      domain.ImageStatusPending,
+		Private:     true,
+	}
+	if err := s.repo.Create(ctx, image); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
 func (s *imageService) determineImageStatus(annotations *visionpb.SafeSearchAnnotation) domain.ImageStatus {
 	if annotations == nil {
 		return domain.ImageStatusPending
@@ -177,18 +245,25 @@ func (s *imageService) determineImageStatus(annotations *visionpb.SafeSearchAnno
 }
 
 func (s *imageService) GetImage(ctx context.Context, id string) (*domain.Image, error) {
-	return s.repo.GetByID(ctx, id)
+	image, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrImageNotFound)
+	}
+	return image, nil
 }
 
 func (s *imageService) UpdateImageStatus(ctx context.Context, id string, newStatus domain.ImageStatus) error {
 	image, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return notFound(err, ErrImageNotFound)
 	}
 
 	if image.Status == newStatus {
 		return nil
 	}
+	if image.Private && newStatus == domain.ImageStatusApproved {
+		return ErrPrivateImage
+	}
 
 	// Move file logic
 	// If moving TO Approved -> Private to Public
@@ -212,12 +287,15 @@ func (s *imageService) UpdateImageStatus(ctx context.Context, id string, newStat
 func (s *imageService) GetImageContent(ctx context.Context, id string) (io.ReadCloser, error) {
 	image, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, ErrImageNotFound)
+	}
+	if image.DeleteAfter != nil {
+		return nil, ErrImageNotFound.WithCause(mongo.ErrNoDocuments)
 	}
 
-	bucket := s.privateBucket
-	if image.Status == domain.ImageStatusApproved {
-		bucket = s.publicBucket
+	bucket := s.publicBucket
+	if image.InPrivateBucket() {
+		bucket = s.privateBucket
 	}
 
 	rc, err := s.storageClient.Bucket(bucket).Object(image.GCSPath).NewReader(ctx)
@@ -227,6 +305,53 @@ func (s *imageService) GetImageContent(ctx context.Context, id string) (io.ReadC
 	return rc, nil
 }
 
+// PurgeScheduledImages deletes the GCS objects and records of images whose
+// deletion date has passed. Returns the number of images removed.
+func (s *imageService) PurgeScheduledImages(ctx context.Context) (int, error) {
+	if s.storageClient == nil {
+		return 0, errors.New("storage client not configured")
+	}
+
+	images, err := s.repo.ListDueForDeletion(ctx, time.Now(), imagePurgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, image := range images {
+		// The object lives in the public bucket only while approved
+		bucket := s.privateBucket
+		if image.Status == domain.ImageStatusApproved {
+			bucket = s.publicBucket
+		}
+		err := s.storageClient.Bucket(bucket).Object(image.GCSPath).Delete(ctx)
+		if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			logger.ErrorContext(ctx, "Failed to delete image from GCS", "imageId", image.ID.Hex(), "error", err)
+			continue
+		}
+		if err := s.repo.Delete(ctx, image.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// Helper to upload a file to a bucket
+func (s *imageService) writeObject(ctx context.Context, bucket, object, contentType string, file io.Reader) error {
+	wc := s.storageClient.Bucket(bucket).Object(object).NewWriter(ctx)
+	if contentType != "" {
+		wc.ContentType = contentType
+	}
+	if _, err := io.Copy(wc, file); err != nil {
+		return fmt.Errorf("failed to upload to GCS: %w", err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("failed to close GCS writer: %w", err)
+	}
+	return nil
+}
+
 // Helper to move file between buckets
 func (s *imageService) moveFile(ctx context.Context, srcBucket, srcObject, dstBucket, dstObject string) error {
 	src := s.storageClient.Bucket(srcBucket).Object(srcObject)
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"mime/multipart"
 	"testing"
 
 	"cloud.google.com/go/vision/v2/apiv1/visionpb"
@@ -175,3 +178,36 @@ func TestDetermineImageStatus_EmptyConfig(t *testing.T) {
 		})
 	}
 }
+
+// memoryFile 以記憶體內容實作 multipart.File
+type memoryFile struct {
+	*bytes.Reader
+}
+
+func (memoryFile) Close() error { return nil }
+
+func TestUploadPrivateImage_Rejected(t *testing.T) {
+	ctx := context.Background()
+	svc := &imageService{cfg: &config.Config{}}
+	userID := "507f1f77bcf86cd799439011"
+	upload := func(content []byte, size int64, userID string) error {
+		file := memoryFile{bytes.NewReader(content)}
+		_, err := svc.UploadPrivateImage(ctx, file, &multipart.FileHeader{Filename: "doc.jpg", Size: size}, userID)
+		return err
+	}
+
+	t.Run("Unsupported Type", func(t *testing.T) {
+		content := []byte("<html><body>not a document</body></html>")
+		assert.ErrorIs(t, upload(content, int64(len(content)), userID), ErrUnsupportedFileType)
+	})
+
+	t.Run("Too Large", func(t *testing.T) {
+		content := []byte("%PDF-1.7")
+		assert.ErrorIs(t, upload(content, maxPrivateUploadSize+1, userID), ErrFileTooLarge)
+	})
+
+	t.Run("Invalid User ID", func(t *testing.T) {
+		content := []byte("%PDF-1.7")
+		assert.Error(t, upload(content, int64(len(content)), "not-an-id"))
+	})
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/email"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrAccountLocked = errcode.TooManyRequests("ACCOUNT_LOCKED", "account temporarily locked due to too many failed login attempts")
+
+// AccountLockedError 表示帳號因登入失敗次數過多而暫時鎖定，RetryAfter 為剩餘的鎖定時間
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("account temporarily locked, retry after %s", e.RetryAfter)
+}
+
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+const (
+	defaultMaxFailedLogins    = 10
+	defaultMaxIPFailedLogins  = 100
+	defaultLoginFailureWindow = 15 * time.Minute
+	defaultLockoutDuration    = 15 * time.Minute
+	defaultLoginDelayAfter    = 3
+	defaultMFAMaxAttempts     = 5
+	// maxLoginDelay 是逐步加倍等待時間的上限
+	maxLoginDelay = 30 * time.Second
+)
+
+// LoginProtectionService 以帳號與 IP 記錄登入失敗次數，提供逐步延遲與暫時鎖定，
+// 並限制每個 MFA challenge 可嘗試驗證碼的次數。
+// 計數存放在 MongoDB，多個實例間共用。
+type LoginProtectionService interface {
+	Check(ctx context.Context, email, ip string) error
+	RecordFailure(ctx context.Context, email, ip string) error
+	RecordSuccess(ctx context.Context, user *domain.User) error
+	Unlock(ctx context.Context, userID string) error
+	CheckChallenge(ctx context.Context, challengeID string, ttl time.Duration) error
+	ConsumeChallenge(ctx context.Context, challengeID string, ttl time.Duration) error
+}
+
+type loginProtectionService struct {
+	attemptRepo     repository.LoginAttemptRepository
+	userRepo        repository.UserRepository
+	emailSender     email.EmailSender
+	maxFailures     int
+	maxIPFailures   int
+	window          time.Duration
+	lockoutDuration time.Duration
+	delayAfter      int
+	mfaMaxAttempts  int
+}
+
+func NewLoginProtectionService(attemptRepo repository.LoginAttemptRepository, userRepo repository.UserRepository, emailSender email.EmailSender, cfg *config.Config) LoginProtectionService {
+	maxFailures := cfg.Auth.MaxFailedLogins
+	if maxFailures <= 0 {
+		maxFailures = defaultMaxFailedLogins
+	}
+	maxIPFailures := cfg.Auth.MaxIPFailedLogins
+	if maxIPFailures <= 0 {
+		maxIPFailures = defaultMaxIPFailedLogins
+	}
+	window := cfg.Auth.LoginFailureWindow
+	if window <= 0 {
+		window = defaultLoginFailureWindow
+	}
+	lockoutDuration := cfg.Auth.LockoutDuration
+	if lockoutDuration <= 0 {
+		lockoutDuration = defaultLockoutDuration
+	}
+	delayAfter := cfg.Auth.LoginDelayAfter
+	if delayAfter <= 0 {
+		delayAfter = defaultLoginDelayAfter
+	}
+	mfaMaxAttempts := cfg.Auth.MFAMaxAttempts
+	if mfaMaxAttempts <= 0 {
+		mfaMaxAttempts = defaultMFAMaxAttempts
+	}
+
+	return &loginProtectionService{
+		attemptRepo:     attemptRepo,
+		userRepo:        userRepo,
+		emailSender:     emailSender,
+		maxFailures:     maxFailures,
+		maxIPFailures:   maxIPFailures,
+		window:          window,
+		lockoutDuration: lockoutDuration,
+		delayAfter:      delayAfter,
+		mfaMaxAttempts:  mfaMaxAttempts,
+	}
+}
+
+// Check 在比對密碼前呼叫。
+// 帳號鎖定時回傳 *AccountLockedError；IP 被封鎖或尚在逐步延遲期間時回傳 *RateLimitedError。
+func (s *loginProtectionService) Check(ctx context.Context, email, ip string) error {
+	now := time.Now()
+
+	if ip != "" {
+		attempt, err := s.getAttempt(ctx, ipAttemptKey(ip))
+		if err != nil {
+			return err
+		}
+		if attempt != nil && attempt.IsLocked(now) {
+			return &RateLimitedError{RetryAfter: attempt.LockedUntil.Sub(now)}
+		}
+	}
+
+	attempt, err := s.getAttempt(ctx, emailAttemptKey(email))
+	if err != nil || attempt == nil {
+		return err
+	}
+	if attempt.IsLocked(now) {
+		return &AccountLockedError{RetryAfter: attempt.LockedUntil.Sub(now)}
+	}
+	if attempt.WindowStart.Add(s.window).Before(now) {
+		return nil
+	}
+	if wait := attempt.LastFailureAt.Add(s.delayFor(attempt.Failures)).Sub(now); wait > 0 {
+		return &RateLimitedError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// RecordFailure 記錄一次登入失敗，達到門檻時鎖定帳號 (或 IP)。
+// 不存在的 email 同樣會被計數，避免透過回應差異探測帳號是否存在。
+func (s *loginProtectionService) RecordFailure(ctx context.Context, email, ip string) error {
+	if ip != "" {
+		attempt, err := s.attemptRepo.RecordFailure(ctx, ipAttemptKey(ip), s.window)
+		if err != nil {
+			return err
+		}
+		if attempt.Failures >= s.maxIPFailures && !attempt.IsLocked(time.Now()) {
+			if err := s.attemptRepo.Lock(ctx, attempt.Key, time.Now().Add(s.lockoutDuration)); err != nil {
+				return err
+			}
+			logger.Warn("IP blocked after too many failed logins", "ip", ip, "failures", attempt.Failures)
+		}
+	}
+
+	attempt, err := s.attemptRepo.RecordFailure(ctx, emailAttemptKey(email), s.window)
+	if err != nil {
+		return err
+	}
+	if attempt.Failures < s.maxFailures || attempt.IsLocked(time.Now()) {
+		return nil
+	}
+
+	lockedUntil := time.Now().Add(s.lockoutDuration)
+	if err := s.attemptRepo.Lock(ctx, attempt.Key, lockedUntil); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		return err
+	}
+	if err := s.userRepo.Update(ctx, user.ID, bson.M{"lockedUntil": lockedUntil}); err != nil {
+		return err
+	}
+
+	logger.Warn("Account locked after too many failed logins", "userId", user.ID, "failures", attempt.Failures)
+	s.sendLockoutNotice(messageLocale(ctx, user), user, lockedUntil)
+	return nil
+}
+
+// RecordSuccess 在登入成功後清除該帳號的失敗紀錄
+func (s *loginProtectionService) RecordSuccess(ctx context.Context, user *domain.User) error {
+	if err := s.attemptRepo.Delete(ctx, emailAttemptKey(user.Email)); err != nil {
+		return err
+	}
+	if user.LockedUntil != nil {
+		if err := s.userRepo.Update(ctx, user.ID, bson.M{"lockedUntil": nil}); err != nil {
+			return err
+		}
+		user.LockedUntil = nil
+	}
+	return nil
+}
+
+// Unlock 由管理員解除帳號鎖定並清除失敗紀錄
+func (s *loginProtectionService) Unlock(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.attemptRepo.Delete(ctx, emailAttemptKey(user.Email)); err != nil {
+		return err
+	}
+	if err := s.userRepo.Update(ctx, userID, bson.M{"lockedUntil": nil}); err != nil {
+		return err
+	}
+
+	logger.Info("Account unlocked", "userId", userID)
+	return nil
+}
+
+// CheckChallenge 在比對 MFA 驗證碼前呼叫，計入一次該 challenge 的嘗試。
+// challenge 已使用過或嘗試次數用盡時回傳 ErrInvalidMFAChallenge，使用者需重新登入。
+// 計數在比對前以原子操作累加，同一個 challenge 的並行請求也無法超過上限。
+// ttl 需不小於 challenge 的有效時間，避免計數在 challenge 過期前被重設。
+func (s *loginProtectionService) CheckChallenge(ctx context.Context, challengeID string, ttl time.Duration) error {
+	attempt, err := s.attemptRepo.RecordFailure(ctx, challengeAttemptKey(challengeID), ttl)
+	if err != nil {
+		return err
+	}
+	if attempt.IsLocked(time.Now()) || attempt.Failures > s.mfaMaxAttempts {
+		return ErrInvalidMFAChallenge
+	}
+	return nil
+}
+
+// ConsumeChallenge 在兩步驟驗證成功後將 challenge 標記為已使用，之後無法再以同一個 challenge 登入
+func (s *loginProtectionService) ConsumeChallenge(ctx context.Context, challengeID string, ttl time.Duration) error {
+	return s.attemptRepo.Lock(ctx, challengeAttemptKey(challengeID), time.Now().Add(ttl))
+}
+
+// delayFor 回傳失敗 failures 次後下一次嘗試前需等待的時間：
+// 超過 delayAfter 次後從 1 秒開始每次加倍，上限為 maxLoginDelay
+func (s *loginProtectionService) delayFor(failures int) time.Duration {
+	if failures < s.delayAfter {
+		return 0
+	}
+	// 限制位移次數避免溢位
+	exp := min(failures-s.delayAfter, 10)
+	return min(time.Second<<exp, maxLoginDelay)
+}
+
+func (s *loginProtectionService) getAttempt(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	attempt, err := s.attemptRepo.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return attempt, nil
+}
+
+func (s *loginProtectionService) sendLockoutNotice(locale i18n.Locale, user *domain.User, lockedUntil time.Time) {
+	subject := i18n.T(locale, "email.accountLocked.subject")
+	body := i18n.T(locale, "email.accountLocked.body",
+		"name", html.EscapeString(user.Name),
+		"until", lockedUntil.UTC().Format("2006-01-02 15:04"),
+	)
+
+	go func() {
+		if err := s.emailSender.Send(user.Email, user.Name, subject, body); err != nil {
+			logger.Error("Failed to send account lockout email", "userId", user.ID, "error", err)
+		}
+	}()
+}
+
+func emailAttemptKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+func challengeAttemptKey(challengeID string) string {
+	return "mfa:" + challengeID
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MockLoginAttemptRepository 是一個用於測試的 LoginAttemptRepository mock
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginAttemptRepository) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) RecordFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempt, error) {
+	args := m.Called(ctx, key, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LoginAttempt), args.Error(1)
+}
+
+func (m *MockLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	args := m.Called(ctx, key, until)
+	return args.Error(0)
+}
+
+func (m *MockLoginAttemptRepository) Delete(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+// MockLoginProtectionService 是一個用於測試的 LoginProtectionService mock
+type MockLoginProtectionService struct {
+	mock.Mock
+}
+
+func (m *MockLoginProtectionService) Check(ctx context.Context, email, ip string) error {
+	args := m.Called(ctx, email, ip)
+	return args.Error(0)
+}
+
+func (m *MockLoginProtectionService) RecordFailure(ctx context.Context, email, ip string) error {
+	args := m.Called(ctx, email, ip)
+	return args.Error(0)
+}
+
+func (m *MockLoginProtectionService) RecordSuccess(ctx context.Context, user *domain.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockLoginProtectionService) Unlock(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockLoginProtectionService) CheckChallenge(ctx context.Context, challengeID string, ttl time.Duration) error {
+	args := m.Called(ctx, challengeID, ttl)
+	return args.Error(0)
+}
+
+func (m *MockLoginProtectionService) ConsumeChallenge(ctx context.Context, challengeID string, ttl time.Duration) error {
+	args := m.Called(ctx, challengeID, ttl)
+	return args.Error(0)
+}
+
+func newTestLoginProtectionService(attempts *MockLoginAttemptRepository, userRepo *mockUserRepository, sender *MockEmailSender) LoginProtectionService {
+	return NewLoginProtectionService(attempts, userRepo, sender, &config.Config{})
+}
+
+func TestLoginProtectionCheck(t *testing.T) {
+	ctx := context.Background()
+	emailKey := "email:test@example.com"
+	ipKey := "ip:1.2.3.4"
+
+	t.Run("No Previous Failures", func(t *testing.T) {
+		mockAttempts := new(MockLoginAttemptRepository)
+		svc := newTestLoginProtectionService(mockAttempts, new(mockUserRepository), new(MockEmailSender))
+
+		mockAttempts.On("Get", ctx, ipKey).Return(nil, mongo.ErrNoDocuments)
+		mockAttempts.On("Get", ctx, emailKey).Return(nil, mongo.ErrNoDocuments)
+
+		assert.NoError(t, svc.Check(ctx, "Test@Example.com", "1.2.3.4"))
+	})
+
+	t.Run("Locked Account", func(t *testing.T) {
+		mockAttempts := new(MockLoginAttemptRepository)
+		svc := newTestLoginProtectionService(mockAttempts, new(mockUserRepository), new(MockEmailSender))
+
+		until := time.Now().Add(10 * time.Minute)
+		mockAttempts.On("Get", ctx, ipKey).Return(nil, mongo.ErrNoDocuments)
+		mockAttempts.On("Get", ctx, emailKey).Return(&domain.LoginAttempt{Key: emailKey, Failures: 10, LockedUntil: &until}, nil)
+
+		err := svc.Check(ctx, "test@example.com", "1.2.3.4")
+
+		var locked *AccountLockedError
+		assert.ErrorAs(t, err, &locked)
+		assert.ErrorIs(t, err, ErrAccountLocked)
+		assert.InDelta(t, (10 * time.Minute).Seconds(), locked.RetryAfter.Seconds(), 1)
+	})
+
+	t.Run("Blocked IP", func(t *testing.T) {
+		mockAttempts := new(MockLoginAttemptRepository)
+		svc := newTestLoginProtectionService(mockAttempts, new(mockUserRepository), new(MockEmailSender))
+
+		until := time.Now().Add(time.Minute)
+		mockAttempts.On("Get", ctx, ipKey).Return(&domain.LoginAttempt{Key: ipKey, LockedUntil: &until}, nil)
+
+		err := svc.Check(ctx, "test@example.com", "1.2.3.4")
+
+		assert.ErrorIs(t, err, ErrRateLimited)
+		mockAttempts.AssertNotCalled(t, "Get", ctx, emailKey)
+	})
+
+	t.Run("Progressive Delay", func(t *testing.T) {
+		mockAttempts := new(MockLoginAttemptRepository)
+		svc := newTestLoginProtectionService(mockAttempts, new(mockUserRepository), new(MockEmailSender))
+
+		// 失敗 5 次：需等待 4 秒
+		now := time.Now()
+		mockAttempts.On("Get", ctx, emailKey).Return(&domain.LoginAttempt{Key: emailKey, Failures: 5, WindowStart: now.Add(-time.Minute), LastFailureAt: now}, nil)
+
+		err := svc.Check(ctx, "test@example.com", "")
+
+		var rateLimited *RateLimitedError
+		assert.ErrorAs(t, err, &rateLimited)
+		assert.InDelta(t, 4, rateLimited.RetryAfter.Seconds(), 0.5)
+	})
+
+	t.Run("Delay Elapsed", func(t *testing.T) {
+		mockAttempts := new(MockLoginAttemptRepository)
+		svc := newTestLoginProtectionService(mockAttempts, new(mockUserRepository), new(MockEmailSender))
+
+		now := time.Now()
+		mockAttempts.On("Get", ctx, emailKey).Return(&domain.LoginAttempt{Key: emailKey, Failures: 3, WindowStart: now.Add(-time.Minute), LastFailureAt: now.Add(-2 * time.Second)}, nil)
+
+		assert.NoError(t, svc.Check(ctx, "test@example.com", ""))
+	})
+}
+
+func TestLoginProtectionRecordFailure(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	emailKey := "email:test@example.com"
+	window := defaultLoginFailureWindow
+
+	t.Run("Below Threshold", func(t *testing.T) {
+		mockAttempts := new(MockLoginAttemptRepository)
+		svc := newTestLoginProtectionService(mockAttempts, new(mockUserRepository), new(MockEmailSender))
+
+		mockAttempts.On("RecordFailure", ctx, "ip:1.2.3.4", window).Return(&domain.LoginAttempt{Key: "ip:1.2.3.4", Failures: 1}, nil)
+		mockAttempts.On("RecordFailure", ctx, emailKey, window).Return(&domain.LoginAttempt{Key: emailKey, Failures: 2}, nil)
+
+		assert.NoError(t, svc.RecordFailure(ctx, "test@example.com", "1.2.3.4"))
+		mockAttempts.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Locks Account And Notifies User", func(t *testing.T) {
+		mockAttempts := new(MockLoginAttemptRepository)
+		mockRepo := new(mockUserRepository)
+		mockSender := new(MockEmailSender)
+		svc := newTestLoginProtectionService(mockAttempts, mockRepo, mockSender)
+
+		user := &domain.User{ID: "user-1", Name: "Test", Email: "test@example.com"}
+		mockAttempts.On("RecordFailure", ctx, emailKey, window).Return(&domain.LoginAttempt{Key: emailKey, Failures: defaultMaxFailedLogins}, nil)
+		mockAttempts.On("Lock", ctx, emailKey, mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("GetByEmail", ctx, "test@example.com").Return(user, nil)
+		mockRepo.On("Update", ctx, "user-1", mock.MatchedBy(func(payload bson.M) bool {
+			_, ok := payload["lockedUntil"].(time.Time)
+			return ok
+		})).Return(nil)
+
+		sent := make(chan struct{})
+		mockSender.On("Send", "test@example.com", "Test", mock.Anything, mock.Anything).Return(nil).Run(func(mock.Arguments) {
+			close(sent)
+		})
+
+		assert.NoError(t, svc.RecordFailure(ctx, "test@example.com", ""))
+
+		select {
+		case <-sent:
+		case <-time.After(time.Second):
+			t.Fatal("lockout email was not sent")
+		}
+		mockAttempts.AssertExpectations(t)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Email Is Still Locked", func(t *testing.T) {
+		mockAttempts := new(MockLoginAttemptRepository)
+		mockRepo := new(mockUserRepository)
+		svc := newTestLoginProtectionService(mockAttempts, mockRepo, new(MockEmailSender))
+
+		mockAttempts.On("RecordFailure", ctx, emailKey, window).Return(&domain.LoginAttempt{Key: emailKey, Failures: defaultMaxFailedLogins}, nil)
+		mockAttempts.On("Lock", ctx, emailKey, mock.AnythingOfType("time.Time")).Return(nil)
+		mockRepo.On("GetByEmail", ctx, "test@example.com").Return(nil, mongo.ErrNoDocuments)
+
+		assert.NoError(t, svc.RecordFailure(ctx, "test@example.com", ""))
+		mockAttempts.AssertExpectations(t)
+	})
+}
+
+func TestLoginProtectionUnlock(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+
+	mockAttempts := new(MockLoginAttemptRepository)
+	mockRepo := new(mockUserRepository)
+	svc := newTestLoginProtectionService(mockAttempts, mockRepo, new(MockEmailSender))
+
+	mockRepo.On("GetByID", ctx, "user-1").Return(&domain.User{ID: "user-1", Email: "Test@example.com"}, nil)
+	mockAttempts.On("Delete", ctx, "email:test@example.com").Return(nil)
+	mockRepo.On("Update", ctx, "user-1", bson.M{"lockedUntil": nil}).Return(nil)
+
+	assert.NoError(t, svc.Unlock(ctx, "user-1"))
+	mockAttempts.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLoginProtectionChallenge(t *testing.T) {
+	ctx := context.Background()
+	key := "mfa:challenge-1"
+	ttl := 5 * time.Minute
+
+	t.Run("Within Attempt Limit", func(t *testing.T) {
+		mockAttempts := new(MockLoginAttemptRepository)
+		svc := newTestLoginProtectionService(mockAttempts, new(mockUserRepository), new(MockEmailSender))
+
+		mockAttempts.On("RecordFailure", ctx, key, ttl).Return(&domain.LoginAttempt{Key: key, Failures: defaultMFAMaxAttempts}, nil)
+
+		assert.NoError(t, svc.CheckChallenge(ctx, "challenge-1", ttl))
+	})
+
+	t.Run("Attempts Exhausted", func(t *testing.T) {
+		mockAttempts := new(MockLoginAttemptRepository)
+		svc := newTestLoginProtectionService(mockAttempts, new(mockUserRepository), new(MockEmailSender))
+
+		mockAttempts.On("RecordFailure", ctx, key, ttl).Return(&domain.LoginAttempt{Key: key, Failures: defaultMFAMaxAttempts + 1}, nil)
+
+		assert.ErrorIs(t, svc.CheckChallenge(ctx, "challenge-1", ttl), ErrInvalidMFAChallenge)
+	})
+
+	t.Run("Consumed Challenge", func(t *testing.T) {
+		mockAttempts := new(MockLoginAttemptRepository)
+		svc := newTestLoginProtectionService(mockAttempts, new(mockUserRepository), new(MockEmailSender))
+
+		until := time.Now().Add(ttl)
+		mockAttempts.On("Lock", ctx, key, mock.AnythingOfType("time.Time")).Return(nil)
+		mockAttempts.On("RecordFailure", ctx, key, ttl).Return(&domain.LoginAttempt{Key: key, Failures: 2, LockedUntil: &until}, nil)
+
+		assert.NoError(t, svc.ConsumeChallenge(ctx, "challenge-1", ttl))
+		assert.ErrorIs(t, svc.CheckChallenge(ctx, "challenge-1", ttl), ErrInvalidMFAChallenge)
+		mockAttempts.AssertExpectations(t)
+	})
+}
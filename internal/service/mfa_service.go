@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"github.com/taiwanstay/taiwanstay-back/pkg/totp"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrMFAAlreadyEnabled     = errcode.Conflict("MFA_ALREADY_ENABLED", "two-factor authentication is already enabled")
+	ErrMFANotEnabled         = errcode.Conflict("MFA_NOT_ENABLED", "two-factor authentication is not enabled")
+	ErrMFAEnrollmentNotFound = errcode.Conflict("MFA_ENROLLMENT_NOT_FOUND", "no pending two-factor enrollment")
+	ErrInvalidMFACode        = errcode.BadRequest("INVALID_MFA_CODE", "invalid two-factor code")
+	ErrInvalidMFAChallenge   = errcode.Unauthorized("INVALID_MFA_CHALLENGE", "invalid or expired two-factor challenge, please login again")
+	ErrAccountSuspended      = errcode.Forbidden("ACCOUNT_SUSPENDED", "account is suspended or deleted")
+)
+
+// mfaChallengeTokenType 用於區分 MFA challenge token 與 access token
+const mfaChallengeTokenType = "mfa_challenge"
+
+const (
+	defaultMFAIssuer       = "TaiwanStay"
+	defaultMFAChallengeTTL = 5 * time.Minute
+	recoveryCodeCount      = 10
+	// totpSkew 允許前後一個時間區間 (30 秒) 的時鐘誤差
+	totpSkew = 1
+)
+
+// LoginResult 是登入第一步的結果。
+// 啟用兩步驟驗證的使用者不會直接取得 token，而是取得 ChallengeToken，
+// 需再以驗證碼呼叫 CompleteLogin。
+type LoginResult struct {
+	User                  *domain.User
+	Tokens                *AuthTokens
+	MFARequired           bool
+	ChallengeToken        string
+	ChallengeExpiresIn    int64 // challenge token 有效秒數
+	MFAEnrollmentRequired bool  // 帳號依政策必須啟用兩步驟驗證
+}
+
+// MFAEnrollment 是開始設定兩步驟驗證時回傳給使用者的資料
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"` // otpauth:// URI，前端可轉為 QR code
+}
+
+// MFAService 處理 TOTP 兩步驟驗證的設定與兩階段登入
+type MFAService interface {
+	StartLogin(ctx context.Context, user *domain.User, meta SessionMeta) (*LoginResult, error)
+	CompleteLogin(ctx context.Context, challengeToken, code string, meta SessionMeta) (*LoginResult, error)
+	BeginEnrollment(ctx context.Context, userID string) (*MFAEnrollment, error)
+	ConfirmEnrollment(ctx context.Context, userID, code string) ([]string, error)
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+	Disable(ctx context.Context, userID, code string) error
+}
+
+type mfaService struct {
+	userRepo        repository.UserRepository
+	sessionService  SessionService
+	loginProtection LoginProtectionService
+	jwtSecret       string
+	issuer          string
+	challengeTTL    time.Duration
+	requireAdminMFA bool
+}
+
+func NewMFAService(userRepo repository.UserRepository, sessionService SessionService, loginProtection LoginProtectionService, cfg *config.Config) MFAService {
+	issuer := cfg.Auth.MFAIssuer
+	if issuer == "" {
+		issuer = defaultMFAIssuer
+	}
+	challengeTTL := cfg.Auth.MFAChallengeTTL
+	if challengeTTL <= 0 {
+		challengeTTL = defaultMFAChallengeTTL
+	}
+
+	return &mfaService{
+		userRepo:        userRepo,
+		sessionService:  sessionService,
+		loginProtection: loginProtection,
+		jwtSecret:       cfg.Server.JWTSecret,
+		issuer:          issuer,
+		challengeTTL:    challengeTTL,
+		requireAdminMFA: cfg.Auth.RequireAdminMFA,
+	}
+}
+
+// StartLogin 在第一步驗證 (密碼或第三方登入) 成功後呼叫。
+// 未啟用兩步驟驗證時直接建立 session，否則回傳 MFA challenge。
+func (s *mfaService) StartLogin(ctx context.Context, user *domain.User, meta SessionMeta) (*LoginResult, error) {
+	// 停權或已刪除的帳號不得登入
+	if user.Status == domain.UserStatusSuspended || user.Status == domain.UserStatusDeleted {
+		return nil, ErrAccountSuspended
+	}
+
+	if user.MFA.Enabled {
+		now := time.Now()
+		claims := jwt.MapClaims{
+			"typ": mfaChallengeTokenType,
+			"sub": user.ID,
+			"jti": uuid.New().String(), // 用於計算嘗試次數，並在使用後作廢
+			"iat": now.Unix(),
+			"exp": now.Add(s.challengeTTL).Unix(),
+		}
+		challenge, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.jwtSecret))
+		if err != nil {
+			return nil, err
+		}
+
+		user.Password = ""
+		return &LoginResult{
+			User:               user,
+			MFARequired:        true,
+			ChallengeToken:     challenge,
+			ChallengeExpiresIn: int64(s.challengeTTL.Seconds()),
+		}, nil
+	}
+
+	meta.MFAVerified = false
+	tokens, err := s.sessionService.CreateSession(ctx, user, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+	return &LoginResult{
+		User:                  user,
+		Tokens:                tokens,
+		MFAEnrollmentRequired: s.requireAdminMFA && user.Role.IsStaff(),
+	}, nil
+}
+
+// CompleteLogin 以 challenge token 與 TOTP 驗證碼 (或復原碼) 完成登入。
+// 每個 challenge 只能成功使用一次，嘗試次數用盡後即失效；
+// 驗證碼錯誤會與密碼錯誤一樣計入帳號與 IP 的登入失敗次數，達到門檻時鎖定帳號。
+func (s *mfaService) CompleteLogin(ctx context.Context, challengeToken, code string, meta SessionMeta) (*LoginResult, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(challengeToken, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(s.jwtSecret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	typ, _ := claims["typ"].(string)
+	userID, _ := claims["sub"].(string)
+	challengeID, _ := claims["jti"].(string)
+	if typ != mfaChallengeTokenType || userID == "" || challengeID == "" {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrInvalidMFAChallenge
+		}
+		return nil, err
+	}
+	if user.Status == domain.UserStatusSuspended || user.Status == domain.UserStatusDeleted || !user.MFA.Enabled {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	// 帳號或 IP 已因失敗次數過多被鎖定時不再比對驗證碼
+	if err := s.loginProtection.Check(ctx, user.Email, meta.IP); err != nil {
+		return nil, err
+	}
+	if err := s.loginProtection.CheckChallenge(ctx, challengeID, s.challengeTTL); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyCode(ctx, user, code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if recordErr := s.loginProtection.RecordFailure(ctx, user.Email, meta.IP); recordErr != nil {
+				logger.Error("Failed to record MFA failure", "userId", user.ID, "error", recordErr)
+			}
+		}
+		return nil, err
+	}
+
+	if err := s.loginProtection.ConsumeChallenge(ctx, challengeID, s.challengeTTL); err != nil {
+		return nil, err
+	}
+	if err := s.loginProtection.RecordSuccess(ctx, user); err != nil {
+		logger.Error("Failed to clear login failures", "userId", user.ID, "error", err)
+	}
+
+	meta.MFAVerified = true
+	tokens, err := s.sessionService.CreateSession(ctx, user, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+	return &LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// BeginEnrollment 產生新的 TOTP 密鑰，需以 ConfirmEnrollment 確認後才會啟用
+func (s *mfaService) BeginEnrollment(ctx context.Context, userID string) (*MFAEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFA.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Update(ctx, userID, bson.M{"mfa.pendingSecret": secret}); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, s.issuer, user.Email),
+	}, nil
+}
+
+// ConfirmEnrollment 以驗證器 App 產生的驗證碼確認設定，成功後啟用兩步驟驗證並回傳復原碼。
+// 復原碼只會在此時顯示一次，資料庫僅保存雜湊值。
+func (s *mfaService) ConfirmEnrollment(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFA.Enabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFA.PendingSecret == "" {
+		return nil, ErrMFAEnrollmentNotFound
+	}
+
+	step, ok := totp.Validate(user.MFA.PendingSecret, normalizeMFACode(code), time.Now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	settings := domain.MFASettings{
+		Enabled:            true,
+		EnabledAt:          &now,
+		Secret:             user.MFA.PendingSecret,
+		RecoveryCodeHashes: hashes,
+		LastUsedStep:       step,
+	}
+	if err := s.userRepo.Update(ctx, userID, bson.M{"mfa": settings}); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Two-factor authentication enabled", "userId", userID)
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes 產生新的一組復原碼，舊的復原碼全部失效
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFA.Enabled {
+		return nil, ErrMFANotEnabled
+	}
+	if err := s.verifyCode(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Update(ctx, userID, bson.M{"mfa.recoveryCodeHashes": hashes}); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable 驗證後停用兩步驟驗證
+func (s *mfaService) Disable(ctx context.Context, userID, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFA.Enabled {
+		return ErrMFANotEnabled
+	}
+	if err := s.verifyCode(ctx, user, code); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Update(ctx, userID, bson.M{"mfa": domain.MFASettings{}}); err != nil {
+		return err
+	}
+
+	logger.Info("Two-factor authentication disabled", "userId", userID)
+	return nil
+}
+
+// verifyCode 驗證 TOTP 驗證碼或復原碼，兩者皆只能使用一次
+func (s *mfaService) verifyCode(ctx context.Context, user *domain.User, code string) error {
+	code = normalizeMFACode(code)
+
+	if len(code) == totp.Digits {
+		step, ok := totp.Validate(user.MFA.Secret, code, time.Now(), totpSkew)
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		fresh, err := s.userRepo.UseMFAStep(ctx, user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	consumed, err := s.userRepo.ConsumeRecoveryCode(ctx, user.ID, hashToken(code))
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidMFACode
+	}
+	logger.Info("Recovery code used", "userId", user.ID)
+	return nil
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCodes 產生復原碼 (格式 xxxxx-xxxxx) 與其雜湊值
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeMFACode 移除使用者輸入時常見的空白與連字號
+func normalizeMFACode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
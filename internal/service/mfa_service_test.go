@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"github.com/taiwanstay/taiwanstay-back/pkg/totp"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var testMFAConfig = &config.Config{Server: config.ServerConfig{JWTSecret: "test-secret"}}
+
+func newTestMFAService(userRepo *mockUserRepository, sessions *MockSessionService) MFAService {
+	return NewMFAService(userRepo, sessions, new(MockLoginProtectionService), testMFAConfig)
+}
+
+func TestStartLogin(t *testing.T) {
+	ctx := context.Background()
+	tokens := &AuthTokens{AccessToken: "access"}
+
+	t.Run("Without MFA Creates Session", func(t *testing.T) {
+		mockSessions := new(MockSessionService)
+		svc := newTestMFAService(new(mockUserRepository), mockSessions)
+
+		user := &domain.User{ID: "user-1", Role: domain.RoleUser}
+		mockSessions.On("CreateSession", ctx, user, SessionMeta{IP: "1.2.3.4"}).Return(tokens, nil)
+
+		result, err := svc.StartLogin(ctx, user, SessionMeta{IP: "1.2.3.4"})
+
+		assert.NoError(t, err)
+		assert.False(t, result.MFARequired)
+		assert.Equal(t, tokens, result.Tokens)
+		assert.False(t, result.MFAEnrollmentRequired)
+	})
+
+	t.Run("Admin Without MFA Must Enroll When Required", func(t *testing.T) {
+		mockSessions := new(MockSessionService)
+		svc := NewMFAService(new(mockUserRepository), mockSessions, new(MockLoginProtectionService), &config.Config{
+			Server: config.ServerConfig{JWTSecret: "test-secret"},
+			Auth:   config.AuthConfig{RequireAdminMFA: true},
+		})
+
+		admin := &domain.User{ID: "admin-1", Role: domain.RoleAdmin}
+		mockSessions.On("CreateSession", ctx, admin, SessionMeta{}).Return(tokens, nil)
+
+		result, err := svc.StartLogin(ctx, admin, SessionMeta{})
+
+		assert.NoError(t, err)
+		assert.True(t, result.MFAEnrollmentRequired)
+	})
+
+	t.Run("With MFA Returns Challenge", func(t *testing.T) {
+		mockSessions := new(MockSessionService)
+		svc := newTestMFAService(new(mockUserRepository), mockSessions)
+
+		user := &domain.User{ID: "user-1", MFA: domain.MFASettings{Enabled: true}}
+
+		result, err := svc.StartLogin(ctx, user, SessionMeta{})
+
+		assert.NoError(t, err)
+		assert.True(t, result.MFARequired)
+		assert.NotEmpty(t, result.ChallengeToken)
+		assert.Nil(t, result.Tokens)
+		mockSessions.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestCompleteLogin(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	newUser := func() *domain.User {
+		return &domain.User{ID: "user-1", Email: "mfa@example.com", MFA: domain.MFASettings{Enabled: true, Secret: secret}}
+	}
+	challengeFor := func(t *testing.T, svc MFAService, user *domain.User) string {
+		result, err := svc.StartLogin(ctx, user, SessionMeta{})
+		require.NoError(t, err)
+		return result.ChallengeToken
+	}
+	// newProtection 允許通過帳號與 challenge 檢查
+	newProtection := func() *MockLoginProtectionService {
+		p := new(MockLoginProtectionService)
+		p.On("Check", ctx, "mfa@example.com", "1.2.3.4").Return(nil)
+		p.On("CheckChallenge", ctx, mock.AnythingOfType("string"), defaultMFAChallengeTTL).Return(nil)
+		return p
+	}
+	meta := SessionMeta{IP: "1.2.3.4"}
+
+	t.Run("Valid TOTP Code", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockSessions := new(MockSessionService)
+		mockProtection := newProtection()
+		svc := NewMFAService(mockRepo, mockSessions, mockProtection, testMFAConfig)
+
+		user := newUser()
+		code, _ := totp.Code(secret, totp.Step(time.Now()))
+		mockRepo.On("GetByID", ctx, "user-1").Return(user, nil)
+		mockRepo.On("UseMFAStep", ctx, "user-1", mock.AnythingOfType("int64")).Return(true, nil)
+		mockProtection.On("ConsumeChallenge", ctx, mock.AnythingOfType("string"), defaultMFAChallengeTTL).Return(nil)
+		mockProtection.On("RecordSuccess", ctx, user).Return(nil)
+		mockSessions.On("CreateSession", ctx, user, SessionMeta{IP: "1.2.3.4", MFAVerified: true}).Return(&AuthTokens{AccessToken: "access"}, nil)
+
+		result, err := svc.CompleteLogin(ctx, challengeFor(t, svc, newUser()), code, meta)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "access", result.Tokens.AccessToken)
+		mockSessions.AssertExpectations(t)
+		mockProtection.AssertExpectations(t)
+	})
+
+	t.Run("Replayed TOTP Code", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockProtection := newProtection()
+		svc := NewMFAService(mockRepo, new(MockSessionService), mockProtection, testMFAConfig)
+
+		code, _ := totp.Code(secret, totp.Step(time.Now()))
+		mockRepo.On("GetByID", ctx, "user-1").Return(newUser(), nil)
+		mockRepo.On("UseMFAStep", ctx, "user-1", mock.AnythingOfType("int64")).Return(false, nil)
+		mockProtection.On("RecordFailure", ctx, "mfa@example.com", "1.2.3.4").Return(nil)
+
+		_, err := svc.CompleteLogin(ctx, challengeFor(t, svc, newUser()), code, meta)
+
+		assert.ErrorIs(t, err, ErrInvalidMFACode)
+		mockProtection.AssertExpectations(t)
+		mockProtection.AssertNotCalled(t, "RecordSuccess", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Recovery Code", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockSessions := new(MockSessionService)
+		mockProtection := newProtection()
+		svc := NewMFAService(mockRepo, mockSessions, mockProtection, testMFAConfig)
+
+		mockRepo.On("GetByID", ctx, "user-1").Return(newUser(), nil)
+		mockRepo.On("ConsumeRecoveryCode", ctx, "user-1", hashToken("abcde12345")).Return(true, nil)
+		mockProtection.On("ConsumeChallenge", ctx, mock.AnythingOfType("string"), defaultMFAChallengeTTL).Return(nil)
+		mockProtection.On("RecordSuccess", ctx, mock.Anything).Return(nil)
+		mockSessions.On("CreateSession", ctx, mock.Anything, SessionMeta{IP: "1.2.3.4", MFAVerified: true}).Return(&AuthTokens{}, nil)
+
+		_, err := svc.CompleteLogin(ctx, challengeFor(t, svc, newUser()), "ABCDE-12345", meta)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Challenge Attempts Exhausted", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockProtection := new(MockLoginProtectionService)
+		svc := NewMFAService(mockRepo, new(MockSessionService), mockProtection, testMFAConfig)
+
+		mockRepo.On("GetByID", ctx, "user-1").Return(newUser(), nil)
+		mockProtection.On("Check", ctx, "mfa@example.com", "1.2.3.4").Return(nil)
+		mockProtection.On("CheckChallenge", ctx, mock.AnythingOfType("string"), defaultMFAChallengeTTL).Return(ErrInvalidMFAChallenge)
+
+		code, _ := totp.Code(secret, totp.Step(time.Now()))
+		_, err := svc.CompleteLogin(ctx, challengeFor(t, svc, newUser()), code, meta)
+
+		assert.ErrorIs(t, err, ErrInvalidMFAChallenge)
+		mockRepo.AssertNotCalled(t, "UseMFAStep", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Locked Account", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockProtection := new(MockLoginProtectionService)
+		svc := NewMFAService(mockRepo, new(MockSessionService), mockProtection, testMFAConfig)
+
+		mockRepo.On("GetByID", ctx, "user-1").Return(newUser(), nil)
+		mockProtection.On("Check", ctx, "mfa@example.com", "1.2.3.4").Return(&AccountLockedError{RetryAfter: time.Minute})
+
+		_, err := svc.CompleteLogin(ctx, challengeFor(t, svc, newUser()), "123456", meta)
+
+		assert.ErrorIs(t, err, ErrAccountLocked)
+		mockProtection.AssertNotCalled(t, "CheckChallenge", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Challenge Without ID", func(t *testing.T) {
+		svc := newTestMFAService(new(mockUserRepository), new(MockSessionService))
+
+		_, err := svc.CompleteLogin(ctx, signVerificationToken(t, map[string]any{
+			"typ": mfaChallengeTokenType,
+			"sub": "user-1",
+			"exp": time.Now().Add(time.Minute).Unix(),
+		}), "123456", meta)
+
+		assert.ErrorIs(t, err, ErrInvalidMFAChallenge)
+	})
+
+	t.Run("Access Token Is Not A Challenge", func(t *testing.T) {
+		svc := newTestMFAService(new(mockUserRepository), new(MockSessionService))
+
+		_, err := svc.CompleteLogin(ctx, signVerificationToken(t, map[string]any{
+			"sub": "user-1",
+			"sid": "session-1",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}), "123456", SessionMeta{})
+
+		assert.ErrorIs(t, err, ErrInvalidMFAChallenge)
+	})
+}
+
+func TestMFAEnrollment(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+
+	mockRepo := new(mockUserRepository)
+	svc := newTestMFAService(mockRepo, new(MockSessionService))
+
+	// 1. 產生密鑰
+	var pendingSecret string
+	mockRepo.On("GetByID", ctx, "user-1").Return(&domain.User{ID: "user-1", Email: "host@example.com"}, nil).Once()
+	mockRepo.On("Update", ctx, "user-1", mock.MatchedBy(func(payload bson.M) bool {
+		secret, ok := payload["mfa.pendingSecret"].(string)
+		if ok {
+			pendingSecret = secret
+		}
+		return ok
+	})).Return(nil).Once()
+
+	enrollment, err := svc.BeginEnrollment(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, pendingSecret, enrollment.Secret)
+	assert.True(t, strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/TaiwanStay:host@example.com"))
+
+	// 2. 錯誤的驗證碼
+	mockRepo.On("GetByID", ctx, "user-1").Return(&domain.User{ID: "user-1", MFA: domain.MFASettings{PendingSecret: pendingSecret}}, nil)
+	_, err = svc.ConfirmEnrollment(ctx, "user-1", "000000x")
+	assert.ErrorIs(t, err, ErrInvalidMFACode)
+
+	// 3. 確認後啟用並回傳復原碼
+	var saved domain.MFASettings
+	mockRepo.On("Update", ctx, "user-1", mock.MatchedBy(func(payload bson.M) bool {
+		settings, ok := payload["mfa"].(domain.MFASettings)
+		if ok {
+			saved = settings
+		}
+		return ok && settings.Enabled
+	})).Return(nil).Once()
+
+	code, _ := totp.Code(pendingSecret, totp.Step(time.Now()))
+	codes, err := svc.ConfirmEnrollment(ctx, "user-1", code)
+
+	require.NoError(t, err)
+	assert.Len(t, codes, 10)
+	assert.Equal(t, pendingSecret, saved.Secret)
+	assert.Contains(t, saved.RecoveryCodeHashes, hashToken(strings.ReplaceAll(codes[0], "-", "")))
+	assert.NotContains(t, saved.RecoveryCodeHashes, codes[0])
+}
@@ -7,12 +7,13 @@ import (
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/repository"
 	"github.com/taiwanstay/taiwanstay-back/pkg/email"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
 	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type NotificationService interface {
-	SendNotification(ctx context.Context, userID string, notifType domain.NotificationType, title, message string, data map[string]string) error
+	SendNotification(ctx context.Context, userID string, notifType domain.NotificationType, data map[string]string) error
 	ListNotifications(ctx context.Context, userID string, limit, offset int64) ([]*domain.Notification, int64, error)
 	MarkAsRead(ctx context.Context, id string, userID string) error
 	MarkAllAsRead(ctx context.Context, userID string) error
@@ -32,13 +33,30 @@ func NewNotificationService(repo repository.NotificationRepository, userRepo rep
 	}
 }
 
-func (s *notificationService) SendNotification(ctx context.Context, userID string, notifType domain.NotificationType, title, message string, data map[string]string) error {
-	// 1. Save In-App Notification
+// SendNotification 建立站內通知並寄送 email。
+// 標題與內容依 notifType 從訊息目錄產生，使用收件者的偏好語系，data 同時作為訊息參數。
+func (s *notificationService) SendNotification(ctx context.Context, userID string, notifType domain.NotificationType, data map[string]string) error {
 	userObjID, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
 		return err
 	}
 
+	// 1. Get recipient for locale and email
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get user for notification", "userId", userID, "error", err)
+		user = nil // Still save the in-app notification in the default locale
+	}
+
+	locale := messageLocale(ctx, user)
+	args := make([]any, 0, len(data)*2)
+	for k, v := range data {
+		args = append(args, k, v)
+	}
+	title := i18n.T(locale, "notification."+string(notifType)+".title", args...)
+	message := i18n.T(locale, "notification."+string(notifType)+".message", args...)
+
+	// 2. Save In-App Notification
 	notification := &domain.Notification{
 		UserID:    userObjID,
 		Type:      notifType,
@@ -55,11 +73,8 @@ func (s *notificationService) SendNotification(ctx context.Context, userID strin
 		// Ideally yes, but for consistency let's log and proceed.
 	}
 
-	// 2. Send Email (Async)
-	// We need to fetch user email first
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		logger.Error("Failed to get user for email notification", "userId", userID, "error", err)
+	// 3. Send Email (Async)
+	if user == nil {
 		return nil // Don't fail the whole operation if user not found for email
 	}
 
@@ -80,7 +95,7 @@ func (s *notificationService) ListNotifications(ctx context.Context, userID stri
 }
 
 func (s *notificationService) MarkAsRead(ctx context.Context, id string, userID string) error {
-	return s.repo.MarkAsRead(ctx, id, userID)
+	return notFound(s.repo.MarkAsRead(ctx, id, userID), ErrNotificationNotFound)
 }
 
 func (s *notificationService) MarkAllAsRead(ctx context.Context, userID string) error {
@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -36,6 +38,11 @@ func (m *MockNotificationRepository) MarkAllAsRead(ctx context.Context, userID s
 	return args.Error(0)
 }
 
+func (m *MockNotificationRepository) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 type MockEmailSender struct {
 	mock.Mock
 }
@@ -90,6 +97,44 @@ func (m *MockUserRepository) List(ctx context.Context, filter bson.M, limit, off
 	return args.Get(0).([]*domain.User), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockUserRepository) GetByIdentity(ctx context.Context, provider domain.AuthProvider, subject string) (*domain.User, error) {
+	args := m.Called(ctx, provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) AddIdentity(ctx context.Context, id string, identity domain.ProviderIdentity) error {
+	args := m.Called(ctx, id, identity)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) RemoveIdentity(ctx context.Context, id string, provider domain.AuthProvider, subject string) error {
+	args := m.Called(ctx, id, provider, subject)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkVerificationEmailSent(ctx context.Context, id string, sentBefore time.Time) (bool, error) {
+	args := m.Called(ctx, id, sentBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkPasswordResetSent(ctx context.Context, id string, sentBefore time.Time) (bool, error) {
+	args := m.Called(ctx, id, sentBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) UseMFAStep(ctx context.Context, id string, step int64) (bool, error) {
+	args := m.Called(ctx, id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) ConsumeRecoveryCode(ctx context.Context, id string, codeHash string) (bool, error) {
+	args := m.Called(ctx, id, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestSendNotification(t *testing.T) {
 	mockRepo := new(MockNotificationRepository)
 	mockUserRepo := new(MockUserRepository)
@@ -98,29 +143,54 @@ func TestSendNotification(t *testing.T) {
 
 	userID := primitive.NewObjectID().Hex()
 	user := &domain.User{
-		ID:    primitive.NewObjectID().Hex(),
-		Email: "test@example.com",
-		Name:  "Test User",
+		ID:     primitive.NewObjectID().Hex(),
+		Email:  "test@example.com",
+		Name:   "Test User",
+		Locale: "zh-TW",
 	}
 
-	// Expectation: Create notification in DB
+	// Expectation: Get User for locale and email
+	mockUserRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+
+	// Expectation: Create notification in the recipient's locale
 	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
-		return n.Title == "Test Title"
+		return n.Title == "收到新的申請" && n.Message == "「山中小屋」有一筆新的申請"
 	})).Return(nil)
 
-	// Expectation: Get User for email
-	mockUserRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
-
 	// Expectation: Send Email
 	mockEmailSender.On("Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
-	err := service.SendNotification(context.Background(), userID, domain.NotificationTypeApplicationCreated, "Test Title", "Test Message", nil)
+	data := map[string]string{"applicationId": "app-1", "opportunityTitle": "山中小屋"}
+	err := service.SendNotification(context.Background(), userID, domain.NotificationTypeApplicationCreated, data)
 
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestSendNotification_RequestLocaleFallback(t *testing.T) {
+	mockRepo := new(MockNotificationRepository)
+	mockUserRepo := new(MockUserRepository)
+	mockEmailSender := new(MockEmailSender)
+	service := NewNotificationService(mockRepo, mockUserRepo, mockEmailSender)
+
+	userID := primitive.NewObjectID().Hex()
+	user := &domain.User{ID: userID, Email: "test@example.com", Name: "Test User"}
+
+	mockUserRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Title == "新しい応募が届きました"
+	})).Return(nil)
+	mockEmailSender.On("Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// 使用者沒有偏好語系時使用請求的語系
+	ctx := i18n.WithLocale(context.Background(), i18n.LocaleJa)
+	err := service.SendNotification(ctx, userID, domain.NotificationTypeApplicationCreated, map[string]string{"opportunityTitle": "Farm"})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestListNotifications(t *testing.T) {
 	mockRepo := new(MockNotificationRepository)
 	mockUserRepo := new(MockUserRepository)
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"github.com/taiwanstay/taiwanstay-back/pkg/oauth"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrProviderNotSupported = errcode.New(http.StatusNotImplemented, "PROVIDER_NOT_SUPPORTED", "login provider not supported")
+	ErrInvalidIDToken       = errcode.Unauthorized("INVALID_ID_TOKEN", "invalid id token")
+	ErrEmailNotVerified     = errcode.Forbidden("PROVIDER_EMAIL_NOT_VERIFIED", "email is not verified by the provider")
+	ErrInvalidNotification  = errcode.BadRequest("INVALID_NOTIFICATION", "invalid provider notification")
+)
+
+// OAuthLoginRequest 是第三方登入所需的資料
+type OAuthLoginRequest struct {
+	Provider domain.AuthProvider
+	IDToken  string
+	Nonce    string // 客戶端產生的原始 nonce
+	Name     string // Apple 只在第一次授權時將姓名交給客戶端，之後的 token 都不含姓名
+}
+
+// OAuthService 處理第三方登入 (Google、Apple 等)
+type OAuthService interface {
+	Login(ctx context.Context, req OAuthLoginRequest, meta SessionMeta) (*LoginResult, error)
+	HandleAppleNotification(ctx context.Context, payload string) error
+}
+
+type oauthService struct {
+	userRepo       repository.UserRepository
+	sessionService SessionService
+	mfaService     MFAService
+	verifiers      map[domain.AuthProvider]oauth.Verifier
+}
+
+// NewOAuthService 建立 OAuthService，verifiers 中未設定的 provider 會回傳 ErrProviderNotSupported
+func NewOAuthService(userRepo repository.UserRepository, sessionService SessionService, mfaService MFAService, verifiers map[domain.AuthProvider]oauth.Verifier) OAuthService {
+	return &oauthService{
+		userRepo:       userRepo,
+		sessionService: sessionService,
+		mfaService:     mfaService,
+		verifiers:      verifiers,
+	}
+}
+
+// Login 驗證 ID token 並登入對應的使用者。
+// 1. 已綁定此身分的使用者直接登入
+// 2. 否則以已驗證的 email 綁定既有帳號
+// 3. 都找不到時自動註冊新帳號
+// 啟用兩步驟驗證的使用者同樣需要完成第二步驟。
+func (s *oauthService) Login(ctx context.Context, req OAuthLoginRequest, meta SessionMeta) (*LoginResult, error) {
+	verifier, ok := s.verifiers[req.Provider]
+	if !ok {
+		return nil, ErrProviderNotSupported
+	}
+
+	identity, err := verifier.Verify(ctx, req.IDToken, req.Nonce)
+	if err != nil {
+		if errors.Is(err, oauth.ErrInvalidToken) {
+			return nil, ErrInvalidIDToken
+		}
+		return nil, err
+	}
+	if identity.Name == "" {
+		identity.Name = strings.TrimSpace(req.Name)
+	}
+
+	user, err := s.findOrCreateUser(ctx, req.Provider, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mfaService.StartLogin(ctx, user, meta)
+}
+
+func (s *oauthService) findOrCreateUser(ctx context.Context, provider domain.AuthProvider, identity *oauth.Identity) (*domain.User, error) {
+	// 1. 已綁定的身分
+	user, err := s.userRepo.GetByIdentity(ctx, provider, identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	// 未驗證的 email 不可用於綁定或註冊，避免帳號被接管
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	link := domain.ProviderIdentity{
+		Provider:     provider,
+		Subject:      identity.Subject,
+		Email:        identity.Email,
+		PrivateRelay: identity.IsPrivateEmail,
+		LinkedAt:     time.Now(),
+	}
+
+	// 2. 以 email 綁定既有帳號
+	user, err = s.userRepo.GetByEmail(ctx, identity.Email)
+	if err == nil {
+		// provider 已驗證此 email，可視同完成 email 驗證
+		if user.EmailVerified == nil {
+			if err := s.claimUnverifiedAccount(ctx, user); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := s.userRepo.AddIdentity(ctx, user.ID, link); err != nil {
+			return nil, err
+		}
+		user.Identities = append(user.Identities, link)
+		return user, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	// 3. 自動註冊
+	name := identity.Name
+	if name == "" {
+		if identity.IsPrivateEmail {
+			// 轉寄地址的 local part 是隨機字串，不適合當作名稱
+			name = "Traveler"
+		} else {
+			name = strings.Split(identity.Email, "@")[0]
+		}
+	}
+	now := time.Now()
+	newUser := newTravelerUser(ctx, name, identity.Email)
+	newUser.Image = identity.Picture
+	newUser.EmailVerified = &now
+	newUser.Identities = []domain.ProviderIdentity{link}
+
+	userID, err := s.userRepo.Create(ctx, newUser)
+	if err != nil {
+		return nil, err
+	}
+	newUser.ID = userID
+
+	return newUser, nil
+}
+
+// claimUnverifiedAccount 在 provider 證明 email 擁有權後接手尚未驗證 email 的帳號。
+// 此帳號可能是他人預先以這個 email 註冊的，因此清除原有的密碼與兩步驟驗證並登出所有裝置，
+// 之後只能以 provider 登入，或透過忘記密碼重新設定密碼。
+func (s *oauthService) claimUnverifiedAccount(ctx context.Context, user *domain.User) error {
+	now := time.Now()
+	if err := s.userRepo.Update(ctx, user.ID, bson.M{
+		"emailVerified": now,
+		"password":      "",
+		"mfa":           domain.MFASettings{},
+	}); err != nil {
+		return err
+	}
+	if _, err := s.sessionService.RevokeAllUserSessions(ctx, user.ID, "", SessionRevokedAccountClaimed); err != nil {
+		return err
+	}
+
+	user.EmailVerified = &now
+	user.Password = ""
+	user.MFA = domain.MFASettings{}
+	logger.Warn("Unverified account claimed by provider login, credentials reset", "userId", user.ID)
+	return nil
+}
+
+// HandleAppleNotification 處理 Apple server-to-server 通知。
+// 使用者撤銷授權或刪除 Apple ID 時，解除綁定並登出所有裝置。
+func (s *oauthService) HandleAppleNotification(ctx context.Context, payload string) error {
+	verifier, ok := s.verifiers[domain.ProviderApple].(oauth.AppleNotificationVerifier)
+	if !ok {
+		return ErrProviderNotSupported
+	}
+
+	event, err := verifier.VerifyNotification(ctx, payload)
+	if err != nil {
+		if errors.Is(err, oauth.ErrInvalidToken) {
+			return ErrInvalidNotification
+		}
+		return err
+	}
+
+	switch event.Type {
+	case oauth.AppleEventConsentRevoked, oauth.AppleEventAccountDelete:
+		user, err := s.userRepo.GetByIdentity(ctx, domain.ProviderApple, event.Subject)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				// 已解除綁定或從未綁定，通知可能重送
+				return nil
+			}
+			return err
+		}
+
+		if err := s.userRepo.RemoveIdentity(ctx, user.ID, domain.ProviderApple, event.Subject); err != nil {
+			return err
+		}
+		if _, err := s.sessionService.RevokeAllUserSessions(ctx, user.ID, "", SessionRevokedProviderUnlinked); err != nil {
+			return err
+		}
+		logger.Info("Apple identity unlinked", "userId", user.ID, "event", event.Type)
+	default:
+		// email-disabled / email-enabled: 轉寄地址狀態變更，目前僅記錄
+		logger.Info("Apple notification received", "event", event.Type)
+	}
+
+	return nil
+}
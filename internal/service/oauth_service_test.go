@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"github.com/taiwanstay/taiwanstay-back/pkg/oauth"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MockVerifier is a mock implementation of oauth.Verifier
+type MockVerifier struct {
+	mock.Mock
+}
+
+func (m *MockVerifier) Verify(ctx context.Context, idToken, nonce string) (*oauth.Identity, error) {
+	args := m.Called(ctx, idToken, nonce)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*oauth.Identity), args.Error(1)
+}
+
+// MockAppleVerifier also verifies Apple server-to-server notifications
+type MockAppleVerifier struct {
+	MockVerifier
+}
+
+func (m *MockAppleVerifier) VerifyNotification(ctx context.Context, payload string) (*oauth.AppleEvent, error) {
+	args := m.Called(ctx, payload)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*oauth.AppleEvent), args.Error(1)
+}
+
+func newTestOAuthService(userRepo *mockUserRepository, sessions *MockSessionService, verifier *MockVerifier) OAuthService {
+	return NewOAuthService(userRepo, sessions, newTestMFAService(userRepo, sessions), map[domain.AuthProvider]oauth.Verifier{
+		domain.ProviderGoogle: verifier,
+	})
+}
+
+func googleLogin(idToken string) OAuthLoginRequest {
+	return OAuthLoginRequest{Provider: domain.ProviderGoogle, IDToken: idToken}
+}
+
+func TestOAuthLogin(t *testing.T) {
+	ctx := context.Background()
+	identity := &oauth.Identity{
+		Provider:      "google",
+		Subject:       "google-123",
+		Email:         "traveler@example.com",
+		EmailVerified: true,
+		Name:          "Traveler",
+	}
+	tokens := &AuthTokens{AccessToken: "access", RefreshToken: "refresh"}
+
+	t.Run("Existing Linked Identity", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockSessions := new(MockSessionService)
+		mockVerifier := new(MockVerifier)
+		svc := newTestOAuthService(mockRepo, mockSessions, mockVerifier)
+
+		user := &domain.User{ID: "user-1", Email: identity.Email}
+		mockVerifier.On("Verify", ctx, "id-token", "").Return(identity, nil)
+		mockRepo.On("GetByIdentity", ctx, domain.ProviderGoogle, "google-123").Return(user, nil)
+		mockSessions.On("CreateSession", ctx, user, SessionMeta{}).Return(tokens, nil)
+
+		result, err := svc.Login(ctx, googleLogin("id-token"), SessionMeta{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", result.User.ID)
+		assert.Equal(t, tokens, result.Tokens)
+		mockRepo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Suspended User Is Rejected", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockSessions := new(MockSessionService)
+		mockVerifier := new(MockVerifier)
+		svc := newTestOAuthService(mockRepo, mockSessions, mockVerifier)
+
+		user := &domain.User{ID: "user-1", Email: identity.Email, Status: domain.UserStatusSuspended}
+		mockVerifier.On("Verify", ctx, "id-token", "").Return(identity, nil)
+		mockRepo.On("GetByIdentity", ctx, domain.ProviderGoogle, "google-123").Return(user, nil)
+
+		_, err := svc.Login(ctx, googleLogin("id-token"), SessionMeta{})
+
+		assert.ErrorIs(t, err, ErrAccountSuspended)
+		mockSessions.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Links Existing Account By Email", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockSessions := new(MockSessionService)
+		mockVerifier := new(MockVerifier)
+		svc := newTestOAuthService(mockRepo, mockSessions, mockVerifier)
+
+		verifiedAt := time.Now().Add(-24 * time.Hour)
+		user := &domain.User{ID: "user-1", Email: identity.Email, Password: "hashed", EmailVerified: &verifiedAt}
+		mockVerifier.On("Verify", ctx, "id-token", "").Return(identity, nil)
+		mockRepo.On("GetByIdentity", ctx, domain.ProviderGoogle, "google-123").Return(nil, mongo.ErrNoDocuments)
+		mockRepo.On("GetByEmail", ctx, identity.Email).Return(user, nil)
+		mockRepo.On("AddIdentity", ctx, "user-1", mock.MatchedBy(func(i domain.ProviderIdentity) bool {
+			return i.Provider == domain.ProviderGoogle && i.Subject == "google-123"
+		})).Return(nil)
+		mockSessions.On("CreateSession", ctx, user, SessionMeta{}).Return(tokens, nil)
+
+		result, err := svc.Login(ctx, googleLogin("id-token"), SessionMeta{})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.User.Identities, 1)
+		assert.Empty(t, result.User.Password)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		mockSessions.AssertNotCalled(t, "RevokeAllUserSessions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unverified Account Is Reset Before Linking", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockSessions := new(MockSessionService)
+		mockVerifier := new(MockVerifier)
+		svc := newTestOAuthService(mockRepo, mockSessions, mockVerifier)
+
+		// 他人預先以受害者的 email 註冊並設定了密碼與兩步驟驗證
+		user := &domain.User{ID: "user-1", Email: identity.Email, Password: "attacker-hash", MFA: domain.MFASettings{Enabled: true, Secret: "attacker"}}
+		mockVerifier.On("Verify", ctx, "id-token", "").Return(identity, nil)
+		mockRepo.On("GetByIdentity", ctx, domain.ProviderGoogle, "google-123").Return(nil, mongo.ErrNoDocuments)
+		mockRepo.On("GetByEmail", ctx, identity.Email).Return(user, nil)
+		mockRepo.On("Update", ctx, "user-1", mock.MatchedBy(func(payload bson.M) bool {
+			_, verified := payload["emailVerified"]
+			mfa, _ := payload["mfa"].(domain.MFASettings)
+			return verified && payload["password"] == "" && !mfa.Enabled && mfa.Secret == ""
+		})).Return(nil)
+		mockSessions.On("RevokeAllUserSessions", ctx, "user-1", "", SessionRevokedAccountClaimed).Return(int64(2), nil)
+		mockRepo.On("AddIdentity", ctx, "user-1", mock.AnythingOfType("domain.ProviderIdentity")).Return(nil)
+		mockSessions.On("CreateSession", ctx, user, SessionMeta{}).Return(tokens, nil)
+
+		result, err := svc.Login(ctx, googleLogin("id-token"), SessionMeta{})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result.User.EmailVerified)
+		assert.False(t, result.MFARequired)
+		assert.Equal(t, tokens, result.Tokens)
+		mockRepo.AssertExpectations(t)
+		mockSessions.AssertExpectations(t)
+	})
+
+	t.Run("Registers New User", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockSessions := new(MockSessionService)
+		mockVerifier := new(MockVerifier)
+		svc := newTestOAuthService(mockRepo, mockSessions, mockVerifier)
+
+		mockVerifier.On("Verify", ctx, "id-token", "").Return(identity, nil)
+		mockRepo.On("GetByIdentity", ctx, domain.ProviderGoogle, "google-123").Return(nil, mongo.ErrNoDocuments)
+		mockRepo.On("GetByEmail", ctx, identity.Email).Return(nil, mongo.ErrNoDocuments)
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Name == "Traveler" && u.EmailVerified != nil && u.Role == domain.RoleUser && len(u.Identities) == 1
+		})).Return("new-user-id", nil)
+		mockSessions.On("CreateSession", ctx, mock.AnythingOfType("*domain.User"), SessionMeta{}).Return(tokens, nil)
+
+		result, err := svc.Login(ctx, googleLogin("id-token"), SessionMeta{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "new-user-id", result.User.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unverified Email Is Not Linked", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockVerifier := new(MockVerifier)
+		svc := newTestOAuthService(mockRepo, new(MockSessionService), mockVerifier)
+
+		unverified := *identity
+		unverified.EmailVerified = false
+		mockVerifier.On("Verify", ctx, "id-token", "").Return(&unverified, nil)
+		mockRepo.On("GetByIdentity", ctx, domain.ProviderGoogle, "google-123").Return(nil, mongo.ErrNoDocuments)
+
+		_, err := svc.Login(ctx, googleLogin("id-token"), SessionMeta{})
+
+		assert.ErrorIs(t, err, ErrEmailNotVerified)
+		mockRepo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid Token", func(t *testing.T) {
+		mockVerifier := new(MockVerifier)
+		svc := newTestOAuthService(new(mockUserRepository), new(MockSessionService), mockVerifier)
+
+		mockVerifier.On("Verify", ctx, "bad-token", "").Return(nil, oauth.ErrInvalidToken)
+
+		_, err := svc.Login(ctx, googleLogin("bad-token"), SessionMeta{})
+
+		assert.ErrorIs(t, err, ErrInvalidIDToken)
+	})
+
+	t.Run("Provider Not Configured", func(t *testing.T) {
+		svc := NewOAuthService(new(mockUserRepository), new(MockSessionService), nil, nil)
+
+		_, err := svc.Login(ctx, googleLogin("id-token"), SessionMeta{})
+
+		assert.ErrorIs(t, err, ErrProviderNotSupported)
+	})
+}
+
+func TestOAuthLogin_Apple(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("First Login Uses Name From Request", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockSessions := new(MockSessionService)
+		mockVerifier := new(MockAppleVerifier)
+		svc := NewOAuthService(mockRepo, mockSessions, newTestMFAService(mockRepo, mockSessions), map[domain.AuthProvider]oauth.Verifier{domain.ProviderApple: mockVerifier})
+
+		relayEmail := "abc123@privaterelay.appleid.com"
+		mockVerifier.On("Verify", ctx, "apple-token", "raw-nonce").Return(&oauth.Identity{
+			Provider:       "apple",
+			Subject:        "apple-001",
+			Email:          relayEmail,
+			EmailVerified:  true,
+			IsPrivateEmail: true,
+		}, nil)
+		mockRepo.On("GetByIdentity", ctx, domain.ProviderApple, "apple-001").Return(nil, mongo.ErrNoDocuments)
+		mockRepo.On("GetByEmail", ctx, relayEmail).Return(nil, mongo.ErrNoDocuments)
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Name == "Lin Mei" && u.Email == relayEmail && u.Identities[0].PrivateRelay
+		})).Return("apple-user-id", nil)
+		mockSessions.On("CreateSession", ctx, mock.AnythingOfType("*domain.User"), SessionMeta{}).Return(&AuthTokens{}, nil)
+
+		result, err := svc.Login(ctx, OAuthLoginRequest{
+			Provider: domain.ProviderApple,
+			IDToken:  "apple-token",
+			Nonce:    "raw-nonce",
+			Name:     " Lin Mei ",
+		}, SessionMeta{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "apple-user-id", result.User.ID)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestHandleAppleNotification(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+
+	t.Run("Consent Revoked Unlinks And Signs Out", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockSessions := new(MockSessionService)
+		mockVerifier := new(MockAppleVerifier)
+		svc := NewOAuthService(mockRepo, mockSessions, newTestMFAService(mockRepo, mockSessions), map[domain.AuthProvider]oauth.Verifier{domain.ProviderApple: mockVerifier})
+
+		mockVerifier.On("VerifyNotification", ctx, "payload").Return(&oauth.AppleEvent{Type: oauth.AppleEventConsentRevoked, Subject: "apple-001"}, nil)
+		mockRepo.On("GetByIdentity", ctx, domain.ProviderApple, "apple-001").Return(&domain.User{ID: "user-1"}, nil)
+		mockRepo.On("RemoveIdentity", ctx, "user-1", domain.ProviderApple, "apple-001").Return(nil)
+		mockSessions.On("RevokeAllUserSessions", ctx, "user-1", "", SessionRevokedProviderUnlinked).Return(int64(1), nil)
+
+		err := svc.HandleAppleNotification(ctx, "payload")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockSessions.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Subject Is Ignored", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockVerifier := new(MockAppleVerifier)
+		svc := NewOAuthService(mockRepo, new(MockSessionService), nil, map[domain.AuthProvider]oauth.Verifier{domain.ProviderApple: mockVerifier})
+
+		mockVerifier.On("VerifyNotification", ctx, "payload").Return(&oauth.AppleEvent{Type: oauth.AppleEventAccountDelete, Subject: "gone"}, nil)
+		mockRepo.On("GetByIdentity", ctx, domain.ProviderApple, "gone").Return(nil, mongo.ErrNoDocuments)
+
+		err := svc.HandleAppleNotification(ctx, "payload")
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "RemoveIdentity", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid Payload", func(t *testing.T) {
+		mockVerifier := new(MockAppleVerifier)
+		svc := NewOAuthService(new(mockUserRepository), new(MockSessionService), nil, map[domain.AuthProvider]oauth.Verifier{domain.ProviderApple: mockVerifier})
+
+		mockVerifier.On("VerifyNotification", ctx, "forged").Return(nil, oauth.ErrInvalidToken)
+
+		err := svc.HandleAppleNotification(ctx, "forged")
+
+		assert.ErrorIs(t, err, ErrInvalidNotification)
+	})
+}
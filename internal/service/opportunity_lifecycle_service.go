@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+)
+
+// OpportunityLifecycleResult 是一次排程執行處理的數量
+type OpportunityLifecycleResult struct {
+	ClosedSlots int64 // 有時段被關閉的工作機會數
+	Notified    int64
+	Expired     int64
+	Filled      int64
+}
+
+// OpportunityLifecycleService 由排程定期執行：關閉已結束的時段、到期前通知接待主、
+// 將過了申請截止時間或最後一個時段的工作機會改為 EXPIRED，以及將時段全滿的工作機會改為 FILLED
+type OpportunityLifecycleService interface {
+	Run(ctx context.Context, now time.Time) (*OpportunityLifecycleResult, error)
+}
+
+type opportunityLifecycleService struct {
+	repo         repository.OpportunityRepository
+	hostRepo     repository.HostRepository
+	oppService   OpportunityService
+	notifService NotificationService
+	notice       time.Duration
+	loc          *time.Location
+}
+
+func NewOpportunityLifecycleService(repo repository.OpportunityRepository, hostRepo repository.HostRepository, oppService OpportunityService, notifService NotificationService, cfg *config.Config) OpportunityLifecycleService {
+	loc, err := time.LoadLocation(cfg.Scheduler.Timezone)
+	if err != nil {
+		logger.Warn("Invalid scheduler timezone, using local time", "timezone", cfg.Scheduler.Timezone, "error", err)
+		loc = time.Local
+	}
+	return &opportunityLifecycleService{
+		repo:         repo,
+		hostRepo:     hostRepo,
+		oppService:   oppService,
+		notifService: notifService,
+		notice:       cfg.Scheduler.ExpiryNotice,
+		loc:          loc,
+	}
+}
+
+// Run 依序處理各項工作；單一工作機會失敗只記錄 log，不影響其他工作機會
+func (s *opportunityLifecycleService) Run(ctx context.Context, now time.Time) (*OpportunityLifecycleResult, error) {
+	now = now.In(s.loc)
+	today := now.Format(domain.TimeSlotDateLayout)
+	result := &OpportunityLifecycleResult{}
+
+	closed, err := s.repo.CloseEndedTimeSlots(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+	result.ClosedSlots = closed
+
+	cutoff := now.Add(s.notice)
+	expiring, err := s.repo.ListExpiring(ctx, cutoff, cutoff.Format(domain.TimeSlotDateLayout))
+	if err != nil {
+		return nil, err
+	}
+	for _, opp := range expiring {
+		expiresAt, ok := opp.ExpiresAt(s.loc)
+		if !ok {
+			continue
+		}
+		if !now.Before(expiresAt) {
+			if s.changeStatus(ctx, opp, domain.OpportunityStatusExpired, expiryReason(opp, expiresAt)) {
+				result.Expired++
+			}
+			continue
+		}
+		if opp.ExpiryNotifiedAt == nil && s.notifyExpiring(ctx, opp, expiresAt, now) {
+			result.Notified++
+		}
+	}
+
+	full, err := s.repo.ListFullyBooked(ctx, today)
+	if err != nil {
+		return nil, err
+	}
+	for _, opp := range full {
+		if opp.SlotsFull(today) && s.changeStatus(ctx, opp, domain.OpportunityStatusFilled, "all time slots are full") {
+			result.Filled++
+		}
+	}
+
+	return result, nil
+}
+
+// expiryReason 回傳到期的原因，寫入 StatusHistory
+func expiryReason(opp *domain.Opportunity, expiresAt time.Time) string {
+	if deadline := opp.ApplicationProcess.Deadline; !deadline.IsZero() && !deadline.After(expiresAt) {
+		return "application deadline passed"
+	}
+	return "all time slots ended"
+}
+
+func (s *opportunityLifecycleService) changeStatus(ctx context.Context, opp *domain.Opportunity, to domain.OpportunityStatus, reason string) bool {
+	_, err := s.oppService.ChangeStatus(ctx, opp.ID.Hex(), to, domain.ActorSystem, "", reason)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to change opportunity status", "opportunityId", opp.ID.Hex(), "to", string(to), "error", err)
+		return false
+	}
+	return true
+}
+
+// notifyExpiring 通知接待主工作機會即將到期，成功後記錄通知時間避免重複通知
+func (s *opportunityLifecycleService) notifyExpiring(ctx context.Context, opp *domain.Opportunity, expiresAt, now time.Time) bool {
+	host, err := s.hostRepo.GetByID(ctx, opp.HostID.Hex())
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to load host for expiry notification", "opportunityId", opp.ID.Hex(), "error", err)
+		return false
+	}
+
+	err = s.notifService.SendNotification(ctx, host.UserID.Hex(), domain.NotificationTypeOpportunityExpiring, map[string]string{
+		"opportunityId":    opp.ID.Hex(),
+		"opportunityTitle": opp.Title,
+		"lastDay":          expiresAt.In(s.loc).Add(-time.Second).Format(domain.TimeSlotDateLayout),
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to notify host of expiring opportunity", "opportunityId", opp.ID.Hex(), "error", err)
+		return false
+	}
+	if err := s.repo.MarkExpiryNotified(ctx, opp.ID.Hex(), now); err != nil {
+		logger.ErrorContext(ctx, "Failed to mark opportunity expiry notified", "opportunityId", opp.ID.Hex(), "error", err)
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestOpportunityExpiresAt(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*60*60)
+
+	t.Run("Last Time Slot", func(t *testing.T) {
+		opp := &domain.Opportunity{TimeSlots: []domain.TimeSlot{{EndDate: "2026-08-31"}, {EndDate: "2026-09-30"}}}
+		expiresAt, ok := opp.ExpiresAt(loc)
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2026, 10, 1, 0, 0, 0, 0, loc), expiresAt)
+	})
+
+	t.Run("Earlier Deadline", func(t *testing.T) {
+		opp := &domain.Opportunity{TimeSlots: []domain.TimeSlot{{EndDate: "2026-09-30"}}}
+		opp.ApplicationProcess.Deadline = time.Date(2026, 8, 15, 12, 0, 0, 0, time.UTC)
+		expiresAt, ok := opp.ExpiresAt(loc)
+		assert.True(t, ok)
+		assert.True(t, expiresAt.Equal(opp.ApplicationProcess.Deadline))
+	})
+
+	t.Run("No Deadline Or Slots", func(t *testing.T) {
+		_, ok := (&domain.Opportunity{}).ExpiresAt(loc)
+		assert.False(t, ok)
+	})
+}
+
+func TestOpportunitySlotsFull(t *testing.T) {
+	today := "2026-07-01"
+	opp := &domain.Opportunity{TimeSlots: []domain.TimeSlot{
+		{EndDate: "2026-06-30", DefaultCapacity: 2},                    // 已結束
+		{EndDate: "2026-08-31", DefaultCapacity: 2, ConfirmedCount: 2}, // 額滿
+		{EndDate: "2026-09-30", Status: domain.TimeSlotStatusFilled},   // 接待主標記額滿
+		{EndDate: "2026-10-31", Status: domain.TimeSlotStatusClosed},   // 已關閉
+	}}
+	assert.True(t, opp.SlotsFull(today))
+
+	opp.TimeSlots = append(opp.TimeSlots, domain.TimeSlot{EndDate: "2026-11-30", DefaultCapacity: 2, ConfirmedCount: 1})
+	assert.False(t, opp.SlotsFull(today))
+
+	assert.False(t, (&domain.Opportunity{}).SlotsFull(today))
+}
+
+func TestOpportunityLifecycle_Run(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	hostID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	now := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	cfg := &config.Config{Scheduler: config.SchedulerConfig{Timezone: "UTC", ExpiryNotice: 72 * time.Hour}}
+
+	mockOppRepo := new(MockOpportunityRepository)
+	mockHostRepo := new(MockHostRepository)
+	mockNotif := new(MockNotificationService)
+	oppService := NewOpportunityService(mockOppRepo, mockHostRepo, new(MockOpportunityApprovalRepository), mockNotif, cfg)
+	service := NewOpportunityLifecycleService(mockOppRepo, mockHostRepo, oppService, mockNotif, cfg)
+
+	pastDeadline := &domain.Opportunity{ID: primitive.NewObjectID(), HostID: hostID, Status: domain.OpportunityStatusActive}
+	pastDeadline.ApplicationProcess.Deadline = now.Add(-time.Hour)
+	endingSoon := &domain.Opportunity{ID: primitive.NewObjectID(), HostID: hostID, Title: "Farm Helper", Status: domain.OpportunityStatusActive,
+		TimeSlots: []domain.TimeSlot{{EndDate: "2026-07-02"}}}
+	notifiedAt := now.Add(-time.Hour)
+	alreadyNotified := &domain.Opportunity{ID: primitive.NewObjectID(), HostID: hostID, Status: domain.OpportunityStatusPaused,
+		TimeSlots: []domain.TimeSlot{{EndDate: "2026-07-02"}}, ExpiryNotifiedAt: &notifiedAt}
+	full := &domain.Opportunity{ID: primitive.NewObjectID(), HostID: hostID, Status: domain.OpportunityStatusActive,
+		TimeSlots: []domain.TimeSlot{{EndDate: "2026-09-30", DefaultCapacity: 1, ConfirmedCount: 1}}}
+
+	mockOppRepo.On("CloseEndedTimeSlots", ctx, "2026-07-01").Return(int64(2), nil)
+	mockOppRepo.On("ListExpiring", ctx, now.Add(72*time.Hour), "2026-07-04").Return([]*domain.Opportunity{pastDeadline, endingSoon, alreadyNotified}, nil)
+	mockOppRepo.On("ListFullyBooked", ctx, "2026-07-01").Return([]*domain.Opportunity{full}, nil)
+	mockHostRepo.On("GetByID", ctx, hostID.Hex()).Return(&domain.Host{ID: hostID, UserID: ownerID}, nil)
+
+	// 過了申請截止時間
+	mockOppRepo.On("GetByID", ctx, pastDeadline.ID.Hex()).Return(pastDeadline, nil)
+	mockOppRepo.On("UpdateStatus", ctx, pastDeadline.ID.Hex(), domain.OpportunityStatusActive, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+		return e.Status == domain.OpportunityStatusExpired && e.Reason == "application deadline passed" && e.ChangedBy.IsZero()
+	}), bson.M{}).Return(nil)
+	mockNotif.On("SendNotification", ctx, ownerID.Hex(), domain.NotificationTypeOpportunityExpired, mock.Anything).Return(nil)
+
+	// 即將到期
+	mockNotif.On("SendNotification", ctx, ownerID.Hex(), domain.NotificationTypeOpportunityExpiring, mock.MatchedBy(func(data map[string]string) bool {
+		return data["opportunityId"] == endingSoon.ID.Hex() && data["lastDay"] == "2026-07-02"
+	})).Return(nil)
+	mockOppRepo.On("MarkExpiryNotified", ctx, endingSoon.ID.Hex(), now).Return(nil)
+
+	// 時段全滿
+	mockOppRepo.On("GetByID", ctx, full.ID.Hex()).Return(full, nil)
+	mockOppRepo.On("UpdateStatus", ctx, full.ID.Hex(), domain.OpportunityStatusActive, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+		return e.Status == domain.OpportunityStatusFilled
+	}), bson.M{}).Return(nil)
+	mockNotif.On("SendNotification", ctx, ownerID.Hex(), domain.NotificationTypeOpportunityFilled, mock.Anything).Return(nil)
+
+	result, err := service.Run(ctx, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &OpportunityLifecycleResult{ClosedSlots: 2, Notified: 1, Expired: 1, Filled: 1}, result)
+	mockOppRepo.AssertExpectations(t)
+	mockNotif.AssertExpectations(t)
+	mockOppRepo.AssertNotCalled(t, "MarkExpiryNotified", ctx, alreadyNotified.ID.Hex(), mock.Anything)
+}
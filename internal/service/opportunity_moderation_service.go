@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrOpportunityNotPendingReview = errcode.Conflict("OPPORTUNITY_NOT_PENDING_REVIEW", "opportunity is not waiting for review")
+	ErrOpportunityPendingReview    = errcode.Conflict("OPPORTUNITY_PENDING_REVIEW", "opportunity is waiting for review, approve or reject it through the review queue")
+	ErrOpportunityEditedAgain      = errcode.Conflict("OPPORTUNITY_EDITED_AGAIN", "opportunity was edited again during review, check the latest changes")
+)
+
+// diffIgnoredFields 是由系統維護、不屬於接待主編輯內容的欄位，比對修改時略過
+var diffIgnoredFields = map[string]bool{
+	"_id":                                    true,
+	"hostId":                                 true,
+	"publicId":                               true,
+	"status":                                 true,
+	"statusNote":                             true,
+	"statusHistory":                          true,
+	"submittedAt":                            true,
+	"lastApprovedAt":                         true,
+	"lastEditedAt":                           true,
+	"moderationFlags":                        true,
+	"expiryNotifiedAt":                       true,
+	"ratings":                                true,
+	"stats":                                  true,
+	"createdAt":                              true,
+	"updatedAt":                              true,
+	"applicationProcess.currentApplications": true,
+}
+
+// OpportunityModerationService 處理管理員的工作機會審核佇列
+type OpportunityModerationService interface {
+	ListPending(ctx context.Context, filter repository.OpportunityReviewFilter) ([]*domain.Opportunity, int64, error)
+	Review(ctx context.Context, reviewerID, id string, decision domain.OpportunityReviewDecision, note string) (*domain.Opportunity, error)
+	GetChanges(ctx context.Context, id string) (*domain.OpportunityChanges, error)
+	ChangeStatus(ctx context.Context, adminID, id string, status domain.OpportunityStatus, reason string) (*domain.Opportunity, error)
+}
+
+type opportunityModerationService struct {
+	oppRepo      repository.OpportunityRepository
+	approvalRepo repository.OpportunityApprovalRepository
+	oppService   OpportunityService
+}
+
+func NewOpportunityModerationService(oppRepo repository.OpportunityRepository, approvalRepo repository.OpportunityApprovalRepository, oppService OpportunityService) OpportunityModerationService {
+	return &opportunityModerationService{
+		oppRepo:      oppRepo,
+		approvalRepo: approvalRepo,
+		oppService:   oppService,
+	}
+}
+
+// ListPending 列出等待審核的工作機會，先送審的排在前面；filter.Edited 時列出上架後被修改的工作機會
+func (s *opportunityModerationService) ListPending(ctx context.Context, filter repository.OpportunityReviewFilter) ([]*domain.Opportunity, int64, error) {
+	return s.oppRepo.ListPendingReview(ctx, filter)
+}
+
+// Review 通過 (上架) 或拒絕送審中的工作機會，note 會成為 StatusNote 並通知接待主。
+// 上架後被修改的工作機會也透過 Review 審核修改內容，見 reviewEdits。
+func (s *opportunityModerationService) Review(ctx context.Context, reviewerID, id string, decision domain.OpportunityReviewDecision, note string) (*domain.Opportunity, error) {
+	var status domain.OpportunityStatus
+	switch decision {
+	case domain.OpportunityReviewApprove:
+		status = domain.OpportunityStatusActive
+	case domain.OpportunityReviewReject:
+		status = domain.OpportunityStatusRejected
+	default:
+		return nil, ErrInvalidReviewDecision
+	}
+
+	opp, err := s.oppRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrOpportunityNotFound)
+	}
+	if opp.Status != domain.OpportunityStatusPending {
+		if opp.LastEditedAt != nil && opp.Status.IsLive() {
+			return s.reviewEdits(ctx, reviewerID, opp, decision, note)
+		}
+		return nil, ErrOpportunityNotPendingReview.WithMeta("status", string(opp.Status))
+	}
+
+	return s.oppService.ChangeStatus(ctx, id, status, domain.ActorAdmin, reviewerID, note)
+}
+
+// reviewEdits 審核上架後的修改：通過時保存目前內容供之後比對並清除修改紀錄，狀態不變；
+// 拒絕時由管理員暫停工作機會，note 會成為 StatusNote 並通知接待主。
+func (s *opportunityModerationService) reviewEdits(ctx context.Context, reviewerID string, opp *domain.Opportunity, decision domain.OpportunityReviewDecision, note string) (*domain.Opportunity, error) {
+	id := opp.ID.Hex()
+	if decision == domain.OpportunityReviewReject {
+		return s.oppService.ChangeStatus(ctx, id, domain.OpportunityStatusAdminPaused, domain.ActorAdmin, reviewerID, note)
+	}
+
+	now := time.Now()
+	if err := s.oppRepo.MarkEditsReviewed(ctx, id, *opp.LastEditedAt, now); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrOpportunityEditedAgain
+		}
+		return nil, err
+	}
+	opp.LastApprovedAt = &now
+	opp.LastEditedAt = nil
+
+	// 保存失敗只影響之後的差異比對，不影響審核結果
+	reviewerObjID, _ := primitive.ObjectIDFromHex(reviewerID)
+	approval := &domain.OpportunityApproval{OpportunityID: opp.ID, Snapshot: *opp, ApprovedBy: reviewerObjID, ApprovedAt: now}
+	if err := s.approvalRepo.Save(ctx, approval); err != nil {
+		logger.ErrorContext(ctx, "Failed to save opportunity approval snapshot", "opportunityId", id, "error", err)
+	}
+	return opp, nil
+}
+
+// ChangeStatus 由管理員暫停或恢復工作機會。
+// 送審中的機會只能透過 Review 審核，確保每次上架都經過同一流程並留下 StatusNote。
+func (s *opportunityModerationService) ChangeStatus(ctx context.Context, adminID, id string, status domain.OpportunityStatus, reason string) (*domain.Opportunity, error) {
+	opp, err := s.oppRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrOpportunityNotFound)
+	}
+	if opp.Status == domain.OpportunityStatusPending {
+		return nil, ErrOpportunityPendingReview
+	}
+
+	return s.oppService.ChangeStatus(ctx, id, status, domain.ActorAdmin, adminID, reason)
+}
+
+// GetChanges 比對工作機會目前的內容與最近一次通過審核時的內容
+func (s *opportunityModerationService) GetChanges(ctx context.Context, id string) (*domain.OpportunityChanges, error) {
+	opp, err := s.oppRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrOpportunityNotFound)
+	}
+
+	result := &domain.OpportunityChanges{OpportunityID: opp.ID, Changes: []domain.OpportunityFieldChange{}}
+	approval, err := s.approvalRepo.GetByOpportunityID(ctx, opp.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result.ApprovedBy = approval.ApprovedBy
+	result.ApprovedAt = &approval.ApprovedAt
+	result.Changes, err = diffOpportunities(&approval.Snapshot, opp)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// diffOpportunities 以 bson 路徑逐欄比對兩個版本，陣列視為單一欄位整體比較
+func diffOpportunities(before, after *domain.Opportunity) ([]domain.OpportunityFieldChange, error) {
+	beforeFields, err := editableFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := editableFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(beforeFields)+len(afterFields))
+	for k := range beforeFields {
+		keys = append(keys, k)
+	}
+	for k := range afterFields {
+		if _, ok := beforeFields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := []domain.OpportunityFieldChange{}
+	for _, k := range keys {
+		b, a := beforeFields[k], afterFields[k]
+		if !reflect.DeepEqual(b, a) {
+			changes = append(changes, domain.OpportunityFieldChange{Field: k, Before: b, After: a})
+		}
+	}
+	return changes, nil
+}
+
+// editableFields 將工作機會攤平為 bson 路徑 -> 值，並去除系統維護的欄位與時段的報名人數
+func editableFields(opp *domain.Opportunity) (map[string]any, error) {
+	content := *opp
+	content.TimeSlots = make([]domain.TimeSlot, len(opp.TimeSlots))
+	for i, slot := range opp.TimeSlots {
+		slot.AppliedCount = 0
+		slot.ConfirmedCount = 0
+		slot.Status = ""
+		slot.MonthlyCapacities = make([]domain.MonthlyCapacity, len(slot.MonthlyCapacities))
+		for j, mc := range opp.TimeSlots[i].MonthlyCapacities {
+			mc.BookedCount = 0
+			slot.MonthlyCapacities[j] = mc
+		}
+		content.TimeSlots[i] = slot
+	}
+
+	raw, err := bson.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	fields := map[string]any{}
+	flattenDocument("", doc, fields)
+	for k := range fields {
+		if diffIgnoredFields[k] || diffIgnoredFields[strings.SplitN(k, ".", 2)[0]] {
+			delete(fields, k)
+		}
+	}
+	return fields, nil
+}
+
+func flattenDocument(prefix string, doc bson.M, out map[string]any) {
+	for k, v := range doc {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch nested := v.(type) {
+		case bson.M:
+			flattenDocument(key, nested, out)
+		case bson.D:
+			m := bson.M{}
+			for _, e := range nested {
+				m[e.Key] = e.Value
+			}
+			flattenDocument(key, m, out)
+		default:
+			out[key] = v
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MockOpportunityApprovalRepository struct {
+	mock.Mock
+}
+
+func (m *MockOpportunityApprovalRepository) Save(ctx context.Context, approval *domain.OpportunityApproval) error {
+	args := m.Called(ctx, approval)
+	return args.Error(0)
+}
+
+func (m *MockOpportunityApprovalRepository) GetByOpportunityID(ctx context.Context, oppID primitive.ObjectID) (*domain.OpportunityApproval, error) {
+	args := m.Called(ctx, oppID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OpportunityApproval), args.Error(1)
+}
+
+func TestScreenOpportunity(t *testing.T) {
+	cfg := config.ModerationConfig{
+		BannedWords:       []string{"deposit"},
+		RequiredFields:    []string{"description", "timeSlots", "unknown"},
+		StipendCurrency:   "TWD",
+		MinMonthlyStipend: 1,
+		MaxMonthlyStipend: 60000,
+	}
+
+	opp := &domain.Opportunity{
+		Title:        "Farm Helper",
+		Description:  "Pay a DEPOSIT before arrival",
+		Translations: map[string]domain.OpportunityTranslation{"en": {Title: "No deposit needed"}},
+	}
+	opp.Benefits.Stipend.Provided = true
+	opp.Benefits.Stipend.Amount = 5000
+	opp.Benefits.Stipend.Frequency = "daily"
+
+	flags := screenOpportunity(cfg, opp)
+
+	assert.Equal(t, []domain.ModerationFlag{
+		{Code: domain.ModerationFlagBannedWord, Field: "description", Detail: "deposit"},
+		{Code: domain.ModerationFlagBannedWord, Field: "translations.en.title", Detail: "deposit"},
+		{Code: domain.ModerationFlagMissingField, Field: "timeSlots"},
+		{Code: domain.ModerationFlagSuspiciousStipend, Field: "benefits.stipend.amount", Detail: "5000/daily"},
+	}, flags)
+
+	t.Run("Other Currency Is Not Checked", func(t *testing.T) {
+		opp.Benefits.Stipend.Currency = "USD"
+		_, flagged := screenStipend(cfg, opp)
+		assert.False(t, flagged)
+	})
+
+	t.Run("Zero Stipend", func(t *testing.T) {
+		opp.Benefits.Stipend.Currency = "TWD"
+		opp.Benefits.Stipend.Amount = 0
+		_, flagged := screenStipend(cfg, opp)
+		assert.True(t, flagged)
+	})
+}
+
+func TestReviewOpportunity(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	hostID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	reviewerID := primitive.NewObjectID()
+
+	setup := func(status domain.OpportunityStatus) (*MockOpportunityRepository, *MockHostRepository, *MockOpportunityApprovalRepository, *MockNotificationService, OpportunityModerationService, *domain.Opportunity) {
+		mockOppRepo := new(MockOpportunityRepository)
+		mockHostRepo := new(MockHostRepository)
+		mockApprovalRepo := new(MockOpportunityApprovalRepository)
+		mockNotif := new(MockNotificationService)
+		oppService := NewOpportunityService(mockOppRepo, mockHostRepo, mockApprovalRepo, mockNotif, &config.Config{})
+		opp := &domain.Opportunity{ID: primitive.NewObjectID(), HostID: hostID, Title: "Farm Helper", Status: status}
+		mockOppRepo.On("GetByID", ctx, opp.ID.Hex()).Return(opp, nil)
+		return mockOppRepo, mockHostRepo, mockApprovalRepo, mockNotif, NewOpportunityModerationService(mockOppRepo, mockApprovalRepo, oppService), opp
+	}
+
+	t.Run("Approve Saves Snapshot", func(t *testing.T) {
+		mockOppRepo, mockHostRepo, mockApprovalRepo, mockNotif, service, opp := setup(domain.OpportunityStatusPending)
+		mockHostRepo.On("GetByID", ctx, hostID.Hex()).Return(&domain.Host{ID: hostID, UserID: ownerID, Status: domain.HostStatusActive}, nil)
+		mockOppRepo.On("UpdateStatus", ctx, opp.ID.Hex(), domain.OpportunityStatusPending, mock.AnythingOfType("domain.OpportunityStatusHistory"), mock.MatchedBy(func(fields bson.M) bool {
+			return fields["lastApprovedAt"] != nil
+		})).Return(nil)
+		mockApprovalRepo.On("Save", ctx, mock.MatchedBy(func(a *domain.OpportunityApproval) bool {
+			return a.OpportunityID == opp.ID && a.ApprovedBy == reviewerID && a.Snapshot.Status == domain.OpportunityStatusActive
+		})).Return(nil)
+		mockNotif.On("SendNotification", ctx, ownerID.Hex(), domain.NotificationTypeOpportunityPublished, mock.Anything).Return(nil)
+
+		updated, err := service.Review(ctx, reviewerID.Hex(), opp.ID.Hex(), domain.OpportunityReviewApprove, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OpportunityStatusActive, updated.Status)
+		assert.NotNil(t, updated.LastApprovedAt)
+		mockApprovalRepo.AssertExpectations(t)
+		mockNotif.AssertExpectations(t)
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		mockOppRepo, mockHostRepo, mockApprovalRepo, mockNotif, service, opp := setup(domain.OpportunityStatusPending)
+		mockHostRepo.On("GetByID", ctx, hostID.Hex()).Return(&domain.Host{ID: hostID, UserID: ownerID}, nil)
+		mockOppRepo.On("UpdateStatus", ctx, opp.ID.Hex(), domain.OpportunityStatusPending, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+			return e.Status == domain.OpportunityStatusRejected && e.Reason == "missing photos"
+		}), mock.Anything).Return(nil)
+		mockNotif.On("SendNotification", ctx, ownerID.Hex(), domain.NotificationTypeOpportunityRejected, mock.Anything).Return(nil)
+
+		updated, err := service.Review(ctx, reviewerID.Hex(), opp.ID.Hex(), domain.OpportunityReviewReject, "missing photos")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "missing photos", updated.StatusNote)
+		mockApprovalRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not Pending", func(t *testing.T) {
+		mockOppRepo, _, _, _, service, opp := setup(domain.OpportunityStatusDraft)
+
+		_, err := service.Review(ctx, reviewerID.Hex(), opp.ID.Hex(), domain.OpportunityReviewApprove, "")
+
+		assert.ErrorIs(t, err, ErrOpportunityNotPendingReview)
+		mockOppRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestReviewOpportunityEdits(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	hostID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	reviewerID := primitive.NewObjectID()
+	editedAt := time.Now().Add(-time.Hour)
+
+	setup := func() (*MockOpportunityRepository, *MockHostRepository, *MockOpportunityApprovalRepository, *MockNotificationService, OpportunityModerationService, *domain.Opportunity) {
+		mockOppRepo := new(MockOpportunityRepository)
+		mockHostRepo := new(MockHostRepository)
+		mockApprovalRepo := new(MockOpportunityApprovalRepository)
+		mockNotif := new(MockNotificationService)
+		oppService := NewOpportunityService(mockOppRepo, mockHostRepo, mockApprovalRepo, mockNotif, &config.Config{})
+		opp := &domain.Opportunity{ID: primitive.NewObjectID(), HostID: hostID, Title: "Farm Helper", Status: domain.OpportunityStatusActive, LastEditedAt: &editedAt}
+		mockOppRepo.On("GetByID", ctx, opp.ID.Hex()).Return(opp, nil)
+		return mockOppRepo, mockHostRepo, mockApprovalRepo, mockNotif, NewOpportunityModerationService(mockOppRepo, mockApprovalRepo, oppService), opp
+	}
+
+	t.Run("Approve Clears Edit Marker", func(t *testing.T) {
+		mockOppRepo, _, mockApprovalRepo, _, service, opp := setup()
+		mockOppRepo.On("MarkEditsReviewed", ctx, opp.ID.Hex(), editedAt, mock.AnythingOfType("time.Time")).Return(nil)
+		mockApprovalRepo.On("Save", ctx, mock.MatchedBy(func(a *domain.OpportunityApproval) bool {
+			return a.OpportunityID == opp.ID && a.ApprovedBy == reviewerID && a.Snapshot.LastEditedAt == nil
+		})).Return(nil)
+
+		updated, err := service.Review(ctx, reviewerID.Hex(), opp.ID.Hex(), domain.OpportunityReviewApprove, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OpportunityStatusActive, updated.Status)
+		assert.Nil(t, updated.LastEditedAt)
+		assert.NotNil(t, updated.LastApprovedAt)
+		mockApprovalRepo.AssertExpectations(t)
+		mockOppRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Edited Again During Review", func(t *testing.T) {
+		mockOppRepo, _, mockApprovalRepo, _, service, opp := setup()
+		mockOppRepo.On("MarkEditsReviewed", ctx, opp.ID.Hex(), editedAt, mock.AnythingOfType("time.Time")).Return(mongo.ErrNoDocuments)
+
+		_, err := service.Review(ctx, reviewerID.Hex(), opp.ID.Hex(), domain.OpportunityReviewApprove, "")
+
+		assert.ErrorIs(t, err, ErrOpportunityEditedAgain)
+		mockApprovalRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reject Pauses Listing", func(t *testing.T) {
+		mockOppRepo, mockHostRepo, _, mockNotif, service, opp := setup()
+		mockOppRepo.On("UpdateStatus", ctx, opp.ID.Hex(), domain.OpportunityStatusActive, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+			return e.Status == domain.OpportunityStatusAdminPaused && e.Reason == "misleading photos"
+		}), mock.Anything).Return(nil)
+		mockHostRepo.On("GetByID", ctx, hostID.Hex()).Return(&domain.Host{ID: hostID, UserID: ownerID}, nil)
+		mockNotif.On("SendNotification", ctx, ownerID.Hex(), domain.NotificationTypeOpportunityAdminPaused, mock.Anything).Return(nil)
+
+		updated, err := service.Review(ctx, reviewerID.Hex(), opp.ID.Hex(), domain.OpportunityReviewReject, "misleading photos")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OpportunityStatusAdminPaused, updated.Status)
+		mockOppRepo.AssertNotCalled(t, "MarkEditsReviewed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestChangeOpportunityStatusByAdmin(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	adminID := primitive.NewObjectID()
+
+	setup := func(status domain.OpportunityStatus) (*MockOpportunityRepository, *MockHostRepository, *MockNotificationService, OpportunityModerationService, *domain.Opportunity) {
+		mockOppRepo := new(MockOpportunityRepository)
+		mockHostRepo := new(MockHostRepository)
+		mockNotif := new(MockNotificationService)
+		oppService := NewOpportunityService(mockOppRepo, mockHostRepo, nil, mockNotif, &config.Config{})
+		opp := &domain.Opportunity{ID: primitive.NewObjectID(), HostID: primitive.NewObjectID(), Status: status}
+		mockOppRepo.On("GetByID", ctx, opp.ID.Hex()).Return(opp, nil)
+		return mockOppRepo, mockHostRepo, mockNotif, NewOpportunityModerationService(mockOppRepo, nil, oppService), opp
+	}
+
+	t.Run("Pending Must Be Reviewed", func(t *testing.T) {
+		mockOppRepo, _, _, service, opp := setup(domain.OpportunityStatusPending)
+
+		_, err := service.ChangeStatus(ctx, adminID.Hex(), opp.ID.Hex(), domain.OpportunityStatusActive, "")
+
+		assert.ErrorIs(t, err, ErrOpportunityPendingReview)
+		mockOppRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Admin Pause", func(t *testing.T) {
+		mockOppRepo, mockHostRepo, mockNotif, service, opp := setup(domain.OpportunityStatusActive)
+		mockHostRepo.On("GetByID", ctx, opp.HostID.Hex()).Return(&domain.Host{ID: opp.HostID, UserID: primitive.NewObjectID()}, nil)
+		mockOppRepo.On("UpdateStatus", ctx, opp.ID.Hex(), domain.OpportunityStatusActive, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+			return e.Status == domain.OpportunityStatusAdminPaused && e.ChangedBy == adminID
+		}), mock.Anything).Return(nil)
+		mockNotif.On("SendNotification", ctx, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		updated, err := service.ChangeStatus(ctx, adminID.Hex(), opp.ID.Hex(), domain.OpportunityStatusAdminPaused, "reported")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OpportunityStatusAdminPaused, updated.Status)
+	})
+}
+
+func TestGetOpportunityChanges(t *testing.T) {
+	ctx := context.Background()
+	mockOppRepo := new(MockOpportunityRepository)
+	mockApprovalRepo := new(MockOpportunityApprovalRepository)
+	service := NewOpportunityModerationService(mockOppRepo, mockApprovalRepo, nil)
+
+	approvedAt := time.Now().Add(-24 * time.Hour)
+	approved := domain.Opportunity{
+		ID:        primitive.NewObjectID(),
+		Title:     "Farm Helper",
+		Status:    domain.OpportunityStatusActive,
+		TimeSlots: []domain.TimeSlot{{StartDate: "2026-07-01", EndDate: "2026-09-30", DefaultCapacity: 2}},
+	}
+	approved.Benefits.Stipend.Amount = 3000
+
+	current := approved
+	current.Title = "Organic Farm Helper"
+	current.Status = domain.OpportunityStatusPaused
+	current.Stats.Views = 120
+	current.Benefits.Stipend.Amount = 9000
+	current.TimeSlots = []domain.TimeSlot{{StartDate: "2026-07-01", EndDate: "2026-09-30", DefaultCapacity: 2, AppliedCount: 3}}
+
+	mockOppRepo.On("GetByID", ctx, current.ID.Hex()).Return(&current, nil)
+
+	t.Run("Since Last Approval", func(t *testing.T) {
+		mockApprovalRepo.On("GetByOpportunityID", ctx, current.ID).Return(&domain.OpportunityApproval{
+			OpportunityID: approved.ID,
+			Snapshot:      approved,
+			ApprovedAt:    approvedAt,
+		}, nil).Once()
+
+		changes, err := service.GetChanges(ctx, current.ID.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, approvedAt, *changes.ApprovedAt)
+		assert.Equal(t, []domain.OpportunityFieldChange{
+			{Field: "benefits.stipend.amount", Before: float64(3000), After: float64(9000)},
+			{Field: "title", Before: "Farm Helper", After: "Organic Farm Helper"},
+		}, changes.Changes)
+	})
+
+	t.Run("Never Approved", func(t *testing.T) {
+		mockApprovalRepo.On("GetByOpportunityID", ctx, current.ID).Return(nil, mongo.ErrNoDocuments).Once()
+
+		changes, err := service.GetChanges(ctx, current.ID.Hex())
+
+		assert.NoError(t, err)
+		assert.Nil(t, changes.ApprovedAt)
+		assert.Empty(t, changes.Changes)
+	})
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+)
+
+// screeningFieldChecks 是可以在 moderation.required_fields 設定的必填欄位，回傳 false 代表缺少
+var screeningFieldChecks = map[string]func(opp *domain.Opportunity) bool{
+	"description":       func(opp *domain.Opportunity) bool { return strings.TrimSpace(opp.Description) != "" },
+	"shortDescription":  func(opp *domain.Opportunity) bool { return strings.TrimSpace(opp.ShortDescription) != "" },
+	"location.city":     func(opp *domain.Opportunity) bool { return opp.Location.City != "" },
+	"location.country":  func(opp *domain.Opportunity) bool { return opp.Location.Country != "" },
+	"workDetails.tasks": func(opp *domain.Opportunity) bool { return len(opp.WorkDetails.Tasks) > 0 },
+	"media.coverImage":  func(opp *domain.Opportunity) bool { return opp.Media.CoverImage != nil },
+	"timeSlots":         func(opp *domain.Opportunity) bool { return len(opp.TimeSlots) > 0 },
+}
+
+// stipendMonthlyFactor 將不同發放頻率的津貼換算為每月金額
+var stipendMonthlyFactor = map[string]float64{
+	"daily":   30,
+	"weekly":  52.0 / 12,
+	"monthly": 1,
+}
+
+// screenOpportunity 依設定檢查禁用詞、必填欄位與津貼金額，回傳需要管理員留意的項目
+func screenOpportunity(cfg config.ModerationConfig, opp *domain.Opportunity) []domain.ModerationFlag {
+	var flags []domain.ModerationFlag
+
+	type screenedText struct{ field, text string }
+	texts := []screenedText{
+		{"title", opp.Title},
+		{"shortDescription", opp.ShortDescription},
+		{"description", opp.Description},
+	}
+	locales := make([]string, 0, len(opp.Translations))
+	for locale := range opp.Translations {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	for _, locale := range locales {
+		tr := opp.Translations[locale]
+		prefix := "translations." + locale + "."
+		texts = append(texts,
+			screenedText{prefix + "title", tr.Title},
+			screenedText{prefix + "shortDescription", tr.ShortDescription},
+			screenedText{prefix + "description", tr.Description},
+		)
+	}
+	for _, t := range texts {
+		lower := strings.ToLower(t.text)
+		for _, word := range cfg.BannedWords {
+			word = strings.TrimSpace(word)
+			if word != "" && strings.Contains(lower, strings.ToLower(word)) {
+				flags = append(flags, domain.ModerationFlag{Code: domain.ModerationFlagBannedWord, Field: t.field, Detail: word})
+			}
+		}
+	}
+
+	for _, field := range cfg.RequiredFields {
+		if check, ok := screeningFieldChecks[field]; ok && !check(opp) {
+			flags = append(flags, domain.ModerationFlag{Code: domain.ModerationFlagMissingField, Field: field})
+		}
+	}
+
+	if flag, ok := screenStipend(cfg, opp); ok {
+		flags = append(flags, flag)
+	}
+	return flags
+}
+
+// screenStipend 只檢查幣別與設定相同 (或未填) 的津貼，換算為每月金額後超出範圍即標記
+func screenStipend(cfg config.ModerationConfig, opp *domain.Opportunity) (domain.ModerationFlag, bool) {
+	stipend := opp.Benefits.Stipend
+	if !stipend.Provided {
+		return domain.ModerationFlag{}, false
+	}
+	if stipend.Currency != "" && !strings.EqualFold(stipend.Currency, cfg.StipendCurrency) {
+		return domain.ModerationFlag{}, false
+	}
+
+	factor, ok := stipendMonthlyFactor[strings.ToLower(stipend.Frequency)]
+	if !ok {
+		factor = 1
+	}
+	monthly := stipend.Amount * factor
+	if monthly >= cfg.MinMonthlyStipend && (cfg.MaxMonthlyStipend <= 0 || monthly <= cfg.MaxMonthlyStipend) {
+		return domain.ModerationFlag{}, false
+	}
+	detail := strings.TrimSpace(fmt.Sprintf("%g %s", stipend.Amount, stipend.Currency))
+	if stipend.Frequency != "" {
+		detail += "/" + stipend.Frequency
+	}
+	return domain.ModerationFlag{
+		Code:   domain.ModerationFlagSuspiciousStipend,
+		Field:  "benefits.stipend.amount",
+		Detail: detail,
+	}, true
+}
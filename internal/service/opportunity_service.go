@@ -2,31 +2,62 @@ package service
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+var (
+	ErrInvalidProfileField      = errcode.BadRequest("INVALID_PROFILE_FIELD", "invalid required profile field")
+	ErrHostNotActive            = errcode.Forbidden("HOST_NOT_ACTIVE", "host must be active to publish opportunities")
+	ErrOpportunityStatusChanged = errcode.Conflict("OPPORTUNITY_STATUS_CHANGED", "the opportunity status changed while it was being edited, reload and try again")
+)
+
+// rescreenFlaggedReason 記錄在上架後修改出現新篩檢結果而送回審核的工作機會上
+const rescreenFlaggedReason = "Edited content was flagged by automatic screening"
+
 type OpportunityService interface {
 	CreateOpportunity(ctx context.Context, opp *domain.Opportunity) (*domain.Opportunity, error)
 	GetOpportunityByID(ctx context.Context, id string) (*domain.Opportunity, error)
 	ListOpportunities(ctx context.Context, filter bson.M, limit, offset int64) ([]*domain.Opportunity, error)
 	UpdateOpportunity(ctx context.Context, id string, opp *domain.Opportunity) error
-	DeleteOpportunity(ctx context.Context, id string) error
+	DeleteOpportunity(ctx context.Context, id string, actor domain.Actor, actorID, reason string) error
+	ChangeStatus(ctx context.Context, id string, to domain.OpportunityStatus, actor domain.Actor, actorID, reason string) (*domain.Opportunity, error)
 	SearchOpportunities(ctx context.Context, filter repository.OpportunityFilter) ([]*domain.Opportunity, int64, error)
 }
 
 type opportunityService struct {
-	repo repository.OpportunityRepository
+	repo         repository.OpportunityRepository
+	hostRepo     repository.HostRepository
+	approvalRepo repository.OpportunityApprovalRepository
+	notifService NotificationService
+	moderation   config.ModerationConfig
 }
 
-func NewOpportunityService(repo repository.OpportunityRepository) OpportunityService {
-	return &opportunityService{repo: repo}
+func NewOpportunityService(repo repository.OpportunityRepository, hostRepo repository.HostRepository, approvalRepo repository.OpportunityApprovalRepository, notifService NotificationService, cfg *config.Config) OpportunityService {
+	return &opportunityService{
+		repo:         repo,
+		hostRepo:     hostRepo,
+		approvalRepo: approvalRepo,
+		notifService: notifService,
+		moderation:   cfg.Moderation,
+	}
 }
 
 func (s *opportunityService) CreateOpportunity(ctx context.Context, opp *domain.Opportunity) (*domain.Opportunity, error) {
+	if err := validateRequiredProfileFields(opp); err != nil {
+		return nil, err
+	}
+
 	// Generate Slug
 	if opp.Slug == "" {
 		opp.Slug = generateSlug(opp.Title)
@@ -37,9 +68,18 @@ func (s *opportunityService) CreateOpportunity(ctx context.Context, opp *domain.
 		opp.PublicID = uuid.New().String()
 	}
 
-	// Set default status
-	if opp.Status == "" {
-		opp.Status = domain.OpportunityStatusDraft
+	// 新建立的工作機會一律為草稿，之後透過 ChangeStatus 送審與上架
+	opp.Status = domain.OpportunityStatusDraft
+	opp.StatusNote = ""
+	opp.StatusHistory = nil
+	opp.SubmittedAt = nil
+	opp.LastApprovedAt = nil
+	opp.ExpiryNotifiedAt = nil
+	opp.ModerationFlags = screenOpportunity(s.moderation, opp)
+
+	// 未指定內容語系時視為與請求相同
+	if opp.DefaultLocale == "" {
+		opp.DefaultLocale = string(requestLocale(ctx))
 	}
 
 	err := s.repo.Create(ctx, opp)
@@ -50,21 +90,200 @@ func (s *opportunityService) CreateOpportunity(ctx context.Context, opp *domain.
 }
 
 func (s *opportunityService) GetOpportunityByID(ctx context.Context, id string) (*domain.Opportunity, error) {
-	return s.repo.GetByID(ctx, id)
+	opp, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrOpportunityNotFound)
+	}
+	return opp, nil
 }
 
 func (s *opportunityService) ListOpportunities(ctx context.Context, filter bson.M, limit, offset int64) ([]*domain.Opportunity, error) {
 	return s.repo.List(ctx, filter, limit, offset)
 }
 
+// UpdateOpportunity 更新工作機會內容並重新篩檢；狀態相關欄位沿用原本的值，只能透過 ChangeStatus 變更。
+// 已通過審核的工作機會會記錄修改時間供後台檢視，重新篩檢出現新的結果時由系統送回審核。
 func (s *opportunityService) UpdateOpportunity(ctx context.Context, id string, opp *domain.Opportunity) error {
-	return s.repo.Update(ctx, id, opp)
+	if err := validateRequiredProfileFields(opp); err != nil {
+		return err
+	}
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return notFound(err, ErrOpportunityNotFound)
+	}
+	opp.Status = existing.Status
+	opp.StatusNote = existing.StatusNote
+	opp.StatusHistory = existing.StatusHistory
+	opp.SubmittedAt = existing.SubmittedAt
+	opp.LastApprovedAt = existing.LastApprovedAt
+	opp.LastEditedAt = existing.LastEditedAt
+	if existing.Status.IsLive() {
+		now := time.Now()
+		opp.LastEditedAt = &now
+	}
+	opp.ModerationFlags = screenOpportunity(s.moderation, opp)
+	// 到期時間改變時重新通知
+	oldExpiry, _ := existing.ExpiresAt(time.UTC)
+	newExpiry, _ := opp.ExpiresAt(time.UTC)
+	if oldExpiry.Equal(newExpiry) {
+		opp.ExpiryNotifiedAt = existing.ExpiryNotifiedAt
+	} else {
+		opp.ExpiryNotifiedAt = nil
+	}
+	if err := s.repo.Update(ctx, id, existing.Status, opp); err != nil {
+		// 讀取後狀態已被其他請求或排程變更，沿用舊狀態覆寫會還原該變更
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrOpportunityStatusChanged
+		}
+		return err
+	}
+
+	if existing.Status.IsLive() && hasNewModerationFlags(existing.ModerationFlags, opp.ModerationFlags) {
+		if _, err := s.ChangeStatus(ctx, id, domain.OpportunityStatusPending, domain.ActorSystem, "", rescreenFlaggedReason); err != nil {
+			return err
+		}
+		logger.InfoContext(ctx, "Returned edited opportunity to review", "opportunityId", id, "flags", len(opp.ModerationFlags))
+	}
+	return nil
 }
 
-func (s *opportunityService) DeleteOpportunity(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+// hasNewModerationFlags 回傳 after 是否包含 before 沒有的篩檢結果
+func hasNewModerationFlags(before, after []domain.ModerationFlag) bool {
+	seen := make(map[domain.ModerationFlag]bool, len(before))
+	for _, flag := range before {
+		seen[flag] = true
+	}
+	for _, flag := range after {
+		if !seen[flag] {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeStatus 依狀態機變更工作機會狀態並寫入 StatusHistory，reason 同時成為 StatusNote。
+// 上架前需確認接待主為 ACTIVE；送審時重新篩檢，審核通過時保存當下內容供之後比對並清除修改紀錄；
+// 管理員或系統變更狀態時會通知接待主。
+func (s *opportunityService) ChangeStatus(ctx context.Context, id string, to domain.OpportunityStatus, actor domain.Actor, actorID, reason string) (*domain.Opportunity, error) {
+	opp, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrOpportunityNotFound)
+	}
+	from := opp.Status
+	if !from.CanTransitionTo(to, actor) {
+		return nil, invalidTransition(from, to)
+	}
+	if to == domain.OpportunityStatusActive {
+		if err := s.checkCanPublish(ctx, opp); err != nil {
+			return nil, err
+		}
+	}
+
+	actorObjID, _ := primitive.ObjectIDFromHex(actorID)
+	now := time.Now()
+	entry := domain.OpportunityStatusHistory{
+		Status:    to,
+		Reason:    reason,
+		ChangedBy: actorObjID,
+		ChangedAt: now,
+	}
+	fields := bson.M{}
+	if to == domain.OpportunityStatusPending {
+		opp.SubmittedAt = &now
+		opp.ModerationFlags = screenOpportunity(s.moderation, opp)
+		fields["submittedAt"] = now
+		fields["moderationFlags"] = opp.ModerationFlags
+	}
+	approved := from == domain.OpportunityStatusPending && to == domain.OpportunityStatusActive
+	if approved {
+		opp.LastApprovedAt = &now
+		opp.LastEditedAt = nil
+		fields["lastApprovedAt"] = now
+		fields["lastEditedAt"] = nil
+	}
+	if err := s.repo.UpdateStatus(ctx, id, from, entry, fields); err != nil {
+		// 讀取後狀態已被其他請求變更
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, invalidTransition(from, to)
+		}
+		return nil, err
+	}
+	opp.Status = to
+	opp.StatusNote = reason
+	opp.StatusHistory = append(opp.StatusHistory, entry)
+
+	if approved {
+		// 保存失敗只影響之後的差異比對，不影響審核結果
+		approval := &domain.OpportunityApproval{OpportunityID: opp.ID, Snapshot: *opp, ApprovedBy: actorObjID, ApprovedAt: now}
+		if err := s.approvalRepo.Save(ctx, approval); err != nil {
+			logger.ErrorContext(ctx, "Failed to save opportunity approval snapshot", "opportunityId", opp.ID.Hex(), "error", err)
+		}
+	}
+	if actor != domain.ActorHost {
+		s.notifyStatusChanged(ctx, opp, reason)
+	}
+	return opp, nil
+}
+
+// notifyStatusChanged 通知接待主管理員或系統變更了工作機會狀態，通知失敗不影響狀態變更
+func (s *opportunityService) notifyStatusChanged(ctx context.Context, opp *domain.Opportunity, reason string) {
+	host, err := s.hostRepo.GetByID(ctx, opp.HostID.Hex())
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to load host for opportunity status notification", "opportunityId", opp.ID.Hex(), "error", err)
+		return
+	}
+
+	notifType := domain.NotificationTypeOpportunityStatusChanged
+	switch opp.Status {
+	case domain.OpportunityStatusActive:
+		notifType = domain.NotificationTypeOpportunityPublished
+	case domain.OpportunityStatusRejected:
+		notifType = domain.NotificationTypeOpportunityRejected
+	case domain.OpportunityStatusAdminPaused:
+		notifType = domain.NotificationTypeOpportunityAdminPaused
+	case domain.OpportunityStatusExpired:
+		notifType = domain.NotificationTypeOpportunityExpired
+	case domain.OpportunityStatusFilled:
+		notifType = domain.NotificationTypeOpportunityFilled
+	}
+	err = s.notifService.SendNotification(ctx, host.UserID.Hex(), notifType, map[string]string{
+		"opportunityId":    opp.ID.Hex(),
+		"opportunityTitle": opp.Title,
+		"status":           string(opp.Status),
+		"reason":           reason,
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to notify host of opportunity status change", "opportunityId", opp.ID.Hex(), "error", err)
+	}
+}
+
+// checkCanPublish 只有 ACTIVE 的接待主可以上架工作機會
+func (s *opportunityService) checkCanPublish(ctx context.Context, opp *domain.Opportunity) error {
+	host, err := s.hostRepo.GetByID(ctx, opp.HostID.Hex())
+	if err != nil {
+		return notFound(err, ErrHostNotFound)
+	}
+	if host.Status != domain.HostStatusActive {
+		return ErrHostNotActive.WithMeta("hostStatus", string(host.Status))
+	}
+	return nil
+}
+
+// DeleteOpportunity 以狀態機將工作機會標記為 DELETED 並寫入狀態歷程，管理員暫停中的工作機會只有管理員能刪除
+func (s *opportunityService) DeleteOpportunity(ctx context.Context, id string, actor domain.Actor, actorID, reason string) error {
+	_, err := s.ChangeStatus(ctx, id, domain.OpportunityStatusDeleted, actor, actorID, reason)
+	return err
 }
 
 func (s *opportunityService) SearchOpportunities(ctx context.Context, filter repository.OpportunityFilter) ([]*domain.Opportunity, int64, error) {
 	return s.repo.Search(ctx, filter)
 }
+
+func validateRequiredProfileFields(opp *domain.Opportunity) error {
+	for _, f := range opp.Requirements.RequiredProfileFields {
+		if !f.IsValid() {
+			return ErrInvalidProfileField.WithDetails(string(f))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestCreateOpportunity_AlwaysDraft(t *testing.T) {
+	ctx := context.Background()
+	mockOppRepo := new(MockOpportunityRepository)
+	mockHostRepo := new(MockHostRepository)
+	service := NewOpportunityService(mockOppRepo, mockHostRepo, new(MockOpportunityApprovalRepository), new(MockNotificationService), &config.Config{})
+
+	mockOppRepo.On("Create", ctx, mock.AnythingOfType("*domain.Opportunity")).Return(nil)
+
+	opp, err := service.CreateOpportunity(ctx, &domain.Opportunity{
+		HostID:        primitive.NewObjectID(),
+		Title:         "Farm Helper",
+		Status:        domain.OpportunityStatusActive,
+		StatusHistory: []domain.OpportunityStatusHistory{{Status: domain.OpportunityStatusActive}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OpportunityStatusDraft, opp.Status)
+	assert.Empty(t, opp.StatusHistory)
+	mockHostRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestUpdateOpportunity_KeepsStatus(t *testing.T) {
+	ctx := context.Background()
+	mockOppRepo := new(MockOpportunityRepository)
+	service := NewOpportunityService(mockOppRepo, new(MockHostRepository), new(MockOpportunityApprovalRepository), new(MockNotificationService), &config.Config{})
+
+	id := primitive.NewObjectID()
+	existing := &domain.Opportunity{
+		ID:            id,
+		Status:        domain.OpportunityStatusAdminPaused,
+		StatusNote:    "misleading photos",
+		StatusHistory: []domain.OpportunityStatusHistory{{Status: domain.OpportunityStatusAdminPaused}},
+	}
+	mockOppRepo.On("GetByID", ctx, id.Hex()).Return(existing, nil)
+	mockOppRepo.On("Update", ctx, id.Hex(), domain.OpportunityStatusAdminPaused, mock.MatchedBy(func(o *domain.Opportunity) bool {
+		return o.Status == domain.OpportunityStatusAdminPaused && o.StatusNote == "misleading photos" && len(o.StatusHistory) == 1
+	})).Return(nil)
+
+	err := service.UpdateOpportunity(ctx, id.Hex(), &domain.Opportunity{Title: "Farm Helper", Status: domain.OpportunityStatusActive})
+
+	assert.NoError(t, err)
+	mockOppRepo.AssertExpectations(t)
+}
+
+func TestUpdateOpportunity_StatusChangedConcurrently(t *testing.T) {
+	ctx := context.Background()
+	mockOppRepo := new(MockOpportunityRepository)
+	service := NewOpportunityService(mockOppRepo, new(MockHostRepository), new(MockOpportunityApprovalRepository), new(MockNotificationService), &config.Config{})
+
+	existing := &domain.Opportunity{ID: primitive.NewObjectID(), Status: domain.OpportunityStatusActive}
+	mockOppRepo.On("GetByID", ctx, existing.ID.Hex()).Return(existing, nil)
+	// 讀取後被管理員暫停，取代文件的條件不再成立
+	mockOppRepo.On("Update", ctx, existing.ID.Hex(), domain.OpportunityStatusActive, mock.AnythingOfType("*domain.Opportunity")).Return(mongo.ErrNoDocuments)
+
+	err := service.UpdateOpportunity(ctx, existing.ID.Hex(), &domain.Opportunity{Title: "Farm Helper"})
+
+	assert.ErrorIs(t, err, ErrOpportunityStatusChanged)
+	mockOppRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateOpportunity_LiveListing(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	hostID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	cfg := &config.Config{Moderation: config.ModerationConfig{BannedWords: []string{"deposit"}}}
+
+	setup := func() (*MockOpportunityRepository, *MockHostRepository, *MockNotificationService, OpportunityService, *domain.Opportunity) {
+		mockOppRepo := new(MockOpportunityRepository)
+		mockHostRepo := new(MockHostRepository)
+		mockNotif := new(MockNotificationService)
+		approvedAt := time.Now().Add(-24 * time.Hour)
+		existing := &domain.Opportunity{ID: primitive.NewObjectID(), HostID: hostID, Title: "Farm Helper", Status: domain.OpportunityStatusActive, LastApprovedAt: &approvedAt}
+		mockOppRepo.On("GetByID", ctx, existing.ID.Hex()).Return(existing, nil)
+		return mockOppRepo, mockHostRepo, mockNotif, NewOpportunityService(mockOppRepo, mockHostRepo, new(MockOpportunityApprovalRepository), mockNotif, cfg), existing
+	}
+
+	t.Run("Records Edit Time", func(t *testing.T) {
+		mockOppRepo, _, _, service, existing := setup()
+		mockOppRepo.On("Update", ctx, existing.ID.Hex(), domain.OpportunityStatusActive, mock.MatchedBy(func(o *domain.Opportunity) bool {
+			return o.Status == domain.OpportunityStatusActive && o.LastEditedAt != nil && o.LastApprovedAt == existing.LastApprovedAt
+		})).Return(nil)
+
+		err := service.UpdateOpportunity(ctx, existing.ID.Hex(), &domain.Opportunity{Title: "Farm Helper", Description: "Harvest tea"})
+
+		assert.NoError(t, err)
+		mockOppRepo.AssertExpectations(t)
+		mockOppRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("New Flags Return It To Review", func(t *testing.T) {
+		mockOppRepo, mockHostRepo, mockNotif, service, existing := setup()
+		mockOppRepo.On("Update", ctx, existing.ID.Hex(), domain.OpportunityStatusActive, mock.MatchedBy(func(o *domain.Opportunity) bool {
+			return len(o.ModerationFlags) == 1 && o.ModerationFlags[0].Code == domain.ModerationFlagBannedWord
+		})).Return(nil)
+		mockOppRepo.On("UpdateStatus", ctx, existing.ID.Hex(), domain.OpportunityStatusActive, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+			return e.Status == domain.OpportunityStatusPending && e.Reason == rescreenFlaggedReason
+		}), mock.Anything).Return(nil)
+		mockHostRepo.On("GetByID", ctx, hostID.Hex()).Return(&domain.Host{ID: hostID, UserID: ownerID, Status: domain.HostStatusActive}, nil)
+		mockNotif.On("SendNotification", ctx, ownerID.Hex(), domain.NotificationTypeOpportunityStatusChanged, mock.Anything).Return(nil)
+
+		err := service.UpdateOpportunity(ctx, existing.ID.Hex(), &domain.Opportunity{Title: "Farm Helper", Description: "Pay a deposit first"})
+
+		assert.NoError(t, err)
+		mockOppRepo.AssertExpectations(t)
+		mockNotif.AssertExpectations(t)
+	})
+
+	t.Run("Existing Flags Do Not Return It To Review", func(t *testing.T) {
+		mockOppRepo, _, _, service, existing := setup()
+		existing.ModerationFlags = []domain.ModerationFlag{{Code: domain.ModerationFlagBannedWord, Field: "description", Detail: "deposit"}}
+		mockOppRepo.On("Update", ctx, existing.ID.Hex(), domain.OpportunityStatusActive, mock.AnythingOfType("*domain.Opportunity")).Return(nil)
+
+		err := service.UpdateOpportunity(ctx, existing.ID.Hex(), &domain.Opportunity{Title: "Farm Helper", Description: "No deposit needed"})
+
+		assert.NoError(t, err)
+		mockOppRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestOpportunityStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to domain.OpportunityStatus
+		actor    domain.Actor
+		want     bool
+	}{
+		{domain.OpportunityStatusDraft, domain.OpportunityStatusPending, domain.ActorHost, true},
+		{domain.OpportunityStatusDraft, domain.OpportunityStatusActive, domain.ActorHost, false},
+		{domain.OpportunityStatusPending, domain.OpportunityStatusActive, domain.ActorHost, false},
+		{domain.OpportunityStatusPending, domain.OpportunityStatusActive, domain.ActorAdmin, true},
+		{domain.OpportunityStatusActive, domain.OpportunityStatusPaused, domain.ActorHost, true},
+		{domain.OpportunityStatusActive, domain.OpportunityStatusAdminPaused, domain.ActorHost, false},
+		{domain.OpportunityStatusAdminPaused, domain.OpportunityStatusActive, domain.ActorHost, false},
+		{domain.OpportunityStatusAdminPaused, domain.OpportunityStatusActive, domain.ActorAdmin, true},
+		{domain.OpportunityStatusPaused, domain.OpportunityStatusActive, domain.ActorHost, true},
+		{domain.OpportunityStatusActive, domain.OpportunityStatusExpired, domain.ActorSystem, true},
+		{domain.OpportunityStatusActive, domain.OpportunityStatusFilled, domain.ActorHost, true},
+		{domain.OpportunityStatusActive, domain.OpportunityStatusPending, domain.ActorSystem, true},
+		{domain.OpportunityStatusActive, domain.OpportunityStatusPending, domain.ActorHost, false},
+		{domain.OpportunityStatusActive, domain.OpportunityStatusDeleted, domain.ActorHost, true},
+		{domain.OpportunityStatusExpired, domain.OpportunityStatusDeleted, domain.ActorHost, true},
+		{domain.OpportunityStatusAdminPaused, domain.OpportunityStatusDeleted, domain.ActorHost, false},
+		{domain.OpportunityStatusAdminPaused, domain.OpportunityStatusDeleted, domain.ActorAdmin, true},
+		{domain.OpportunityStatusDeleted, domain.OpportunityStatusDraft, domain.ActorHost, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.from.CanTransitionTo(tt.to, tt.actor), "%s -> %s by %s", tt.from, tt.to, tt.actor)
+	}
+}
+
+func TestChangeOpportunityStatus(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	hostID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+	adminID := primitive.NewObjectID().Hex()
+
+	cfg := &config.Config{Moderation: config.ModerationConfig{RequiredFields: []string{"description"}}}
+
+	setup := func(status domain.OpportunityStatus) (*MockOpportunityRepository, *MockHostRepository, *MockNotificationService, OpportunityService, *domain.Opportunity) {
+		mockOppRepo := new(MockOpportunityRepository)
+		mockHostRepo := new(MockHostRepository)
+		mockNotif := new(MockNotificationService)
+		opp := &domain.Opportunity{ID: primitive.NewObjectID(), HostID: hostID, Title: "Farm Helper", Status: status}
+		mockOppRepo.On("GetByID", ctx, opp.ID.Hex()).Return(opp, nil)
+		return mockOppRepo, mockHostRepo, mockNotif, NewOpportunityService(mockOppRepo, mockHostRepo, new(MockOpportunityApprovalRepository), mockNotif, cfg), opp
+	}
+
+	t.Run("Host Submits Draft", func(t *testing.T) {
+		mockOppRepo, _, mockNotif, service, opp := setup(domain.OpportunityStatusDraft)
+		mockOppRepo.On("UpdateStatus", ctx, opp.ID.Hex(), domain.OpportunityStatusDraft, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+			return e.Status == domain.OpportunityStatusPending && e.ChangedBy == ownerID
+		}), mock.MatchedBy(func(fields bson.M) bool {
+			flags := fields["moderationFlags"].([]domain.ModerationFlag)
+			return fields["submittedAt"] != nil && len(flags) == 1 && flags[0].Field == "description"
+		})).Return(nil)
+
+		updated, err := service.ChangeStatus(ctx, opp.ID.Hex(), domain.OpportunityStatusPending, domain.ActorHost, ownerID.Hex(), "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.OpportunityStatusPending, updated.Status)
+		assert.Len(t, updated.StatusHistory, 1)
+		assert.NotNil(t, updated.SubmittedAt)
+		assert.Len(t, updated.ModerationFlags, 1)
+		mockNotif.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Host Cannot Publish", func(t *testing.T) {
+		mockOppRepo, _, _, service, opp := setup(domain.OpportunityStatusPending)
+
+		_, err := service.ChangeStatus(ctx, opp.ID.Hex(), domain.OpportunityStatusActive, domain.ActorHost, ownerID.Hex(), "")
+
+		assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+		mockOppRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Publish Requires Active Host", func(t *testing.T) {
+		mockOppRepo, mockHostRepo, _, service, opp := setup(domain.OpportunityStatusPending)
+		mockHostRepo.On("GetByID", ctx, hostID.Hex()).Return(&domain.Host{ID: hostID, Status: domain.HostStatusSuspended}, nil)
+
+		_, err := service.ChangeStatus(ctx, opp.ID.Hex(), domain.OpportunityStatusActive, domain.ActorAdmin, adminID, "")
+
+		assert.ErrorIs(t, err, ErrHostNotActive)
+		mockOppRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Admin Pause Notifies Host", func(t *testing.T) {
+		mockOppRepo, mockHostRepo, mockNotif, service, opp := setup(domain.OpportunityStatusActive)
+		mockOppRepo.On("UpdateStatus", ctx, opp.ID.Hex(), domain.OpportunityStatusActive, mock.AnythingOfType("domain.OpportunityStatusHistory"), mock.Anything).Return(nil)
+		mockHostRepo.On("GetByID", ctx, hostID.Hex()).Return(&domain.Host{ID: hostID, UserID: ownerID, Status: domain.HostStatusActive}, nil)
+		mockNotif.On("SendNotification", ctx, ownerID.Hex(), domain.NotificationTypeOpportunityAdminPaused, mock.MatchedBy(func(data map[string]string) bool {
+			return data["reason"] == "misleading photos" && data["opportunityId"] == opp.ID.Hex()
+		})).Return(nil)
+
+		updated, err := service.ChangeStatus(ctx, opp.ID.Hex(), domain.OpportunityStatusAdminPaused, domain.ActorAdmin, adminID, "misleading photos")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "misleading photos", updated.StatusNote)
+		mockNotif.AssertExpectations(t)
+	})
+
+	t.Run("Concurrent Change", func(t *testing.T) {
+		mockOppRepo, _, _, service, opp := setup(domain.OpportunityStatusActive)
+		mockOppRepo.On("UpdateStatus", ctx, opp.ID.Hex(), domain.OpportunityStatusActive, mock.AnythingOfType("domain.OpportunityStatusHistory"), mock.Anything).Return(mongo.ErrNoDocuments)
+
+		_, err := service.ChangeStatus(ctx, opp.ID.Hex(), domain.OpportunityStatusPaused, domain.ActorHost, ownerID.Hex(), "")
+
+		assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+	})
+}
+
+func TestDeleteOpportunity(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	hostID := primitive.NewObjectID()
+	ownerID := primitive.NewObjectID()
+
+	setup := func(status domain.OpportunityStatus) (*MockOpportunityRepository, *MockHostRepository, *MockNotificationService, OpportunityService, *domain.Opportunity) {
+		mockOppRepo := new(MockOpportunityRepository)
+		mockHostRepo := new(MockHostRepository)
+		mockNotif := new(MockNotificationService)
+		opp := &domain.Opportunity{ID: primitive.NewObjectID(), HostID: hostID, Title: "Farm Helper", Status: status}
+		mockOppRepo.On("GetByID", ctx, opp.ID.Hex()).Return(opp, nil)
+		return mockOppRepo, mockHostRepo, mockNotif, NewOpportunityService(mockOppRepo, mockHostRepo, new(MockOpportunityApprovalRepository), mockNotif, &config.Config{}), opp
+	}
+
+	t.Run("Host Deletes Active Listing", func(t *testing.T) {
+		mockOppRepo, _, _, service, opp := setup(domain.OpportunityStatusActive)
+		mockOppRepo.On("UpdateStatus", ctx, opp.ID.Hex(), domain.OpportunityStatusActive, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+			return e.Status == domain.OpportunityStatusDeleted && e.ChangedBy == ownerID && e.Reason == "season over"
+		}), mock.Anything).Return(nil)
+
+		err := service.DeleteOpportunity(ctx, opp.ID.Hex(), domain.ActorHost, ownerID.Hex(), "season over")
+
+		assert.NoError(t, err)
+		mockOppRepo.AssertExpectations(t)
+	})
+
+	t.Run("Host Cannot Delete Admin Paused Listing", func(t *testing.T) {
+		mockOppRepo, _, _, service, opp := setup(domain.OpportunityStatusAdminPaused)
+
+		err := service.DeleteOpportunity(ctx, opp.ID.Hex(), domain.ActorHost, ownerID.Hex(), "")
+
+		assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+		mockOppRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Admin Deletes Admin Paused Listing", func(t *testing.T) {
+		mockOppRepo, mockHostRepo, mockNotif, service, opp := setup(domain.OpportunityStatusAdminPaused)
+		mockOppRepo.On("UpdateStatus", ctx, opp.ID.Hex(), domain.OpportunityStatusAdminPaused, mock.MatchedBy(func(e domain.OpportunityStatusHistory) bool {
+			return e.Status == domain.OpportunityStatusDeleted
+		}), mock.Anything).Return(nil)
+		mockHostRepo.On("GetByID", ctx, hostID.Hex()).Return(&domain.Host{ID: hostID, UserID: ownerID}, nil)
+		mockNotif.On("SendNotification", ctx, ownerID.Hex(), domain.NotificationTypeOpportunityStatusChanged, mock.Anything).Return(nil)
+
+		err := service.DeleteOpportunity(ctx, opp.ID.Hex(), domain.ActorAdmin, primitive.NewObjectID().Hex(), "scam")
+
+		assert.NoError(t, err)
+		mockNotif.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,510 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrOrganizationNotFound         = errcode.NotFound("ORGANIZATION_NOT_FOUND", "organization not found")
+	ErrOrganizationMemberNotFound   = errcode.NotFound("ORGANIZATION_MEMBER_NOT_FOUND", "user is not a member of this organization")
+	ErrNotOrganizationMember        = errcode.Forbidden("NOT_ORGANIZATION_MEMBER", "you are not a member of this organization")
+	ErrOrganizationPermissionDenied = errcode.Forbidden("ORGANIZATION_PERMISSION_DENIED", "your organization role does not allow this action")
+	ErrAlreadyInOrganization        = errcode.Conflict("ALREADY_IN_ORGANIZATION", "user already belongs to an organization")
+	ErrLastOrganizationOwner        = errcode.Conflict("LAST_ORGANIZATION_OWNER", "an organization must keep at least one owner")
+	ErrNotHostOwner                 = errcode.Forbidden("NOT_HOST_OWNER", "only the host owner can add it to an organization")
+	ErrHostInOtherOrganization      = errcode.Conflict("HOST_IN_OTHER_ORGANIZATION", "host already belongs to another organization")
+
+	ErrOrganizationInvitationNotFound = errcode.NotFound("ORGANIZATION_INVITATION_NOT_FOUND", "invitation not found")
+	ErrOrganizationInvitationClosed   = errcode.Conflict("ORGANIZATION_INVITATION_CLOSED", "invitation has already been answered or has expired")
+	ErrOrganizationInvitationPending  = errcode.Conflict("ORGANIZATION_INVITATION_ALREADY_PENDING", "an invitation has already been sent to this email")
+)
+
+// organizationInvitationTTL 是組織成員邀請的有效期限
+const organizationInvitationTTL = 7 * 24 * time.Hour
+
+// OrganizationService 管理組織、成員角色與旗下接待主。
+// 使用者同時只能屬於一個組織；建立者成為擁有者 (OWNER)，其他成員需經邀請並由本人接受後才會加入。
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, userID string, org *domain.Organization) (*domain.Organization, error)
+	GetOrganization(ctx context.Context, userID, id string) (*domain.Organization, error)
+	UpdateOrganization(ctx context.Context, userID, id string, org *domain.Organization) (*domain.Organization, error)
+	InviteMember(ctx context.Context, userID, id, email string, role domain.OrganizationRole) (*domain.OrganizationInvitation, error)
+	ListInvitations(ctx context.Context, userID, id string) ([]*domain.OrganizationInvitation, error)
+	RevokeInvitation(ctx context.Context, userID, id, invitationID string) error
+	ListMyInvitations(ctx context.Context, userID string) ([]*domain.OrganizationInvitation, error)
+	AcceptInvitation(ctx context.Context, userID, invitationID string) (*domain.Organization, error)
+	DeclineInvitation(ctx context.Context, userID, invitationID string) error
+	UpdateMemberRole(ctx context.Context, userID, id, memberID string, role domain.OrganizationRole) error
+	RemoveMember(ctx context.Context, userID, id, memberID string) error
+	ListHosts(ctx context.Context, userID, id string) ([]*domain.Host, error)
+	AddHost(ctx context.Context, userID, id, hostID string) (*domain.Host, error)
+	RemoveHost(ctx context.Context, userID, id, hostID string) error
+	GetDashboard(ctx context.Context, userID, id string) (*domain.OrganizationDashboard, error)
+	ListApplications(ctx context.Context, userID, id string, filter OrganizationApplicationFilter) ([]*domain.Application, int64, error)
+	CanManageHost(ctx context.Context, userID string, host *domain.Host, permission domain.MemberPermission) (bool, error)
+}
+
+// OrganizationApplicationFilter 是組織申請列表的篩選條件，HostID 為空時列出所有旗下接待主
+type OrganizationApplicationFilter struct {
+	HostID string
+	Status domain.ApplicationStatus
+	Limit  int64
+	Offset int64
+}
+
+type organizationService struct {
+	repo           repository.OrganizationRepository
+	invitationRepo repository.OrganizationInvitationRepository
+	hostRepo       repository.HostRepository
+	userRepo       repository.UserRepository
+	appRepo        repository.ApplicationRepository
+	notifService   NotificationService
+}
+
+func NewOrganizationService(repo repository.OrganizationRepository, invitationRepo repository.OrganizationInvitationRepository, hostRepo repository.HostRepository, userRepo repository.UserRepository, appRepo repository.ApplicationRepository, notifService NotificationService) OrganizationService {
+	return &organizationService{
+		repo:           repo,
+		invitationRepo: invitationRepo,
+		hostRepo:       hostRepo,
+		userRepo:       userRepo,
+		appRepo:        appRepo,
+		notifService:   notifService,
+	}
+}
+
+// authorize 讀取組織並確認使用者為成員且角色擁有指定權限
+func (s *organizationService) authorize(ctx context.Context, userID, id string, permission domain.MemberPermission) (*domain.Organization, error) {
+	org, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFound(err, ErrOrganizationNotFound)
+	}
+	member := org.Member(userID)
+	if member == nil {
+		return nil, ErrNotOrganizationMember
+	}
+	if !member.Role.HasPermission(permission) {
+		return nil, ErrOrganizationPermissionDenied.WithMeta("permission", string(permission))
+	}
+	return org, nil
+}
+
+func (s *organizationService) CreateOrganization(ctx context.Context, userID string, org *domain.Organization) (*domain.Organization, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+	if user.OrganizationID != "" {
+		return nil, ErrAlreadyInOrganization
+	}
+
+	ownerID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	org.Members = []domain.OrganizationMember{{
+		UserID:  ownerID,
+		Role:    domain.OrganizationRoleOwner,
+		AddedAt: time.Now(),
+	}}
+	if err := s.repo.Create(ctx, org); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Update(ctx, userID, bson.M{"organizationId": org.ID.Hex()}); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// GetOrganization 只有成員可以查看組織資料與成員名單
+func (s *organizationService) GetOrganization(ctx context.Context, userID, id string) (*domain.Organization, error) {
+	return s.authorize(ctx, userID, id, domain.MemberPermissionViewDashboard)
+}
+
+func (s *organizationService) UpdateOrganization(ctx context.Context, userID, id string, org *domain.Organization) (*domain.Organization, error) {
+	existing, err := s.authorize(ctx, userID, id, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := bson.M{
+		"name":         org.Name,
+		"type":         org.Type,
+		"description":  org.Description,
+		"website":      org.Website,
+		"contactEmail": org.ContactEmail,
+	}
+	if err := s.repo.Update(ctx, id, fields); err != nil {
+		return nil, notFound(err, ErrOrganizationNotFound)
+	}
+
+	existing.Name = org.Name
+	existing.Type = org.Type
+	existing.Description = org.Description
+	existing.Website = org.Website
+	existing.ContactEmail = org.ContactEmail
+	return existing, nil
+}
+
+// InviteMember 以 Email 邀請已註冊的使用者加入組織，受邀者會收到通知，接受後才會成為成員
+func (s *organizationService) InviteMember(ctx context.Context, userID, id, email string, role domain.OrganizationRole) (*domain.OrganizationInvitation, error) {
+	org, err := s.authorize(ctx, userID, id, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	email = normalizeEmail(email)
+	invitee, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+	if invitee.OrganizationID != "" {
+		return nil, ErrAlreadyInOrganization
+	}
+
+	now := time.Now()
+	open, err := s.invitationRepo.HasOpen(ctx, org.ID, email, now)
+	if err != nil {
+		return nil, err
+	}
+	if open {
+		return nil, ErrOrganizationInvitationPending
+	}
+
+	invitedBy, _ := primitive.ObjectIDFromHex(userID)
+	inv := &domain.OrganizationInvitation{
+		OrganizationID:   org.ID,
+		OrganizationName: org.Name,
+		Email:            email,
+		Role:             role,
+		Status:           domain.OrganizationInvitationPending,
+		InvitedBy:        invitedBy,
+		ExpiresAt:        now.Add(organizationInvitationTTL),
+	}
+	if err := s.invitationRepo.Create(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	s.notifyInvitation(ctx, invitee.ID, domain.NotificationTypeOrganizationInvitation, inv)
+	return inv, nil
+}
+
+// ListInvitations 列出組織尚未回覆的邀請
+func (s *organizationService) ListInvitations(ctx context.Context, userID, id string) ([]*domain.OrganizationInvitation, error) {
+	org, err := s.authorize(ctx, userID, id, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return nil, err
+	}
+	return s.invitationRepo.ListByOrganizationID(ctx, org.ID, domain.OrganizationInvitationPending)
+}
+
+func (s *organizationService) RevokeInvitation(ctx context.Context, userID, id, invitationID string) error {
+	org, err := s.authorize(ctx, userID, id, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return err
+	}
+	inv, err := s.invitationRepo.GetByID(ctx, invitationID)
+	if err != nil {
+		return notFound(err, ErrOrganizationInvitationNotFound)
+	}
+	if inv.OrganizationID != org.ID {
+		return ErrOrganizationInvitationNotFound
+	}
+	return s.respondInvitation(ctx, inv, domain.OrganizationInvitationRevoked)
+}
+
+// ListMyInvitations 列出寄給使用者 Email 的有效邀請
+func (s *organizationService) ListMyInvitations(ctx context.Context, userID string) ([]*domain.OrganizationInvitation, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+	return s.invitationRepo.ListOpenByEmail(ctx, normalizeEmail(user.Email), time.Now())
+}
+
+// AcceptInvitation 接受邀請並加入組織，已屬於其他組織的使用者不能接受
+func (s *organizationService) AcceptInvitation(ctx context.Context, userID, invitationID string) (*domain.Organization, error) {
+	user, inv, err := s.invitationFor(ctx, userID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if user.OrganizationID != "" {
+		return nil, ErrAlreadyInOrganization
+	}
+	if _, err := s.repo.GetByID(ctx, inv.OrganizationID.Hex()); err != nil {
+		return nil, notFound(err, ErrOrganizationNotFound)
+	}
+
+	memberID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	// 先關閉邀請，避免同一份邀請被重複接受
+	if err := s.respondInvitation(ctx, inv, domain.OrganizationInvitationAccepted); err != nil {
+		return nil, err
+	}
+	member := domain.OrganizationMember{
+		UserID:  memberID,
+		Role:    inv.Role,
+		AddedBy: inv.InvitedBy,
+		AddedAt: time.Now(),
+	}
+	if err := s.repo.AddMember(ctx, inv.OrganizationID.Hex(), member); err != nil {
+		// 組織存在時查無資料代表使用者已是成員
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrAlreadyInOrganization.WithCause(err)
+		}
+		return nil, err
+	}
+	if err := s.userRepo.Update(ctx, user.ID, bson.M{"organizationId": inv.OrganizationID.Hex()}); err != nil {
+		return nil, err
+	}
+
+	org, err := s.repo.GetByID(ctx, inv.OrganizationID.Hex())
+	if err != nil {
+		return nil, notFound(err, ErrOrganizationNotFound)
+	}
+	s.notifyInvitation(ctx, inv.InvitedBy.Hex(), domain.NotificationTypeOrganizationInvitationAccepted, inv)
+	return org, nil
+}
+
+func (s *organizationService) DeclineInvitation(ctx context.Context, userID, invitationID string) error {
+	_, inv, err := s.invitationFor(ctx, userID, invitationID)
+	if err != nil {
+		return err
+	}
+	return s.respondInvitation(ctx, inv, domain.OrganizationInvitationDeclined)
+}
+
+// invitationFor 讀取寄給使用者 Email 且仍可回覆的邀請
+func (s *organizationService) invitationFor(ctx context.Context, userID, invitationID string) (*domain.User, *domain.OrganizationInvitation, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, notFound(err, ErrUserNotFound)
+	}
+	inv, err := s.invitationRepo.GetByID(ctx, invitationID)
+	if err != nil {
+		return nil, nil, notFound(err, ErrOrganizationInvitationNotFound)
+	}
+	// 寄給其他人的邀請視為不存在
+	if inv.Email != normalizeEmail(user.Email) {
+		return nil, nil, ErrOrganizationInvitationNotFound
+	}
+	if !inv.IsOpen(time.Now()) {
+		return nil, nil, ErrOrganizationInvitationClosed
+	}
+	return user, inv, nil
+}
+
+// respondInvitation 回覆邀請，邀請已被回覆時回傳 ErrOrganizationInvitationClosed
+func (s *organizationService) respondInvitation(ctx context.Context, inv *domain.OrganizationInvitation, status domain.OrganizationInvitationStatus) error {
+	now := time.Now()
+	if err := s.invitationRepo.Respond(ctx, inv.ID.Hex(), status, now); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrOrganizationInvitationClosed.WithCause(err)
+		}
+		return err
+	}
+	inv.Status = status
+	inv.RespondedAt = &now
+	return nil
+}
+
+// notifyInvitation 發送邀請相關通知，通知失敗不影響邀請結果
+func (s *organizationService) notifyInvitation(ctx context.Context, userID string, notifType domain.NotificationType, inv *domain.OrganizationInvitation) {
+	err := s.notifService.SendNotification(ctx, userID, notifType, map[string]string{
+		"invitationId":     inv.ID.Hex(),
+		"organizationId":   inv.OrganizationID.Hex(),
+		"organizationName": inv.OrganizationName,
+		"email":            inv.Email,
+		"role":             string(inv.Role),
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to send organization invitation notification", "invitationId", inv.ID.Hex(), "error", err)
+	}
+}
+
+func (s *organizationService) UpdateMemberRole(ctx context.Context, userID, id, memberID string, role domain.OrganizationRole) error {
+	org, err := s.authorize(ctx, userID, id, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return err
+	}
+	member := org.Member(memberID)
+	if member == nil {
+		return ErrOrganizationMemberNotFound
+	}
+	if member.Role == domain.OrganizationRoleOwner && role != domain.OrganizationRoleOwner && org.OwnerCount() == 1 {
+		return ErrLastOrganizationOwner
+	}
+	return notFound(s.repo.UpdateMemberRole(ctx, id, memberID, role), ErrOrganizationMemberNotFound)
+}
+
+// RemoveMember 移除成員；成員也可以自行退出組織
+func (s *organizationService) RemoveMember(ctx context.Context, userID, id, memberID string) error {
+	permission := domain.MemberPermissionManageTeam
+	if memberID == userID {
+		permission = domain.MemberPermissionViewDashboard
+	}
+	org, err := s.authorize(ctx, userID, id, permission)
+	if err != nil {
+		return err
+	}
+	member := org.Member(memberID)
+	if member == nil {
+		return ErrOrganizationMemberNotFound
+	}
+	if member.Role == domain.OrganizationRoleOwner && org.OwnerCount() == 1 {
+		return ErrLastOrganizationOwner
+	}
+
+	if err := s.repo.RemoveMember(ctx, id, memberID); err != nil {
+		return notFound(err, ErrOrganizationMemberNotFound)
+	}
+	return s.userRepo.Update(ctx, memberID, bson.M{"organizationId": ""})
+}
+
+func (s *organizationService) ListHosts(ctx context.Context, userID, id string) ([]*domain.Host, error) {
+	org, err := s.authorize(ctx, userID, id, domain.MemberPermissionViewDashboard)
+	if err != nil {
+		return nil, err
+	}
+	return s.hostRepo.ListByOrganizationID(ctx, org.ID)
+}
+
+// AddHost 將接待主加入組織，只有接待主的擁有者 (OWNER) 可以把接待主加入組織
+func (s *organizationService) AddHost(ctx context.Context, userID, id, hostID string) (*domain.Host, error) {
+	org, err := s.authorize(ctx, userID, id, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := s.hostRepo.GetByID(ctx, hostID)
+	if err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+	if !host.HasPermission(userID, domain.MemberPermissionManageTeam) {
+		return nil, ErrNotHostOwner
+	}
+	if host.OrganizationID != nil && *host.OrganizationID != org.ID {
+		return nil, ErrHostInOtherOrganization
+	}
+
+	if err := s.hostRepo.SetOrganization(ctx, hostID, &org.ID); err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+	host.OrganizationID = &org.ID
+	return host, nil
+}
+
+func (s *organizationService) RemoveHost(ctx context.Context, userID, id, hostID string) error {
+	org, err := s.authorize(ctx, userID, id, domain.MemberPermissionManageTeam)
+	if err != nil {
+		return err
+	}
+
+	host, err := s.hostRepo.GetByID(ctx, hostID)
+	if err != nil {
+		return notFound(err, ErrHostNotFound)
+	}
+	if host.OrganizationID == nil || *host.OrganizationID != org.ID {
+		return ErrHostNotFound
+	}
+	return notFound(s.hostRepo.SetOrganization(ctx, hostID, nil), ErrHostNotFound)
+}
+
+// GetDashboard 依接待主與狀態統計組織旗下所有申請
+func (s *organizationService) GetDashboard(ctx context.Context, userID, id string) (*domain.OrganizationDashboard, error) {
+	org, err := s.authorize(ctx, userID, id, domain.MemberPermissionViewDashboard)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := s.hostRepo.ListByOrganizationID(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &domain.OrganizationDashboard{
+		OrganizationID: org.ID.Hex(),
+		Hosts:          []domain.OrganizationHostSummary{},
+		Applications:   map[domain.ApplicationStatus]int64{},
+	}
+	if len(hosts) == 0 {
+		return dashboard, nil
+	}
+
+	counts, err := s.appRepo.CountByHostAndStatus(ctx, hostIDs(hosts))
+	if err != nil {
+		return nil, err
+	}
+	for _, host := range hosts {
+		summary := domain.OrganizationHostSummary{
+			ID:           host.ID.Hex(),
+			Name:         host.Name,
+			Slug:         host.Slug,
+			Status:       host.Status,
+			Applications: map[domain.ApplicationStatus]int64{},
+		}
+		for status, n := range counts[host.ID] {
+			summary.Applications[status] = n
+			dashboard.Applications[status] += n
+		}
+		dashboard.Hosts = append(dashboard.Hosts, summary)
+	}
+	return dashboard, nil
+}
+
+// ListApplications 列出組織旗下接待主收到的申請
+func (s *organizationService) ListApplications(ctx context.Context, userID, id string, filter OrganizationApplicationFilter) ([]*domain.Application, int64, error) {
+	org, err := s.authorize(ctx, userID, id, domain.MemberPermissionViewDashboard)
+	if err != nil {
+		return nil, 0, err
+	}
+	hosts, err := s.hostRepo.ListByOrganizationID(ctx, org.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := hostIDs(hosts)
+	if filter.HostID != "" {
+		ids = nil
+		for _, host := range hosts {
+			if host.ID.Hex() == filter.HostID {
+				ids = append(ids, host.ID)
+			}
+		}
+		if len(ids) == 0 {
+			return nil, 0, ErrHostNotFound
+		}
+	}
+	if len(ids) == 0 {
+		return []*domain.Application{}, 0, nil
+	}
+
+	query := bson.M{"hostId": bson.M{"$in": ids}}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	return s.appRepo.List(ctx, query, filter.Limit, filter.Offset)
+}
+
+// CanManageHost 回傳使用者是否可以對接待主執行指定操作，見 canManageHost
+func (s *organizationService) CanManageHost(ctx context.Context, userID string, host *domain.Host, permission domain.MemberPermission) (bool, error) {
+	return canManageHost(ctx, s.repo, userID, host, permission)
+}
+
+func hostIDs(hosts []*domain.Host) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(hosts))
+	for _, host := range hosts {
+		ids = append(ids, host.ID)
+	}
+	return ids
+}
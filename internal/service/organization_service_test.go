@@ -0,0 +1,416 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockOrganizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationRepository) Create(ctx context.Context, org *domain.Organization) error {
+	args := m.Called(ctx, org)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) GetByID(ctx context.Context, id string) (*domain.Organization, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Organization), args.Error(1)
+}
+
+func (m *MockOrganizationRepository) Update(ctx context.Context, id string, fields bson.M) error {
+	args := m.Called(ctx, id, fields)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) AddMember(ctx context.Context, id string, member domain.OrganizationMember) error {
+	args := m.Called(ctx, id, member)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) UpdateMemberRole(ctx context.Context, id, userID string, role domain.OrganizationRole) error {
+	args := m.Called(ctx, id, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationRepository) RemoveMember(ctx context.Context, id, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+type MockOrganizationInvitationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganizationInvitationRepository) Create(ctx context.Context, inv *domain.OrganizationInvitation) error {
+	args := m.Called(ctx, inv)
+	return args.Error(0)
+}
+
+func (m *MockOrganizationInvitationRepository) GetByID(ctx context.Context, id string) (*domain.OrganizationInvitation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.OrganizationInvitation), args.Error(1)
+}
+
+func (m *MockOrganizationInvitationRepository) ListByOrganizationID(ctx context.Context, organizationID primitive.ObjectID, status domain.OrganizationInvitationStatus) ([]*domain.OrganizationInvitation, error) {
+	args := m.Called(ctx, organizationID, status)
+	return args.Get(0).([]*domain.OrganizationInvitation), args.Error(1)
+}
+
+func (m *MockOrganizationInvitationRepository) ListOpenByEmail(ctx context.Context, email string, now time.Time) ([]*domain.OrganizationInvitation, error) {
+	args := m.Called(ctx, email, now)
+	return args.Get(0).([]*domain.OrganizationInvitation), args.Error(1)
+}
+
+func (m *MockOrganizationInvitationRepository) HasOpen(ctx context.Context, organizationID primitive.ObjectID, email string, now time.Time) (bool, error) {
+	args := m.Called(ctx, organizationID, email, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrganizationInvitationRepository) Respond(ctx context.Context, id string, status domain.OrganizationInvitationStatus, at time.Time) error {
+	args := m.Called(ctx, id, status, at)
+	return args.Error(0)
+}
+
+func newTestOrganization(members map[primitive.ObjectID]domain.OrganizationRole) *domain.Organization {
+	org := &domain.Organization{ID: primitive.NewObjectID(), Name: "Hostel Chain"}
+	for userID, role := range members {
+		org.Members = append(org.Members, domain.OrganizationMember{UserID: userID, Role: role})
+	}
+	return org
+}
+
+func TestOrganizationRolePermissions(t *testing.T) {
+	assert.True(t, domain.OrganizationRoleOwner.HasPermission(domain.MemberPermissionManageTeam))
+	assert.False(t, domain.OrganizationRoleManager.HasPermission(domain.MemberPermissionManageTeam))
+	assert.True(t, domain.OrganizationRoleManager.HasPermission(domain.MemberPermissionEditListings))
+	assert.False(t, domain.OrganizationRoleReviewer.HasPermission(domain.MemberPermissionEditListings))
+	assert.True(t, domain.OrganizationRoleReviewer.HasPermission(domain.MemberPermissionReviewApplications))
+	assert.False(t, domain.OrganizationRole("GUEST").IsValid())
+}
+
+func TestCreateOrganization(t *testing.T) {
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+
+	t.Run("Creator Becomes Owner", func(t *testing.T) {
+		mockRepo := new(MockOrganizationRepository)
+		mockUserRepo := new(MockUserRepository)
+		service := NewOrganizationService(mockRepo, nil, nil, mockUserRepo, nil, nil)
+
+		mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(&domain.User{ID: userID.Hex()}, nil)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Organization")).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Organization).ID = primitive.NewObjectID()
+		}).Return(nil)
+		mockUserRepo.On("Update", ctx, userID.Hex(), mock.AnythingOfType("primitive.M")).Return(nil)
+
+		org, err := service.CreateOrganization(ctx, userID.Hex(), &domain.Organization{Name: "Farm Network"})
+
+		assert.NoError(t, err)
+		assert.Len(t, org.Members, 1)
+		assert.Equal(t, domain.OrganizationRoleOwner, org.Member(userID.Hex()).Role)
+		mockUserRepo.AssertCalled(t, "Update", ctx, userID.Hex(), bson.M{"organizationId": org.ID.Hex()})
+	})
+
+	t.Run("Already In Organization", func(t *testing.T) {
+		mockRepo := new(MockOrganizationRepository)
+		mockUserRepo := new(MockUserRepository)
+		service := NewOrganizationService(mockRepo, nil, nil, mockUserRepo, nil, nil)
+
+		mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(&domain.User{ID: userID.Hex(), OrganizationID: primitive.NewObjectID().Hex()}, nil)
+
+		_, err := service.CreateOrganization(ctx, userID.Hex(), &domain.Organization{Name: "Farm Network"})
+
+		assert.ErrorIs(t, err, ErrAlreadyInOrganization)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrganizationMembers(t *testing.T) {
+	ctx := context.Background()
+	ownerID := primitive.NewObjectID()
+	managerID := primitive.NewObjectID()
+
+	t.Run("Manager Cannot Invite Members", func(t *testing.T) {
+		mockRepo := new(MockOrganizationRepository)
+		service := NewOrganizationService(mockRepo, nil, nil, nil, nil, nil)
+		org := newTestOrganization(map[primitive.ObjectID]domain.OrganizationRole{ownerID: domain.OrganizationRoleOwner, managerID: domain.OrganizationRoleManager})
+
+		mockRepo.On("GetByID", ctx, org.ID.Hex()).Return(org, nil)
+
+		_, err := service.InviteMember(ctx, managerID.Hex(), org.ID.Hex(), "new@example.com", domain.OrganizationRoleReviewer)
+
+		assert.ErrorIs(t, err, ErrOrganizationPermissionDenied)
+	})
+
+	t.Run("Owner Invites Member", func(t *testing.T) {
+		mockRepo := new(MockOrganizationRepository)
+		mockInvRepo := new(MockOrganizationInvitationRepository)
+		mockUserRepo := new(MockUserRepository)
+		mockNotif := new(MockNotificationService)
+		service := NewOrganizationService(mockRepo, mockInvRepo, nil, mockUserRepo, nil, mockNotif)
+		org := newTestOrganization(map[primitive.ObjectID]domain.OrganizationRole{ownerID: domain.OrganizationRoleOwner})
+		newUserID := primitive.NewObjectID()
+
+		mockRepo.On("GetByID", ctx, org.ID.Hex()).Return(org, nil)
+		mockUserRepo.On("GetByEmail", ctx, "new@example.com").Return(&domain.User{ID: newUserID.Hex()}, nil)
+		mockInvRepo.On("HasOpen", ctx, org.ID, "new@example.com", mock.Anything).Return(false, nil)
+		mockInvRepo.On("Create", ctx, mock.MatchedBy(func(inv *domain.OrganizationInvitation) bool {
+			return inv.OrganizationID == org.ID && inv.Role == domain.OrganizationRoleReviewer && inv.Status == domain.OrganizationInvitationPending && inv.InvitedBy == ownerID
+		})).Return(nil)
+		mockNotif.On("SendNotification", ctx, newUserID.Hex(), domain.NotificationTypeOrganizationInvitation, mock.Anything).Return(nil)
+
+		inv, err := service.InviteMember(ctx, ownerID.Hex(), org.ID.Hex(), " New@Example.com ", domain.OrganizationRoleReviewer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "new@example.com", inv.Email)
+		// 接受邀請前不會成為成員
+		mockRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything, mock.Anything)
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+		mockInvRepo.AssertExpectations(t)
+		mockNotif.AssertExpectations(t)
+	})
+
+	t.Run("Invitee Already In Organization", func(t *testing.T) {
+		mockRepo := new(MockOrganizationRepository)
+		mockUserRepo := new(MockUserRepository)
+		service := NewOrganizationService(mockRepo, nil, nil, mockUserRepo, nil, nil)
+		org := newTestOrganization(map[primitive.ObjectID]domain.OrganizationRole{ownerID: domain.OrganizationRoleOwner})
+
+		mockRepo.On("GetByID", ctx, org.ID.Hex()).Return(org, nil)
+		mockUserRepo.On("GetByEmail", ctx, "new@example.com").Return(&domain.User{ID: primitive.NewObjectID().Hex(), OrganizationID: primitive.NewObjectID().Hex()}, nil)
+
+		_, err := service.InviteMember(ctx, ownerID.Hex(), org.ID.Hex(), "new@example.com", domain.OrganizationRoleReviewer)
+
+		assert.ErrorIs(t, err, ErrAlreadyInOrganization)
+	})
+
+	t.Run("Last Owner Cannot Leave", func(t *testing.T) {
+		mockRepo := new(MockOrganizationRepository)
+		service := NewOrganizationService(mockRepo, nil, nil, nil, nil, nil)
+		org := newTestOrganization(map[primitive.ObjectID]domain.OrganizationRole{ownerID: domain.OrganizationRoleOwner, managerID: domain.OrganizationRoleManager})
+
+		mockRepo.On("GetByID", ctx, org.ID.Hex()).Return(org, nil)
+
+		err := service.RemoveMember(ctx, ownerID.Hex(), org.ID.Hex(), ownerID.Hex())
+		assert.ErrorIs(t, err, ErrLastOrganizationOwner)
+
+		err = service.UpdateMemberRole(ctx, ownerID.Hex(), org.ID.Hex(), ownerID.Hex(), domain.OrganizationRoleManager)
+		assert.ErrorIs(t, err, ErrLastOrganizationOwner)
+	})
+
+	t.Run("Member Can Leave", func(t *testing.T) {
+		mockRepo := new(MockOrganizationRepository)
+		mockUserRepo := new(MockUserRepository)
+		service := NewOrganizationService(mockRepo, nil, nil, mockUserRepo, nil, nil)
+		org := newTestOrganization(map[primitive.ObjectID]domain.OrganizationRole{ownerID: domain.OrganizationRoleOwner, managerID: domain.OrganizationRoleManager})
+
+		mockRepo.On("GetByID", ctx, org.ID.Hex()).Return(org, nil)
+		mockRepo.On("RemoveMember", ctx, org.ID.Hex(), managerID.Hex()).Return(nil)
+		mockUserRepo.On("Update", ctx, managerID.Hex(), bson.M{"organizationId": ""}).Return(nil)
+
+		err := service.RemoveMember(ctx, managerID.Hex(), org.ID.Hex(), managerID.Hex())
+
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+func TestAcceptOrganizationInvitation(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	newInvitation := func() *domain.OrganizationInvitation {
+		return &domain.OrganizationInvitation{
+			ID:             primitive.NewObjectID(),
+			OrganizationID: primitive.NewObjectID(),
+			Email:          "staff@example.com",
+			Role:           domain.OrganizationRoleManager,
+			Status:         domain.OrganizationInvitationPending,
+			InvitedBy:      primitive.NewObjectID(),
+			ExpiresAt:      time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockOrganizationRepository)
+		mockInvRepo := new(MockOrganizationInvitationRepository)
+		mockUserRepo := new(MockUserRepository)
+		mockNotif := new(MockNotificationService)
+		service := NewOrganizationService(mockRepo, mockInvRepo, nil, mockUserRepo, nil, mockNotif)
+		inv := newInvitation()
+		org := &domain.Organization{ID: inv.OrganizationID, Name: "Hostel Chain"}
+
+		mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(&domain.User{ID: userID.Hex(), Email: "Staff@example.com"}, nil)
+		mockInvRepo.On("GetByID", ctx, inv.ID.Hex()).Return(inv, nil)
+		mockRepo.On("GetByID", ctx, org.ID.Hex()).Return(org, nil)
+		mockInvRepo.On("Respond", ctx, inv.ID.Hex(), domain.OrganizationInvitationAccepted, mock.Anything).Return(nil)
+		mockRepo.On("AddMember", ctx, org.ID.Hex(), mock.MatchedBy(func(m domain.OrganizationMember) bool {
+			return m.UserID == userID && m.Role == domain.OrganizationRoleManager && m.AddedBy == inv.InvitedBy
+		})).Return(nil)
+		mockUserRepo.On("Update", ctx, userID.Hex(), bson.M{"organizationId": org.ID.Hex()}).Return(nil)
+		mockNotif.On("SendNotification", ctx, inv.InvitedBy.Hex(), domain.NotificationTypeOrganizationInvitationAccepted, mock.Anything).Return(nil)
+
+		result, err := service.AcceptInvitation(ctx, userID.Hex(), inv.ID.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, org.ID, result.ID)
+		assert.Equal(t, domain.OrganizationInvitationAccepted, inv.Status)
+		mockRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Invitation For Someone Else", func(t *testing.T) {
+		mockInvRepo := new(MockOrganizationInvitationRepository)
+		mockUserRepo := new(MockUserRepository)
+		service := NewOrganizationService(nil, mockInvRepo, nil, mockUserRepo, nil, nil)
+		inv := newInvitation()
+
+		mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(&domain.User{ID: userID.Hex(), Email: "other@example.com"}, nil)
+		mockInvRepo.On("GetByID", ctx, inv.ID.Hex()).Return(inv, nil)
+
+		_, err := service.AcceptInvitation(ctx, userID.Hex(), inv.ID.Hex())
+
+		assert.ErrorIs(t, err, ErrOrganizationInvitationNotFound)
+	})
+
+	t.Run("Already In Organization", func(t *testing.T) {
+		mockInvRepo := new(MockOrganizationInvitationRepository)
+		mockUserRepo := new(MockUserRepository)
+		service := NewOrganizationService(nil, mockInvRepo, nil, mockUserRepo, nil, nil)
+		inv := newInvitation()
+
+		mockUserRepo.On("GetByID", ctx, userID.Hex()).Return(&domain.User{ID: userID.Hex(), Email: "staff@example.com", OrganizationID: primitive.NewObjectID().Hex()}, nil)
+		mockInvRepo.On("GetByID", ctx, inv.ID.Hex()).Return(inv, nil)
+
+		_, err := service.AcceptInvitation(ctx, userID.Hex(), inv.ID.Hex())
+
+		assert.ErrorIs(t, err, ErrAlreadyInOrganization)
+		mockInvRepo.AssertNotCalled(t, "Respond", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAddHostToOrganization(t *testing.T) {
+	ctx := context.Background()
+	ownerID := primitive.NewObjectID()
+	org := newTestOrganization(map[primitive.ObjectID]domain.OrganizationRole{ownerID: domain.OrganizationRoleOwner})
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockOrganizationRepository)
+		mockHostRepo := new(MockHostRepository)
+		service := NewOrganizationService(mockRepo, nil, mockHostRepo, nil, nil, nil)
+		host := &domain.Host{ID: primitive.NewObjectID(), UserID: ownerID}
+
+		mockRepo.On("GetByID", ctx, org.ID.Hex()).Return(org, nil)
+		mockHostRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+		mockHostRepo.On("SetOrganization", ctx, host.ID.Hex(), &org.ID).Return(nil)
+
+		result, err := service.AddHost(ctx, ownerID.Hex(), org.ID.Hex(), host.ID.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, org.ID, *result.OrganizationID)
+	})
+
+	t.Run("Not Host Owner", func(t *testing.T) {
+		mockRepo := new(MockOrganizationRepository)
+		mockHostRepo := new(MockHostRepository)
+		service := NewOrganizationService(mockRepo, nil, mockHostRepo, nil, nil, nil)
+		host := &domain.Host{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID()}
+
+		mockRepo.On("GetByID", ctx, org.ID.Hex()).Return(org, nil)
+		mockHostRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+
+		_, err := service.AddHost(ctx, ownerID.Hex(), org.ID.Hex(), host.ID.Hex())
+
+		assert.ErrorIs(t, err, ErrNotHostOwner)
+	})
+
+	t.Run("Host In Other Organization", func(t *testing.T) {
+		mockRepo := new(MockOrganizationRepository)
+		mockHostRepo := new(MockHostRepository)
+		service := NewOrganizationService(mockRepo, nil, mockHostRepo, nil, nil, nil)
+		otherOrgID := primitive.NewObjectID()
+		host := &domain.Host{ID: primitive.NewObjectID(), UserID: ownerID, OrganizationID: &otherOrgID}
+
+		mockRepo.On("GetByID", ctx, org.ID.Hex()).Return(org, nil)
+		mockHostRepo.On("GetByID", ctx, host.ID.Hex()).Return(host, nil)
+
+		_, err := service.AddHost(ctx, ownerID.Hex(), org.ID.Hex(), host.ID.Hex())
+
+		assert.ErrorIs(t, err, ErrHostInOtherOrganization)
+	})
+}
+
+func TestGetOrganizationDashboard(t *testing.T) {
+	ctx := context.Background()
+	reviewerID := primitive.NewObjectID()
+	org := newTestOrganization(map[primitive.ObjectID]domain.OrganizationRole{reviewerID: domain.OrganizationRoleReviewer})
+	hostA := &domain.Host{ID: primitive.NewObjectID(), Name: "Taipei Hostel", OrganizationID: &org.ID}
+	hostB := &domain.Host{ID: primitive.NewObjectID(), Name: "Tainan Hostel", OrganizationID: &org.ID}
+
+	mockRepo := new(MockOrganizationRepository)
+	mockHostRepo := new(MockHostRepository)
+	mockAppRepo := new(MockApplicationRepository)
+	service := NewOrganizationService(mockRepo, nil, mockHostRepo, nil, mockAppRepo, nil)
+
+	mockRepo.On("GetByID", ctx, org.ID.Hex()).Return(org, nil)
+	mockHostRepo.On("ListByOrganizationID", ctx, org.ID).Return([]*domain.Host{hostA, hostB}, nil)
+	mockAppRepo.On("CountByHostAndStatus", ctx, []primitive.ObjectID{hostA.ID, hostB.ID}).Return(map[primitive.ObjectID]map[domain.ApplicationStatus]int64{
+		hostA.ID: {domain.ApplicationStatusPending: 3, domain.ApplicationStatusAccepted: 1},
+		hostB.ID: {domain.ApplicationStatusPending: 2},
+	}, nil)
+
+	dashboard, err := service.GetDashboard(ctx, reviewerID.Hex(), org.ID.Hex())
+
+	assert.NoError(t, err)
+	assert.Len(t, dashboard.Hosts, 2)
+	assert.Equal(t, int64(5), dashboard.Applications[domain.ApplicationStatusPending])
+	assert.Equal(t, int64(1), dashboard.Applications[domain.ApplicationStatusAccepted])
+	assert.Equal(t, int64(2), dashboard.Hosts[1].Applications[domain.ApplicationStatusPending])
+}
+
+func TestCanManageHost(t *testing.T) {
+	ctx := context.Background()
+	ownerID := primitive.NewObjectID()
+	managerID := primitive.NewObjectID()
+	reviewerID := primitive.NewObjectID()
+	org := newTestOrganization(map[primitive.ObjectID]domain.OrganizationRole{managerID: domain.OrganizationRoleManager, reviewerID: domain.OrganizationRoleReviewer})
+	host := &domain.Host{ID: primitive.NewObjectID(), UserID: ownerID, OrganizationID: &org.ID}
+
+	mockRepo := new(MockOrganizationRepository)
+	service := NewOrganizationService(mockRepo, nil, nil, nil, nil, nil)
+	mockRepo.On("GetByID", ctx, org.ID.Hex()).Return(org, nil)
+
+	tests := []struct {
+		name   string
+		userID primitive.ObjectID
+		want   bool
+	}{
+		{"Host Owner", ownerID, true},
+		{"Org Manager", managerID, true},
+		{"Org Reviewer", reviewerID, false},
+		{"Outsider", primitive.NewObjectID(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := service.CanManageHost(ctx, tt.userID.Hex(), host, domain.MemberPermissionEditListings)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/email"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidResetToken = errcode.BadRequest("INVALID_RESET_TOKEN", "invalid or expired reset token")
+	ErrIncorrectPassword = errcode.BadRequest("INCORRECT_PASSWORD", "password is incorrect")
+	ErrPasswordNotSet    = errcode.Conflict("PASSWORD_NOT_SET", "account has no password, use forgot password to set one")
+	ErrPasswordUnchanged = errcode.BadRequest("PASSWORD_UNCHANGED", "new password must be different from the current password")
+)
+
+const (
+	defaultPasswordResetTTL      = time.Hour
+	defaultPasswordResetInterval = time.Minute
+	defaultPasswordResetIPLimit  = 20
+	// passwordResetIPWindow 是計算同一 IP 重設密碼請求次數的時間窗
+	passwordResetIPWindow = time.Hour
+)
+
+// PasswordService 處理忘記密碼、重設密碼與變更密碼
+type PasswordService interface {
+	ForgotPassword(ctx context.Context, email, ip string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	ChangePassword(ctx context.Context, userID, sessionID, currentPassword, newPassword string) error
+}
+
+type passwordService struct {
+	userRepo       repository.UserRepository
+	resetRepo      repository.PasswordResetRepository
+	attemptRepo    repository.LoginAttemptRepository
+	sessionService SessionService
+	emailSender    email.EmailSender
+	frontendURL    string
+	resetTTL       time.Duration
+	resetInterval  time.Duration
+	ipLimit        int
+}
+
+func NewPasswordService(userRepo repository.UserRepository, resetRepo repository.PasswordResetRepository, attemptRepo repository.LoginAttemptRepository, sessionService SessionService, emailSender email.EmailSender, cfg *config.Config) PasswordService {
+	resetTTL := cfg.Auth.PasswordResetTTL
+	if resetTTL <= 0 {
+		resetTTL = defaultPasswordResetTTL
+	}
+	resetInterval := cfg.Auth.PasswordResetInterval
+	if resetInterval <= 0 {
+		resetInterval = defaultPasswordResetInterval
+	}
+	ipLimit := cfg.Auth.PasswordResetIPLimit
+	if ipLimit <= 0 {
+		ipLimit = defaultPasswordResetIPLimit
+	}
+
+	return &passwordService{
+		userRepo:       userRepo,
+		resetRepo:      resetRepo,
+		attemptRepo:    attemptRepo,
+		sessionService: sessionService,
+		emailSender:    emailSender,
+		frontendURL:    strings.TrimRight(cfg.Server.FrontendURL, "/"),
+		resetTTL:       resetTTL,
+		resetInterval:  resetInterval,
+		ipLimit:        ipLimit,
+	}
+}
+
+// ForgotPassword 寄送重設密碼信。
+// 同一 IP 請求過於頻繁時回傳 *RateLimitedError；其餘情況一律回傳成功，
+// 查詢帳號、建立重設連結與寄信都在背景執行，回應時間不會因 email 是否存在而不同。
+func (s *passwordService) ForgotPassword(ctx context.Context, email, ip string) error {
+	if err := s.checkIPLimit(ctx, ip); err != nil {
+		return err
+	}
+
+	// 請求結束後仍需完成，不隨請求的 context 取消
+	bgCtx := context.WithoutCancel(ctx)
+	go func() {
+		if err := s.sendResetLink(bgCtx, email, ip); err != nil {
+			logger.Error("Failed to create password reset", "error", err)
+		}
+	}()
+	return nil
+}
+
+// checkIPLimit 計入一次該 IP 的重設密碼請求，在 passwordResetIPWindow 內超過 ipLimit 次時回傳 *RateLimitedError
+func (s *passwordService) checkIPLimit(ctx context.Context, ip string) error {
+	if ip == "" {
+		return nil
+	}
+	attempt, err := s.attemptRepo.RecordFailure(ctx, "reset-ip:"+ip, passwordResetIPWindow)
+	if err != nil {
+		return err
+	}
+	if attempt.Failures <= s.ipLimit {
+		return nil
+	}
+	retryAfter := attempt.WindowStart.Add(passwordResetIPWindow).Sub(time.Now())
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	return &RateLimitedError{RetryAfter: retryAfter}
+}
+
+// sendResetLink 建立重設連結並寄出重設密碼信。
+// 找不到使用者或同一帳號在 resetInterval 內已寄送過時不做任何事。
+func (s *passwordService) sendResetLink(ctx context.Context, email, ip string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			logger.Info("Password reset requested for unknown email")
+			return nil
+		}
+		return err
+	}
+
+	allowed, err := s.userRepo.MarkPasswordResetSent(ctx, user.ID, time.Now().Add(-s.resetInterval))
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		logger.Info("Password reset throttled", "userId", user.ID)
+		return nil
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil {
+		return err
+	}
+
+	// 同時只保留最新的一個重設連結
+	if err := s.resetRepo.InvalidateByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	reset := &domain.PasswordReset{
+		UserID:    userObjID,
+		TokenHash: hashToken(token),
+		IP:        ip,
+		ExpiresAt: time.Now().Add(s.resetTTL),
+	}
+	if err := s.resetRepo.Create(ctx, reset); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.frontendURL, url.QueryEscape(token))
+	locale := messageLocale(ctx, user)
+	subject := i18n.T(locale, "email.passwordReset.subject")
+	body := i18n.T(locale, "email.passwordReset.body",
+		"name", html.EscapeString(user.Name),
+		"link", html.EscapeString(link),
+		"ttl", s.resetTTL,
+	)
+
+	if err := s.emailSender.Send(user.Email, user.Name, subject, body); err != nil {
+		logger.Error("Failed to send password reset email", "userId", user.ID, "error", err)
+	}
+	return nil
+}
+
+// ResetPassword 以重設 token 設定新密碼，並登出所有裝置。
+// 能收到重設信即代表擁有該 email，因此同時將 email 標記為已驗證。
+func (s *passwordService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	reset, err := s.resetRepo.Consume(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	userID := reset.UserID.Hex()
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	payload := bson.M{"password": string(hashedPassword)}
+	if user.EmailVerified == nil {
+		payload["emailVerified"] = time.Now()
+	}
+	if err := s.userRepo.Update(ctx, userID, payload); err != nil {
+		return err
+	}
+
+	if _, err := s.sessionService.RevokeAllUserSessions(ctx, userID, "", SessionRevokedPasswordReset); err != nil {
+		return err
+	}
+
+	logger.Info("Password reset", "userId", userID)
+	return nil
+}
+
+// ChangePassword 驗證目前密碼後變更密碼，並登出目前裝置以外的所有裝置
+func (s *passwordService) ChangePassword(ctx context.Context, userID, sessionID, currentPassword, newPassword string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// 僅以第三方登入註冊的帳號沒有密碼，需透過忘記密碼流程設定
+	if user.Password == "" {
+		return ErrPasswordNotSet
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return ErrIncorrectPassword
+	}
+	if currentPassword == newPassword {
+		return ErrPasswordUnchanged
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.Update(ctx, userID, bson.M{"password": string(hashedPassword)}); err != nil {
+		return err
+	}
+
+	// 舊的重設連結也一併失效
+	if err := s.resetRepo.InvalidateByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	if _, err := s.sessionService.RevokeAllUserSessions(ctx, userID, sessionID, SessionRevokedPasswordChanged); err != nil {
+		return err
+	}
+
+	return nil
+}
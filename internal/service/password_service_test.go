@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MockPasswordResetRepository 是一個用於測試的 PasswordResetRepository mock
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(ctx context.Context, reset *domain.PasswordReset) error {
+	args := m.Called(ctx, reset)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) Consume(ctx context.Context, tokenHash string) (*domain.PasswordReset, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PasswordReset), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) InvalidateByUserID(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func newTestPasswordService(userRepo *mockUserRepository, resetRepo *MockPasswordResetRepository, sessions *MockSessionService, sender *MockEmailSender) PasswordService {
+	return newTestPasswordServiceWithAttempts(userRepo, resetRepo, new(MockLoginAttemptRepository), sessions, sender)
+}
+
+func newTestPasswordServiceWithAttempts(userRepo *mockUserRepository, resetRepo *MockPasswordResetRepository, attempts *MockLoginAttemptRepository, sessions *MockSessionService, sender *MockEmailSender) PasswordService {
+	return NewPasswordService(userRepo, resetRepo, attempts, sessions, sender, &config.Config{
+		Server: config.ServerConfig{FrontendURL: "https://taiwanstay.test"},
+		Auth:   config.AuthConfig{PasswordResetIPLimit: 2},
+	})
+}
+
+func TestForgotPassword(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+
+	t.Run("Responds Before Looking Up The Account", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockResets := new(MockPasswordResetRepository)
+		mockAttempts := new(MockLoginAttemptRepository)
+		mockSender := new(MockEmailSender)
+		svc := newTestPasswordServiceWithAttempts(mockRepo, mockResets, mockAttempts, new(MockSessionService), mockSender)
+
+		user := &domain.User{ID: userID.Hex(), Name: "Traveler", Email: "traveler@example.com"}
+		release := make(chan time.Time)
+		sent := make(chan string, 1)
+		mockAttempts.On("RecordFailure", ctx, "reset-ip:127.0.0.1", passwordResetIPWindow).Return(&domain.LoginAttempt{Failures: 1, WindowStart: time.Now()}, nil)
+		mockRepo.On("GetByEmail", mock.Anything, user.Email).WaitUntil(release).Return(user, nil)
+		mockRepo.On("MarkPasswordResetSent", mock.Anything, user.ID, mock.AnythingOfType("time.Time")).Return(true, nil)
+		mockResets.On("InvalidateByUserID", mock.Anything, user.ID).Return(nil)
+		mockResets.On("Create", mock.Anything, mock.MatchedBy(func(r *domain.PasswordReset) bool {
+			return r.UserID == userID && len(r.TokenHash) == 64 && r.ExpiresAt.After(time.Now())
+		})).Return(nil)
+		mockSender.On("Send", user.Email, user.Name, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			sent <- args.String(3)
+		}).Return(nil)
+
+		// 帳號查詢被擋住時仍立即回應
+		err := svc.ForgotPassword(ctx, user.Email, "127.0.0.1")
+		assert.NoError(t, err)
+		close(release)
+
+		select {
+		case body := <-sent:
+			assert.Contains(t, body, "https://taiwanstay.test/reset-password?token=")
+		case <-time.After(time.Second):
+			t.Fatal("reset email was not sent")
+		}
+		mockResets.AssertExpectations(t)
+	})
+
+	t.Run("Rate Limited By IP", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockAttempts := new(MockLoginAttemptRepository)
+		svc := newTestPasswordServiceWithAttempts(mockRepo, new(MockPasswordResetRepository), mockAttempts, new(MockSessionService), new(MockEmailSender))
+
+		mockAttempts.On("RecordFailure", ctx, "reset-ip:127.0.0.1", passwordResetIPWindow).Return(&domain.LoginAttempt{Failures: 3, WindowStart: time.Now().Add(-10 * time.Minute)}, nil)
+
+		err := svc.ForgotPassword(ctx, "traveler@example.com", "127.0.0.1")
+
+		var rateLimited *RateLimitedError
+		assert.ErrorAs(t, err, &rateLimited)
+		assert.InDelta(t, 50*time.Minute, rateLimited.RetryAfter, float64(time.Second))
+		mockRepo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	})
+}
+
+func TestSendResetLink(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	user := &domain.User{ID: userID.Hex(), Name: "Traveler", Email: "traveler@example.com"}
+
+	t.Run("Unknown Email Does Not Reveal Anything", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockResets := new(MockPasswordResetRepository)
+		svc := newTestPasswordService(mockRepo, mockResets, new(MockSessionService), new(MockEmailSender)).(*passwordService)
+
+		mockRepo.On("GetByEmail", ctx, "nobody@example.com").Return(nil, mongo.ErrNoDocuments)
+
+		err := svc.sendResetLink(ctx, "nobody@example.com", "127.0.0.1")
+
+		assert.NoError(t, err)
+		mockResets.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Throttled Per Account", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockResets := new(MockPasswordResetRepository)
+		mockSender := new(MockEmailSender)
+		svc := newTestPasswordService(mockRepo, mockResets, new(MockSessionService), mockSender).(*passwordService)
+
+		mockRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+		mockRepo.On("MarkPasswordResetSent", ctx, user.ID, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		err := svc.sendResetLink(ctx, user.Email, "127.0.0.1")
+
+		assert.NoError(t, err)
+		mockResets.AssertNotCalled(t, "InvalidateByUserID", mock.Anything, mock.Anything)
+		mockResets.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mockSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+
+	t.Run("Success Revokes All Sessions", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockResets := new(MockPasswordResetRepository)
+		mockSessions := new(MockSessionService)
+		svc := newTestPasswordService(mockRepo, mockResets, mockSessions, new(MockEmailSender))
+
+		mockResets.On("Consume", ctx, hashToken("reset-token")).Return(&domain.PasswordReset{UserID: userID}, nil)
+		mockRepo.On("GetByID", ctx, userID.Hex()).Return(&domain.User{ID: userID.Hex()}, nil)
+		mockRepo.On("Update", ctx, userID.Hex(), mock.MatchedBy(func(payload bson.M) bool {
+			hashed, _ := payload["password"].(string)
+			_, verified := payload["emailVerified"]
+			return verified && bcrypt.CompareHashAndPassword([]byte(hashed), []byte("newpassword")) == nil
+		})).Return(nil)
+		mockSessions.On("RevokeAllUserSessions", ctx, userID.Hex(), "", SessionRevokedPasswordReset).Return(int64(2), nil)
+
+		err := svc.ResetPassword(ctx, "reset-token", "newpassword")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockSessions.AssertExpectations(t)
+	})
+
+	t.Run("Used Or Expired Token", func(t *testing.T) {
+		mockResets := new(MockPasswordResetRepository)
+		svc := newTestPasswordService(new(mockUserRepository), mockResets, new(MockSessionService), new(MockEmailSender))
+
+		mockResets.On("Consume", ctx, hashToken("used-token")).Return(nil, mongo.ErrNoDocuments)
+
+		err := svc.ResetPassword(ctx, "used-token", "newpassword")
+
+		assert.ErrorIs(t, err, ErrInvalidResetToken)
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	user := func() *domain.User {
+		return &domain.User{ID: "user-1", Password: string(hashed)}
+	}
+
+	t.Run("Success Revokes Other Sessions", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockResets := new(MockPasswordResetRepository)
+		mockSessions := new(MockSessionService)
+		svc := newTestPasswordService(mockRepo, mockResets, mockSessions, new(MockEmailSender))
+
+		mockRepo.On("GetByID", ctx, "user-1").Return(user(), nil)
+		mockRepo.On("Update", ctx, "user-1", mock.AnythingOfType("primitive.M")).Return(nil)
+		mockResets.On("InvalidateByUserID", ctx, "user-1").Return(nil)
+		mockSessions.On("RevokeAllUserSessions", ctx, "user-1", "session-1", SessionRevokedPasswordChanged).Return(int64(1), nil)
+
+		err := svc.ChangePassword(ctx, "user-1", "session-1", "password123", "newpassword")
+
+		assert.NoError(t, err)
+		mockSessions.AssertExpectations(t)
+	})
+
+	t.Run("Incorrect Current Password", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		svc := newTestPasswordService(mockRepo, new(MockPasswordResetRepository), new(MockSessionService), new(MockEmailSender))
+
+		mockRepo.On("GetByID", ctx, "user-1").Return(user(), nil)
+
+		err := svc.ChangePassword(ctx, "user-1", "session-1", "wrong-password", "newpassword")
+
+		assert.ErrorIs(t, err, ErrIncorrectPassword)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("OAuth Only Account", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		svc := newTestPasswordService(mockRepo, new(MockPasswordResetRepository), new(MockSessionService), new(MockEmailSender))
+
+		mockRepo.On("GetByID", ctx, "user-1").Return(&domain.User{ID: "user-1"}, nil)
+
+		err := svc.ChangePassword(ctx, "user-1", "session-1", "anything", "newpassword")
+
+		assert.ErrorIs(t, err, ErrPasswordNotSet)
+	})
+}
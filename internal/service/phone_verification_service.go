@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"github.com/taiwanstay/taiwanstay-back/pkg/sms"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidPhoneCode          = errcode.BadRequest("INVALID_PHONE_CODE", "invalid or expired verification code")
+	ErrPhoneCodeAttemptsExceeded = errcode.TooManyRequests("PHONE_CODE_ATTEMPTS_EXCEEDED", "too many incorrect attempts, please request a new code")
+	ErrInvalidPhoneNumber        = errcode.BadRequest("INVALID_PHONE_NUMBER", "invalid phone number")
+)
+
+const (
+	defaultPhoneOTPTTL            = 10 * time.Minute
+	defaultPhoneOTPResendInterval = time.Minute
+	defaultPhoneOTPDailyLimit     = 5
+	defaultPhoneOTPNumberLimit    = 10
+	defaultPhoneOTPMaxAttempts    = 5
+	// phoneOTPDigits 是簡訊驗證碼的位數
+	phoneOTPDigits = 6
+)
+
+// PhoneVerificationService 以簡訊驗證碼驗證使用者的手機號碼
+type PhoneVerificationService interface {
+	SendCode(ctx context.Context, userID, phone string) error
+	VerifyCode(ctx context.Context, userID, code string) (string, error)
+}
+
+type phoneVerificationService struct {
+	repo           repository.PhoneVerificationRepository
+	userRepo       repository.UserRepository
+	smsSender      sms.Sender
+	codeTTL        time.Duration
+	resendInterval time.Duration
+	dailyLimit     int
+	numberLimit    int
+	maxAttempts    int
+}
+
+func NewPhoneVerificationService(repo repository.PhoneVerificationRepository, userRepo repository.UserRepository, smsSender sms.Sender, cfg *config.Config) PhoneVerificationService {
+	codeTTL := cfg.Auth.PhoneOTPTTL
+	if codeTTL <= 0 {
+		codeTTL = defaultPhoneOTPTTL
+	}
+	resendInterval := cfg.Auth.PhoneOTPResendInterval
+	if resendInterval <= 0 {
+		resendInterval = defaultPhoneOTPResendInterval
+	}
+	dailyLimit := cfg.Auth.PhoneOTPDailyLimit
+	if dailyLimit <= 0 {
+		dailyLimit = defaultPhoneOTPDailyLimit
+	}
+	numberLimit := cfg.Auth.PhoneOTPNumberDailyLimit
+	if numberLimit <= 0 {
+		numberLimit = defaultPhoneOTPNumberLimit
+	}
+	maxAttempts := cfg.Auth.PhoneOTPMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultPhoneOTPMaxAttempts
+	}
+
+	return &phoneVerificationService{
+		repo:           repo,
+		userRepo:       userRepo,
+		smsSender:      smsSender,
+		codeTTL:        codeTTL,
+		resendInterval: resendInterval,
+		dailyLimit:     dailyLimit,
+		numberLimit:    numberLimit,
+		maxAttempts:    maxAttempts,
+	}
+}
+
+// SendCode 寄送驗證碼到指定的手機號碼。
+// 同一使用者在 resendInterval 內只能寄送一次，24 小時內最多寄送 dailyLimit 次；
+// 同一號碼 24 小時內最多收到 numberLimit 次 (不分帳號)。超過時回傳 *RateLimitedError。
+// 號碼格式錯誤時回傳 sms.ErrInvalidPhoneNumber。
+func (s *phoneVerificationService) SendCode(ctx context.Context, userID, phone string) error {
+	normalized, err := sms.NormalizePhone(phone)
+	if err != nil {
+		return ErrInvalidPhoneNumber.WithCause(err)
+	}
+	userObjID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.checkSendLimits(ctx, userID, normalized, primitive.NilObjectID, now); err != nil {
+		return err
+	}
+
+	code, err := generatePhoneCode()
+	if err != nil {
+		return err
+	}
+	verification := &domain.PhoneVerification{
+		UserID:    userObjID,
+		Phone:     normalized,
+		CodeHash:  hashPhoneCode(normalized, code),
+		ExpiresAt: now.Add(s.codeTTL),
+	}
+	if err := s.repo.Create(ctx, verification); err != nil {
+		return err
+	}
+
+	// 並行的請求可能同時通過上面的檢查，寫入後只以排在這一筆之前的紀錄重新檢查，
+	// 超過上限時撤回這一筆，確保實際寄出的數量不超過上限
+	if err := s.checkSendLimits(ctx, userID, normalized, verification.ID, now); err != nil {
+		if delErr := s.repo.Delete(ctx, verification.ID); delErr != nil {
+			return delErr
+		}
+		return err
+	}
+
+	// 驗證碼由使用者本人申請，使用當前請求的語系
+	message := i18n.T(messageLocale(ctx, nil), "sms.phoneCode", "code", code, "minutes", int(s.codeTTL.Minutes()))
+	if err := s.smsSender.Send(ctx, normalized, message); err != nil {
+		logger.Error("Failed to send phone verification code", "userId", userID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// VerifyCode 以最近一次寄送的驗證碼驗證手機號碼，成功後更新使用者的 profile.phoneNumber 並標記為已驗證，回傳驗證後的號碼。
+// 每組驗證碼最多可嘗試 maxAttempts 次，超過後必須重新寄送。
+func (s *phoneVerificationService) VerifyCode(ctx context.Context, userID, code string) (string, error) {
+	now := time.Now()
+	recent, err := s.repo.ListSince(ctx, userID, now.Add(-s.codeTTL))
+	if err != nil {
+		return "", err
+	}
+	if len(recent) == 0 {
+		return "", ErrInvalidPhoneCode
+	}
+
+	// 只接受最新一組驗證碼，舊的驗證碼在重新寄送後即失效
+	latest := recent[len(recent)-1]
+	if latest.UsedAt != nil || now.After(latest.ExpiresAt) {
+		return "", ErrInvalidPhoneCode
+	}
+
+	// 先累加嘗試次數再比對，避免並行請求繞過上限
+	latest, err = s.repo.IncrementAttempts(ctx, latest.ID)
+	if err != nil {
+		return "", err
+	}
+	if latest.Attempts > s.maxAttempts {
+		return "", ErrPhoneCodeAttemptsExceeded
+	}
+	if subtle.ConstantTimeCompare([]byte(hashPhoneCode(latest.Phone, code)), []byte(latest.CodeHash)) != 1 {
+		return "", ErrInvalidPhoneCode
+	}
+
+	marked, err := s.repo.MarkUsed(ctx, latest.ID)
+	if err != nil {
+		return "", err
+	}
+	if !marked {
+		return "", ErrInvalidPhoneCode
+	}
+
+	if err := s.userRepo.Update(ctx, userID, bson.M{
+		"profile.phoneNumber":     latest.Phone,
+		"profile.isPhoneVerified": true,
+	}); err != nil {
+		return "", err
+	}
+
+	logger.Info("Phone number verified", "userId", userID)
+	return latest.Phone, nil
+}
+
+// checkSendLimits 檢查使用者與號碼的寄送上限。
+// self 不為 NilObjectID 時只計算排在 self 之前的紀錄，self 本身與之後寫入的不列入。
+func (s *phoneVerificationService) checkSendLimits(ctx context.Context, userID, phone string, self primitive.ObjectID, now time.Time) error {
+	since := now.Add(-24 * time.Hour)
+	byUser, err := s.repo.ListSince(ctx, userID, since)
+	if err != nil {
+		return err
+	}
+	byUser = sentBefore(byUser, self)
+	if len(byUser) >= s.dailyLimit {
+		return &RateLimitedError{RetryAfter: byUser[len(byUser)-s.dailyLimit].CreatedAt.Add(24 * time.Hour).Sub(now)}
+	}
+	if len(byUser) > 0 {
+		if wait := byUser[len(byUser)-1].CreatedAt.Add(s.resendInterval).Sub(now); wait > 0 {
+			return &RateLimitedError{RetryAfter: wait}
+		}
+	}
+
+	byPhone, err := s.repo.ListSinceByPhone(ctx, phone, since)
+	if err != nil {
+		return err
+	}
+	byPhone = sentBefore(byPhone, self)
+	if len(byPhone) >= s.numberLimit {
+		return &RateLimitedError{RetryAfter: byPhone[len(byPhone)-s.numberLimit].CreatedAt.Add(24 * time.Hour).Sub(now)}
+	}
+	return nil
+}
+
+// sentBefore 回傳依寄送順序排在 self 之前的紀錄，self 為 NilObjectID 或不在清單中時回傳全部
+func sentBefore(verifications []*domain.PhoneVerification, self primitive.ObjectID) []*domain.PhoneVerification {
+	if self.IsZero() {
+		return verifications
+	}
+	for i, v := range verifications {
+		if v.ID == self {
+			return verifications[:i]
+		}
+	}
+	return verifications
+}
+
+// generatePhoneCode 產生固定位數的數字驗證碼
+func generatePhoneCode() (string, error) {
+	limit := big.NewInt(1)
+	for range phoneOTPDigits {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", phoneOTPDigits, n), nil
+}
+
+// hashPhoneCode 將號碼與驗證碼一起雜湊，驗證碼只能用於寄送時的號碼
+func hashPhoneCode(phone, code string) string {
+	return hashToken(phone + ":" + code)
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"github.com/taiwanstay/taiwanstay-back/pkg/sms"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockPhoneVerificationRepository 是一個用於測試的 PhoneVerificationRepository mock
+type MockPhoneVerificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockPhoneVerificationRepository) Create(ctx context.Context, verification *domain.PhoneVerification) error {
+	args := m.Called(ctx, verification)
+	return args.Error(0)
+}
+
+func (m *MockPhoneVerificationRepository) ListSince(ctx context.Context, userID string, since time.Time) ([]*domain.PhoneVerification, error) {
+	args := m.Called(ctx, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PhoneVerification), args.Error(1)
+}
+
+func (m *MockPhoneVerificationRepository) ListSinceByPhone(ctx context.Context, phone string, since time.Time) ([]*domain.PhoneVerification, error) {
+	args := m.Called(ctx, phone, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PhoneVerification), args.Error(1)
+}
+
+func (m *MockPhoneVerificationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPhoneVerificationRepository) IncrementAttempts(ctx context.Context, id primitive.ObjectID) (*domain.PhoneVerification, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.PhoneVerification), args.Error(1)
+}
+
+func (m *MockPhoneVerificationRepository) MarkUsed(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockSMSSender 是一個用於測試的 sms.Sender mock
+type MockSMSSender struct {
+	mock.Mock
+}
+
+func (m *MockSMSSender) Send(ctx context.Context, to, message string) error {
+	args := m.Called(ctx, to, message)
+	return args.Error(0)
+}
+
+func newTestPhoneVerificationService(repo *MockPhoneVerificationRepository, userRepo *mockUserRepository, sender *MockSMSSender) PhoneVerificationService {
+	return NewPhoneVerificationService(repo, userRepo, sender, &config.Config{})
+}
+
+func TestPhoneVerificationSendCode(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	userID := primitive.NewObjectID().Hex()
+
+	t.Run("Sends Code To Normalized Number", func(t *testing.T) {
+		mockRepo := new(MockPhoneVerificationRepository)
+		mockSender := new(MockSMSSender)
+		svc := newTestPhoneVerificationService(mockRepo, new(mockUserRepository), mockSender)
+
+		var stored *domain.PhoneVerification
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{}, nil)
+		mockRepo.On("ListSinceByPhone", ctx, "+886912345678", mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{}, nil)
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.PhoneVerification")).Return(nil).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.PhoneVerification)
+		})
+		var message string
+		mockSender.On("Send", ctx, "+886912345678", mock.AnythingOfType("string")).Return(nil).Run(func(args mock.Arguments) {
+			message = args.String(2)
+		})
+
+		assert.NoError(t, svc.SendCode(ctx, userID, "0912-345-678"))
+
+		code := regexp.MustCompile(`\d{6}`).FindString(message)
+		assert.NotEmpty(t, code)
+		assert.Equal(t, "+886912345678", stored.Phone)
+		assert.Equal(t, hashPhoneCode("+886912345678", code), stored.CodeHash)
+		assert.WithinDuration(t, time.Now().Add(defaultPhoneOTPTTL), stored.ExpiresAt, time.Second)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Invalid Number", func(t *testing.T) {
+		svc := newTestPhoneVerificationService(new(MockPhoneVerificationRepository), new(mockUserRepository), new(MockSMSSender))
+
+		err := svc.SendCode(ctx, userID, "12")
+		assert.ErrorIs(t, err, sms.ErrInvalidPhoneNumber)
+	})
+
+	t.Run("Resend Too Soon", func(t *testing.T) {
+		mockRepo := new(MockPhoneVerificationRepository)
+		mockSender := new(MockSMSSender)
+		svc := newTestPhoneVerificationService(mockRepo, new(mockUserRepository), mockSender)
+
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{
+			{CreatedAt: time.Now().Add(-20 * time.Second)},
+		}, nil)
+
+		err := svc.SendCode(ctx, userID, "0912345678")
+
+		var rateLimited *RateLimitedError
+		assert.ErrorAs(t, err, &rateLimited)
+		assert.InDelta(t, 40, rateLimited.RetryAfter.Seconds(), 1)
+		mockSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Daily Limit Reached", func(t *testing.T) {
+		mockRepo := new(MockPhoneVerificationRepository)
+		svc := newTestPhoneVerificationService(mockRepo, new(mockUserRepository), new(MockSMSSender))
+
+		// 最早的一筆在 20 小時前寄出，4 小時後才能再次寄送
+		now := time.Now()
+		recent := []*domain.PhoneVerification{}
+		for i := range defaultPhoneOTPDailyLimit {
+			recent = append(recent, &domain.PhoneVerification{CreatedAt: now.Add(-20*time.Hour + time.Duration(i)*time.Hour)})
+		}
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return(recent, nil)
+
+		err := svc.SendCode(ctx, userID, "0912345678")
+
+		var rateLimited *RateLimitedError
+		assert.ErrorAs(t, err, &rateLimited)
+		assert.InDelta(t, (4 * time.Hour).Seconds(), rateLimited.RetryAfter.Seconds(), 1)
+	})
+
+	t.Run("Number Daily Limit Reached Across Accounts", func(t *testing.T) {
+		mockRepo := new(MockPhoneVerificationRepository)
+		mockSender := new(MockSMSSender)
+		svc := newTestPhoneVerificationService(mockRepo, new(mockUserRepository), mockSender)
+
+		// 其他帳號在 23 小時前開始每 10 分鐘寄一次，最早的一筆 1 小時後過期
+		now := time.Now()
+		recent := []*domain.PhoneVerification{}
+		for i := range defaultPhoneOTPNumberLimit {
+			recent = append(recent, &domain.PhoneVerification{UserID: primitive.NewObjectID(), CreatedAt: now.Add(-23*time.Hour + time.Duration(i)*10*time.Minute)})
+		}
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{}, nil)
+		mockRepo.On("ListSinceByPhone", ctx, "+886912345678", mock.AnythingOfType("time.Time")).Return(recent, nil)
+
+		err := svc.SendCode(ctx, userID, "0912345678")
+
+		var rateLimited *RateLimitedError
+		assert.ErrorAs(t, err, &rateLimited)
+		assert.InDelta(t, time.Hour.Seconds(), rateLimited.RetryAfter.Seconds(), 1)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		mockSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Concurrent Request Is Withdrawn", func(t *testing.T) {
+		mockRepo := new(MockPhoneVerificationRepository)
+		mockSender := new(MockSMSSender)
+		svc := newTestPhoneVerificationService(mockRepo, new(mockUserRepository), mockSender)
+
+		// 檢查時沒有紀錄，寫入後發現另一個並行請求的紀錄排在前面
+		selfID := primitive.NewObjectID()
+		earlier := &domain.PhoneVerification{ID: primitive.NewObjectID(), CreatedAt: time.Now()}
+		self := &domain.PhoneVerification{ID: selfID, CreatedAt: time.Now()}
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{}, nil).Once()
+		mockRepo.On("ListSinceByPhone", ctx, "+886912345678", mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{}, nil).Once()
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.PhoneVerification")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.PhoneVerification).ID = selfID
+		})
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{earlier, self}, nil)
+		mockRepo.On("Delete", ctx, selfID).Return(nil)
+
+		err := svc.SendCode(ctx, userID, "0912345678")
+
+		var rateLimited *RateLimitedError
+		assert.ErrorAs(t, err, &rateLimited)
+		mockRepo.AssertExpectations(t)
+		mockSender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Earlier Concurrent Request Is Kept", func(t *testing.T) {
+		mockRepo := new(MockPhoneVerificationRepository)
+		mockSender := new(MockSMSSender)
+		svc := newTestPhoneVerificationService(mockRepo, new(mockUserRepository), mockSender)
+
+		// 另一個並行請求排在這一筆之後，只有它會被撤回
+		selfID := primitive.NewObjectID()
+		self := &domain.PhoneVerification{ID: selfID, CreatedAt: time.Now()}
+		later := &domain.PhoneVerification{ID: primitive.NewObjectID(), CreatedAt: time.Now()}
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{}, nil).Once()
+		mockRepo.On("ListSinceByPhone", ctx, "+886912345678", mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{}, nil).Once()
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.PhoneVerification")).Return(nil).Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.PhoneVerification).ID = selfID
+		})
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{self, later}, nil)
+		mockRepo.On("ListSinceByPhone", ctx, "+886912345678", mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{self, later}, nil)
+		mockSender.On("Send", ctx, "+886912345678", mock.AnythingOfType("string")).Return(nil)
+
+		assert.NoError(t, svc.SendCode(ctx, userID, "0912345678"))
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		mockSender.AssertExpectations(t)
+	})
+}
+
+func TestPhoneVerificationVerifyCode(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+	userID := primitive.NewObjectID().Hex()
+	phone := "+886912345678"
+
+	newVerification := func(attempts int) *domain.PhoneVerification {
+		return &domain.PhoneVerification{
+			ID:        primitive.NewObjectID(),
+			Phone:     phone,
+			CodeHash:  hashPhoneCode(phone, "123456"),
+			Attempts:  attempts,
+			CreatedAt: time.Now().Add(-time.Minute),
+			ExpiresAt: time.Now().Add(9 * time.Minute),
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := new(MockPhoneVerificationRepository)
+		mockUsers := new(mockUserRepository)
+		svc := newTestPhoneVerificationService(mockRepo, mockUsers, new(MockSMSSender))
+
+		v := newVerification(0)
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{v}, nil)
+		mockRepo.On("IncrementAttempts", ctx, v.ID).Return(newVerification(1), nil)
+		mockRepo.On("MarkUsed", ctx, mock.Anything).Return(true, nil)
+		mockUsers.On("Update", ctx, userID, bson.M{"profile.phoneNumber": phone, "profile.isPhoneVerified": true}).Return(nil)
+
+		verified, err := svc.VerifyCode(ctx, userID, "123456")
+
+		assert.NoError(t, err)
+		assert.Equal(t, phone, verified)
+		mockUsers.AssertExpectations(t)
+	})
+
+	t.Run("Wrong Code", func(t *testing.T) {
+		mockRepo := new(MockPhoneVerificationRepository)
+		mockUsers := new(mockUserRepository)
+		svc := newTestPhoneVerificationService(mockRepo, mockUsers, new(MockSMSSender))
+
+		v := newVerification(0)
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{v}, nil)
+		mockRepo.On("IncrementAttempts", ctx, v.ID).Return(newVerification(1), nil)
+
+		_, err := svc.VerifyCode(ctx, userID, "654321")
+
+		assert.ErrorIs(t, err, ErrInvalidPhoneCode)
+		mockUsers.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Too Many Attempts", func(t *testing.T) {
+		mockRepo := new(MockPhoneVerificationRepository)
+		svc := newTestPhoneVerificationService(mockRepo, new(mockUserRepository), new(MockSMSSender))
+
+		v := newVerification(defaultPhoneOTPMaxAttempts)
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{v}, nil)
+		mockRepo.On("IncrementAttempts", ctx, v.ID).Return(newVerification(defaultPhoneOTPMaxAttempts+1), nil)
+
+		// 即使驗證碼正確也不接受
+		_, err := svc.VerifyCode(ctx, userID, "123456")
+		assert.ErrorIs(t, err, ErrPhoneCodeAttemptsExceeded)
+	})
+
+	t.Run("Only Latest Code Is Accepted", func(t *testing.T) {
+		mockRepo := new(MockPhoneVerificationRepository)
+		svc := newTestPhoneVerificationService(mockRepo, new(mockUserRepository), new(MockSMSSender))
+
+		older := newVerification(0)
+		latest := newVerification(0)
+		latest.CodeHash = hashPhoneCode(phone, "999999")
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{older, latest}, nil)
+		mockRepo.On("IncrementAttempts", ctx, latest.ID).Return(latest, nil)
+
+		_, err := svc.VerifyCode(ctx, userID, "123456")
+		assert.ErrorIs(t, err, ErrInvalidPhoneCode)
+	})
+
+	t.Run("No Pending Code", func(t *testing.T) {
+		mockRepo := new(MockPhoneVerificationRepository)
+		svc := newTestPhoneVerificationService(mockRepo, new(mockUserRepository), new(MockSMSSender))
+
+		mockRepo.On("ListSince", ctx, userID, mock.AnythingOfType("time.Time")).Return([]*domain.PhoneVerification{}, nil)
+
+		_, err := svc.VerifyCode(ctx, userID, "123456")
+		assert.ErrorIs(t, err, ErrInvalidPhoneCode)
+	})
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ProfileService 提供依隱私設定過濾後的使用者個人檔案
+type ProfileService interface {
+	GetPublicProfile(ctx context.Context, viewerID string, viewerRole domain.UserRole, userID string) (*domain.PublicProfile, error)
+}
+
+type profileService struct {
+	userRepo    repository.UserRepository
+	hostRepo    repository.HostRepository
+	appRepo     repository.ApplicationRepository
+	roleService RoleService
+}
+
+func NewProfileService(userRepo repository.UserRepository, hostRepo repository.HostRepository, appRepo repository.ApplicationRepository, roleService RoleService) ProfileService {
+	return &profileService{
+		userRepo:    userRepo,
+		hostRepo:    hostRepo,
+		appRepo:     appRepo,
+		roleService: roleService,
+	}
+}
+
+// GetPublicProfile 依查看者的身分回傳個人檔案。
+// viewerID 為空字串表示未登入；停權或已刪除使用者的檔案只有本人與管理員可見。
+func (s *profileService) GetPublicProfile(ctx context.Context, viewerID string, viewerRole domain.UserRole, userID string) (*domain.PublicProfile, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFound(err, ErrUserNotFound)
+	}
+
+	viewer, contactShared, err := s.resolveViewer(ctx, viewerID, viewerRole, user)
+	if err != nil {
+		return nil, err
+	}
+	hidden := user.Status == domain.UserStatusSuspended || user.Status == domain.UserStatusDeleted
+	if hidden && viewer != domain.ProfileViewerSelf && viewer != domain.ProfileViewerAdmin {
+		return nil, ErrUserNotFound.WithCause(mongo.ErrNoDocuments)
+	}
+
+	return projectProfile(user, viewer, contactShared), nil
+}
+
+// resolveViewer 判斷查看者與檔案主人的關係。
+// contactShared 表示查看者是已接受此使用者申請的接待主，可看到聯絡方式。
+func (s *profileService) resolveViewer(ctx context.Context, viewerID string, viewerRole domain.UserRole, user *domain.User) (domain.ProfileViewer, bool, error) {
+	if viewerID == "" {
+		return domain.ProfileViewerAnonymous, false, nil
+	}
+	if viewerID == user.ID {
+		return domain.ProfileViewerSelf, false, nil
+	}
+
+	isAdmin, err := s.roleService.HasPermission(ctx, viewerRole, domain.PermissionUsersRead)
+	if err != nil {
+		return "", false, err
+	}
+	if isAdmin {
+		return domain.ProfileViewerAdmin, false, nil
+	}
+
+	host, err := s.hostRepo.GetByUserID(ctx, viewerID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.ProfileViewerRegistered, false, nil
+		}
+		return "", false, err
+	}
+
+	applicantID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil {
+		return "", false, err
+	}
+	apps, _, err := s.appRepo.List(ctx, bson.M{
+		"userId": applicantID,
+		"hostId": host.ID,
+		"status": bson.M{"$in": []domain.ApplicationStatus{domain.ApplicationStatusPending, domain.ApplicationStatusAccepted}},
+	}, 20, 0)
+	if err != nil {
+		return "", false, err
+	}
+	if len(apps) == 0 {
+		return domain.ProfileViewerRegistered, false, nil
+	}
+
+	for _, app := range apps {
+		if app.Status == domain.ApplicationStatusAccepted {
+			return domain.ProfileViewerHost, true, nil
+		}
+	}
+	return domain.ProfileViewerHost, false, nil
+}
+
+// projectProfile 依隱私設定與查看者身分過濾欄位
+func projectProfile(user *domain.User, viewer domain.ProfileViewer, contactShared bool) *domain.PublicProfile {
+	privacy := user.PrivacySettings.WithDefaults()
+	p := user.Profile
+
+	visible := func(level domain.PrivacyLevel) bool {
+		switch viewer {
+		case domain.ProfileViewerSelf, domain.ProfileViewerAdmin:
+			return true
+		case domain.ProfileViewerAnonymous:
+			return level == domain.PrivacyPublic
+		default:
+			return level == domain.PrivacyPublic || level == domain.PrivacyRegistered
+		}
+	}
+
+	profile := &domain.PublicProfile{
+		ID:                user.ID,
+		Name:              user.Name,
+		Image:             user.Image,
+		Viewer:            viewer,
+		MemberSince:       user.CreatedAt,
+		IsEmailVerified:   user.EmailVerified != nil,
+		IsPhoneVerified:   p.IsPhoneVerified,
+		Avatar:            p.Avatar,
+		WorkExperience:    p.WorkExperience,
+		CulturalInterests: p.CulturalInterests,
+		LearningGoals:     p.LearningGoals,
+	}
+
+	// 已接受申請的接待主需要聯絡方式，不受隱私設定限制
+	if visible(privacy.Email) || contactShared {
+		profile.Email = user.Email
+	}
+	if visible(privacy.Phone) || contactShared {
+		profile.PhoneNumber = p.PhoneNumber
+	}
+	if visible(privacy.Bio) {
+		profile.Bio = p.Bio
+	}
+	if visible(privacy.Skills) {
+		profile.Skills = p.Skills
+	}
+	if visible(privacy.Languages) {
+		profile.Languages = p.Languages
+	}
+	if visible(privacy.SocialMedia) {
+		profile.SocialMedia = p.SocialMedia
+	}
+	if visible(privacy.PersonalInfo) {
+		profile.PersonalInfo = p.PersonalInfo
+	}
+	if visible(privacy.WorkExchangePreferences) {
+		profile.WorkExchangePreferences = p.WorkExchangePreferences
+	}
+
+	// 緊急聯絡人、健康狀況等只提供給需要接待此使用者的人
+	switch viewer {
+	case domain.ProfileViewerSelf, domain.ProfileViewerAdmin, domain.ProfileViewerHost:
+		if !p.BirthDate.IsZero() {
+			birthDate := p.BirthDate
+			profile.BirthDate = &birthDate
+		}
+		emergencyContact := p.EmergencyContact
+		profile.EmergencyContact = &emergencyContact
+		profile.PhysicalCondition = p.PhysicalCondition
+		profile.AccommodationNeeds = p.AccommodationNeeds
+		profile.PreferredWorkHours = p.PreferredWorkHours
+	}
+
+	return profile
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestGetPublicProfile(t *testing.T) {
+	ctx := context.Background()
+	travelerID := primitive.NewObjectID()
+	hostID := primitive.NewObjectID()
+
+	newTraveler := func() *domain.User {
+		return &domain.User{
+			ID:    travelerID.Hex(),
+			Name:  "Traveler",
+			Email: "traveler@example.com",
+			Role:  domain.RoleUser,
+			Profile: domain.Profile{
+				Bio:              "Hello",
+				PhoneNumber:      "0912345678",
+				Skills:           []string{"cooking"},
+				PersonalInfo:     &domain.PersonalInfo{Nationality: "TW"},
+				EmergencyContact: domain.EmergencyContact{Name: "Mom"},
+			},
+			PrivacySettings: domain.DefaultPrivacySettings(),
+		}
+	}
+	newService := func(userRepo *mockUserRepository, hostRepo *MockHostRepository, appRepo *MockApplicationRepository) ProfileService {
+		roleRepo := new(MockRoleRepository)
+		roleRepo.On("GetByName", mock.Anything, domain.RoleUser).Return(&domain.Role{Name: domain.RoleUser}, nil)
+		roleRepo.On("GetByName", mock.Anything, domain.RoleHost).Return(&domain.Role{Name: domain.RoleHost}, nil)
+		return NewProfileService(userRepo, hostRepo, appRepo, NewRoleService(roleRepo, userRepo, new(MockSessionService)))
+	}
+
+	t.Run("Anonymous Sees Public Fields Only", func(t *testing.T) {
+		userRepo := new(mockUserRepository)
+		userRepo.On("GetByID", ctx, travelerID.Hex()).Return(newTraveler(), nil)
+		svc := newService(userRepo, new(MockHostRepository), new(MockApplicationRepository))
+
+		profile, err := svc.GetPublicProfile(ctx, "", "", travelerID.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ProfileViewerAnonymous, profile.Viewer)
+		assert.Equal(t, "Hello", profile.Bio)
+		assert.Equal(t, []string{"cooking"}, profile.Skills)
+		assert.Nil(t, profile.PersonalInfo)
+		assert.Empty(t, profile.Email)
+		assert.Empty(t, profile.PhoneNumber)
+		assert.Nil(t, profile.EmergencyContact)
+	})
+
+	t.Run("Registered User Without Relation", func(t *testing.T) {
+		userRepo := new(mockUserRepository)
+		hostRepo := new(MockHostRepository)
+		userRepo.On("GetByID", ctx, travelerID.Hex()).Return(newTraveler(), nil)
+		hostRepo.On("GetByUserID", ctx, "viewer-1").Return(nil, mongo.ErrNoDocuments)
+		svc := newService(userRepo, hostRepo, new(MockApplicationRepository))
+
+		profile, err := svc.GetPublicProfile(ctx, "viewer-1", domain.RoleUser, travelerID.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ProfileViewerRegistered, profile.Viewer)
+		assert.NotNil(t, profile.PersonalInfo)
+		assert.Empty(t, profile.Email)
+		assert.Nil(t, profile.EmergencyContact)
+	})
+
+	t.Run("Host With Pending Application", func(t *testing.T) {
+		userRepo := new(mockUserRepository)
+		hostRepo := new(MockHostRepository)
+		appRepo := new(MockApplicationRepository)
+		userRepo.On("GetByID", ctx, travelerID.Hex()).Return(newTraveler(), nil)
+		hostRepo.On("GetByUserID", ctx, "host-user").Return(&domain.Host{ID: hostID}, nil)
+		appRepo.On("List", ctx, mock.Anything, int64(20), int64(0)).Return([]*domain.Application{
+			{UserID: travelerID, HostID: hostID, Status: domain.ApplicationStatusPending},
+		}, int64(1), nil)
+		svc := newService(userRepo, hostRepo, appRepo)
+
+		profile, err := svc.GetPublicProfile(ctx, "host-user", domain.RoleHost, travelerID.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ProfileViewerHost, profile.Viewer)
+		assert.Equal(t, "Mom", profile.EmergencyContact.Name)
+		assert.Empty(t, profile.Email) // 尚未接受申請，不提供聯絡方式
+	})
+
+	t.Run("Host With Accepted Application Sees Contact", func(t *testing.T) {
+		userRepo := new(mockUserRepository)
+		hostRepo := new(MockHostRepository)
+		appRepo := new(MockApplicationRepository)
+		userRepo.On("GetByID", ctx, travelerID.Hex()).Return(newTraveler(), nil)
+		hostRepo.On("GetByUserID", ctx, "host-user").Return(&domain.Host{ID: hostID}, nil)
+		appRepo.On("List", ctx, mock.Anything, int64(20), int64(0)).Return([]*domain.Application{
+			{UserID: travelerID, HostID: hostID, Status: domain.ApplicationStatusAccepted},
+		}, int64(1), nil)
+		svc := newService(userRepo, hostRepo, appRepo)
+
+		profile, err := svc.GetPublicProfile(ctx, "host-user", domain.RoleHost, travelerID.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "traveler@example.com", profile.Email)
+		assert.Equal(t, "0912345678", profile.PhoneNumber)
+	})
+
+	t.Run("Admin Sees Everything", func(t *testing.T) {
+		userRepo := new(mockUserRepository)
+		userRepo.On("GetByID", ctx, travelerID.Hex()).Return(newTraveler(), nil)
+		svc := newService(userRepo, new(MockHostRepository), new(MockApplicationRepository))
+
+		profile, err := svc.GetPublicProfile(ctx, "admin-1", domain.RoleAdmin, travelerID.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.ProfileViewerAdmin, profile.Viewer)
+		assert.Equal(t, "traveler@example.com", profile.Email)
+		assert.NotNil(t, profile.EmergencyContact)
+	})
+
+	t.Run("Suspended User Is Hidden", func(t *testing.T) {
+		userRepo := new(mockUserRepository)
+		suspended := newTraveler()
+		suspended.Status = domain.UserStatusSuspended
+		userRepo.On("GetByID", ctx, travelerID.Hex()).Return(suspended, nil)
+		svc := newService(userRepo, new(MockHostRepository), new(MockApplicationRepository))
+
+		_, err := svc.GetPublicProfile(ctx, "", "", travelerID.Hex())
+
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+}
+
+func TestPrivacySettingsWithDefaults(t *testing.T) {
+	settings := domain.PrivacySettings{Email: domain.PrivacyPublic}.WithDefaults()
+
+	assert.Equal(t, domain.PrivacyPublic, settings.Email)
+	assert.Equal(t, domain.PrivacyPrivate, settings.Phone)
+	assert.Equal(t, domain.PrivacyRegistered, settings.PersonalInfo)
+}
+
+func TestProfileCompleteness(t *testing.T) {
+	t.Run("Registration Placeholders Count As Missing", func(t *testing.T) {
+		profile := domain.Profile{
+			BirthDate:          time.Now(),
+			EmergencyContact:   domain.EmergencyContact{Name: "N/A", Relationship: "N/A", Phone: "N/A"},
+			PhysicalCondition:  "N/A",
+			PreferredWorkHours: 8,
+		}
+
+		c := profile.Completeness()
+
+		assert.Equal(t, 0, c.Score)
+		assert.Empty(t, c.Completed)
+		assert.Contains(t, c.Missing, domain.ProfileFieldBirthDate)
+		assert.Contains(t, c.Missing, domain.ProfileFieldEmergencyContact)
+		assert.Contains(t, c.Missing, domain.ProfileFieldPhysicalCondition)
+	})
+
+	t.Run("Weighted Score", func(t *testing.T) {
+		profile := domain.Profile{
+			Bio:              "Hello",
+			BirthDate:        time.Date(1995, 5, 1, 0, 0, 0, 0, time.UTC),
+			EmergencyContact: domain.EmergencyContact{Name: "Mom", Phone: "0912345678"},
+		}
+
+		c := profile.Completeness()
+
+		assert.Equal(t, 35, c.Score)
+		assert.Equal(t, []domain.ProfileField{domain.ProfileFieldBio, domain.ProfileFieldBirthDate, domain.ProfileFieldEmergencyContact}, c.Completed)
+	})
+
+	t.Run("Missing Required Fields", func(t *testing.T) {
+		profile := domain.Profile{Languages: []string{"zh-TW"}}
+
+		missing := profile.MissingFields([]domain.ProfileField{domain.ProfileFieldLanguages, domain.ProfileFieldPhoneNumber})
+
+		assert.Equal(t, []domain.ProfileField{domain.ProfileFieldPhoneNumber}, missing)
+	})
+}
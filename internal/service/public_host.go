@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// publicHostOpportunityLimit 是接待主頁面列出的工作機會上限
+const publicHostOpportunityLimit = 50
+
+// 未公開精確位置時，公開座標與實際位置的距離範圍 (公尺)
+const (
+	minLocationFuzzMeters = 200
+	maxLocationFuzzMeters = 800
+)
+
+// locationKeyInfo 是由 JWT secret 衍生座標偏移金鑰時使用的 HKDF info，
+// 讓衍生的金鑰只用於這個用途，無法回推 JWT secret
+const locationKeyInfo = "taiwanstay/host-location-fuzz"
+
+// hostLocationKey 回傳產生公開座標偏移量的金鑰：優先使用 LocationKey，未設定時以 HKDF 由 JWTSecret 衍生
+func hostLocationKey(cfg config.ServerConfig) []byte {
+	if cfg.LocationKey != "" {
+		return []byte(cfg.LocationKey)
+	}
+	// 輸出長度固定為 sha256.Size，不會超過 HKDF 的上限
+	key, _ := hkdf.Key(sha256.New, []byte(cfg.JWTSecret), nil, locationKeyInfo, sha256.Size)
+	return key
+}
+
+// GetPublicHost 依 slug 回傳公開的接待主頁面與其上架中的工作機會，內容使用 locale 的翻譯。
+// 只有 ACTIVE 的接待主有公開頁面。
+func (s *hostService) GetPublicHost(ctx context.Context, slug, locale string) (*domain.PublicHost, error) {
+	host, err := s.repo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, notFound(err, ErrHostNotFound)
+	}
+	if host.Status != domain.HostStatusActive {
+		return nil, ErrHostNotFound.WithCause(mongo.ErrNoDocuments)
+	}
+
+	filter := bson.M{"hostId": host.ID, "status": domain.OpportunityStatusActive}
+	opps, err := s.oppRepo.List(ctx, filter, publicHostOpportunityLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	host.Localize(locale)
+	page := projectHost(host, s.locationKey)
+	for _, opp := range opps {
+		opp.Localize(locale)
+		page.Opportunities = append(page.Opportunities, domain.PublicHostOpportunity{
+			ID:               opp.ID.Hex(),
+			Slug:             opp.Slug,
+			PublicID:         opp.PublicID,
+			Title:            opp.Title,
+			ShortDescription: opp.ShortDescription,
+			Type:             opp.Type,
+			CoverImage:       opp.Media.CoverImage,
+			City:             opp.Location.City,
+			Ratings:          opp.Ratings,
+			Locale:           opp.Locale,
+		})
+	}
+	return page, nil
+}
+
+// projectHost 將接待主資料轉為公開頁面，未公開精確位置時隱藏地址並以 locationKey 偏移座標
+func projectHost(host *domain.Host, locationKey []byte) *domain.PublicHost {
+	loc := host.Location
+	location := domain.PublicHostLocation{
+		City:        loc.City,
+		District:    loc.District,
+		Country:     loc.Country,
+		Coordinates: loc.Coordinates,
+		Approximate: !loc.ShowExactLocation,
+	}
+	if loc.ShowExactLocation {
+		location.Address = loc.Address
+	} else if loc.Coordinates != nil && len(loc.Coordinates.Coordinates) == 2 {
+		location.Coordinates = &domain.GeoJSON{
+			Type:        loc.Coordinates.Type,
+			Coordinates: fuzzCoordinates(loc.Coordinates.Coordinates, locationKey, host.ID.Hex()),
+		}
+	}
+
+	return &domain.PublicHost{
+		ID:                host.ID.Hex(),
+		Slug:              host.Slug,
+		Name:              host.Name,
+		Description:       host.Description,
+		Type:              host.Type,
+		Category:          host.Category,
+		Verified:          host.Verified,
+		Location:          location,
+		Photos:            host.Photos,
+		PhotoDescriptions: host.PhotoDescriptions,
+		VideoIntroduction: host.VideoIntroduction,
+		AdditionalMedia:   host.AdditionalMedia,
+		Amenities:         host.Amenities,
+		Details:           host.Details,
+		Features:          host.Features,
+		Ratings:           host.Ratings,
+		Website:           host.ContactInfo.Website,
+		SocialMedia:       host.ContactInfo.SocialMedia,
+		MemberSince:       host.CreatedAt,
+		Locale:            host.Locale,
+		AvailableLocales:  host.AvailableLocales,
+		Opportunities:     []domain.PublicHostOpportunity{},
+	}
+}
+
+// fuzzCoordinates 將 [經度, 緯度] 往固定方向偏移一段距離並降低精度。
+// 偏移量由以 key 計算的 seed HMAC 決定，同一個接待主每次得到相同的座標，無法透過多次查詢平均回推實際位置；
+// 不知道 key 時也無法由公開的 seed 算出偏移量。
+func fuzzCoordinates(coords []float64, key []byte, seed string) []float64 {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(seed))
+	sum := binary.BigEndian.Uint64(mac.Sum(nil)[:8])
+
+	angle := float64(sum&0xffff) / 0x10000 * 2 * math.Pi
+	distance := minLocationFuzzMeters + float64((sum>>16)&0xffff)/0x10000*(maxLocationFuzzMeters-minLocationFuzzMeters)
+
+	const metersPerDegree = 111320.0
+	lng, lat := coords[0], coords[1]
+	lat += distance * math.Cos(angle) / metersPerDegree
+	lng += distance * math.Sin(angle) / (metersPerDegree * math.Cos(lat*math.Pi/180))
+
+	round := func(v float64) float64 { return math.Round(v*1000) / 1000 }
+	return []float64{round(lng), round(lat)}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetPublicHost(t *testing.T) {
+	ctx := context.Background()
+	newHost := func(status domain.HostStatus, exact bool) *domain.Host {
+		return &domain.Host{
+			ID:          primitive.NewObjectID(),
+			Slug:        "green-farm-1a2b3c4d",
+			Name:        "Green Farm",
+			Description: "有機農場",
+			Status:      status,
+			ContactInfo: domain.ContactInfo{ContactEmail: "farm@example.com", ContactMobile: "0912345678", Website: "https://farm.example.com"},
+			Location: domain.HostLocation{
+				Address:           "No. 1, Farm Rd.",
+				City:              "Hualien",
+				Country:           "Taiwan",
+				Coordinates:       &domain.GeoJSON{Type: "Point", Coordinates: []float64{121.6, 23.97}},
+				ShowExactLocation: exact,
+			},
+			DefaultLocale: "zh-TW",
+			Translations:  map[string]domain.HostTranslation{"en": {Description: "Organic farm"}},
+		}
+	}
+
+	t.Run("Approximate Location", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		mockOppRepo := new(MockOpportunityRepository)
+		service := NewHostService(mockRepo, mockOppRepo, testHostConfig)
+		host := newHost(domain.HostStatusActive, false)
+		opp := &domain.Opportunity{ID: primitive.NewObjectID(), Title: "幫手", Status: domain.OpportunityStatusActive, Location: domain.OpportunityLocation{City: "Hualien", Address: "No. 1, Farm Rd."}}
+
+		mockRepo.On("GetBySlug", ctx, host.Slug).Return(host, nil)
+		mockOppRepo.On("List", ctx, bson.M{"hostId": host.ID, "status": domain.OpportunityStatusActive}, int64(publicHostOpportunityLimit), int64(0)).Return([]*domain.Opportunity{opp}, nil)
+
+		page, err := service.GetPublicHost(ctx, host.Slug, "en")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Organic farm", page.Description)
+		assert.Equal(t, "en", page.Locale)
+		assert.Equal(t, "https://farm.example.com", page.Website)
+		assert.True(t, page.Location.Approximate)
+		assert.Empty(t, page.Location.Address)
+		assert.NotEqual(t, []float64{121.6, 23.97}, page.Location.Coordinates.Coordinates)
+		assert.Len(t, page.Opportunities, 1)
+		assert.Equal(t, "Hualien", page.Opportunities[0].City)
+
+		// 原始座標不可被修改
+		assert.Equal(t, []float64{121.6, 23.97}, host.Location.Coordinates.Coordinates)
+	})
+
+	t.Run("Exact Location", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		mockOppRepo := new(MockOpportunityRepository)
+		service := NewHostService(mockRepo, mockOppRepo, testHostConfig)
+		host := newHost(domain.HostStatusActive, true)
+
+		mockRepo.On("GetBySlug", ctx, host.Slug).Return(host, nil)
+		mockOppRepo.On("List", ctx, bson.M{"hostId": host.ID, "status": domain.OpportunityStatusActive}, int64(publicHostOpportunityLimit), int64(0)).Return([]*domain.Opportunity{}, nil)
+
+		page, err := service.GetPublicHost(ctx, host.Slug, "")
+
+		assert.NoError(t, err)
+		assert.False(t, page.Location.Approximate)
+		assert.Equal(t, "No. 1, Farm Rd.", page.Location.Address)
+		assert.Equal(t, []float64{121.6, 23.97}, page.Location.Coordinates.Coordinates)
+		assert.NotNil(t, page.Opportunities)
+	})
+
+	t.Run("Not Active", func(t *testing.T) {
+		mockRepo := new(MockHostRepository)
+		service := NewHostService(mockRepo, new(MockOpportunityRepository), testHostConfig)
+		host := newHost(domain.HostStatusSuspended, true)
+
+		mockRepo.On("GetBySlug", ctx, host.Slug).Return(host, nil)
+
+		_, err := service.GetPublicHost(ctx, host.Slug, "")
+
+		assert.ErrorIs(t, err, ErrHostNotFound)
+	})
+}
+
+func TestFuzzCoordinates(t *testing.T) {
+	coords := []float64{121.5654, 25.0330}
+	key := []byte("test-secret")
+	a := fuzzCoordinates(coords, key, "host-a")
+
+	// 同一個接待主每次得到相同結果，不同接待主或不同 key 得到不同結果
+	assert.Equal(t, a, fuzzCoordinates(coords, key, "host-a"))
+	assert.NotEqual(t, a, fuzzCoordinates(coords, key, "host-b"))
+	assert.NotEqual(t, a, fuzzCoordinates(coords, []byte("other-secret"), "host-a"))
+
+	dLat := (a[1] - coords[1]) * 111320
+	dLng := (a[0] - coords[0]) * 111320 * math.Cos(coords[1]*math.Pi/180)
+	distance := math.Hypot(dLat, dLng)
+	// 捨入到小數三位會再產生最多約 80 公尺的誤差
+	assert.Greater(t, distance, float64(minLocationFuzzMeters-100))
+	assert.Less(t, distance, float64(maxLocationFuzzMeters+100))
+}
+
+func TestHostLocationKey(t *testing.T) {
+	derived := hostLocationKey(config.ServerConfig{JWTSecret: "test-secret"})
+
+	// 衍生的金鑰固定且不等於 JWT secret；設定 LocationKey 時改用設定值
+	assert.Len(t, derived, sha256.Size)
+	assert.Equal(t, derived, hostLocationKey(config.ServerConfig{JWTSecret: "test-secret"}))
+	assert.NotEqual(t, []byte("test-secret"), derived)
+	assert.NotEqual(t, derived, hostLocationKey(config.ServerConfig{JWTSecret: "other-secret"}))
+	assert.Equal(t, []byte("location-key"), hostLocationKey(config.ServerConfig{JWTSecret: "test-secret", LocationKey: "location-key"}))
+}
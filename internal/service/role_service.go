@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrRoleNotFound        = errcode.NotFound("ROLE_NOT_FOUND", "role not found")
+	ErrInvalidRole         = errcode.BadRequest("INVALID_ROLE", "invalid role name")
+	ErrInvalidPermission   = errcode.BadRequest("INVALID_PERMISSION", "invalid permission")
+	ErrAdminOnlyPermission = errcode.BadRequest("ADMIN_ONLY_PERMISSION", "user and role management permissions cannot be granted to the USER or HOST role")
+	ErrRoleNotEditable     = errcode.Forbidden("ROLE_NOT_EDITABLE", "the ADMIN role always has every permission and cannot be edited")
+	ErrCannotChangeOwnRole = errcode.Forbidden("CANNOT_CHANGE_OWN_ROLE", "cannot change your own role")
+	ErrAdminRoleRequired   = errcode.Forbidden("ADMIN_ROLE_REQUIRED", "only administrators can assign or remove the ADMIN role")
+	ErrPermissionNotHeld   = errcode.Forbidden("PERMISSION_NOT_HELD", "cannot grant a permission you do not hold")
+)
+
+// rolePermissionCacheTTL 控制權限快取的時間，角色權限調整後最多延遲此時間生效
+const rolePermissionCacheTTL = time.Minute
+
+// defaultRoles 是系統內建的角色，啟動時若不存在會自動建立
+var defaultRoles = []*domain.Role{
+	{Name: domain.RoleAdmin, Description: "Full access to every administrative feature", Permissions: domain.AllPermissions, System: true},
+	{
+		Name:        domain.RoleModerator,
+		Description: "Reviews user-generated content",
+		Permissions: []domain.Permission{
+			domain.PermissionStatsRead,
+			domain.PermissionImagesReview,
+			domain.PermissionOpportunitiesModerate,
+			domain.PermissionUsersRead,
+		},
+		System: true,
+	},
+	{Name: domain.RoleHost, Description: "Hosts offering opportunities", Permissions: []domain.Permission{}, System: true},
+	{Name: domain.RoleUser, Description: "Travelers", Permissions: []domain.Permission{}, System: true},
+}
+
+// RoleService 管理角色的權限組合與使用者的角色指派
+type RoleService interface {
+	EnsureDefaultRoles(ctx context.Context) error
+	HasPermission(ctx context.Context, role domain.UserRole, permission domain.Permission) (bool, error)
+	ListRoles(ctx context.Context) ([]*domain.Role, error)
+	UpdateRole(ctx context.Context, actorID string, name domain.UserRole, description string, permissions []domain.Permission) (*domain.Role, error)
+	AssignRole(ctx context.Context, actorID, userID string, role domain.UserRole) error
+}
+
+type cachedPermissions struct {
+	permissions map[domain.Permission]bool
+	expiresAt   time.Time
+}
+
+type roleService struct {
+	roleRepo       repository.RoleRepository
+	userRepo       repository.UserRepository
+	sessionService SessionService
+
+	mu    sync.RWMutex
+	cache map[domain.UserRole]cachedPermissions
+}
+
+func NewRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository, sessionService SessionService) RoleService {
+	return &roleService{
+		roleRepo:       roleRepo,
+		userRepo:       userRepo,
+		sessionService: sessionService,
+		cache:          make(map[domain.UserRole]cachedPermissions),
+	}
+}
+
+// EnsureDefaultRoles 建立內建角色。
+// ADMIN 每次都會同步為所有權限，其他角色只在不存在時建立，保留管理員的調整。
+func (s *roleService) EnsureDefaultRoles(ctx context.Context) error {
+	for _, role := range defaultRoles {
+		var err error
+		if role.Name == domain.RoleAdmin {
+			err = s.roleRepo.Upsert(ctx, role)
+		} else {
+			err = s.roleRepo.CreateIfNotExists(ctx, role)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HasPermission 回傳角色是否擁有指定權限，ADMIN 永遠擁有所有權限
+func (s *roleService) HasPermission(ctx context.Context, role domain.UserRole, permission domain.Permission) (bool, error) {
+	if role == domain.RoleAdmin {
+		return true, nil
+	}
+
+	s.mu.RLock()
+	cached, ok := s.cache[role]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.permissions[permission], nil
+	}
+
+	permissions := make(map[domain.Permission]bool)
+	stored, err := s.roleRepo.GetByName(ctx, role)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return false, err
+	}
+	if stored != nil {
+		for _, p := range stored.Permissions {
+			// 即使資料庫中的 USER/HOST 角色被寫入管理權限也不生效
+			if p.IsAdminOnly() && !role.IsStaff() {
+				continue
+			}
+			permissions[p] = true
+		}
+	}
+
+	s.mu.Lock()
+	s.cache[role] = cachedPermissions{permissions: permissions, expiresAt: time.Now().Add(rolePermissionCacheTTL)}
+	s.mu.Unlock()
+
+	return permissions[permission], nil
+}
+
+func (s *roleService) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	return s.roleRepo.List(ctx)
+}
+
+// UpdateRole 設定角色的權限組合，角色不存在時會建立新角色。
+// 操作者必須擁有角色調整前後的所有權限，避免替自己或他人提升權限。
+// USER 與 HOST 涵蓋所有一般帳號，不可授予帳號與角色管理權限。
+func (s *roleService) UpdateRole(ctx context.Context, actorID string, name domain.UserRole, description string, permissions []domain.Permission) (*domain.Role, error) {
+	if !name.IsValid() {
+		return nil, ErrInvalidRole
+	}
+	if name == domain.RoleAdmin {
+		return nil, ErrRoleNotEditable
+	}
+	for _, p := range permissions {
+		if !p.IsValid() {
+			return nil, ErrInvalidPermission
+		}
+		if p.IsAdminOnly() && !name.IsStaff() {
+			return nil, ErrAdminOnlyPermission.WithMeta("permission", p)
+		}
+	}
+	if permissions == nil {
+		permissions = []domain.Permission{}
+	}
+
+	actorRole, err := s.actorRole(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.roleRepo.GetByName(ctx, name)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	if current != nil {
+		if err := s.requirePermissions(ctx, actorRole, current.Permissions); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.requirePermissions(ctx, actorRole, permissions); err != nil {
+		return nil, err
+	}
+
+	role := &domain.Role{Name: name, Description: description, Permissions: permissions}
+	if err := s.roleRepo.Upsert(ctx, role); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	delete(s.cache, name)
+	s.mu.Unlock()
+
+	logger.Info("Role permissions updated", "role", name, "permissions", permissions)
+	return s.roleRepo.GetByName(ctx, name)
+}
+
+// AssignRole 變更使用者的角色。
+// 只有 ADMIN 能指派或移除 ADMIN，其他情況操作者必須擁有新舊角色的所有權限。
+// 角色寫在 access token 中，因此變更後撤銷該使用者所有 session，強制以新角色重新登入。
+func (s *roleService) AssignRole(ctx context.Context, actorID, userID string, role domain.UserRole) error {
+	if !role.IsValid() {
+		return ErrInvalidRole
+	}
+	if actorID == userID {
+		return ErrCannotChangeOwnRole
+	}
+
+	target, err := s.roleRepo.GetByName(ctx, role)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// 角色名稱來自請求內容，不存在時視為參數錯誤
+			return ErrRoleNotFound.WithStatus(http.StatusBadRequest)
+		}
+		return err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return notFound(err, ErrUserNotFound)
+	}
+	if user.Role == role {
+		return nil
+	}
+
+	actorRole, err := s.actorRole(ctx, actorID)
+	if err != nil {
+		return err
+	}
+	if actorRole != domain.RoleAdmin && (role == domain.RoleAdmin || user.Role == domain.RoleAdmin) {
+		return ErrAdminRoleRequired
+	}
+	if err := s.requirePermissions(ctx, actorRole, target.Permissions); err != nil {
+		return err
+	}
+	current, err := s.roleRepo.GetByName(ctx, user.Role)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	if current != nil {
+		if err := s.requirePermissions(ctx, actorRole, current.Permissions); err != nil {
+			return err
+		}
+	}
+
+	if err := s.userRepo.Update(ctx, userID, bson.M{"role": role}); err != nil {
+		return err
+	}
+	if _, err := s.sessionService.RevokeAllUserSessions(ctx, userID, "", SessionRevokedRoleChanged); err != nil {
+		return err
+	}
+
+	logger.Info("User role changed", "userId", userID, "from", user.Role, "to", role, "by", actorID)
+	return nil
+}
+
+// actorRole 讀取操作者目前的角色，以資料庫為準而非 token 內可能過期的角色
+func (s *roleService) actorRole(ctx context.Context, actorID string) (domain.UserRole, error) {
+	actor, err := s.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", errcode.ErrForbidden
+		}
+		return "", err
+	}
+	return actor.Role, nil
+}
+
+// requirePermissions 確認操作者的角色擁有所有指定權限
+func (s *roleService) requirePermissions(ctx context.Context, actorRole domain.UserRole, permissions []domain.Permission) error {
+	for _, p := range permissions {
+		allowed, err := s.HasPermission(ctx, actorRole, p)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ErrPermissionNotHeld.WithMeta("permission", p)
+		}
+	}
+	return nil
+}
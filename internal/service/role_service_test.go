@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MockRoleRepository 是一個用於測試的 RoleRepository mock
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockRoleRepository) GetByName(ctx context.Context, name domain.UserRole) (*domain.Role, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) List(ctx context.Context) ([]*domain.Role, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Role), args.Error(1)
+}
+
+func (m *MockRoleRepository) Upsert(ctx context.Context, role *domain.Role) error {
+	args := m.Called(ctx, role)
+	return args.Error(0)
+}
+
+func (m *MockRoleRepository) CreateIfNotExists(ctx context.Context, role *domain.Role) error {
+	args := m.Called(ctx, role)
+	return args.Error(0)
+}
+
+func TestEnsureDefaultRoles(t *testing.T) {
+	ctx := context.Background()
+	mockRoles := new(MockRoleRepository)
+	svc := NewRoleService(mockRoles, new(mockUserRepository), new(MockSessionService))
+
+	// ADMIN 永遠同步為所有權限，其他角色不覆寫
+	mockRoles.On("Upsert", ctx, mock.MatchedBy(func(r *domain.Role) bool {
+		return r.Name == domain.RoleAdmin && len(r.Permissions) == len(domain.AllPermissions)
+	})).Return(nil).Once()
+	mockRoles.On("CreateIfNotExists", ctx, mock.AnythingOfType("*domain.Role")).Return(nil).Times(3)
+
+	assert.NoError(t, svc.EnsureDefaultRoles(ctx))
+	mockRoles.AssertExpectations(t)
+}
+
+func TestHasPermission(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Admin Has Every Permission", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		svc := NewRoleService(mockRoles, new(mockUserRepository), new(MockSessionService))
+
+		allowed, err := svc.HasPermission(ctx, domain.RoleAdmin, domain.PermissionUsersSuspend)
+
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+		mockRoles.AssertNotCalled(t, "GetByName", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Moderator Permissions Are Cached", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		svc := NewRoleService(mockRoles, new(mockUserRepository), new(MockSessionService))
+
+		mockRoles.On("GetByName", ctx, domain.RoleModerator).Return(&domain.Role{
+			Name:        domain.RoleModerator,
+			Permissions: []domain.Permission{domain.PermissionImagesReview},
+		}, nil).Once()
+
+		allowed, err := svc.HasPermission(ctx, domain.RoleModerator, domain.PermissionImagesReview)
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+
+		allowed, err = svc.HasPermission(ctx, domain.RoleModerator, domain.PermissionUsersSuspend)
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		mockRoles.AssertNumberOfCalls(t, "GetByName", 1)
+	})
+
+	t.Run("Ignores Admin Only Permissions On User Role", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		svc := NewRoleService(mockRoles, new(mockUserRepository), new(MockSessionService))
+
+		mockRoles.On("GetByName", ctx, domain.RoleUser).Return(&domain.Role{
+			Name:        domain.RoleUser,
+			Permissions: []domain.Permission{domain.PermissionStatsRead, domain.PermissionUsersDelete},
+		}, nil)
+
+		allowed, err := svc.HasPermission(ctx, domain.RoleUser, domain.PermissionUsersDelete)
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		allowed, _ = svc.HasPermission(ctx, domain.RoleUser, domain.PermissionStatsRead)
+		assert.True(t, allowed)
+	})
+
+	t.Run("Unknown Role Has No Permissions", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		svc := NewRoleService(mockRoles, new(mockUserRepository), new(MockSessionService))
+
+		mockRoles.On("GetByName", ctx, domain.UserRole("GHOST")).Return(nil, mongo.ErrNoDocuments)
+
+		allowed, err := svc.HasPermission(ctx, "GHOST", domain.PermissionStatsRead)
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+	})
+}
+
+func TestUpdateRole(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+
+	t.Run("Admin Role Is Not Editable", func(t *testing.T) {
+		svc := NewRoleService(new(MockRoleRepository), new(mockUserRepository), new(MockSessionService))
+
+		_, err := svc.UpdateRole(ctx, "admin-1", domain.RoleAdmin, "", nil)
+		assert.ErrorIs(t, err, ErrRoleNotEditable)
+	})
+
+	t.Run("Invalid Role Name", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		mockRepo := new(mockUserRepository)
+		svc := NewRoleService(mockRoles, mockRepo, new(MockSessionService))
+
+		for _, name := range []domain.UserRole{"", "MOD ERATOR", "ADMIN\x00", "$ADMIN"} {
+			_, err := svc.UpdateRole(ctx, "admin-1", name, "", nil)
+			assert.ErrorIs(t, err, ErrInvalidRole)
+		}
+		mockRoles.AssertNotCalled(t, "GetByName", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Admin Only Permission On User And Host", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		mockRepo := new(mockUserRepository)
+		svc := NewRoleService(mockRoles, mockRepo, new(MockSessionService))
+
+		for _, name := range []domain.UserRole{domain.RoleUser, domain.RoleHost} {
+			for _, p := range []domain.Permission{domain.PermissionUsersSuspend, domain.PermissionRolesAssign} {
+				_, err := svc.UpdateRole(ctx, "admin-1", name, "", []domain.Permission{domain.PermissionStatsRead, p})
+				assert.ErrorIs(t, err, ErrAdminOnlyPermission)
+			}
+		}
+		mockRoles.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Unknown Permission", func(t *testing.T) {
+		svc := NewRoleService(new(MockRoleRepository), new(mockUserRepository), new(MockSessionService))
+
+		_, err := svc.UpdateRole(ctx, "admin-1", domain.RoleModerator, "", []domain.Permission{"images:delete-everything"})
+		assert.ErrorIs(t, err, ErrInvalidPermission)
+	})
+
+	t.Run("Invalidates Cache", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		mockRepo := new(mockUserRepository)
+		svc := NewRoleService(mockRoles, mockRepo, new(MockSessionService))
+
+		mockRepo.On("GetByID", ctx, "admin-1").Return(&domain.User{ID: "admin-1", Role: domain.RoleAdmin}, nil)
+
+		before := &domain.Role{Name: domain.RoleModerator, Permissions: []domain.Permission{}}
+		after := &domain.Role{Name: domain.RoleModerator, Permissions: []domain.Permission{domain.PermissionHostsVerify}}
+		mockRoles.On("GetByName", ctx, domain.RoleModerator).Return(before, nil).Once()
+		allowed, _ := svc.HasPermission(ctx, domain.RoleModerator, domain.PermissionHostsVerify)
+		assert.False(t, allowed)
+
+		mockRoles.On("Upsert", ctx, mock.AnythingOfType("*domain.Role")).Return(nil)
+		mockRoles.On("GetByName", ctx, domain.RoleModerator).Return(after, nil)
+
+		role, err := svc.UpdateRole(ctx, "admin-1", domain.RoleModerator, "Moderators", after.Permissions)
+		assert.NoError(t, err)
+		assert.Equal(t, after, role)
+
+		allowed, _ = svc.HasPermission(ctx, domain.RoleModerator, domain.PermissionHostsVerify)
+		assert.True(t, allowed)
+	})
+
+	t.Run("Cannot Grant Permission Not Held", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		mockRepo := new(mockUserRepository)
+		svc := NewRoleService(mockRoles, mockRepo, new(MockSessionService))
+
+		manager := domain.UserRole("ROLE_MANAGER")
+		mockRepo.On("GetByID", ctx, "manager-1").Return(&domain.User{ID: "manager-1", Role: manager}, nil)
+		mockRoles.On("GetByName", ctx, manager).Return(&domain.Role{Name: manager, Permissions: []domain.Permission{domain.PermissionRolesManage}}, nil)
+
+		_, err := svc.UpdateRole(ctx, "manager-1", manager, "", domain.AllPermissions)
+		assert.ErrorIs(t, err, ErrPermissionNotHeld)
+		mockRoles.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAssignRole(t *testing.T) {
+	logger.InitLogger("error")
+	ctx := context.Background()
+
+	t.Run("Revokes Sessions After Change", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		mockRepo := new(mockUserRepository)
+		mockSessions := new(MockSessionService)
+		svc := NewRoleService(mockRoles, mockRepo, mockSessions)
+
+		mockRepo.On("GetByID", ctx, "admin-1").Return(&domain.User{ID: "admin-1", Role: domain.RoleAdmin}, nil)
+		mockRoles.On("GetByName", ctx, domain.RoleModerator).Return(&domain.Role{Name: domain.RoleModerator}, nil)
+		mockRoles.On("GetByName", ctx, domain.RoleUser).Return(&domain.Role{Name: domain.RoleUser}, nil)
+		mockRepo.On("GetByID", ctx, "user-1").Return(&domain.User{ID: "user-1", Role: domain.RoleUser}, nil)
+		mockRepo.On("Update", ctx, "user-1", bson.M{"role": domain.RoleModerator}).Return(nil)
+		mockSessions.On("RevokeAllUserSessions", ctx, "user-1", "", SessionRevokedRoleChanged).Return(int64(1), nil)
+
+		assert.NoError(t, svc.AssignRole(ctx, "admin-1", "user-1", domain.RoleModerator))
+		mockRepo.AssertExpectations(t)
+		mockSessions.AssertExpectations(t)
+	})
+
+	t.Run("Cannot Change Own Role", func(t *testing.T) {
+		svc := NewRoleService(new(MockRoleRepository), new(mockUserRepository), new(MockSessionService))
+
+		err := svc.AssignRole(ctx, "admin-1", "admin-1", domain.RoleUser)
+		assert.ErrorIs(t, err, ErrCannotChangeOwnRole)
+	})
+
+	t.Run("Unknown Role", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		svc := NewRoleService(mockRoles, new(mockUserRepository), new(MockSessionService))
+
+		mockRoles.On("GetByName", ctx, domain.UserRole("WIZARD")).Return(nil, mongo.ErrNoDocuments)
+
+		err := svc.AssignRole(ctx, "admin-1", "user-1", "WIZARD")
+		assert.ErrorIs(t, err, ErrRoleNotFound)
+		var e *errcode.Error
+		assert.ErrorAs(t, err, &e)
+		assert.Equal(t, http.StatusBadRequest, e.Status)
+	})
+
+	t.Run("Invalid Role Name", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		mockRepo := new(mockUserRepository)
+		svc := NewRoleService(mockRoles, mockRepo, new(MockSessionService))
+
+		err := svc.AssignRole(ctx, "admin-1", "user-1", "ADMIN; DROP")
+		assert.ErrorIs(t, err, ErrInvalidRole)
+		mockRoles.AssertNotCalled(t, "GetByName", mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Same Role Is A No-op", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		mockRepo := new(mockUserRepository)
+		mockSessions := new(MockSessionService)
+		svc := NewRoleService(mockRoles, mockRepo, mockSessions)
+
+		mockRoles.On("GetByName", ctx, domain.RoleHost).Return(&domain.Role{Name: domain.RoleHost}, nil)
+		mockRepo.On("GetByID", ctx, "user-1").Return(&domain.User{ID: "user-1", Role: domain.RoleHost}, nil)
+
+		assert.NoError(t, svc.AssignRole(ctx, "admin-1", "user-1", domain.RoleHost))
+		mockSessions.AssertNotCalled(t, "RevokeAllUserSessions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Only Admin Can Assign Admin", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		mockRepo := new(mockUserRepository)
+		svc := NewRoleService(mockRoles, mockRepo, new(MockSessionService))
+
+		mockRoles.On("GetByName", ctx, domain.RoleAdmin).Return(&domain.Role{Name: domain.RoleAdmin, Permissions: domain.AllPermissions}, nil)
+		mockRepo.On("GetByID", ctx, "user-1").Return(&domain.User{ID: "user-1", Role: domain.RoleUser}, nil)
+		mockRepo.On("GetByID", ctx, "mod-1").Return(&domain.User{ID: "mod-1", Role: domain.RoleModerator}, nil)
+
+		err := svc.AssignRole(ctx, "mod-1", "user-1", domain.RoleAdmin)
+		assert.ErrorIs(t, err, ErrAdminRoleRequired)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Only Admin Can Demote Admin", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		mockRepo := new(mockUserRepository)
+		svc := NewRoleService(mockRoles, mockRepo, new(MockSessionService))
+
+		mockRoles.On("GetByName", ctx, domain.RoleUser).Return(&domain.Role{Name: domain.RoleUser}, nil)
+		mockRepo.On("GetByID", ctx, "admin-2").Return(&domain.User{ID: "admin-2", Role: domain.RoleAdmin}, nil)
+		mockRepo.On("GetByID", ctx, "mod-1").Return(&domain.User{ID: "mod-1", Role: domain.RoleModerator}, nil)
+
+		err := svc.AssignRole(ctx, "mod-1", "admin-2", domain.RoleUser)
+		assert.ErrorIs(t, err, ErrAdminRoleRequired)
+	})
+
+	t.Run("Cannot Assign Role With Permissions Not Held", func(t *testing.T) {
+		mockRoles := new(MockRoleRepository)
+		mockRepo := new(mockUserRepository)
+		svc := NewRoleService(mockRoles, mockRepo, new(MockSessionService))
+
+		mockRoles.On("GetByName", ctx, domain.RoleModerator).Return(&domain.Role{
+			Name:        domain.RoleModerator,
+			Permissions: []domain.Permission{domain.PermissionImagesReview},
+		}, nil)
+		mockRoles.On("GetByName", ctx, domain.RoleHost).Return(&domain.Role{Name: domain.RoleHost}, nil)
+		mockRepo.On("GetByID", ctx, "user-1").Return(&domain.User{ID: "user-1", Role: domain.RoleUser}, nil)
+		mockRepo.On("GetByID", ctx, "assigner-1").Return(&domain.User{ID: "assigner-1", Role: domain.RoleHost}, nil)
+
+		err := svc.AssignRole(ctx, "assigner-1", "user-1", domain.RoleModerator)
+		assert.ErrorIs(t, err, ErrPermissionNotHeld)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/internal/repository"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrInvalidRefreshToken = errcode.Unauthorized("INVALID_REFRESH_TOKEN", "invalid refresh token")
+	ErrRefreshTokenReused  = errcode.Unauthorized("REFRESH_TOKEN_REUSED", "refresh token reuse detected")
+	ErrSessionNotFound     = errcode.NotFound("SESSION_NOT_FOUND", "session not found")
+)
+
+// Session 撤銷原因
+const (
+	SessionRevokedLogout           = "LOGOUT"
+	SessionRevokedTokenReuse       = "REFRESH_TOKEN_REUSE"
+	SessionRevokedUserInactive     = "USER_INACTIVE"
+	SessionRevokedByUser           = "REVOKED_BY_USER"
+	SessionRevokedByAdmin          = "REVOKED_BY_ADMIN"
+	SessionRevokedProviderUnlinked = "PROVIDER_UNLINKED"
+	SessionRevokedPasswordReset    = "PASSWORD_RESET"
+	SessionRevokedPasswordChanged  = "PASSWORD_CHANGED"
+	SessionRevokedRoleChanged      = "ROLE_CHANGED"
+	SessionRevokedAccountDeleted   = "ACCOUNT_DELETED"
+	SessionRevokedAccountClaimed   = "ACCOUNT_CLAIMED"
+)
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// SessionMeta 記錄建立或使用 session 時的裝置資訊
+type SessionMeta struct {
+	UserAgent   string
+	IP          string
+	MFAVerified bool // 此次登入是否通過兩步驟驗證
+}
+
+// AuthTokens 是登入或 refresh 後回傳給客戶端的 token 組合
+type AuthTokens struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int64  `json:"expiresIn"` // access token 有效秒數
+	SessionID    string `json:"-"`
+}
+
+// SessionService 管理伺服器端 session、access token 與 refresh token 的輪替
+type SessionService interface {
+	CreateSession(ctx context.Context, user *domain.User, meta SessionMeta) (*AuthTokens, error)
+	RefreshSession(ctx context.Context, refreshToken string, meta SessionMeta) (*AuthTokens, error)
+	RevokeSession(ctx context.Context, sessionID, reason string) error
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+	ListUserSessions(ctx context.Context, userID string) ([]*domain.Session, error)
+	GetUserSession(ctx context.Context, userID, sessionID string) (*domain.Session, error)
+	RevokeUserSession(ctx context.Context, userID, sessionID string) error
+	RevokeAllUserSessions(ctx context.Context, userID, exceptSessionID, reason string) (int64, error)
+}
+
+type sessionService struct {
+	repo            repository.SessionRepository
+	userRepo        repository.UserRepository
+	jwtSecret       string
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+func NewSessionService(repo repository.SessionRepository, userRepo repository.UserRepository, cfg *config.Config) SessionService {
+	accessTTL := cfg.Auth.AccessTokenTTL
+	if accessTTL <= 0 {
+		accessTTL = defaultAccessTokenTTL
+	}
+	refreshTTL := cfg.Auth.RefreshTokenTTL
+	if refreshTTL <= 0 {
+		refreshTTL = defaultRefreshTokenTTL
+	}
+	return &sessionService{
+		repo:            repo,
+		userRepo:        userRepo,
+		jwtSecret:       cfg.Server.JWTSecret,
+		accessTokenTTL:  accessTTL,
+		refreshTokenTTL: refreshTTL,
+	}
+}
+
+func (s *sessionService) CreateSession(ctx context.Context, user *domain.User, meta SessionMeta) (*AuthTokens, error) {
+	userObjID, err := primitive.ObjectIDFromHex(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &domain.Session{
+		UserID:           userObjID,
+		RefreshTokenHash: hashToken(refreshToken),
+		UserAgent:        meta.UserAgent,
+		IP:               meta.IP,
+		ExpiresAt:        time.Now().Add(s.refreshTokenTTL),
+		MFAVerified:      meta.MFAVerified,
+	}
+	if err := s.repo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session, refreshToken)
+}
+
+func (s *sessionService) RefreshSession(ctx context.Context, refreshToken string, meta SessionMeta) (*AuthTokens, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+	oldHash := hashToken(refreshToken)
+
+	// 1. 找出目前持有此 refresh token 的 session
+	session, err := s.repo.GetByRefreshTokenHash(ctx, oldHash)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, s.detectReuse(ctx, oldHash)
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// 2. 重新讀取使用者，確保名稱/角色為最新且帳號仍有效
+	user, err := s.userRepo.GetByID(ctx, session.UserID.Hex())
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			_ = s.repo.Revoke(ctx, session.ID.Hex(), SessionRevokedUserInactive)
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	if user.Status == domain.UserStatusSuspended || user.Status == domain.UserStatusDeleted {
+		_ = s.repo.Revoke(ctx, session.ID.Hex(), SessionRevokedUserInactive)
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// 3. 輪替 refresh token
+	newRefreshToken, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	session.RefreshTokenHash = hashToken(newRefreshToken)
+	session.UserAgent = meta.UserAgent
+	session.IP = meta.IP
+	session.ExpiresAt = now.Add(s.refreshTokenTTL)
+
+	if err := s.repo.Rotate(ctx, session, oldHash); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			// 另一個請求已搶先使用此 token
+			return nil, s.detectReuse(ctx, oldHash)
+		}
+		return nil, err
+	}
+
+	return s.issueTokens(user, session, newRefreshToken)
+}
+
+// detectReuse 檢查 token 是否為已輪替過的舊 token。
+// 若是，代表 token 可能已外洩，撤銷整個 session (token family)。
+func (s *sessionService) detectReuse(ctx context.Context, hash string) error {
+	session, err := s.repo.GetByPreviousTokenHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrInvalidRefreshToken
+		}
+		return err
+	}
+
+	logger.Warn("Refresh token reuse detected, revoking session", "sessionId", session.ID.Hex(), "userId", session.UserID.Hex())
+	if err := s.repo.Revoke(ctx, session.ID.Hex(), SessionRevokedTokenReuse); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+func (s *sessionService) RevokeSession(ctx context.Context, sessionID, reason string) error {
+	return s.repo.Revoke(ctx, sessionID, reason)
+}
+
+func (s *sessionService) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	session, err := s.repo.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return false, nil
+		}
+		return false, err
+	}
+	return session.IsActive(time.Now()), nil
+}
+
+func (s *sessionService) ListUserSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	return s.repo.ListActiveByUserID(ctx, userID)
+}
+
+// GetUserSession 回傳使用者自己仍有效的某個 session
+func (s *sessionService) GetUserSession(ctx context.Context, userID, sessionID string) (*domain.Session, error) {
+	session, err := s.repo.GetByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	// 不透露其他使用者的 session 是否存在
+	if session.UserID.Hex() != userID || !session.IsActive(time.Now()) {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// RevokeUserSession 撤銷使用者自己的某個 session (例如遺失的裝置)
+func (s *sessionService) RevokeUserSession(ctx context.Context, userID, sessionID string) error {
+	if _, err := s.GetUserSession(ctx, userID, sessionID); err != nil {
+		return err
+	}
+	return s.repo.Revoke(ctx, sessionID, SessionRevokedByUser)
+}
+
+// RevokeAllUserSessions 撤銷使用者所有 session，exceptSessionID 不為空時保留該 session
+func (s *sessionService) RevokeAllUserSessions(ctx context.Context, userID, exceptSessionID, reason string) (int64, error) {
+	return s.repo.RevokeAllByUserID(ctx, userID, exceptSessionID, reason)
+}
+
+// issueTokens 簽發綁定 session 的 access token
+func (s *sessionService) issueTokens(user *domain.User, session *domain.Session, refreshToken string) (*AuthTokens, error) {
+	now := time.Now()
+	sessionID := session.ID.Hex()
+	claims := jwt.MapClaims{
+		"sub":  user.ID,
+		"name": user.Name,
+		"role": user.Role,
+		"sid":  sessionID,
+		"mfa":  session.MFAVerified,
+		"exp":  now.Add(s.accessTokenTTL).Unix(),
+		"iat":  now.Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	accessToken, err := token.SignedString([]byte(s.jwtSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(s.accessTokenTTL.Seconds()),
+		SessionID:    sessionID,
+	}, nil
+}
+
+// generateOpaqueToken 產生一個隨機且不可預測的 token
+func generateOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken 以 SHA-256 雜湊 token，資料庫僅保存雜湊值
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/taiwanstay/taiwanstay-back/internal/domain"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MockSessionRepository is a mock implementation of SessionRepository
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(ctx context.Context, session *domain.Session) error {
+	args := m.Called(ctx, session)
+	if args.Error(0) == nil {
+		session.ID = primitive.NewObjectID()
+	}
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) GetByID(ctx context.Context, id string) (*domain.Session, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) GetByRefreshTokenHash(ctx context.Context, hash string) (*domain.Session, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) GetByPreviousTokenHash(ctx context.Context, hash string) (*domain.Session, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Rotate(ctx context.Context, session *domain.Session, oldHash string) error {
+	args := m.Called(ctx, session, oldHash)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) Revoke(ctx context.Context, id string, reason string) error {
+	args := m.Called(ctx, id, reason)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) ListActiveByUserID(ctx context.Context, userID string) ([]*domain.Session, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeAllByUserID(ctx context.Context, userID string, exceptID string, reason string) (int64, error) {
+	args := m.Called(ctx, userID, exceptID, reason)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func newTestSessionService(repo *MockSessionRepository, userRepo *mockUserRepository) SessionService {
+	logger.InitLogger("error")
+	cfg := &config.Config{Server: config.ServerConfig{JWTSecret: "test-secret"}}
+	return NewSessionService(repo, userRepo, cfg)
+}
+
+func TestSessionService_CreateSession(t *testing.T) {
+	mockRepo := new(MockSessionRepository)
+	svc := newTestSessionService(mockRepo, new(mockUserRepository))
+
+	user := &domain.User{ID: primitive.NewObjectID().Hex(), Name: "tester", Role: domain.RoleUser}
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(s *domain.Session) bool {
+		return s.RefreshTokenHash != "" && s.UserAgent == "ua" && s.IP == "1.2.3.4"
+	})).Return(nil)
+
+	tokens, err := svc.CreateSession(context.Background(), user, SessionMeta{UserAgent: "ua", IP: "1.2.3.4"})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.NotEmpty(t, tokens.SessionID)
+	assert.Equal(t, int64(defaultAccessTokenTTL.Seconds()), tokens.ExpiresIn)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSessionService_RefreshSession(t *testing.T) {
+	userID := primitive.NewObjectID()
+	user := &domain.User{ID: userID.Hex(), Name: "tester", Role: domain.RoleUser, Status: domain.UserStatusActive}
+
+	t.Run("Rotates Token", func(t *testing.T) {
+		mockRepo := new(MockSessionRepository)
+		mockUserRepo := new(mockUserRepository)
+		svc := newTestSessionService(mockRepo, mockUserRepo)
+
+		oldHash := hashToken("old-token")
+		session := &domain.Session{ID: primitive.NewObjectID(), UserID: userID, RefreshTokenHash: oldHash, ExpiresAt: time.Now().Add(time.Hour)}
+		mockRepo.On("GetByRefreshTokenHash", mock.Anything, oldHash).Return(session, nil)
+		mockUserRepo.On("GetByID", mock.Anything, userID.Hex()).Return(user, nil)
+		mockRepo.On("Rotate", mock.Anything, session, oldHash).Return(nil)
+
+		tokens, err := svc.RefreshSession(context.Background(), "old-token", SessionMeta{})
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, "old-token", tokens.RefreshToken)
+		assert.Equal(t, hashToken(tokens.RefreshToken), session.RefreshTokenHash)
+		assert.Equal(t, session.ID.Hex(), tokens.SessionID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Reuse Revokes Session", func(t *testing.T) {
+		mockRepo := new(MockSessionRepository)
+		svc := newTestSessionService(mockRepo, new(mockUserRepository))
+
+		oldHash := hashToken("rotated-token")
+		session := &domain.Session{ID: primitive.NewObjectID(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+		mockRepo.On("GetByRefreshTokenHash", mock.Anything, oldHash).Return(nil, mongo.ErrNoDocuments)
+		mockRepo.On("GetByPreviousTokenHash", mock.Anything, oldHash).Return(session, nil)
+		mockRepo.On("Revoke", mock.Anything, session.ID.Hex(), SessionRevokedTokenReuse).Return(nil)
+
+		_, err := svc.RefreshSession(context.Background(), "rotated-token", SessionMeta{})
+
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown Token", func(t *testing.T) {
+		mockRepo := new(MockSessionRepository)
+		svc := newTestSessionService(mockRepo, new(mockUserRepository))
+
+		hash := hashToken("unknown")
+		mockRepo.On("GetByRefreshTokenHash", mock.Anything, hash).Return(nil, mongo.ErrNoDocuments)
+		mockRepo.On("GetByPreviousTokenHash", mock.Anything, hash).Return(nil, mongo.ErrNoDocuments)
+
+		_, err := svc.RefreshSession(context.Background(), "unknown", SessionMeta{})
+
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Revoked Session", func(t *testing.T) {
+		mockRepo := new(MockSessionRepository)
+		svc := newTestSessionService(mockRepo, new(mockUserRepository))
+
+		revokedAt := time.Now()
+		hash := hashToken("revoked")
+		session := &domain.Session{ID: primitive.NewObjectID(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+		mockRepo.On("GetByRefreshTokenHash", mock.Anything, hash).Return(session, nil)
+
+		_, err := svc.RefreshSession(context.Background(), "revoked", SessionMeta{})
+
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+		mockRepo.AssertNotCalled(t, "Rotate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Suspended User", func(t *testing.T) {
+		mockRepo := new(MockSessionRepository)
+		mockUserRepo := new(mockUserRepository)
+		svc := newTestSessionService(mockRepo, mockUserRepo)
+
+		hash := hashToken("suspended")
+		session := &domain.Session{ID: primitive.NewObjectID(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+		suspended := &domain.User{ID: userID.Hex(), Status: domain.UserStatusSuspended}
+		mockRepo.On("GetByRefreshTokenHash", mock.Anything, hash).Return(session, nil)
+		mockUserRepo.On("GetByID", mock.Anything, userID.Hex()).Return(suspended, nil)
+		mockRepo.On("Revoke", mock.Anything, session.ID.Hex(), SessionRevokedUserInactive).Return(nil)
+
+		_, err := svc.RefreshSession(context.Background(), "suspended", SessionMeta{})
+
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSessionService_IsSessionActive(t *testing.T) {
+	mockRepo := new(MockSessionRepository)
+	svc := newTestSessionService(mockRepo, new(mockUserRepository))
+
+	revokedAt := time.Now()
+	mockRepo.On("GetByID", mock.Anything, "active").Return(&domain.Session{ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	mockRepo.On("GetByID", mock.Anything, "revoked").Return(&domain.Session{ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil)
+	mockRepo.On("GetByID", mock.Anything, "missing").Return(nil, mongo.ErrNoDocuments)
+
+	active, err := svc.IsSessionActive(context.Background(), "active")
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	active, err = svc.IsSessionActive(context.Background(), "revoked")
+	assert.NoError(t, err)
+	assert.False(t, active)
+
+	active, err = svc.IsSessionActive(context.Background(), "missing")
+	assert.NoError(t, err)
+	assert.False(t, active)
+}
+
+func TestSessionService_RevokeUserSession(t *testing.T) {
+	ownerID := primitive.NewObjectID()
+	sessionID := primitive.NewObjectID()
+	session := &domain.Session{ID: sessionID, UserID: ownerID, ExpiresAt: time.Now().Add(time.Hour)}
+
+	t.Run("Owner Revokes Session", func(t *testing.T) {
+		mockRepo := new(MockSessionRepository)
+		svc := newTestSessionService(mockRepo, new(mockUserRepository))
+
+		mockRepo.On("GetByID", mock.Anything, sessionID.Hex()).Return(session, nil)
+		mockRepo.On("Revoke", mock.Anything, sessionID.Hex(), SessionRevokedByUser).Return(nil)
+
+		err := svc.RevokeUserSession(context.Background(), ownerID.Hex(), sessionID.Hex())
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Other User Session", func(t *testing.T) {
+		mockRepo := new(MockSessionRepository)
+		svc := newTestSessionService(mockRepo, new(mockUserRepository))
+
+		mockRepo.On("GetByID", mock.Anything, sessionID.Hex()).Return(session, nil)
+
+		err := svc.RevokeUserSession(context.Background(), primitive.NewObjectID().Hex(), sessionID.Hex())
+
+		assert.ErrorIs(t, err, ErrSessionNotFound)
+		mockRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
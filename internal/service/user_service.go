@@ -3,12 +3,12 @@ package service
 import (
 	"context"
 	"errors"
-	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
 	"github.com/taiwanstay/taiwanstay-back/internal/repository"
-	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
@@ -16,15 +16,15 @@ import (
 
 // ErrEmailAlreadyExists 表示 email 已被註冊
 var (
-	ErrEmailAlreadyExists = errors.New("email already exists")
-	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrEmailAlreadyExists = errcode.Conflict("EMAIL_ALREADY_EXISTS", "email already exists")
+	ErrInvalidCredentials = errcode.Unauthorized("INVALID_CREDENTIALS", "invalid email or password")
 )
 
 // UserService 定義了與使用者相關的業務邏輯介面
 type UserService interface {
 	RegisterUser(ctx context.Context, name, email, password string) (*domain.User, error)
-	LoginUser(ctx context.Context, email, password string) (*domain.User, string, error)
-	LogoutUser(ctx context.Context) error
+	LoginUser(ctx context.Context, email, password string, meta SessionMeta) (*LoginResult, error)
+	LogoutUser(ctx context.Context, sessionID string) error
 	GetAllUsers(ctx context.Context) ([]*domain.User, error)
 	GetUserByID(ctx context.Context, id string) (*domain.User, error)
 	UpdateUser(ctx context.Context, id string, payload bson.M) (*domain.User, error)
@@ -32,17 +32,21 @@ type UserService interface {
 
 // userService 是 UserService 的實作
 type userService struct {
-	userRepo      repository.UserRepository
-	jwtSecret     string
-	tokenDuration time.Duration
+	userRepo          repository.UserRepository
+	sessionService    SessionService
+	emailVerification EmailVerificationService
+	mfaService        MFAService
+	loginProtection   LoginProtectionService
 }
 
 // NewUserService 建立一個新的 UserService 實例
-func NewUserService(repo repository.UserRepository, cfg *config.Config) UserService {
+func NewUserService(repo repository.UserRepository, sessionService SessionService, emailVerification EmailVerificationService, mfaService MFAService, loginProtection LoginProtectionService) UserService {
 	return &userService{
-		userRepo:      repo,
-		jwtSecret:     cfg.Server.JWTSecret,
-		tokenDuration: time.Hour * 24, // Token a 24 horas
+		userRepo:          repo,
+		sessionService:    sessionService,
+		emailVerification: emailVerification,
+		mfaService:        mfaService,
+		loginProtection:   loginProtection,
 	}
 }
 
@@ -66,23 +70,8 @@ func (s *userService) RegisterUser(ctx context.Context, name, email, password st
 	}
 
 	// 3. 建立 User domain 物件
-	newUser := &domain.User{
-		Name:     name,
-		Email:    email,
-		Password: string(hashedPassword),
-		Role:     domain.RoleUser, // 預設角色為 USER
-		Profile: domain.Profile{ // 初始化一些必要的 Profile 欄位
-			BirthDate:          time.Now(), // 暫時使用當前時間，未來應由前端傳入
-			EmergencyContact:   domain.EmergencyContact{Name: "N/A", Relationship: "N/A", Phone: "N/A"},
-			PhysicalCondition:  "N/A",
-			PreferredWorkHours: 8,
-		},
-		PrivacySettings: domain.PrivacySettings{ // 設定預設隱私等級
-			Email: domain.PrivacyPrivate,
-			Phone: domain.PrivacyPrivate,
-			// ... 其他隱私設定
-		},
-	}
+	newUser := newTravelerUser(ctx, name, email)
+	newUser.Password = string(hashedPassword)
 
 	// 3. 呼叫 Repository 將使用者存入資料庫
 	userID, err := s.userRepo.Create(ctx, newUser)
@@ -94,70 +83,99 @@ func (s *userService) RegisterUser(ctx context.Context, name, email, password st
 	newUser.ID = userID
 	newUser.Password = ""
 
+	// 5. 寄送驗證信，寄送失敗不影響註冊，使用者可稍後重新寄送
+	if err := s.emailVerification.SendVerification(ctx, userID); err != nil {
+		logger.Error("Failed to send verification email", "userId", userID, "error", err)
+	}
+
 	return newUser, nil
 }
 
-// LoginUser 處理使用者登入邏輯
-func (s *userService) LoginUser(ctx context.Context, email, password string) (*domain.User, string, error) {
-	// 1. 透過 Email 尋找使用者
+// newTravelerUser 建立一個帶有預設 Profile 與隱私設定的一般使用者，偏好語系預設為註冊時請求的語系
+func newTravelerUser(ctx context.Context, name, email string) *domain.User {
+	user := &domain.User{
+		Name:  name,
+		Email: email,
+		Role:  domain.RoleUser, // 預設角色為 USER
+		Profile: domain.Profile{ // 生日、緊急聯絡人等由使用者在引導流程中填寫
+			PreferredWorkHours: 8,
+		},
+		PrivacySettings: domain.DefaultPrivacySettings(), // 設定預設隱私等級
+	}
+	if l, ok := i18n.FromContext(ctx); ok {
+		user.Locale = string(l)
+	}
+	return user
+}
+
+// messageLocale 決定寄給使用者的訊息語系：優先使用使用者的偏好語系，其次為當前請求的語系
+func messageLocale(ctx context.Context, user *domain.User) i18n.Locale {
+	if user != nil && i18n.IsSupported(user.Locale) {
+		return i18n.Locale(user.Locale)
+	}
+	return requestLocale(ctx)
+}
+
+// requestLocale 回傳當前請求的語系，未指定時為預設語系
+func requestLocale(ctx context.Context) i18n.Locale {
+	if l, ok := i18n.FromContext(ctx); ok {
+		return l
+	}
+	return i18n.DefaultLocale
+}
+
+// LoginUser 處理使用者登入邏輯。
+// 密碼正確後，未啟用兩步驟驗證的使用者直接建立 session，否則回傳 MFA challenge。
+// 失敗次數過多時回傳 *AccountLockedError 或 *RateLimitedError。
+func (s *userService) LoginUser(ctx context.Context, email, password string, meta SessionMeta) (*LoginResult, error) {
+	// 1. 檢查帳號或 IP 是否被鎖定
+	if err := s.loginProtection.Check(ctx, email, meta.IP); err != nil {
+		return nil, err
+	}
+
+	// 2. 透過 Email 尋找使用者
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			// 找不到使用者，回傳無效憑證錯誤
-			return nil, "", ErrInvalidCredentials
+			s.recordLoginFailure(ctx, email, meta.IP)
+			return nil, ErrInvalidCredentials
 		}
 		// 其他資料庫錯誤
-		return nil, "", err
+		return nil, err
 	}
 
-	// 2. 比對密碼
+	// 3. 比對密碼
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
 		// 密碼不匹配
-		return nil, "", ErrInvalidCredentials
+		s.recordLoginFailure(ctx, email, meta.IP)
+		return nil, ErrInvalidCredentials
 	}
 
-	// 3. 生成 JWT Token
-	token, err := s.generateJWT(user)
-	if err != nil {
-		return nil, "", err
+	// 4. 啟用兩步驟驗證的帳號需等 CompleteLogin 成功後才清除失敗紀錄，
+	//    避免只知道密碼就能重設驗證碼的嘗試次數
+	if !user.MFA.Enabled {
+		if err := s.loginProtection.RecordSuccess(ctx, user); err != nil {
+			logger.Error("Failed to clear login failures", "userId", user.ID, "error", err)
+		}
 	}
 
-	// 4. 清除密碼後回傳
-	user.Password = ""
-
-	return user, token, nil
+	// 5. 進入兩步驟驗證或建立 session
+	return s.mfaService.StartLogin(ctx, user, meta)
 }
 
-// generateJWT 根據使用者資訊生成 JWT
-func (s *userService) generateJWT(user *domain.User) (string, error) {
-	// 建立 claims
-	claims := jwt.MapClaims{
-		"sub":  user.ID,
-		"name": user.Name,
-		"role": user.Role,
-		"exp":  time.Now().Add(s.tokenDuration).Unix(),
-		"iat":  time.Now().Unix(),
-	}
-
-	// 建立 token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// 簽署 token
-	signedToken, err := token.SignedString([]byte(s.jwtSecret))
-	if err != nil {
-		return "", err
+// recordLoginFailure 記錄登入失敗，記錄失敗不影響回應
+func (s *userService) recordLoginFailure(ctx context.Context, email, ip string) {
+	if err := s.loginProtection.RecordFailure(ctx, email, ip); err != nil {
+		logger.Error("Failed to record login failure", "error", err)
 	}
-
-	return signedToken, nil
 }
 
-// LogoutUser 處理使用者登出邏輯。
-// 在無狀態的 JWT 機制中，主要由客戶端負責銷毀 token。
-// 此函式為未來擴充（如：token 黑名單）預留。
-func (s *userService) LogoutUser(ctx context.Context) error {
-	// 未來可在此處實作 token 黑名單機制，例如將 token 存入 Redis 直到過期。
-	return nil
+// LogoutUser 處理使用者登出邏輯，撤銷目前的 session。
+// 撤銷後，該 session 的 access token 與 refresh token 都會立即失效。
+func (s *userService) LogoutUser(ctx context.Context, sessionID string) error {
+	return s.sessionService.RevokeSession(ctx, sessionID, SessionRevokedLogout)
 }
 
 // GetAllUsers 取得所有使用者資訊
@@ -177,7 +195,7 @@ func (s *userService) GetAllUsers(ctx context.Context) ([]*domain.User, error) {
 func (s *userService) GetUserByID(ctx context.Context, id string) (*domain.User, error) {
 	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, ErrUserNotFound)
 	}
 	// 為了安全，清除密碼欄位
 	user.Password = ""
@@ -186,11 +204,20 @@ func (s *userService) GetUserByID(ctx context.Context, id string) (*domain.User,
 
 // UpdateUser 更新使用者資訊
 func (s *userService) UpdateUser(ctx context.Context, id string, payload bson.M) (*domain.User, error) {
-	// 在這裡可以加入業務邏輯，例如檢查欄位、權限等
-	// 為了保持範例簡單，我們直接呼叫 repository
+	// 更換手機號碼後需要重新驗證
+	if phone, ok := payload["profile.phoneNumber"]; ok {
+		current, err := s.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, notFound(err, ErrUserNotFound)
+		}
+		if current.Profile.PhoneNumber != phone {
+			payload["profile.isPhoneVerified"] = false
+		}
+	}
+
 	err := s.userRepo.Update(ctx, id, payload)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err, ErrUserNotFound)
 	}
 
 	// 更新成功後，回傳最新的使用者資訊 (不含密碼)
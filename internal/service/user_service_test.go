@@ -2,12 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/taiwanstay/taiwanstay-back/internal/domain"
-	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/crypto/bcrypt"
@@ -67,6 +69,101 @@ func (m *mockUserRepository) UpdateStatus(ctx context.Context, id string, status
 	return args.Error(0)
 }
 
+func (m *mockUserRepository) GetByIdentity(ctx context.Context, provider domain.AuthProvider, subject string) (*domain.User, error) {
+	args := m.Called(ctx, provider, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *mockUserRepository) AddIdentity(ctx context.Context, id string, identity domain.ProviderIdentity) error {
+	args := m.Called(ctx, id, identity)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) RemoveIdentity(ctx context.Context, id string, provider domain.AuthProvider, subject string) error {
+	args := m.Called(ctx, id, provider, subject)
+	return args.Error(0)
+}
+
+func (m *mockUserRepository) MarkVerificationEmailSent(ctx context.Context, id string, sentBefore time.Time) (bool, error) {
+	args := m.Called(ctx, id, sentBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockUserRepository) MarkPasswordResetSent(ctx context.Context, id string, sentBefore time.Time) (bool, error) {
+	args := m.Called(ctx, id, sentBefore)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockUserRepository) UseMFAStep(ctx context.Context, id string, step int64) (bool, error) {
+	args := m.Called(ctx, id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockUserRepository) ConsumeRecoveryCode(ctx context.Context, id string, codeHash string) (bool, error) {
+	args := m.Called(ctx, id, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockSessionService 是一個用於測試的 SessionService mock
+type MockSessionService struct {
+	mock.Mock
+}
+
+func (m *MockSessionService) CreateSession(ctx context.Context, user *domain.User, meta SessionMeta) (*AuthTokens, error) {
+	args := m.Called(ctx, user, meta)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*AuthTokens), args.Error(1)
+}
+
+func (m *MockSessionService) RefreshSession(ctx context.Context, refreshToken string, meta SessionMeta) (*AuthTokens, error) {
+	args := m.Called(ctx, refreshToken, meta)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*AuthTokens), args.Error(1)
+}
+
+func (m *MockSessionService) RevokeSession(ctx context.Context, sessionID, reason string) error {
+	args := m.Called(ctx, sessionID, reason)
+	return args.Error(0)
+}
+
+func (m *MockSessionService) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionService) ListUserSessions(ctx context.Context, userID string) ([]*domain.Session, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Session), args.Error(1)
+}
+
+func (m *MockSessionService) GetUserSession(ctx context.Context, userID, sessionID string) (*domain.Session, error) {
+	args := m.Called(ctx, userID, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Session), args.Error(1)
+}
+
+func (m *MockSessionService) RevokeUserSession(ctx context.Context, userID, sessionID string) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockSessionService) RevokeAllUserSessions(ctx context.Context, userID, exceptSessionID, reason string) (int64, error) {
+	args := m.Called(ctx, userID, exceptSessionID, reason)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func TestLoginUser(t *testing.T) {
 	// 準備加密後的密碼
 	password := "password123"
@@ -85,74 +182,173 @@ func TestLoginUser(t *testing.T) {
 		// 準備 mock
 		mockRepo := new(mockUserRepository)
 		mockRepo.On("GetByEmail", mock.Anything, testUser.Email).Return(testUser, nil)
+		mockSessions := new(MockSessionService)
+		meta := SessionMeta{UserAgent: "test-agent", IP: "127.0.0.1"}
+		mockSessions.On("CreateSession", mock.Anything, testUser, meta).Return(&AuthTokens{AccessToken: "access", RefreshToken: "refresh"}, nil)
+		mockProtection := new(MockLoginProtectionService)
+		mockProtection.On("Check", mock.Anything, testUser.Email, "127.0.0.1").Return(nil)
+		mockProtection.On("RecordSuccess", mock.Anything, testUser).Return(nil)
 
 		// 建立 service
-		cfg := &config.Config{Server: config.ServerConfig{JWTSecret: "test-secret"}}
-		userService := NewUserService(mockRepo, cfg)
+		userService := NewUserService(mockRepo, mockSessions, new(MockEmailVerificationService), newTestMFAService(mockRepo, mockSessions), mockProtection)
 
 		// 執行登入
-		user, token, err := userService.LoginUser(context.Background(), testUser.Email, password)
+		result, err := userService.LoginUser(context.Background(), testUser.Email, password, meta)
 
 		// 斷言
 		assert.NoError(t, err)
-		assert.NotNil(t, user)
-		assert.NotEmpty(t, token)
-		assert.Equal(t, testUser.Email, user.Email)
-		assert.Empty(t, user.Password) // 確保密碼已被清除
+		assert.NotNil(t, result.User)
+		assert.False(t, result.MFARequired)
+		assert.Equal(t, "access", result.Tokens.AccessToken)
+		assert.Equal(t, "refresh", result.Tokens.RefreshToken)
+		assert.Equal(t, testUser.Email, result.User.Email)
+		assert.Empty(t, result.User.Password) // 確保密碼已被清除
 		mockRepo.AssertExpectations(t)
+		mockSessions.AssertExpectations(t)
+		mockProtection.AssertExpectations(t)
 	})
 
 	t.Run("Failed Login - Wrong Password", func(t *testing.T) {
 		// 準備 mock
 		mockRepo := new(mockUserRepository)
 		mockRepo.On("GetByEmail", mock.Anything, testUser.Email).Return(testUser, nil)
+		mockProtection := new(MockLoginProtectionService)
+		mockProtection.On("Check", mock.Anything, testUser.Email, "").Return(nil)
+		mockProtection.On("RecordFailure", mock.Anything, testUser.Email, "").Return(nil)
 
 		// 建立 service
-		cfg := &config.Config{Server: config.ServerConfig{JWTSecret: "test-secret"}}
-		userService := NewUserService(mockRepo, cfg)
+		userService := NewUserService(mockRepo, new(MockSessionService), new(MockEmailVerificationService), newTestMFAService(mockRepo, new(MockSessionService)), mockProtection)
 
 		// 執行登入
-		_, _, err := userService.LoginUser(context.Background(), testUser.Email, "wrongpassword")
+		_, err := userService.LoginUser(context.Background(), testUser.Email, "wrongpassword", SessionMeta{})
 
 		// 斷言
 		assert.Error(t, err)
 		assert.Equal(t, ErrInvalidCredentials, err)
 		mockRepo.AssertExpectations(t)
+		mockProtection.AssertExpectations(t)
 	})
 
 	t.Run("Failed Login - User Not Found", func(t *testing.T) {
 		// 準備 mock
 		mockRepo := new(mockUserRepository)
 		mockRepo.On("GetByEmail", mock.Anything, "notfound@example.com").Return(nil, mongo.ErrNoDocuments)
+		mockProtection := new(MockLoginProtectionService)
+		mockProtection.On("Check", mock.Anything, "notfound@example.com", "").Return(nil)
+		mockProtection.On("RecordFailure", mock.Anything, "notfound@example.com", "").Return(nil)
 
 		// 建立 service
-		cfg := &config.Config{Server: config.ServerConfig{JWTSecret: "test-secret"}}
-		userService := NewUserService(mockRepo, cfg)
+		userService := NewUserService(mockRepo, new(MockSessionService), new(MockEmailVerificationService), newTestMFAService(mockRepo, new(MockSessionService)), mockProtection)
 
 		// 執行登入
-		_, _, err := userService.LoginUser(context.Background(), "notfound@example.com", "password123")
+		_, err := userService.LoginUser(context.Background(), "notfound@example.com", "password123", SessionMeta{})
 
 		// 斷言
 		assert.Error(t, err)
 		assert.Equal(t, ErrInvalidCredentials, err)
 		mockRepo.AssertExpectations(t)
+		mockProtection.AssertExpectations(t)
+	})
+
+	t.Run("Failed Login - Account Locked", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		mockProtection := new(MockLoginProtectionService)
+		mockProtection.On("Check", mock.Anything, testUser.Email, "").Return(&AccountLockedError{RetryAfter: time.Minute})
+
+		userService := NewUserService(mockRepo, new(MockSessionService), new(MockEmailVerificationService), newTestMFAService(mockRepo, new(MockSessionService)), mockProtection)
+		_, err := userService.LoginUser(context.Background(), testUser.Email, password, SessionMeta{})
+
+		assert.ErrorIs(t, err, ErrAccountLocked)
+		mockRepo.AssertNotCalled(t, "GetByEmail", mock.Anything, mock.Anything)
+	})
+
+	t.Run("MFA Enabled Keeps Failures Until Second Factor", func(t *testing.T) {
+		mfaUser := domain.User{
+			ID:       "mfa-user-id",
+			Email:    testUser.Email,
+			Password: string(hashedPassword),
+			Role:     domain.RoleUser,
+			MFA:      domain.MFASettings{Enabled: true},
+		}
+		mockRepo := new(mockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, testUser.Email).Return(&mfaUser, nil)
+		mockProtection := new(MockLoginProtectionService)
+		mockProtection.On("Check", mock.Anything, testUser.Email, "").Return(nil)
+
+		userService := NewUserService(mockRepo, new(MockSessionService), new(MockEmailVerificationService), newTestMFAService(mockRepo, new(MockSessionService)), mockProtection)
+		result, err := userService.LoginUser(context.Background(), testUser.Email, password, SessionMeta{})
+
+		assert.NoError(t, err)
+		assert.True(t, result.MFARequired)
+		mockProtection.AssertNotCalled(t, "RecordSuccess", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failed Login - Account Suspended", func(t *testing.T) {
+		suspended := domain.User{
+			ID:       "suspended-user-id",
+			Email:    testUser.Email,
+			Password: string(hashedPassword),
+			Role:     domain.RoleUser,
+			Status:   domain.UserStatusSuspended,
+		}
+		mockRepo := new(mockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, testUser.Email).Return(&suspended, nil)
+		mockSessions := new(MockSessionService)
+		mockProtection := new(MockLoginProtectionService)
+		mockProtection.On("Check", mock.Anything, testUser.Email, "").Return(nil)
+		mockProtection.On("RecordSuccess", mock.Anything, &suspended).Return(nil)
+
+		userService := NewUserService(mockRepo, mockSessions, new(MockEmailVerificationService), newTestMFAService(mockRepo, mockSessions), mockProtection)
+		_, err := userService.LoginUser(context.Background(), testUser.Email, password, SessionMeta{})
+
+		assert.ErrorIs(t, err, ErrAccountSuspended)
+		mockSessions.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything, mock.Anything)
 	})
 }
 
+func TestLogoutUser(t *testing.T) {
+	mockRepo := new(mockUserRepository)
+	mockSessions := new(MockSessionService)
+	mockSessions.On("RevokeSession", mock.Anything, "session-1", SessionRevokedLogout).Return(nil)
+
+	userService := NewUserService(mockRepo, mockSessions, new(MockEmailVerificationService), newTestMFAService(mockRepo, mockSessions), new(MockLoginProtectionService))
+	err := userService.LogoutUser(context.Background(), "session-1")
+
+	assert.NoError(t, err)
+	mockSessions.AssertExpectations(t)
+}
+
 func TestRegisterUser(t *testing.T) {
 	t.Run("Successful Registration", func(t *testing.T) {
 		mockRepo := new(mockUserRepository)
 		mockRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, mongo.ErrNoDocuments)
 		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return("new-user-id", nil)
+		mockVerification := new(MockEmailVerificationService)
+		mockVerification.On("SendVerification", mock.Anything, "new-user-id").Return(nil)
 
-		cfg := &config.Config{Server: config.ServerConfig{JWTSecret: "test-secret"}}
-		userService := NewUserService(mockRepo, cfg)
+		userService := NewUserService(mockRepo, new(MockSessionService), mockVerification, newTestMFAService(mockRepo, new(MockSessionService)), new(MockLoginProtectionService))
 		user, err := userService.RegisterUser(context.Background(), "newuser", "new@example.com", "password123")
 
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
 		assert.Equal(t, "new-user-id", user.ID)
 		mockRepo.AssertExpectations(t)
+		mockVerification.AssertExpectations(t)
+	})
+
+	t.Run("Verification Email Failure Does Not Fail Registration", func(t *testing.T) {
+		logger.InitLogger("error")
+		mockRepo := new(mockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, mongo.ErrNoDocuments)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return("new-user-id", nil)
+		mockVerification := new(MockEmailVerificationService)
+		mockVerification.On("SendVerification", mock.Anything, "new-user-id").Return(errors.New("smtp down"))
+
+		userService := NewUserService(mockRepo, new(MockSessionService), mockVerification, newTestMFAService(mockRepo, new(MockSessionService)), new(MockLoginProtectionService))
+		user, err := userService.RegisterUser(context.Background(), "newuser", "new@example.com", "password123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "new-user-id", user.ID)
 	})
 
 	t.Run("Failed Registration - Email Exists", func(t *testing.T) {
@@ -160,8 +356,7 @@ func TestRegisterUser(t *testing.T) {
 		mockRepo := new(mockUserRepository)
 		mockRepo.On("GetByEmail", mock.Anything, "exists@example.com").Return(existingUser, nil)
 
-		cfg := &config.Config{Server: config.ServerConfig{JWTSecret: "test-secret"}}
-		userService := NewUserService(mockRepo, cfg)
+		userService := NewUserService(mockRepo, new(MockSessionService), new(MockEmailVerificationService), newTestMFAService(mockRepo, new(MockSessionService)), new(MockLoginProtectionService))
 		_, err := userService.RegisterUser(context.Background(), "anotheruser", "exists@example.com", "password123")
 
 		assert.Error(t, err)
@@ -169,3 +364,37 @@ func TestRegisterUser(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestUpdateUserPhoneNumber(t *testing.T) {
+	ctx := context.Background()
+
+	newService := func(mockRepo *mockUserRepository) UserService {
+		return NewUserService(mockRepo, new(MockSessionService), new(MockEmailVerificationService), newTestMFAService(mockRepo, new(MockSessionService)), new(MockLoginProtectionService))
+	}
+
+	t.Run("Changed Number Requires Verification", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		userService := newService(mockRepo)
+
+		user := &domain.User{ID: "user-1", Profile: domain.Profile{PhoneNumber: "+886912345678", IsPhoneVerified: true}}
+		mockRepo.On("GetByID", ctx, "user-1").Return(user, nil)
+		mockRepo.On("Update", ctx, "user-1", bson.M{"profile.phoneNumber": "+886987654321", "profile.isPhoneVerified": false}).Return(nil)
+
+		_, err := userService.UpdateUser(ctx, "user-1", bson.M{"profile.phoneNumber": "+886987654321"})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Same Number Keeps Verification", func(t *testing.T) {
+		mockRepo := new(mockUserRepository)
+		userService := newService(mockRepo)
+
+		user := &domain.User{ID: "user-1", Profile: domain.Profile{PhoneNumber: "+886912345678", IsPhoneVerified: true}}
+		mockRepo.On("GetByID", ctx, "user-1").Return(user, nil)
+		mockRepo.On("Update", ctx, "user-1", bson.M{"profile.phoneNumber": "+886912345678"}).Return(nil)
+
+		_, err := userService.UpdateUser(ctx, "user-1", bson.M{"profile.phoneNumber": "+886912345678"})
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
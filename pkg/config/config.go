@@ -3,23 +3,31 @@ package config
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	GCP      GCPConfig
-	Image    ImageConfig
-	Email    EmailConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	GCP        GCPConfig
+	Image      ImageConfig
+	Email      EmailConfig
+	SMS        SMSConfig
+	Auth       AuthConfig
+	OAuth      OAuthConfig
+	Moderation ModerationConfig
+	Scheduler  SchedulerConfig
 }
 
 type ServerConfig struct {
-	Port      string `mapstructure:"port"`
-	Mode      string `mapstructure:"mode"` // debug, release
-	JWTSecret string `mapstructure:"jwt_secret"`
+	Port        string `mapstructure:"port"`
+	Mode        string `mapstructure:"mode"` // debug, release
+	JWTSecret   string `mapstructure:"jwt_secret"`
+	LocationKey string `mapstructure:"location_key"` // 產生公開座標偏移量的金鑰，未設定時由 JWTSecret 衍生
+	FrontendURL string `mapstructure:"frontend_url"` // 用於產生 email 中的連結
 }
 
 type DatabaseConfig struct {
@@ -52,6 +60,79 @@ type EmailConfig struct {
 	MailerLiteAPIKey string `mapstructure:"mailerlite_api_key"`
 }
 
+type SMSConfig struct {
+	Provider         string `mapstructure:"provider"` // twilio；未設定時只寫入 log
+	TwilioAccountSID string `mapstructure:"twilio_account_sid"`
+	TwilioAuthToken  string `mapstructure:"twilio_auth_token"`
+	TwilioFromNumber string `mapstructure:"twilio_from_number"`
+}
+
+type AuthConfig struct {
+	AccessTokenTTL  time.Duration `mapstructure:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `mapstructure:"refresh_token_ttl"`
+
+	EmailVerificationTTL time.Duration `mapstructure:"email_verification_ttl"`
+	// EmailResendInterval 限制重新寄送驗證信的頻率
+	EmailResendInterval time.Duration `mapstructure:"email_resend_interval"`
+	PasswordResetTTL    time.Duration `mapstructure:"password_reset_ttl"`
+	// 忘記密碼的頻率限制：同一帳號在 PasswordResetInterval 內只寄送一封重設信，
+	// 同一 IP 每小時最多請求 PasswordResetIPLimit 次
+	PasswordResetInterval time.Duration `mapstructure:"password_reset_interval"`
+	PasswordResetIPLimit  int           `mapstructure:"password_reset_ip_limit"`
+
+	// RequireAdminMFA 開啟時，管理員必須以兩步驟驗證登入才能使用管理功能
+	RequireAdminMFA bool          `mapstructure:"require_admin_mfa"`
+	MFAIssuer       string        `mapstructure:"mfa_issuer"`
+	MFAChallengeTTL time.Duration `mapstructure:"mfa_challenge_ttl"`
+	// MFAMaxAttempts 是每個 challenge 可嘗試驗證碼的次數，用盡後需重新登入
+	MFAMaxAttempts int `mapstructure:"mfa_max_attempts"`
+
+	// 登入暴力破解防護：同一帳號在 LoginFailureWindow 內失敗 MaxFailedLogins 次即鎖定 LockoutDuration，
+	// 失敗 LoginDelayAfter 次後每次嘗試需等待的時間會逐步加倍
+	MaxFailedLogins    int           `mapstructure:"max_failed_logins"`
+	MaxIPFailedLogins  int           `mapstructure:"max_ip_failed_logins"`
+	LoginFailureWindow time.Duration `mapstructure:"login_failure_window"`
+	LockoutDuration    time.Duration `mapstructure:"lockout_duration"`
+	LoginDelayAfter    int           `mapstructure:"login_delay_after"`
+
+	PhoneOTPTTL            time.Duration `mapstructure:"phone_otp_ttl"`
+	PhoneOTPResendInterval time.Duration `mapstructure:"phone_otp_resend_interval"`
+	PhoneOTPDailyLimit     int           `mapstructure:"phone_otp_daily_limit"` // 每個帳號 24 小時內可寄送的驗證碼數量
+	// PhoneOTPNumberDailyLimit 是每個手機號碼 24 小時內可收到的驗證碼數量 (不分帳號)，避免大量帳號對同一號碼灌簡訊
+	PhoneOTPNumberDailyLimit int `mapstructure:"phone_otp_number_daily_limit"`
+	PhoneOTPMaxAttempts    int           `mapstructure:"phone_otp_max_attempts"`
+}
+
+type OAuthConfig struct {
+	GoogleClientIDs []string      `mapstructure:"google_client_ids"` // Web / iOS / Android client IDs
+	GoogleJWKSURL   string        `mapstructure:"google_jwks_url"`
+	AppleClientIDs  []string      `mapstructure:"apple_client_ids"` // Services ID / Bundle ID
+	AppleJWKSURL    string        `mapstructure:"apple_jwks_url"`
+	JWKSCacheTTL    time.Duration `mapstructure:"jwks_cache_ttl"`
+}
+
+// ModerationConfig 設定工作機會送審時的自動篩檢，命中的項目會標記給管理員，不會阻擋送審
+type ModerationConfig struct {
+	BannedWords    []string `mapstructure:"banned_words"`    // comma separated，不分大小寫比對標題與描述
+	RequiredFields []string `mapstructure:"required_fields"` // comma separated，可用欄位見 service.screeningFieldChecks
+	// 津貼換算為每月金額後超出範圍視為可疑，只比對幣別為 StipendCurrency (或未填) 的津貼
+	StipendCurrency   string  `mapstructure:"stipend_currency"`
+	MinMonthlyStipend float64 `mapstructure:"min_monthly_stipend"`
+	MaxMonthlyStipend float64 `mapstructure:"max_monthly_stipend"`
+}
+
+// SchedulerConfig 設定背景排程。多個副本以 Mongo lock 選出一個 leader 執行，LeaseTTL 需大於 Tick
+type SchedulerConfig struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Tick     time.Duration `mapstructure:"tick"` // 續約 leader 與檢查工作是否到期的間隔
+	LeaseTTL time.Duration `mapstructure:"lease_ttl"`
+	Timezone string        `mapstructure:"timezone"` // 判斷時段 (YYYY-MM-DD) 是否結束使用的時區
+
+	OpportunityInterval time.Duration `mapstructure:"opportunity_interval"`
+	ExpiryNotice        time.Duration `mapstructure:"expiry_notice"` // 工作機會到期前多久通知接待主
+	ImagePurgeInterval  time.Duration `mapstructure:"image_purge_interval"`
+}
+
 func LoadConfig() (*Config, error) {
 	// Load .env file if it exists
 	if err := godotenv.Load(); err != nil {
@@ -64,6 +145,7 @@ func LoadConfig() (*Config, error) {
 	// Set default values
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.mode", "debug")
+	viper.SetDefault("server.frontend_url", "http://localhost:3000")
 	viper.SetDefault("database.uri", "mongodb://localhost:27017")
 	viper.SetDefault("database.database", "taiwanstay")
 
@@ -83,11 +165,65 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("email.brevo_sender_name", "")
 	viper.SetDefault("email.mailerlite_api_key", "")
 
+	// SMS Config Defaults
+	viper.SetDefault("sms.provider", "")
+	viper.SetDefault("sms.twilio_account_sid", "")
+	viper.SetDefault("sms.twilio_auth_token", "")
+	viper.SetDefault("sms.twilio_from_number", "")
+
+	// Auth Config Defaults
+	viper.SetDefault("auth.access_token_ttl", "15m")
+	viper.SetDefault("auth.refresh_token_ttl", "720h")
+	viper.SetDefault("auth.email_verification_ttl", "24h")
+	viper.SetDefault("auth.email_resend_interval", "1m")
+	viper.SetDefault("auth.password_reset_ttl", "1h")
+	viper.SetDefault("auth.password_reset_interval", "1m")
+	viper.SetDefault("auth.password_reset_ip_limit", 20)
+	viper.SetDefault("auth.require_admin_mfa", false)
+	viper.SetDefault("auth.mfa_issuer", "TaiwanStay")
+	viper.SetDefault("auth.mfa_challenge_ttl", "5m")
+	viper.SetDefault("auth.mfa_max_attempts", 5)
+	viper.SetDefault("auth.max_failed_logins", 10)
+	viper.SetDefault("auth.max_ip_failed_logins", 100)
+	viper.SetDefault("auth.login_failure_window", "15m")
+	viper.SetDefault("auth.lockout_duration", "15m")
+	viper.SetDefault("auth.login_delay_after", 3)
+	viper.SetDefault("auth.phone_otp_ttl", "10m")
+	viper.SetDefault("auth.phone_otp_resend_interval", "1m")
+	viper.SetDefault("auth.phone_otp_daily_limit", 5)
+	viper.SetDefault("auth.phone_otp_number_daily_limit", 10)
+	viper.SetDefault("auth.phone_otp_max_attempts", 5)
+
+	// OAuth Config Defaults
+	viper.SetDefault("oauth.google_client_ids", []string{})
+	viper.SetDefault("oauth.google_jwks_url", "https://www.googleapis.com/oauth2/v3/certs")
+	viper.SetDefault("oauth.apple_client_ids", []string{})
+	viper.SetDefault("oauth.apple_jwks_url", "https://appleid.apple.com/auth/keys")
+	viper.SetDefault("oauth.jwks_cache_ttl", "1h")
+
+	// Moderation Config Defaults
+	viper.SetDefault("moderation.banned_words", []string{})
+	viper.SetDefault("moderation.required_fields", []string{"description", "location.city", "workDetails.tasks", "timeSlots"})
+	viper.SetDefault("moderation.stipend_currency", "TWD")
+	viper.SetDefault("moderation.min_monthly_stipend", 1)
+	viper.SetDefault("moderation.max_monthly_stipend", 60000)
+
+	// Scheduler Config Defaults
+	viper.SetDefault("scheduler.enabled", true)
+	viper.SetDefault("scheduler.tick", "1m")
+	viper.SetDefault("scheduler.lease_ttl", "3m")
+	viper.SetDefault("scheduler.timezone", "Asia/Taipei")
+	viper.SetDefault("scheduler.opportunity_interval", "1h")
+	viper.SetDefault("scheduler.expiry_notice", "72h")
+	viper.SetDefault("scheduler.image_purge_interval", "1h")
+
 	// Bind environment variables
 	// Example: SERVER_PORT maps to Server.Port
 	_ = viper.BindEnv("server.port", "SERVER_PORT")
 	_ = viper.BindEnv("server.mode", "GIN_MODE")
 	_ = viper.BindEnv("server.jwt_secret", "JWT_SECRET")
+	_ = viper.BindEnv("server.location_key", "LOCATION_KEY")
+	_ = viper.BindEnv("server.frontend_url", "FRONTEND_URL")
 	_ = viper.BindEnv("database.uri", "MONGODB_URI")
 	_ = viper.BindEnv("database.database", "MONGODB_DATABASE")
 	_ = viper.BindEnv("gcp.project_id", "GCP_PROJECT_ID")
@@ -103,6 +239,53 @@ func LoadConfig() (*Config, error) {
 	_ = viper.BindEnv("image.reject_violence", "IMAGE_REJECT_VIOLENCE")
 	_ = viper.BindEnv("image.reject_racy", "IMAGE_REJECT_RACY")
 
+	_ = viper.BindEnv("auth.access_token_ttl", "ACCESS_TOKEN_TTL")
+	_ = viper.BindEnv("auth.refresh_token_ttl", "REFRESH_TOKEN_TTL")
+	_ = viper.BindEnv("auth.email_verification_ttl", "EMAIL_VERIFICATION_TTL")
+	_ = viper.BindEnv("auth.email_resend_interval", "EMAIL_RESEND_INTERVAL")
+	_ = viper.BindEnv("auth.password_reset_ttl", "PASSWORD_RESET_TTL")
+	_ = viper.BindEnv("auth.password_reset_interval", "PASSWORD_RESET_INTERVAL")
+	_ = viper.BindEnv("auth.password_reset_ip_limit", "PASSWORD_RESET_IP_LIMIT")
+	_ = viper.BindEnv("auth.require_admin_mfa", "REQUIRE_ADMIN_MFA")
+	_ = viper.BindEnv("auth.mfa_issuer", "MFA_ISSUER")
+	_ = viper.BindEnv("auth.mfa_challenge_ttl", "MFA_CHALLENGE_TTL")
+	_ = viper.BindEnv("auth.mfa_max_attempts", "MFA_MAX_ATTEMPTS")
+	_ = viper.BindEnv("auth.max_failed_logins", "MAX_FAILED_LOGINS")
+	_ = viper.BindEnv("auth.max_ip_failed_logins", "MAX_IP_FAILED_LOGINS")
+	_ = viper.BindEnv("auth.login_failure_window", "LOGIN_FAILURE_WINDOW")
+	_ = viper.BindEnv("auth.lockout_duration", "LOGIN_LOCKOUT_DURATION")
+	_ = viper.BindEnv("auth.login_delay_after", "LOGIN_DELAY_AFTER")
+	_ = viper.BindEnv("auth.phone_otp_ttl", "PHONE_OTP_TTL")
+	_ = viper.BindEnv("auth.phone_otp_resend_interval", "PHONE_OTP_RESEND_INTERVAL")
+	_ = viper.BindEnv("auth.phone_otp_daily_limit", "PHONE_OTP_DAILY_LIMIT")
+	_ = viper.BindEnv("auth.phone_otp_number_daily_limit", "PHONE_OTP_NUMBER_DAILY_LIMIT")
+	_ = viper.BindEnv("auth.phone_otp_max_attempts", "PHONE_OTP_MAX_ATTEMPTS")
+
+	_ = viper.BindEnv("sms.provider", "SMS_PROVIDER")
+	_ = viper.BindEnv("sms.twilio_account_sid", "TWILIO_ACCOUNT_SID")
+	_ = viper.BindEnv("sms.twilio_auth_token", "TWILIO_AUTH_TOKEN")
+	_ = viper.BindEnv("sms.twilio_from_number", "TWILIO_FROM_NUMBER")
+
+	_ = viper.BindEnv("oauth.google_client_ids", "GOOGLE_CLIENT_IDS") // comma separated
+	_ = viper.BindEnv("oauth.google_jwks_url", "GOOGLE_JWKS_URL")
+	_ = viper.BindEnv("oauth.apple_client_ids", "APPLE_CLIENT_IDS") // comma separated
+	_ = viper.BindEnv("oauth.apple_jwks_url", "APPLE_JWKS_URL")
+	_ = viper.BindEnv("oauth.jwks_cache_ttl", "OAUTH_JWKS_CACHE_TTL")
+
+	_ = viper.BindEnv("moderation.banned_words", "MODERATION_BANNED_WORDS")       // comma separated
+	_ = viper.BindEnv("moderation.required_fields", "MODERATION_REQUIRED_FIELDS") // comma separated
+	_ = viper.BindEnv("moderation.stipend_currency", "MODERATION_STIPEND_CURRENCY")
+	_ = viper.BindEnv("moderation.min_monthly_stipend", "MODERATION_MIN_MONTHLY_STIPEND")
+	_ = viper.BindEnv("moderation.max_monthly_stipend", "MODERATION_MAX_MONTHLY_STIPEND")
+
+	_ = viper.BindEnv("scheduler.enabled", "SCHEDULER_ENABLED")
+	_ = viper.BindEnv("scheduler.tick", "SCHEDULER_TICK")
+	_ = viper.BindEnv("scheduler.lease_ttl", "SCHEDULER_LEASE_TTL")
+	_ = viper.BindEnv("scheduler.timezone", "SCHEDULER_TIMEZONE")
+	_ = viper.BindEnv("scheduler.opportunity_interval", "SCHEDULER_OPPORTUNITY_INTERVAL")
+	_ = viper.BindEnv("scheduler.expiry_notice", "OPPORTUNITY_EXPIRY_NOTICE")
+	_ = viper.BindEnv("scheduler.image_purge_interval", "SCHEDULER_IMAGE_PURGE_INTERVAL")
+
 	var config Config
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, err
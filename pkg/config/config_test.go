@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -14,7 +15,9 @@ func TestLoadConfig(t *testing.T) {
 	os.Setenv("MONGODB_URI", "mongodb://test:27017")
 	defer os.Unsetenv("SERVER_PORT")
 	defer os.Unsetenv("GIN_MODE")
+	os.Setenv("GOOGLE_CLIENT_IDS", "web-client,ios-client")
 	defer os.Unsetenv("MONGODB_URI")
+	defer os.Unsetenv("GOOGLE_CLIENT_IDS")
 
 	cfg, err := LoadConfig()
 	assert.NoError(t, err)
@@ -23,4 +26,32 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, "9090", cfg.Server.Port)
 	assert.Equal(t, "test", cfg.Server.Mode)
 	assert.Equal(t, "mongodb://test:27017", cfg.Database.URI)
+	assert.Equal(t, []string{"web-client", "ios-client"}, cfg.OAuth.GoogleClientIDs)
+
+	// Defaults
+	assert.Equal(t, 15*time.Minute, cfg.Auth.AccessTokenTTL)
+	assert.Equal(t, 720*time.Hour, cfg.Auth.RefreshTokenTTL)
+	assert.Equal(t, 24*time.Hour, cfg.Auth.EmailVerificationTTL)
+	assert.Equal(t, time.Hour, cfg.Auth.PasswordResetTTL)
+	assert.Equal(t, time.Minute, cfg.Auth.PasswordResetInterval)
+	assert.Equal(t, 20, cfg.Auth.PasswordResetIPLimit)
+	assert.False(t, cfg.Auth.RequireAdminMFA)
+	assert.Equal(t, 5*time.Minute, cfg.Auth.MFAChallengeTTL)
+	assert.Equal(t, 10, cfg.Auth.MaxFailedLogins)
+	assert.Equal(t, 100, cfg.Auth.MaxIPFailedLogins)
+	assert.Equal(t, 15*time.Minute, cfg.Auth.LoginFailureWindow)
+	assert.Equal(t, 15*time.Minute, cfg.Auth.LockoutDuration)
+	assert.Equal(t, 3, cfg.Auth.LoginDelayAfter)
+	assert.Equal(t, 10*time.Minute, cfg.Auth.PhoneOTPTTL)
+	assert.Equal(t, 5, cfg.Auth.PhoneOTPDailyLimit)
+	assert.Equal(t, 10, cfg.Auth.PhoneOTPNumberDailyLimit)
+	assert.Equal(t, "", cfg.SMS.Provider)
+	assert.Equal(t, "http://localhost:3000", cfg.Server.FrontendURL)
+	assert.Equal(t, "https://www.googleapis.com/oauth2/v3/certs", cfg.OAuth.GoogleJWKSURL)
+	assert.Equal(t, []string{"description", "location.city", "workDetails.tasks", "timeSlots"}, cfg.Moderation.RequiredFields)
+	assert.Equal(t, 60000.0, cfg.Moderation.MaxMonthlyStipend)
+	assert.True(t, cfg.Scheduler.Enabled)
+	assert.Equal(t, 3*time.Minute, cfg.Scheduler.LeaseTTL)
+	assert.Equal(t, 72*time.Hour, cfg.Scheduler.ExpiryNotice)
+	assert.Equal(t, "Asia/Taipei", cfg.Scheduler.Timezone)
 }
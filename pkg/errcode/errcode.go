@@ -1,25 +1,137 @@
+// Package errcode defines the typed errors returned by services and the
+// envelope the API renders them in. Code is a stable, machine-readable
+// identifier clients can branch on; it also serves as the translation key
+// for Message.
 package errcode
 
 import "net/http"
 
 type Error struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Status    int            `json:"-"`
+	Code      string         `json:"code"`
+	Message   string         `json:"message"`
+	Details   string         `json:"details,omitempty"`
+	Fields    []FieldError   `json:"fields,omitempty"`
+	Meta      map[string]any `json:"meta,omitempty"`
+	RequestID string         `json:"requestId,omitempty"`
+
+	cause error
 }
 
 func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
 	return e.Message
 }
 
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is an *Error with the same code, so copies made
+// by the With* methods still match the sentinel they came from.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// FieldError describes why a single request field was rejected.
+// Field is the JSON path of the value, e.g. "location.coordinates[1]".
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
 // Common Errors
 var (
-	ErrInternalServer = &Error{Code: http.StatusInternalServerError, Message: "Internal Server Error"}
-	ErrInvalidRequest = &Error{Code: http.StatusBadRequest, Message: "Invalid Request"}
-	ErrUnauthorized   = &Error{Code: http.StatusUnauthorized, Message: "Unauthorized"}
-	ErrNotFound       = &Error{Code: http.StatusNotFound, Message: "Not Found"}
+	ErrInternalServer = New(http.StatusInternalServerError, "INTERNAL_ERROR", "internal server error")
+	ErrInvalidRequest = New(http.StatusBadRequest, "INVALID_REQUEST", "invalid request")
+	ErrUnauthorized   = New(http.StatusUnauthorized, "UNAUTHORIZED", "unauthorized")
+	ErrForbidden      = New(http.StatusForbidden, "FORBIDDEN", "access denied")
+	ErrNotFound       = New(http.StatusNotFound, "NOT_FOUND", "resource not found")
 )
 
-func New(code int, msg string) *Error {
-	return &Error{Code: code, Message: msg}
+func New(status int, code, msg string) *Error {
+	return &Error{Status: status, Code: code, Message: msg}
+}
+
+func BadRequest(code, msg string) *Error {
+	return New(http.StatusBadRequest, code, msg)
+}
+
+func Unauthorized(code, msg string) *Error {
+	return New(http.StatusUnauthorized, code, msg)
+}
+
+func Forbidden(code, msg string) *Error {
+	return New(http.StatusForbidden, code, msg)
+}
+
+func NotFound(code, msg string) *Error {
+	return New(http.StatusNotFound, code, msg)
+}
+
+func Conflict(code, msg string) *Error {
+	return New(http.StatusConflict, code, msg)
+}
+
+func Unprocessable(code, msg string) *Error {
+	return New(http.StatusUnprocessableEntity, code, msg)
+}
+
+func TooManyRequests(code, msg string) *Error {
+	return New(http.StatusTooManyRequests, code, msg)
+}
+
+// Validation returns a 400 error carrying the given field errors.
+func Validation(fields []FieldError) *Error {
+	e := BadRequest("VALIDATION_FAILED", "validation failed")
+	e.Fields = fields
+	return e
+}
+
+// The With* methods return a copy so shared sentinels are never mutated.
+
+func (e *Error) WithStatus(status int) *Error {
+	c := e.clone()
+	c.Status = status
+	return c
+}
+
+func (e *Error) WithDetails(details string) *Error {
+	c := e.clone()
+	c.Details = details
+	return c
+}
+
+func (e *Error) WithMeta(key string, value any) *Error {
+	c := e.clone()
+	meta := make(map[string]any, len(e.Meta)+1)
+	for k, v := range e.Meta {
+		meta[k] = v
+	}
+	meta[key] = value
+	c.Meta = meta
+	return c
+}
+
+// WithCause records the underlying error for errors.Is/As and logging; it is
+// never rendered to clients.
+func (e *Error) WithCause(err error) *Error {
+	c := e.clone()
+	c.cause = err
+	return c
+}
+
+func (e *Error) WithRequestID(id string) *Error {
+	c := e.clone()
+	c.RequestID = id
+	return c
+}
+
+func (e *Error) clone() *Error {
+	c := *e
+	return &c
 }
@@ -0,0 +1,135 @@
+// Package i18n 提供訊息目錄與語系協商。
+// 訊息以 key 查詢，內容中的 {name} 會以呼叫時傳入的參數取代。
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+type Locale string
+
+const (
+	LocaleZhTW Locale = "zh-TW"
+	LocaleEn   Locale = "en"
+	LocaleJa   Locale = "ja"
+
+	// DefaultLocale 用於無法判斷語系，或訊息在指定語系中缺少翻譯時
+	DefaultLocale = LocaleEn
+)
+
+// Supported 是所有支援的語系，第一個為預設語系
+var Supported = []Locale{LocaleEn, LocaleZhTW, LocaleJa}
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var (
+	catalogs map[Locale]map[string]string
+	matcher  language.Matcher
+)
+
+func init() {
+	catalogs = make(map[Locale]map[string]string, len(Supported))
+	tags := make([]language.Tag, len(Supported))
+	for i, l := range Supported {
+		data, err := localeFiles.ReadFile("locales/" + string(l) + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog for %s: %v", l, err))
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog for %s: %v", l, err))
+		}
+		catalogs[l] = messages
+		tags[i] = language.MustParse(string(l))
+	}
+	matcher = language.NewMatcher(tags)
+}
+
+// Parse 將語系字串 (例如 "zh-Hant-TW"、"ja-JP") 對應到支援的語系
+func Parse(s string) (Locale, bool) {
+	tag, err := language.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "", false
+	}
+	_, i, confidence := matcher.Match(tag)
+	if confidence < language.High {
+		return "", false
+	}
+	return Supported[i], true
+}
+
+// IsSupported 回傳 s 是否為支援的語系代碼
+func IsSupported(s string) bool {
+	for _, l := range Supported {
+		if string(l) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate 依 Accept-Language 標頭選擇最適合的語系，沒有可用的語系時回傳 DefaultLocale
+func Negotiate(acceptLanguage string) Locale {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return DefaultLocale
+	}
+	for _, tag := range tags {
+		if l, ok := Parse(tag.String()); ok {
+			return l
+		}
+	}
+	return DefaultLocale
+}
+
+type contextKey struct{}
+
+// WithLocale 回傳帶有語系的 context
+func WithLocale(ctx context.Context, l Locale) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext 取得 context 中的語系，未設定時 ok 為 false
+func FromContext(ctx context.Context) (l Locale, ok bool) {
+	l, ok = ctx.Value(contextKey{}).(Locale)
+	return l, ok
+}
+
+// T 回傳訊息的翻譯，args 為成對的參數名稱與值 (與 logger 相同)。
+// 指定語系缺少翻譯時使用預設語系，仍找不到則回傳 key 本身。
+func T(l Locale, key string, args ...any) string {
+	if msg, ok := Lookup(l, key, args...); ok {
+		return msg
+	}
+	return key
+}
+
+// Lookup 與 T 相同，但在找不到訊息時回傳 false
+func Lookup(l Locale, key string, args ...any) (string, bool) {
+	msg, ok := catalogs[l][key]
+	if !ok {
+		msg, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		return "", false
+	}
+	return format(msg, args), true
+}
+
+func format(msg string, args []any) string {
+	if len(args) == 0 {
+		return msg
+	}
+	pairs := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		pairs = append(pairs, "{"+fmt.Sprint(args[i])+"}", fmt.Sprint(args[i+1]))
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
@@ -0,0 +1,70 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Locale
+	}{
+		{"", DefaultLocale},
+		{"zh-TW", LocaleZhTW},
+		{"zh-Hant-HK,zh;q=0.8", LocaleZhTW},
+		{"ja-JP,ja;q=0.9,en;q=0.8", LocaleJa},
+		{"en-US,en;q=0.9", LocaleEn},
+		{"fr-FR,ja;q=0.5", LocaleJa},
+		{"fr-FR", DefaultLocale},
+		{"not a header;;", DefaultLocale},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Negotiate(tt.header), tt.header)
+	}
+}
+
+func TestParse(t *testing.T) {
+	l, ok := Parse("ja-JP")
+	assert.True(t, ok)
+	assert.Equal(t, LocaleJa, l)
+
+	_, ok = Parse("de")
+	assert.False(t, ok)
+
+	assert.True(t, IsSupported("zh-TW"))
+	assert.False(t, IsSupported("zh-tw"))
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "「山中民宿」有一筆新的申請", T(LocaleZhTW, "notification.APPLICATION_CREATED.message", "opportunityTitle", "山中民宿"))
+	assert.Equal(t, "New Application Received", T(LocaleEn, "notification.APPLICATION_CREATED.title"))
+
+	// 未知的 key 回傳 key 本身
+	assert.Equal(t, "no.such.key", T(LocaleJa, "no.such.key"))
+	_, ok := Lookup(LocaleJa, "no.such.key")
+	assert.False(t, ok)
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for key := range catalogs[DefaultLocale] {
+		for _, l := range Supported {
+			_, ok := catalogs[l][key]
+			assert.True(t, ok, "%s is missing %q", l, key)
+		}
+	}
+	for _, l := range Supported {
+		assert.Len(t, catalogs[l], len(catalogs[DefaultLocale]), "%s has keys not in %s", l, DefaultLocale)
+	}
+}
+
+func TestContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	l, ok := FromContext(WithLocale(context.Background(), LocaleJa))
+	assert.True(t, ok)
+	assert.Equal(t, LocaleJa, l)
+}
@@ -0,0 +1,139 @@
+package oauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+)
+
+const appleIssuer = "https://appleid.apple.com"
+
+// Apple server-to-server 通知事件類型
+const (
+	AppleEventEmailDisabled  = "email-disabled"
+	AppleEventEmailEnabled   = "email-enabled"
+	AppleEventConsentRevoked = "consent-revoked"
+	AppleEventAccountDelete  = "account-delete"
+)
+
+// ApplePrivateRelayDomain 是 Apple「隱藏我的電子郵件」轉寄地址的網域
+const ApplePrivateRelayDomain = "privaterelay.appleid.com"
+
+// AppleEvent 是 Apple server-to-server 通知中的事件
+type AppleEvent struct {
+	Type           string
+	Subject        string
+	Email          string
+	IsPrivateEmail bool
+}
+
+// AppleNotificationVerifier 驗證 Apple server-to-server 通知
+type AppleNotificationVerifier interface {
+	VerifyNotification(ctx context.Context, payload string) (*AppleEvent, error)
+}
+
+// AppleVerifier 以 Apple 的 JWKS 驗證 Sign in with Apple 的 identity token 與通知
+type AppleVerifier struct {
+	keys      *KeySet
+	clientIDs []string
+}
+
+func NewAppleVerifier(cfg *config.Config) *AppleVerifier {
+	return &AppleVerifier{
+		keys:      NewKeySet(cfg.OAuth.AppleJWKSURL, cfg.OAuth.JWKSCacheTTL),
+		clientIDs: cfg.OAuth.AppleClientIDs,
+	}
+}
+
+type appleClaims struct {
+	Email          string `json:"email"`
+	EmailVerified  any    `json:"email_verified"`   // Apple 可能回傳 bool 或 "true"
+	IsPrivateEmail any    `json:"is_private_email"` // 同上
+	Nonce          string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// Verify 驗證 identity token。
+// 客戶端送給 Apple 的是 SHA-256(nonce)，因此 token 中的 nonce 需與原始 nonce 的雜湊比對。
+func (v *AppleVerifier) Verify(ctx context.Context, idToken, nonce string) (*Identity, error) {
+	var claims appleClaims
+	if err := v.parse(ctx, idToken, &claims, jwt.WithExpirationRequired()); err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	if nonce != "" || claims.Nonce != "" {
+		sum := sha256.Sum256([]byte(nonce))
+		if claims.Nonce != hex.EncodeToString(sum[:]) {
+			return nil, ErrInvalidToken
+		}
+	}
+
+	return &Identity{
+		Provider:       "apple",
+		Subject:        claims.Subject,
+		Email:          claims.Email,
+		EmailVerified:  isTrue(claims.EmailVerified),
+		IsPrivateEmail: isTrue(claims.IsPrivateEmail) || IsApplePrivateRelay(claims.Email),
+	}, nil
+}
+
+type appleNotificationClaims struct {
+	Events string `json:"events"` // JSON 字串
+	jwt.RegisteredClaims
+}
+
+// VerifyNotification 驗證 Apple server-to-server 通知的 payload 並取出事件
+func (v *AppleVerifier) VerifyNotification(ctx context.Context, payload string) (*AppleEvent, error) {
+	var claims appleNotificationClaims
+	if err := v.parse(ctx, payload, &claims); err != nil {
+		return nil, err
+	}
+
+	var event struct {
+		Type           string `json:"type"`
+		Sub            string `json:"sub"`
+		Email          string `json:"email"`
+		IsPrivateEmail any    `json:"is_private_email"`
+	}
+	if err := json.Unmarshal([]byte(claims.Events), &event); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if event.Type == "" || event.Sub == "" {
+		return nil, ErrInvalidToken
+	}
+
+	return &AppleEvent{
+		Type:           event.Type,
+		Subject:        event.Sub,
+		Email:          event.Email,
+		IsPrivateEmail: isTrue(event.IsPrivateEmail),
+	}, nil
+}
+
+// parse 驗證簽章、issuer 與 audience
+func (v *AppleVerifier) parse(ctx context.Context, token string, claims jwt.Claims, opts ...jwt.ParserOption) error {
+	opts = append(opts, jwt.WithValidMethods([]string{"RS256"}), jwt.WithIssuer(appleIssuer))
+	_, err := jwt.ParseWithClaims(token, claims, keyFunc(ctx, v.keys), opts...)
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	aud, err := claims.GetAudience()
+	if err != nil || !audienceMatches(aud, v.clientIDs) {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// IsApplePrivateRelay 判斷 email 是否為 Apple 的轉寄地址
+func IsApplePrivateRelay(email string) bool {
+	return strings.HasSuffix(strings.ToLower(email), "@"+ApplePrivateRelayDomain)
+}
@@ -0,0 +1,110 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+)
+
+func TestAppleVerifier(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	server, _ := newJWKSServer(t, "apple-key", &privateKey.PublicKey)
+
+	cfg := &config.Config{OAuth: config.OAuthConfig{
+		AppleClientIDs: []string{"tw.taiwanstay.app"},
+		AppleJWKSURL:   server.URL,
+	}}
+	verifier := NewAppleVerifier(cfg)
+
+	hashedNonce := sha256.Sum256([]byte("raw-nonce"))
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":              "https://appleid.apple.com",
+			"aud":              "tw.taiwanstay.app",
+			"sub":              "001234.abcd",
+			"email":            "xyz789@privaterelay.appleid.com",
+			"email_verified":   "true",
+			"is_private_email": "true",
+			"nonce":            hex.EncodeToString(hashedNonce[:]),
+			"exp":              time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	t.Run("Valid Token With Private Relay Email", func(t *testing.T) {
+		identity, err := verifier.Verify(context.Background(), signToken(t, privateKey, "apple-key", validClaims()), "raw-nonce")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "apple", identity.Provider)
+		assert.Equal(t, "001234.abcd", identity.Subject)
+		assert.True(t, identity.EmailVerified)
+		assert.True(t, identity.IsPrivateEmail)
+	})
+
+	t.Run("Wrong Nonce", func(t *testing.T) {
+		_, err := verifier.Verify(context.Background(), signToken(t, privateKey, "apple-key", validClaims()), "other-nonce")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Missing Nonce", func(t *testing.T) {
+		_, err := verifier.Verify(context.Background(), signToken(t, privateKey, "apple-key", validClaims()), "")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Wrong Audience", func(t *testing.T) {
+		claims := validClaims()
+		claims["aud"] = "com.example.other"
+		_, err := verifier.Verify(context.Background(), signToken(t, privateKey, "apple-key", claims), "raw-nonce")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Wrong Issuer", func(t *testing.T) {
+		claims := validClaims()
+		claims["iss"] = "https://accounts.google.com"
+		_, err := verifier.Verify(context.Background(), signToken(t, privateKey, "apple-key", claims), "raw-nonce")
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Notification", func(t *testing.T) {
+		payload := signToken(t, privateKey, "apple-key", jwt.MapClaims{
+			"iss":    "https://appleid.apple.com",
+			"aud":    "tw.taiwanstay.app",
+			"iat":    time.Now().Unix(),
+			"events": `{"type":"consent-revoked","sub":"001234.abcd","event_time":1700000000000}`,
+		})
+
+		event, err := verifier.VerifyNotification(context.Background(), payload)
+
+		assert.NoError(t, err)
+		assert.Equal(t, AppleEventConsentRevoked, event.Type)
+		assert.Equal(t, "001234.abcd", event.Subject)
+	})
+
+	t.Run("Forged Notification", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		payload := signToken(t, otherKey, "apple-key", jwt.MapClaims{
+			"iss":    "https://appleid.apple.com",
+			"aud":    "tw.taiwanstay.app",
+			"events": `{"type":"account-delete","sub":"001234.abcd"}`,
+		})
+
+		_, err = verifier.VerifyNotification(context.Background(), payload)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestIsApplePrivateRelay(t *testing.T) {
+	assert.True(t, IsApplePrivateRelay("abc@privaterelay.appleid.com"))
+	assert.True(t, IsApplePrivateRelay("ABC@PrivateRelay.AppleID.com"))
+	assert.False(t, IsApplePrivateRelay("traveler@example.com"))
+}
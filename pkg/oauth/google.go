@@ -0,0 +1,63 @@
+package oauth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+)
+
+var googleIssuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+// GoogleVerifier 以 Google 的 JWKS 驗證 Google Sign-In 的 ID token
+type GoogleVerifier struct {
+	keys      *KeySet
+	clientIDs []string
+}
+
+func NewGoogleVerifier(cfg *config.Config) *GoogleVerifier {
+	return &GoogleVerifier{
+		keys:      NewKeySet(cfg.OAuth.GoogleJWKSURL, cfg.OAuth.JWKSCacheTTL),
+		clientIDs: cfg.OAuth.GoogleClientIDs,
+	}
+}
+
+type googleClaims struct {
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"` // Google 可能回傳 bool 或 "true"
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+	Nonce         string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+func (v *GoogleVerifier) Verify(ctx context.Context, idToken, nonce string) (*Identity, error) {
+	var claims googleClaims
+	_, err := jwt.ParseWithClaims(idToken, &claims, keyFunc(ctx, v.keys),
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if !slices.Contains(googleIssuers, claims.Issuer) {
+		return nil, ErrInvalidToken
+	}
+	if !audienceMatches(claims.Audience, v.clientIDs) {
+		return nil, ErrInvalidToken
+	}
+	if claims.Subject == "" || claims.Nonce != nonce {
+		return nil, ErrInvalidToken
+	}
+
+	return &Identity{
+		Provider:      "google",
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: isTrue(claims.EmailVerified),
+		Name:          claims.Name,
+		Picture:       claims.Picture,
+	}, nil
+}
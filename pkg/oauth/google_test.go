@@ -0,0 +1,132 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+)
+
+// newJWKSServer 啟動一個回傳指定公鑰的 JWKS stub，並記錄被呼叫的次數
+func newJWKSServer(t *testing.T, kid string, key *rsa.PublicKey) (*httptest.Server, *int32) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": kid,
+				"kty": "RSA",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestGoogleVerifier(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	server, hits := newJWKSServer(t, "key-1", &privateKey.PublicKey)
+
+	cfg := &config.Config{OAuth: config.OAuthConfig{
+		GoogleClientIDs: []string{"web-client"},
+		GoogleJWKSURL:   server.URL,
+	}}
+	verifier := NewGoogleVerifier(cfg)
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":            "https://accounts.google.com",
+			"aud":            "web-client",
+			"sub":            "google-123",
+			"email":          "traveler@example.com",
+			"email_verified": true,
+			"name":           "Traveler",
+			"exp":            time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	t.Run("Valid Token", func(t *testing.T) {
+		identity, err := verifier.Verify(context.Background(), signToken(t, privateKey, "key-1", validClaims()), "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "google", identity.Provider)
+		assert.Equal(t, "google-123", identity.Subject)
+		assert.Equal(t, "traveler@example.com", identity.Email)
+		assert.True(t, identity.EmailVerified)
+		assert.Equal(t, "Traveler", identity.Name)
+	})
+
+	t.Run("Keys Are Cached", func(t *testing.T) {
+		before := atomic.LoadInt32(hits)
+		_, err := verifier.Verify(context.Background(), signToken(t, privateKey, "key-1", validClaims()), "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, before, atomic.LoadInt32(hits))
+	})
+
+	t.Run("Email Verified As String", func(t *testing.T) {
+		claims := validClaims()
+		claims["email_verified"] = "true"
+		identity, err := verifier.Verify(context.Background(), signToken(t, privateKey, "key-1", claims), "")
+
+		assert.NoError(t, err)
+		assert.True(t, identity.EmailVerified)
+	})
+
+	invalid := map[string]func(jwt.MapClaims){
+		"Wrong Audience": func(c jwt.MapClaims) { c["aud"] = "someone-else" },
+		"Wrong Issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"Expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"Missing Expiry": func(c jwt.MapClaims) { delete(c, "exp") },
+		"Nonce Mismatch": func(c jwt.MapClaims) { c["nonce"] = "other-nonce" },
+	}
+	for name, mutate := range invalid {
+		t.Run(name, func(t *testing.T) {
+			claims := validClaims()
+			mutate(claims)
+			_, err := verifier.Verify(context.Background(), signToken(t, privateKey, "key-1", claims), "")
+
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+
+	t.Run("Unknown Signing Key", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = verifier.Verify(context.Background(), signToken(t, otherKey, "key-2", validClaims()), "")
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Forged With Known Kid", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = verifier.Verify(context.Background(), signToken(t, otherKey, "key-1", validClaims()), "")
+
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
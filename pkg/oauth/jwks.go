@@ -0,0 +1,141 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrKeyNotFound 表示 JWKS 中找不到 token 所指定的 kid
+var ErrKeyNotFound = errors.New("signing key not found")
+
+const (
+	defaultKeySetTTL = time.Hour
+	// minRefreshInterval 避免帶著未知 kid 的 token 讓我們不斷重新下載 JWKS
+	minRefreshInterval = time.Minute
+)
+
+// KeySet 從 JWKS endpoint 取得並快取 RSA 公鑰
+type KeySet struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+func NewKeySet(url string, ttl time.Duration) *KeySet {
+	if ttl <= 0 {
+		ttl = defaultKeySetTTL
+	}
+	return &KeySet{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: 10 * time.Second},
+		keys:   make(map[string]*rsa.PublicKey),
+	}
+}
+
+// Key 回傳 kid 對應的公鑰。快取過期或 kid 不在快取中 (金鑰輪替) 時會重新下載。
+func (k *KeySet) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	fresh := time.Since(k.fetchedAt) < k.ttl
+	canRefresh := time.Since(k.fetchedAt) >= minRefreshInterval
+	k.mu.RUnlock()
+
+	if ok && fresh {
+		return key, nil
+	}
+	if !ok && fresh && !canRefresh {
+		return nil, ErrKeyNotFound
+	}
+
+	if err := k.refresh(ctx); err != nil {
+		// 下載失敗時仍可使用舊的金鑰
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok = k.keys[kid]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func (k *KeySet) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks fetch failed: status code %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(body.Keys))
+	for _, key := range body.Keys {
+		if key.Kty != "RSA" {
+			continue
+		}
+		pub, err := parseRSAKey(key)
+		if err != nil {
+			continue
+		}
+		keys[key.Kid] = pub
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.fetchedAt = time.Now()
+	k.mu.Unlock()
+	return nil
+}
+
+func parseRSAKey(key jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
@@ -0,0 +1,53 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrInvalidToken = errors.New("invalid id token")
+
+// Identity 是驗證 ID token 後取得的第三方身分
+type Identity struct {
+	Provider       string
+	Subject        string
+	Email          string
+	EmailVerified  bool
+	IsPrivateEmail bool // Apple 隱藏電子郵件 (private relay)
+	Name           string
+	Picture        string
+}
+
+// Verifier 驗證第三方登入的 ID token。
+// nonce 為客戶端產生的原始 nonce，token 中帶有 nonce 時必須相符。
+type Verifier interface {
+	Verify(ctx context.Context, idToken, nonce string) (*Identity, error)
+}
+
+// keyFunc 依照 token header 的 kid 從 KeySet 取得驗證用公鑰
+func keyFunc(ctx context.Context, keys *KeySet) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, ErrKeyNotFound
+		}
+		return keys.Key(ctx, kid)
+	}
+}
+
+func audienceMatches(aud jwt.ClaimStrings, clientIDs []string) bool {
+	for _, a := range aud {
+		if slices.Contains(clientIDs, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTrue 處理 boolean claim 可能以 bool 或字串 "true" 表示的情況
+func isTrue(v any) bool {
+	return v == true || v == "true"
+}
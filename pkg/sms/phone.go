@@ -0,0 +1,48 @@
+package sms
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidPhoneNumber = errors.New("invalid phone number")
+
+// NormalizePhone 將電話號碼轉為 E.164 格式。
+// 沒有國碼的號碼視為台灣號碼 (例如 0912-345-678 -> +886912345678)。
+func NormalizePhone(number string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(number) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '(' || r == ')' || r == '.':
+			// 忽略常見的分隔符號
+		default:
+			return "", ErrInvalidPhoneNumber
+		}
+	}
+
+	digits := b.String()
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		digits = digits[1:]
+	case strings.HasPrefix(digits, "00"):
+		// 國際冠碼
+		digits = digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		digits = "886" + digits[1:]
+	}
+
+	// 台灣號碼國碼後不應再有開頭的 0 (+886 09... 是常見的誤植)
+	if strings.HasPrefix(digits, "8860") {
+		digits = "886" + digits[4:]
+	}
+
+	// E.164 最多 15 位數字
+	if len(digits) < 8 || len(digits) > 15 || digits[0] == '0' {
+		return "", ErrInvalidPhoneNumber
+	}
+	return "+" + digits, nil
+}
@@ -0,0 +1,36 @@
+package sms
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"0912345678", "+886912345678"},
+		{"0912-345-678", "+886912345678"},
+		{"+886 912 345 678", "+886912345678"},
+		{"+886-0912-345-678", "+886912345678"},
+		{"(02) 2345-6789", "+886223456789"},
+		{"00818012345678", "+818012345678"},
+		{"+1 (415) 555-2671", "+14155552671"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := NormalizePhone(tc.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	for _, invalid := range []string{"", "12", "0912abc678", "+886 9+12", "1234567890123456"} {
+		t.Run("Invalid "+invalid, func(t *testing.T) {
+			_, err := NormalizePhone(invalid)
+			assert.ErrorIs(t, err, ErrInvalidPhoneNumber)
+		})
+	}
+}
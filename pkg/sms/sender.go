@@ -0,0 +1,92 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+	"github.com/taiwanstay/taiwanstay-back/pkg/logger"
+)
+
+// Sender 定義發送簡訊的介面，to 為 E.164 格式的電話號碼
+type Sender interface {
+	Send(ctx context.Context, to, message string) error
+}
+
+// NewSender 依設定選擇簡訊服務商，未設定時使用只寫入 log 的 LogSender
+func NewSender(cfg *config.Config) Sender {
+	switch cfg.SMS.Provider {
+	case "twilio":
+		return NewTwilioSender(cfg)
+	default:
+		logger.Warn("SMS provider not configured, messages will only be logged", "provider", cfg.SMS.Provider)
+		return NewLogSender()
+	}
+}
+
+// LogSender 不實際發送簡訊，只將內容寫入 log，供本機開發與測試環境使用
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (s *LogSender) Send(ctx context.Context, to, message string) error {
+	logger.Info("SMS (not sent)", "to", to, "message", message)
+	return nil
+}
+
+// TwilioSender 實作 Twilio Programmable Messaging 簡訊發送
+type TwilioSender struct {
+	accountSID string
+	authToken  string
+	from       string
+	baseURL    string
+	client     *http.Client
+}
+
+func NewTwilioSender(cfg *config.Config) *TwilioSender {
+	return &TwilioSender{
+		accountSID: cfg.SMS.TwilioAccountSID,
+		authToken:  cfg.SMS.TwilioAuthToken,
+		from:       cfg.SMS.TwilioFromNumber,
+		baseURL:    "https://api.twilio.com",
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *TwilioSender) Send(ctx context.Context, to, message string) error {
+	if s.accountSID == "" || s.authToken == "" {
+		return errors.New("twilio credentials are not configured")
+	}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", s.baseURL, url.PathEscape(s.accountSID))
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", s.from)
+	form.Set("Body", message)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.accountSID, s.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("twilio api error: status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/taiwanstay/taiwanstay-back/pkg/config"
+)
+
+func TestTwilioSender(t *testing.T) {
+	var gotPath, gotUser, gotTo, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotUser, _, _ = r.BasicAuth()
+		_ = r.ParseForm()
+		gotTo = r.PostForm.Get("To")
+		gotBody = r.PostForm.Get("Body")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	sender := NewTwilioSender(&config.Config{SMS: config.SMSConfig{
+		TwilioAccountSID: "AC123",
+		TwilioAuthToken:  "token",
+		TwilioFromNumber: "+15005550006",
+	}})
+	sender.baseURL = server.URL
+
+	err := sender.Send(context.Background(), "+886912345678", "hello")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", gotPath)
+	assert.Equal(t, "AC123", gotUser)
+	assert.Equal(t, "+886912345678", gotTo)
+	assert.Equal(t, "hello", gotBody)
+}
+
+func TestTwilioSender_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sender := NewTwilioSender(&config.Config{SMS: config.SMSConfig{TwilioAccountSID: "AC123", TwilioAuthToken: "token"}})
+	sender.baseURL = server.URL
+
+	assert.Error(t, sender.Send(context.Background(), "+886912345678", "hello"))
+}
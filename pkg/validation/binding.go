@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+)
+
+// UseJSONFieldNames 讓 validator 的錯誤以 JSON 欄位名稱 (而非 Go 欄位名稱) 表示
+func UseJSONFieldNames(v *validator.Validate) {
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+}
+
+// FromBindError 將 JSON 解析或 binding tag 驗證的錯誤轉為 *errcode.Error
+func FromBindError(err error) *errcode.Error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]errcode.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, errcode.FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Rule:    fe.Tag(),
+				Message: bindingMessage(fe),
+			})
+		}
+		return errcode.Validation(fields)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return errcode.Validation([]errcode.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    RuleFormat,
+			Message: fmt.Sprintf("must be of type %s", typeErr.Type),
+		}})
+	}
+
+	if errors.Is(err, io.EOF) {
+		return errcode.BadRequest("REQUEST_BODY_REQUIRED", "request body is required")
+	}
+	return errcode.BadRequest("INVALID_REQUEST_BODY", "invalid request body").WithDetails(err.Error())
+}
+
+// fieldPath 去掉 validator namespace 開頭的 struct 名稱，例如 "Host.location.city" -> "location.city"
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+func bindingMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "eq":
+		return fmt.Sprintf("must be %s", fe.Param())
+	case "min":
+		return fmt.Sprintf("must be at least %s%s", fe.Param(), unit(fe.Kind()))
+	case "max":
+		return fmt.Sprintf("must be at most %s%s", fe.Param(), unit(fe.Kind()))
+	case "email":
+		return "must be a valid email address"
+	default:
+		return fmt.Sprintf("failed the %q rule", fe.Tag())
+	}
+}
+
+// unit 回傳 min/max 規則的單位：字串以字元計、陣列以項目計
+func unit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	default:
+		return ""
+	}
+}
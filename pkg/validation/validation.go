@@ -0,0 +1,175 @@
+// Package validation 提供請求內容的欄位驗證，驗證失敗時回傳帶有逐欄錯誤的 *errcode.Error，
+// 讓前端可以在對應的欄位旁顯示錯誤訊息。
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+	"github.com/taiwanstay/taiwanstay-back/pkg/i18n"
+	"github.com/taiwanstay/taiwanstay-back/pkg/sms"
+)
+
+// 驗證規則名稱，回傳於 FieldError.Rule 供前端判斷錯誤類型
+const (
+	RuleRequired  = "required"
+	RuleMaxLength = "maxLength"
+	RuleRange     = "range"
+	RuleFormat    = "format"
+	RulePhone     = "phone"
+	RuleEmail     = "email"
+	RuleURL       = "url"
+	RuleLanguage  = "language"
+	RuleNotFuture = "notFuture"
+	RuleOrder     = "order"
+	RuleOneOf     = "oneOf"
+)
+
+// languageCodePattern 接受 BCP 47 語言標籤的常見形式，例如 "zh"、"zh-TW"、"zh-Hant-TW"
+var languageCodePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// Validator 收集驗證過程中的欄位錯誤
+type Validator struct {
+	fields []errcode.FieldError
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+// Add 記錄一個欄位錯誤
+func (v *Validator) Add(field, rule, message string) {
+	v.fields = append(v.fields, errcode.FieldError{Field: field, Rule: rule, Message: message})
+}
+
+// Check 在 ok 為 false 時記錄欄位錯誤
+func (v *Validator) Check(ok bool, field, rule, message string) {
+	if !ok {
+		v.Add(field, rule, message)
+	}
+}
+
+// Valid 回傳目前是否沒有任何欄位錯誤
+func (v *Validator) Valid() bool {
+	return len(v.fields) == 0
+}
+
+// Err 沒有錯誤時回傳 nil，否則回傳包含所有欄位錯誤的 *errcode.Error
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return errcode.Validation(v.fields)
+}
+
+// Required 檢查字串不可為空白
+func (v *Validator) Required(field, value string) {
+	v.Check(strings.TrimSpace(value) != "", field, RuleRequired, "is required")
+}
+
+// MaxLength 檢查字串長度 (以字元計) 不超過 max
+func (v *Validator) MaxLength(field, value string, max int) {
+	v.Check(utf8.RuneCountInString(value) <= max, field, RuleMaxLength, fmt.Sprintf("must be at most %d characters", max))
+}
+
+// Range 檢查數值介於 min 與 max 之間 (含)
+func (v *Validator) Range(field string, value, min, max int) {
+	v.Check(value >= min && value <= max, field, RuleRange, fmt.Sprintf("must be between %d and %d", min, max))
+}
+
+// Phone 檢查電話號碼格式，空字串視為未填寫
+func (v *Validator) Phone(field, value string) {
+	if value == "" {
+		return
+	}
+	_, err := sms.NormalizePhone(value)
+	v.Check(err == nil, field, RulePhone, "must be a valid phone number")
+}
+
+// Email 檢查 email 格式，空字串視為未填寫
+func (v *Validator) Email(field, value string) {
+	if value == "" {
+		return
+	}
+	addr, err := mail.ParseAddress(value)
+	v.Check(err == nil && addr.Address == value, field, RuleEmail, "must be a valid email address")
+}
+
+// URL 檢查為 http 或 https 的絕對網址，空字串視為未填寫
+func (v *Validator) URL(field, value string) {
+	if value == "" {
+		return
+	}
+	u, err := url.Parse(value)
+	v.Check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", field, RuleURL, "must be an http or https URL")
+}
+
+// LanguageCode 檢查為 BCP 47 語言代碼，例如 "en" 或 "zh-TW"
+func (v *Validator) LanguageCode(field, value string) {
+	v.Check(languageCodePattern.MatchString(value), field, RuleLanguage, "must be a language code such as \"en\" or \"zh-TW\"")
+}
+
+// LanguageCodes 逐一檢查語言代碼
+func (v *Validator) LanguageCodes(field string, values []string) {
+	for i, value := range values {
+		v.LanguageCode(Index(field, i), value)
+	}
+}
+
+// Locale 檢查為支援的語系 (i18n.Supported)，空字串視為未提供
+func (v *Validator) Locale(field, value string) {
+	if value == "" {
+		return
+	}
+	if !i18n.IsSupported(value) {
+		locales := make([]string, len(i18n.Supported))
+		for i, l := range i18n.Supported {
+			locales[i] = string(l)
+		}
+		v.Add(field, RuleOneOf, "must be one of "+strings.Join(locales, ", "))
+	}
+}
+
+// Coordinates 檢查 GeoJSON 座標為 [經度, 緯度] 且在有效範圍內
+func (v *Validator) Coordinates(field string, coords []float64) {
+	if len(coords) != 2 {
+		v.Add(field, RuleFormat, "must be [longitude, latitude]")
+		return
+	}
+	v.Check(coords[0] >= -180 && coords[0] <= 180, Index(field, 0), RuleRange, "longitude must be between -180 and 180")
+	v.Check(coords[1] >= -90 && coords[1] <= 90, Index(field, 1), RuleRange, "latitude must be between -90 and 90")
+}
+
+// NotFuture 檢查時間不晚於現在
+func (v *Validator) NotFuture(field string, t time.Time) {
+	v.Check(!t.After(time.Now()), field, RuleNotFuture, "must not be in the future")
+}
+
+// Date 檢查為 YYYY-MM-DD 格式的日期，並回傳解析結果
+func (v *Validator) Date(field, value string) (time.Time, bool) {
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		v.Add(field, RuleFormat, "must be a date in YYYY-MM-DD format")
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Field 組合巢狀欄位路徑，例如 Field("location", "coordinates") -> "location.coordinates"
+func Field(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+	return parent + "." + child
+}
+
+// Index 組合陣列元素的欄位路徑，例如 Index("languages", 1) -> "languages[1]"
+func Index(field string, i int) string {
+	return fmt.Sprintf("%s[%d]", field, i)
+}
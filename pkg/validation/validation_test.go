@@ -0,0 +1,101 @@
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/taiwanstay/taiwanstay-back/pkg/errcode"
+)
+
+func fieldRules(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var e *errcode.Error
+	if !errors.As(err, &e) {
+		t.Fatalf("expected *errcode.Error, got %v", err)
+	}
+	assert.Equal(t, http.StatusBadRequest, e.Status)
+	assert.Equal(t, "VALIDATION_FAILED", e.Code)
+	rules := map[string]string{}
+	for _, f := range e.Fields {
+		rules[f.Field] = f.Rule
+	}
+	return rules
+}
+
+func TestValidator(t *testing.T) {
+	t.Run("Valid Input", func(t *testing.T) {
+		v := New()
+		v.Required("name", "Alice")
+		v.Phone("phone", "0912-345-678")
+		v.Phone("emptyPhone", "")
+		v.Email("email", "alice@example.com")
+		v.URL("website", "https://example.com/about")
+		v.LanguageCodes("languages", []string{"en", "zh-TW", "zh-Hant-TW"})
+		v.Coordinates("coordinates", []float64{121.5654, 25.033})
+		v.NotFuture("birthDate", time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC))
+		v.Range("hours", 8, 0, 24)
+
+		assert.True(t, v.Valid())
+		assert.NoError(t, v.Err())
+	})
+
+	t.Run("Collects Every Field Error", func(t *testing.T) {
+		v := New()
+		v.Required("name", "  ")
+		v.MaxLength("bio", "太長了", 2)
+		v.Phone("phone", "call me")
+		v.Email("email", "Alice <alice@example.com>")
+		v.URL("website", "javascript:alert(1)")
+		v.LanguageCodes("languages", []string{"en", "English"})
+		v.Coordinates("location", []float64{181, -91})
+		v.NotFuture("birthDate", time.Now().Add(time.Hour))
+		v.Range("hours", 25, 0, 24)
+		v.Date("startDate", "2024/01/01")
+
+		assert.Equal(t, map[string]string{
+			"name":         RuleRequired,
+			"bio":          RuleMaxLength,
+			"phone":        RulePhone,
+			"email":        RuleEmail,
+			"website":      RuleURL,
+			"languages[1]": RuleLanguage,
+			"location[0]":  RuleRange,
+			"location[1]":  RuleRange,
+			"birthDate":    RuleNotFuture,
+			"hours":        RuleRange,
+			"startDate":    RuleFormat,
+		}, fieldRules(t, v.Err()))
+	})
+
+	t.Run("Coordinates Need Two Values", func(t *testing.T) {
+		v := New()
+		v.Coordinates("location", []float64{121})
+
+		assert.Equal(t, map[string]string{"location": RuleFormat}, fieldRules(t, v.Err()))
+	})
+}
+
+func TestFromBindError(t *testing.T) {
+	type coordinates struct {
+		Values []float64 `json:"coordinates" binding:"required,min=2,max=2"`
+	}
+	type payload struct {
+		Name     string      `json:"name" binding:"required"`
+		Location coordinates `json:"location"`
+	}
+
+	v := validator.New()
+	v.SetTagName("binding")
+	UseJSONFieldNames(v)
+
+	err := v.Struct(payload{Location: coordinates{Values: []float64{1}}})
+
+	assert.Equal(t, map[string]string{
+		"name":                 "required",
+		"location.coordinates": "min",
+	}, fieldRules(t, FromBindError(err)))
+}